@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/internal/app"
+	"github.com/pprishchepa/go-casino-example/internal/config"
+)
+
+// runArchive archives the ledger partitions of the months before the given
+// one to compressed files in PARTITION_ARCHIVE_DIR, e.g.
+//
+//	casino archive -before 2024-01
+func runArchive(ctx context.Context, conf config.Config, args []string) error {
+	fs := flag.NewFlagSet("archive", flag.ContinueOnError)
+	before := fs.String("before", "", "month as YYYY-MM, partitions of earlier months are archived")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *before == "" {
+		return usageErrorf("-before is required")
+	}
+	month, err := time.Parse("2006-01", *before)
+	if err != nil {
+		return usageErrorf("invalid month %q", *before)
+	}
+
+	return app.ArchiveEntries(ctx, conf, month)
+}
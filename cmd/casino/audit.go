@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/pprishchepa/go-casino-example/internal/app"
+	"github.com/pprishchepa/go-casino-example/internal/config"
+)
+
+// runVerifyAudit verifies the hash chain of the audit log of every wallet
+// against the ledger, and exits with exitInconsistent if it does not check
+// out, e.g.
+//
+//	casino verify-audit
+func runVerifyAudit(ctx context.Context, conf config.Config, args []string) error {
+	fs := flag.NewFlagSet("verify-audit", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected args %q", fs.Args())
+	}
+
+	return app.VerifyAudit(ctx, conf)
+}
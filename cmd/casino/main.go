@@ -1,7 +1,134 @@
 package main
 
-import "github.com/pprishchepa/go-casino-example/internal/app"
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pprishchepa/go-casino-example/internal/app"
+	"github.com/pprishchepa/go-casino-example/internal/config"
+)
+
+// Exit codes of the commands. Scripts can tell a ledger that does not check
+// out from a command that could not run.
+const (
+	exitOK           = 0
+	exitFailure      = 1
+	exitUsage        = 2
+	exitInconsistent = 3
+	exitConfig       = 4
+)
+
+const usage = `Usage: casino [command] [flags]
+
+Commands:
+  serve         run the server, the default (-no-migrate to skip migrations)
+  migrate       migrate the database: up, down [N], status or force V
+  seed          create wallets with money on them
+  reconcile     check balances against the ledger and the audit log
+  verify-audit  verify the hash chain of the audit log against the ledger
+  statements    generate account statements in bulk
+  archive       archive ledger partitions of past months
+
+Exit codes: 0 done, 1 failed, 2 bad usage, 3 inconsistencies found,
+4 bad config.
+
+Run "casino <command> -h" for the flags of a command.
+`
+
+// command runs a subcommand with the args that follow its name.
+type command func(ctx context.Context, conf config.Config, args []string) error
+
+var commands = map[string]command{
+	"serve":        runServe,
+	"migrate":      runMigrate,
+	"seed":         runSeed,
+	"reconcile":    runReconcile,
+	"verify-audit": runVerifyAudit,
+	"statements":   runStatements,
+	"archive":      runArchive,
+}
 
 func main() {
-	app.New().Run()
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" || name == "-h" || name == "--help" {
+		fmt.Fprint(os.Stdout, usage)
+		return exitOK
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", name, usage)
+		return exitUsage
+	}
+
+	conf, err := config.NewConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitConfig
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// Not every step gives up on a cancelled ctx, e.g. waiting for the
+	// database to come up, so a second signal kills the command. Reset and
+	// not stop, as fx listens to signals too while an app is running.
+	go func() {
+		<-ctx.Done()
+		signal.Reset(os.Interrupt, syscall.SIGTERM)
+	}()
+
+	err = cmd(ctx, conf, args)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, new(usageError)):
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	case errors.Is(err, app.ErrInconsistent):
+		fmt.Fprintln(os.Stderr, err)
+		return exitInconsistent
+	default:
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+}
+
+// usageError is returned for bad args, as opposed to a command that failed.
+type usageError struct {
+	err error
+}
+
+func usageErrorf(format string, a ...any) error {
+	return usageError{err: fmt.Errorf(format, a...)}
+}
+
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e usageError) Unwrap() error {
+	return e.err
+}
+
+// parseFlags parses the flags of a command, failing with a usage error on bad
+// ones. The flag package prints what is wrong itself.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{err: err}
+	}
+	return nil
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/pprishchepa/go-casino-example/internal/app"
+	"github.com/pprishchepa/go-casino-example/internal/config"
+)
+
+const migrateUsage = `Usage: casino migrate <action>
+
+Actions:
+  up          apply the migrations not applied yet
+  down [N]    roll back the last N migrations, 1 by default, -all for all
+  status      print the current and the latest version
+  force V     set the version after a failed migration was fixed by hand,
+              -1 for none
+`
+
+// runMigrate migrates the database, e.g.
+//
+//	casino migrate up
+//	casino migrate down 1
+//	casino migrate down -all
+//	casino migrate status
+//	casino migrate force 1720623600
+func runMigrate(ctx context.Context, conf config.Config, args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("migrate needs one of up, down, status or force")
+	}
+
+	action, rest := args[0], args[1:]
+	switch action {
+	case "-h", "--help", "help":
+		fmt.Print(migrateUsage)
+		return nil
+	case "up":
+		if len(rest) > 0 {
+			return usageErrorf("unexpected args %q", rest)
+		}
+		return app.MigrateUp(ctx, conf)
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		all := fs.Bool("all", false, "roll back every migration")
+		if err := parseFlags(fs, rest); err != nil {
+			return err
+		}
+		steps, err := parseSteps(fs.Args(), *all)
+		if err != nil {
+			return err
+		}
+		return app.MigrateDown(ctx, conf, steps)
+	case "force":
+		if len(rest) != 1 {
+			return usageErrorf("force needs a version")
+		}
+		version, err := strconv.Atoi(rest[0])
+		if err != nil || version < -1 {
+			return usageErrorf("invalid version %q", rest[0])
+		}
+		return app.MigrateForce(ctx, conf, version)
+	case "status":
+		if len(rest) > 0 {
+			return usageErrorf("unexpected args %q", rest)
+		}
+		status, err := app.MigrateStatus(ctx, conf)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("version %d, latest %d, %d pending", status.Version, status.Latest, status.Pending)
+		if status.Dirty {
+			fmt.Print(", dirty")
+		}
+		fmt.Println()
+		return nil
+	default:
+		return usageErrorf("unknown migrate action %q", action)
+	}
+}
+
+// parseSteps returns how many migrations down rolls back: one by default, and
+// zero, meaning all of them, only if asked for explicitly.
+func parseSteps(args []string, all bool) (int, error) {
+	switch {
+	case all && len(args) > 0:
+		return 0, usageErrorf("-all cannot be combined with a number of steps")
+	case all:
+		return 0, nil
+	case len(args) == 0:
+		return 1, nil
+	case len(args) > 1:
+		return 0, usageErrorf("unexpected args %q", args[1:])
+	}
+
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps <= 0 {
+		return 0, usageErrorf("invalid number of steps %q", args[0])
+	}
+	return steps, nil
+}
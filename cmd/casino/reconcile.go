@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/pprishchepa/go-casino-example/internal/app"
+	"github.com/pprishchepa/go-casino-example/internal/config"
+)
+
+// runReconcile checks every wallet against its ledger and audit log, and
+// exits with exitInconsistent if any does not check out, e.g.
+//
+//	casino reconcile
+func runReconcile(ctx context.Context, conf config.Config, args []string) error {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected args %q", fs.Args())
+	}
+
+	return app.Reconcile(ctx, conf)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/app"
+	"github.com/pprishchepa/go-casino-example/internal/config"
+)
+
+// runSeed creates wallets with money deposited to them, e.g.
+//
+//	casino seed -count 100 -amount 250.50 -brand acme -currency USD
+func runSeed(ctx context.Context, conf config.Config, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	count := fs.Int("count", 10, "number of wallets to create")
+	amount := fs.String("amount", "100", "money to deposit to each wallet, none if zero")
+	brand := fs.String("brand", "default", "brand of the wallets")
+	currency := fs.String("currency", "EUR", "ISO 4217 currency of the wallets")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected args %q", fs.Args())
+	}
+
+	if *count <= 0 {
+		return usageErrorf("invalid count %d", *count)
+	}
+	m, err := money.Parse(*amount)
+	if err != nil || m.IsNegative() {
+		return usageErrorf("invalid amount %q", *amount)
+	}
+
+	if *brand == "" {
+		return usageErrorf("invalid brand %q", *brand)
+	}
+	if len(*currency) != 3 || strings.Trim(*currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return usageErrorf("invalid currency %q", *currency)
+	}
+
+	return app.Seed(ctx, conf, *count, m, *brand, *currency)
+}
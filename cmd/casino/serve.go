@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/pprishchepa/go-casino-example/internal/app"
+	"github.com/pprishchepa/go-casino-example/internal/config"
+)
+
+// runServe runs the server until it gets a signal, e.g.
+//
+//	casino serve --no-migrate
+func runServe(_ context.Context, conf config.Config, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	noMigrate := fs.Bool("no-migrate", false, "do not apply migrations on start")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected args %q", fs.Args())
+	}
+
+	// Run handles signals itself and exits if the server does not start.
+	app.New(conf, app.ServeOptions{NoMigrate: *noMigrate}).Run()
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/internal/app"
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/statement"
+)
+
+// runStatements generates statements in bulk, by default of every wallet for
+// the previous calendar month, e.g.
+//
+//	casino statements -month 2024-06 -format pdf -out ./statements
+func runStatements(ctx context.Context, conf config.Config, args []string) error {
+	fs := flag.NewFlagSet("statements", flag.ContinueOnError)
+	month := fs.String("month", "", "calendar month as YYYY-MM, the previous month if neither it nor -from and -to are set")
+	from := fs.String("from", "", "start of the period as RFC 3339, inclusive")
+	to := fs.String("to", "", "end of the period as RFC 3339, exclusive")
+	format := fs.String("format", string(statement.FormatPDF), "file format: csv, json or pdf")
+	out := fs.String("out", ".", "directory to save the files to")
+	wallets := fs.String("wallets", "", "comma-separated wallet IDs, every wallet if empty")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	opts := app.StatementsOptions{Dir: *out}
+
+	var err error
+	if opts.Period, err = parsePeriod(*month, *from, *to); err != nil {
+		return usageError{err: err}
+	}
+	if opts.Format, err = statement.ParseFormat(*format); err != nil {
+		return usageError{err: err}
+	}
+	if *wallets != "" {
+		for _, s := range strings.Split(*wallets, ",") {
+			walletID, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || walletID <= 0 {
+				return usageErrorf("invalid wallet id %q", s)
+			}
+			opts.WalletIDs = append(opts.WalletIDs, walletID)
+		}
+	}
+
+	return app.GenerateStatements(ctx, conf, opts)
+}
+
+func parsePeriod(month, from, to string) (statement.Period, error) {
+	switch {
+	case month != "" && (from != "" || to != ""):
+		return statement.Period{}, fmt.Errorf("-month cannot be combined with -from and -to")
+	case month != "":
+		t, err := time.Parse("2006-01", month)
+		if err != nil {
+			return statement.Period{}, fmt.Errorf("invalid month %q", month)
+		}
+		return statement.Month(t), nil
+	case from != "" || to != "":
+		var period statement.Period
+		var err error
+		if period.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return statement.Period{}, fmt.Errorf("invalid -from: %w", err)
+		}
+		if period.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return statement.Period{}, fmt.Errorf("invalid -to: %w", err)
+		}
+		return period, nil
+	default:
+		// The last day of the previous month.
+		now := time.Now().UTC()
+		return statement.Month(now.AddDate(0, 0, -now.Day())), nil
+	}
+}
@@ -0,0 +1,254 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+var (
+	ErrWalletFrozen       = errors.New("wallet is frozen")
+	ErrInvalidAdminAction = errors.New("invalid admin action")
+)
+
+// AdminActionKind says what an admin did to a wallet.
+type AdminActionKind string
+
+const (
+	AdminAdjustBalance AdminActionKind = "adjust_balance"
+	AdminFreeze        AdminActionKind = "freeze"
+	AdminUnfreeze      AdminActionKind = "unfreeze"
+	AdminOverrideLimit AdminActionKind = "override_limit"
+	AdminEvictCache    AdminActionKind = "evict_cache"
+	AdminReverseEntry  AdminActionKind = "reverse_entry"
+	AdminLiftExclusion AdminActionKind = "lift_exclusion"
+)
+
+// AdminAction is a record of the audit trail of back-office operations. It
+// is added in the same tx as the change it describes and never changed. ID
+// and CreatedAt are set by the store when the action is added.
+type AdminAction struct {
+	ID       int
+	WalletID int
+	Kind     AdminActionKind
+	// Actor is the subject of the admin token.
+	Actor  string
+	Reason string
+	// Details is a JSON object with what the action changed, such as the
+	// entry it added.
+	Details   json.RawMessage
+	CreatedAt time.Time
+}
+
+// AdminActionFilter selects actions of the audit trail. Zero fields match any
+// action.
+type AdminActionFilter struct {
+	WalletID int
+	Actor    string
+	Kind     AdminActionKind
+	AfterID  int
+	Limit    int
+}
+
+// WalletFilter selects wallets by the state of their balance. Nil and zero
+// fields match any wallet.
+type WalletFilter struct {
+	Flagged *bool
+	Frozen  *bool
+	// MinAmount and MaxAmount bound real money, both inclusive.
+	MinAmount *money.Money
+	MaxAmount *money.Money
+	AfterID   int
+	Limit     int
+}
+
+// AdjustBalance corrects real money of the wallet on behalf of actor, adding
+// amount if it is positive and taking it if it is negative. Unlike a bet, an
+// adjustment is not refused by limits, exclusions or a freeze, but it cannot
+// take more real money than there is.
+func (c WalletUseCases) AdjustBalance(ctx context.Context, walletID int, amount money.Money, actor, reason string) (*Entry, error) {
+	if actor == "" || reason == "" {
+		return nil, fmt.Errorf("%w: actor and reason are required", ErrInvalidAdminAction)
+	}
+	if amount.IsZero() {
+		return nil, fmt.Errorf("%w: adjustment must not be zero", ErrInvalidAdminAction)
+	}
+
+	metadata, err := json.Marshal(map[string]string{"actor": actor, "reason": reason})
+	if err != nil {
+		return nil, fmt.Errorf("marshal metadata: %w", err)
+	}
+	details := EntryDetails{Operation: OperationAdjustment, Initiator: InitiatorAdmin, Metadata: metadata}
+
+	adjustment := Entry{WalletID: walletID, EntryDetails: details}
+	if amount.IsPositive() {
+		entry := DebitEntry{WalletID: walletID, Amount: amount, EntryDetails: details}
+		if err := c.addMoney(ctx, &entry); err != nil {
+			return nil, err
+		}
+		adjustment.ID, adjustment.Debit, adjustment.CreatedAt = entry.ID, &entry.Amount, entry.CreatedAt
+	} else {
+		balance, err := c.storage.GetBalance(ctx, walletID)
+		if err != nil {
+			return nil, fmt.Errorf("get balance: %w", err)
+		}
+		taken := money.Money{}.Sub(amount)
+		if balance.Amount.Cmp(taken) < 0 {
+			return nil, ErrInsufficientFunds
+		}
+		balance.Amount = balance.Amount.Sub(taken)
+		if err := c.storage.SaveBalance(ctx, balance); err != nil {
+			return nil, fmt.Errorf("save balance: %w", err)
+		}
+
+		entry := CreditEntry{WalletID: walletID, Amount: taken, EntryDetails: details}
+		if err := c.storage.AddCreditEntry(ctx, &entry); err != nil {
+			return nil, fmt.Errorf("add credit entry: %w", err)
+		}
+		adjustment.ID, adjustment.Credit, adjustment.CreatedAt = entry.ID, &entry.Amount, entry.CreatedAt
+	}
+
+	err = c.recordAction(ctx, walletID, AdminAdjustBalance, actor, reason, map[string]any{
+		"entryId": adjustment.ID,
+		"amount":  amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &adjustment, nil
+}
+
+// FreezeWallet stops the wallet from betting and withdrawing until it is
+// unfrozen. Money keeps coming in, so that wins of rounds already played and
+// deposits on their way are not lost.
+func (c WalletUseCases) FreezeWallet(ctx context.Context, walletID int, actor, reason string) (*WalletBalance, error) {
+	return c.setFrozen(ctx, walletID, true, actor, reason)
+}
+
+func (c WalletUseCases) UnfreezeWallet(ctx context.Context, walletID int, actor, reason string) (*WalletBalance, error) {
+	return c.setFrozen(ctx, walletID, false, actor, reason)
+}
+
+func (c WalletUseCases) setFrozen(ctx context.Context, walletID int, frozen bool, actor, reason string) (*WalletBalance, error) {
+	if actor == "" || reason == "" {
+		return nil, fmt.Errorf("%w: actor and reason are required", ErrInvalidAdminAction)
+	}
+
+	balance, err := c.storage.GetBalance(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+	if balance.Frozen == frozen {
+		return nil, fmt.Errorf("%w: frozen is already %t", ErrInvalidAdminAction, frozen)
+	}
+
+	balance.Frozen = frozen
+	if err := c.storage.SaveBalance(ctx, balance); err != nil {
+		return nil, fmt.Errorf("save balance: %w", err)
+	}
+
+	kind := AdminUnfreeze
+	if frozen {
+		kind = AdminFreeze
+	}
+	if err := c.recordAction(ctx, walletID, kind, actor, reason, nil); err != nil {
+		return nil, err
+	}
+
+	return balance, nil
+}
+
+// OverrideLimit sets the limit of the given kind and period to amount, or
+// removes it if amount is nil, at once. Unlike SetLimit, raising or removing
+// a limit this way skips the cooling-off period and drops a pending change.
+func (c WalletUseCases) OverrideLimit(
+	ctx context.Context,
+	walletID int,
+	kind LimitKind,
+	period LimitPeriod,
+	amount *money.Money,
+	actor, reason string,
+) (*Limit, error) {
+	if actor == "" || reason == "" {
+		return nil, fmt.Errorf("%w: actor and reason are required", ErrInvalidAdminAction)
+	}
+	if !kind.Valid() || !period.Valid() {
+		return nil, ErrInvalidLimit
+	}
+	if amount != nil && amount.IsNegative() {
+		return nil, ErrInvalidAmount
+	}
+
+	if _, err := c.storage.GetBalance(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+
+	limit := Limit{WalletID: walletID, Kind: kind, Period: period, Amount: amount}
+	if err := c.storage.SaveLimit(ctx, &limit); err != nil {
+		return nil, fmt.Errorf("save limit: %w", err)
+	}
+
+	err := c.recordAction(ctx, walletID, AdminOverrideLimit, actor, reason, map[string]any{
+		"kind":   kind,
+		"period": period,
+		"amount": amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &limit, nil
+}
+
+// RecordCacheEviction adds the eviction of the cached balance of the wallet to
+// the audit trail. The cache itself is not the business of the domain.
+func (c WalletUseCases) RecordCacheEviction(ctx context.Context, walletID int, actor, reason string) error {
+	if actor == "" {
+		return fmt.Errorf("%w: actor is required", ErrInvalidAdminAction)
+	}
+
+	if _, err := c.storage.GetBalance(ctx, walletID); err != nil {
+		return fmt.Errorf("get balance: %w", err)
+	}
+
+	return c.recordAction(ctx, walletID, AdminEvictCache, actor, reason, nil)
+}
+
+// ListAdminActions returns actions of the audit trail that match the filter,
+// oldest first.
+func (c WalletUseCases) ListAdminActions(ctx context.Context, filter AdminActionFilter) ([]AdminAction, error) {
+	actions, err := c.storage.ListAdminActions(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list admin actions: %w", err)
+	}
+	return actions, nil
+}
+
+// recordAction adds an action to the audit trail. Details are marshalled to a
+// JSON object unless nil.
+func (c WalletUseCases) recordAction(
+	ctx context.Context,
+	walletID int,
+	kind AdminActionKind,
+	actor, reason string,
+	details map[string]any,
+) error {
+	action := AdminAction{WalletID: walletID, Kind: kind, Actor: actor, Reason: reason}
+	if details != nil {
+		var err error
+		if action.Details, err = json.Marshal(details); err != nil {
+			return fmt.Errorf("marshal details: %w", err)
+		}
+	}
+
+	if err := c.storage.AddAdminAction(ctx, &action); err != nil {
+		return fmt.Errorf("add admin action: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCases_AdjustBalance(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     string
+		reason     string
+		wantErr    error
+		wantAmount string
+		wantDebit  bool
+	}{
+		{name: "add", amount: "15.5", reason: "goodwill", wantAmount: "115.500", wantDebit: true},
+		{name: "take", amount: "-40", reason: "duplicate payout", wantAmount: "60.000"},
+		{name: "take everything", amount: "-100", reason: "duplicate payout", wantAmount: "0.000"},
+		{name: "take too much", amount: "-100.001", reason: "duplicate payout", wantErr: domain.ErrInsufficientFunds, wantAmount: "100.000"},
+		{name: "zero", amount: "0", reason: "nothing", wantErr: domain.ErrInvalidAdminAction, wantAmount: "100.000"},
+		{name: "without reason", amount: "10", wantErr: domain.ErrInvalidAdminAction, wantAmount: "100.000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newFakeWalletStore(25, mustParse(t, "100"))
+			store.bonus = mustParse(t, "20")
+			uc := domain.NewWalletUseCases(store)
+
+			entry, err := uc.AdjustBalance(ctx, 25, mustParse(t, tt.amount), "support", tt.reason)
+			assert.Equal(t, tt.wantAmount, store.amount.String())
+			assert.Equal(t, "20.000", store.bonus.String(), "bonus money is never adjusted")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, store.actions)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, domain.OperationAdjustment, entry.Operation)
+			assert.Equal(t, domain.InitiatorAdmin, entry.Initiator)
+			assert.Equal(t, tt.wantDebit, entry.Debit != nil)
+			assert.JSONEq(t, `{"actor": "support", "reason": "`+tt.reason+`"}`, string(entry.Metadata))
+
+			require.Len(t, store.actions, 1)
+			assert.Equal(t, domain.AdminAction{
+				ID:       1,
+				WalletID: 25,
+				Kind:     domain.AdminAdjustBalance,
+				Actor:    "support",
+				Reason:   tt.reason,
+				Details:  store.actions[0].Details,
+			}, store.actions[0])
+			assert.JSONEq(t, `{"entryId": 1, "amount": "`+mustParse(t, tt.amount).String()+`"}`, string(store.actions[0].Details))
+		})
+	}
+}
+
+func TestWalletUseCases_FreezeWallet(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeWalletStore(25, mustParse(t, "100"))
+	uc := domain.NewWalletUseCases(store)
+
+	_, err := uc.FreezeWallet(ctx, 25, "support", "")
+	require.ErrorIs(t, err, domain.ErrInvalidAdminAction)
+	_, err = uc.UnfreezeWallet(ctx, 25, "support", "not frozen")
+	require.ErrorIs(t, err, domain.ErrInvalidAdminAction)
+
+	balance, err := uc.FreezeWallet(ctx, 25, "support", "fraud check")
+	require.NoError(t, err)
+	assert.True(t, balance.Frozen)
+	_, err = uc.FreezeWallet(ctx, 25, "support", "again")
+	require.ErrorIs(t, err, domain.ErrInvalidAdminAction)
+
+	err = uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "1")})
+	require.ErrorIs(t, err, domain.ErrWalletFrozen)
+	err = uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "1"), EntryDetails: domain.EntryDetails{Operation: domain.OperationWithdrawal}})
+	require.ErrorIs(t, err, domain.ErrWalletFrozen)
+	require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, "5")}), "wins still arrive")
+	_, err = uc.AdjustBalance(ctx, 25, mustParse(t, "-5"), "support", "undo the win")
+	require.NoError(t, err, "admins can still adjust")
+
+	balance, err = uc.UnfreezeWallet(ctx, 25, "support", "cleared")
+	require.NoError(t, err)
+	assert.False(t, balance.Frozen)
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "1")}))
+
+	actions, err := uc.ListAdminActions(ctx, domain.AdminActionFilter{WalletID: 25})
+	require.NoError(t, err)
+	var kinds []domain.AdminActionKind
+	for _, a := range actions {
+		kinds = append(kinds, a.Kind)
+	}
+	assert.Equal(t, []domain.AdminActionKind{domain.AdminFreeze, domain.AdminAdjustBalance, domain.AdminUnfreeze}, kinds)
+}
+
+func TestWalletUseCases_OverrideLimit(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeWalletStore(25, mustParse(t, "100"))
+	uc := domain.NewWalletUseCases(store)
+
+	low := mustParse(t, "10")
+	_, err := uc.SetLimit(ctx, 25, domain.LimitWager, domain.LimitDay, &low)
+	require.NoError(t, err)
+	high := mustParse(t, "50")
+	limit, err := uc.SetLimit(ctx, 25, domain.LimitWager, domain.LimitDay, &high)
+	require.NoError(t, err)
+	require.NotZero(t, limit.PendingAt, "the player has to wait for a higher limit")
+
+	_, err = uc.OverrideLimit(ctx, 25, domain.LimitWager, domain.LimitDay, &high, "support", "")
+	require.ErrorIs(t, err, domain.ErrInvalidAdminAction)
+
+	limit, err = uc.OverrideLimit(ctx, 25, domain.LimitWager, domain.LimitDay, &high, "support", "verified income")
+	require.NoError(t, err)
+	assert.Equal(t, &high, limit.Amount)
+	assert.Zero(t, limit.PendingAt)
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "30")}))
+
+	limit, err = uc.OverrideLimit(ctx, 25, domain.LimitWager, domain.LimitDay, nil, "support", "limit set by mistake")
+	require.NoError(t, err)
+	assert.Nil(t, limit.Amount)
+	assert.Empty(t, store.limits)
+
+	require.Len(t, store.actions, 2)
+	assert.JSONEq(t, `{"kind": "wager", "period": "day", "amount": "50.000"}`, string(store.actions[0].Details))
+	assert.JSONEq(t, `{"kind": "wager", "period": "day", "amount": null}`, string(store.actions[1].Details))
+}
+
+func TestWalletUseCases_RecordCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeWalletStore(25, money.Money{})
+	uc := domain.NewWalletUseCases(store)
+
+	require.ErrorIs(t, uc.RecordCacheEviction(ctx, 25, "", ""), domain.ErrInvalidAdminAction)
+	require.ErrorIs(t, uc.RecordCacheEviction(ctx, 26, "support", ""), domain.ErrWalletNotFound)
+	require.NoError(t, uc.RecordCacheEviction(ctx, 25, "support", ""))
+
+	require.Len(t, store.actions, 1)
+	assert.Equal(t, domain.AdminEvictCache, store.actions[0].Kind)
+	assert.Nil(t, store.actions[0].Details)
+}
@@ -0,0 +1,259 @@
+package domain
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// AuditRecord is a link of the hash chain kept over the ledger of a wallet.
+// Every entry gets a record when it is added. Payload is the entry as it was
+// then, and Hash covers the payload and the hash of the previous record, so
+// that changing, removing or inserting a record breaks the chain.
+type AuditRecord struct {
+	WalletID int
+	// Seq numbers the records of a wallet from 1 without gaps.
+	Seq     int
+	EntryID int
+	// Payload is kept byte for byte as it was hashed. Stores may encode the
+	// entry differently as long as it decodes to the same auditPayload.
+	Payload json.RawMessage
+	// PrevHash is the hash of the previous record, nil for the first one.
+	PrevHash  []byte
+	Hash      []byte
+	CreatedAt time.Time
+}
+
+// NewAuditRecord returns the record that follows prev, or the first record of
+// the wallet if prev is nil, for an entry that has just been added.
+func NewAuditRecord(prev *AuditRecord, entry Entry) (AuditRecord, error) {
+	payload, err := json.Marshal(newAuditPayload(entry))
+	if err != nil {
+		return AuditRecord{}, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	record := AuditRecord{WalletID: entry.WalletID, Seq: 1, EntryID: entry.ID, Payload: payload, CreatedAt: entry.CreatedAt}
+	if prev != nil {
+		record.Seq, record.PrevHash = prev.Seq+1, prev.Hash
+	}
+	record.Hash = AuditHash(record.PrevHash, record.Payload)
+
+	return record, nil
+}
+
+// AuditHash is SHA-256 over the previous hash followed by the payload. The
+// postgres store computes the same in SQL.
+func AuditHash(prevHash, payload []byte) []byte {
+	h := sha256.New()
+	h.Write(prevHash)
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+// auditPayload is what a record keeps of an entry. Amounts are in thousandths
+// so that no decimal formatting is involved.
+type auditPayload struct {
+	ID         int             `json:"id"`
+	WalletID   int             `json:"walletId"`
+	Debit      *int            `json:"debit"`
+	Credit     *int            `json:"credit"`
+	Bonus      int             `json:"bonus"`
+	Operation  OperationType   `json:"operation"`
+	Reference  string          `json:"reference"`
+	Initiator  Initiator       `json:"initiator"`
+	Metadata   json.RawMessage `json:"metadata"`
+	ReversalOf *int            `json:"reversalOf"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+func newAuditPayload(entry Entry) auditPayload {
+	p := auditPayload{
+		ID:        entry.ID,
+		WalletID:  entry.WalletID,
+		Bonus:     entry.Bonus.AsInt(),
+		Operation: entry.Operation,
+		Reference: entry.Reference,
+		Initiator: entry.Initiator,
+		Metadata:  entry.Metadata,
+		CreatedAt: entry.CreatedAt,
+	}
+	if entry.Debit != nil {
+		v := entry.Debit.AsInt()
+		p.Debit = &v
+	}
+	if entry.Credit != nil {
+		v := entry.Credit.AsInt()
+		p.Credit = &v
+	}
+	if entry.ReversalOf != 0 {
+		v := entry.ReversalOf
+		p.ReversalOf = &v
+	}
+	return p
+}
+
+// verifyRunningBalance checks that the balance after each entry is the
+// balance after the previous one changed by the entry, starting from zero.
+// Each entry is checked against what the previous one says, so a single
+// changed balance shows up on that entry and the next.
+func verifyRunningBalance(entries []Entry) []AuditViolation {
+	entries = slices.Clone(entries)
+	slices.SortFunc(entries, func(a, b Entry) int { return cmp.Compare(a.ID, b.ID) })
+
+	var (
+		prev       EntrySum
+		violations []AuditViolation
+	)
+	for _, e := range entries {
+		want := prev.add(e.Change())
+		if !e.BalanceAfter.Equal(want.Amount) || !e.BonusAfter.Equal(want.Bonus) {
+			violations = append(violations, AuditViolation{
+				EntryID: e.ID,
+				Problem: fmt.Sprintf("balance after is %s real and %s bonus, want %s and %s",
+					e.BalanceAfter, e.BonusAfter, want.Amount, want.Bonus),
+			})
+		}
+		prev = EntrySum{Amount: e.BalanceAfter, Bonus: e.BonusAfter}
+	}
+	return violations
+}
+
+// matches compares the payload with another one field by field, metadata as
+// JSON values and times as instants.
+func (p auditPayload) matches(other auditPayload) bool {
+	return p.ID == other.ID &&
+		p.WalletID == other.WalletID &&
+		equalInts(p.Debit, other.Debit) &&
+		equalInts(p.Credit, other.Credit) &&
+		p.Bonus == other.Bonus &&
+		p.Operation == other.Operation &&
+		p.Reference == other.Reference &&
+		p.Initiator == other.Initiator &&
+		equalJSON(p.Metadata, other.Metadata) &&
+		equalInts(p.ReversalOf, other.ReversalOf) &&
+		p.CreatedAt.Equal(other.CreatedAt)
+}
+
+func equalInts(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// equalJSON treats missing and null values as equal.
+func equalJSON(a, b json.RawMessage) bool {
+	var va, vb any
+	if len(a) > 0 && json.Unmarshal(a, &va) != nil {
+		return false
+	}
+	if len(b) > 0 && json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// AuditReport is the outcome of verifying the audit log of a wallet.
+type AuditReport struct {
+	WalletID int
+	Records  int
+	// Head is the hash of the last record. Keeping it outside of the database
+	// makes it possible to notice records cut off the end of the chain.
+	Head       []byte
+	Violations []AuditViolation
+}
+
+// AuditViolation is a record or an entry that does not check out. Seq is zero
+// for problems with the entry rather than its record.
+type AuditViolation struct {
+	Seq     int
+	EntryID int
+	Problem string
+}
+
+// VerifyAudit walks the audit log of the wallet and checks that the chain has
+// no gaps, that every record hashes to what it says, and that the ledger
+// holds exactly the entries the records describe. It also checks that the
+// balance after each entry follows from the one before.
+func (c WalletUseCases) VerifyAudit(ctx context.Context, walletID int) (*AuditReport, error) {
+	if _, err := c.storage.GetBalance(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+
+	entries, err := c.storage.ListEntries(ctx, EntryFilter{WalletID: walletID})
+	if err != nil {
+		return nil, fmt.Errorf("list entries: %w", err)
+	}
+
+	return c.verifyAudit(ctx, walletID, entries)
+}
+
+// verifyAudit verifies the audit log of the wallet against its entries.
+func (c WalletUseCases) verifyAudit(ctx context.Context, walletID int, entries []Entry) (*AuditReport, error) {
+	records, err := c.storage.ListAuditRecords(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("list audit records: %w", err)
+	}
+
+	ledger := make(map[int]Entry, len(entries))
+	for _, e := range entries {
+		ledger[e.ID] = e
+	}
+
+	report := AuditReport{WalletID: walletID, Records: len(records)}
+	violate := func(record AuditRecord, problem string) {
+		report.Violations = append(report.Violations, AuditViolation{Seq: record.Seq, EntryID: record.EntryID, Problem: problem})
+	}
+
+	var prev *AuditRecord
+	for i, record := range records {
+		wantSeq, wantPrev := 1, []byte(nil)
+		if prev != nil {
+			wantSeq, wantPrev = prev.Seq+1, prev.Hash
+		}
+		if record.Seq != wantSeq {
+			violate(record, fmt.Sprintf("records %d to %d are missing", wantSeq, record.Seq-1))
+		}
+		if !bytes.Equal(record.PrevHash, wantPrev) {
+			violate(record, "previous hash does not match the previous record")
+		}
+		if !bytes.Equal(record.Hash, AuditHash(record.PrevHash, record.Payload)) {
+			violate(record, "hash does not match the record")
+		}
+		prev = &records[i]
+
+		var payload auditPayload
+		if err := json.Unmarshal(record.Payload, &payload); err != nil || payload.ID != record.EntryID {
+			violate(record, "payload does not describe the entry")
+			continue
+		}
+		entry, ok := ledger[record.EntryID]
+		if !ok {
+			violate(record, "entry is missing from the ledger")
+			continue
+		}
+		delete(ledger, record.EntryID)
+		if !payload.matches(newAuditPayload(entry)) {
+			violate(record, "entry differs from the record")
+		}
+	}
+	if prev != nil {
+		report.Head = prev.Hash
+	}
+
+	// Whatever is left was added to the ledger without a record.
+	for _, e := range entries {
+		if _, ok := ledger[e.ID]; ok {
+			report.Violations = append(report.Violations, AuditViolation{EntryID: e.ID, Problem: "entry has no record"})
+		}
+	}
+	report.Violations = append(report.Violations, verifyRunningBalance(entries)...)
+
+	return &report, nil
+}
@@ -0,0 +1,141 @@
+package domain_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCases_VerifyAudit(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, store *fakeWalletStore)
+		want   []domain.AuditViolation
+	}{
+		{
+			name:   "intact",
+			tamper: func(*testing.T, *fakeWalletStore) {},
+		},
+		{
+			name: "entry changed",
+			tamper: func(t *testing.T, store *fakeWalletStore) {
+				store.debits[0].Amount = mustParse(t, "200")
+			},
+			want: []domain.AuditViolation{
+				{Seq: 1, EntryID: 1, Problem: "entry differs from the record"},
+				{EntryID: 1, Problem: "balance after is 20.000 real and 0.000 bonus, want 200.000 and 0.000"},
+			},
+		},
+		{
+			name: "metadata changed",
+			tamper: func(_ *testing.T, store *fakeWalletStore) {
+				store.credits[0].Metadata = json.RawMessage(`{"round": "r-2"}`)
+			},
+			want: []domain.AuditViolation{{Seq: 2, EntryID: 2, Problem: "entry differs from the record"}},
+		},
+		{
+			name: "record changed along with the entry",
+			tamper: func(t *testing.T, store *fakeWalletStore) {
+				store.debits[0].Amount = mustParse(t, "200")
+				store.audit[0].Payload = json.RawMessage(`{"id": 1, "walletId": 25, "debit": 200000}`)
+			},
+			want: []domain.AuditViolation{
+				{Seq: 1, EntryID: 1, Problem: "hash does not match the record"},
+				{Seq: 1, EntryID: 1, Problem: "entry differs from the record"},
+				{EntryID: 1, Problem: "balance after is 20.000 real and 0.000 bonus, want 200.000 and 0.000"},
+			},
+		},
+		{
+			name: "record and entry removed",
+			tamper: func(_ *testing.T, store *fakeWalletStore) {
+				store.credits = nil
+				store.audit = append(store.audit[:1], store.audit[2:]...)
+			},
+			want: []domain.AuditViolation{
+				{Seq: 3, EntryID: 3, Problem: "records 2 to 2 are missing"},
+				{Seq: 3, EntryID: 3, Problem: "previous hash does not match the previous record"},
+				{EntryID: 3, Problem: "balance after is 15.000 real and 0.000 bonus, want 25.000 and 0.000"},
+			},
+		},
+		{
+			name: "entry removed",
+			tamper: func(_ *testing.T, store *fakeWalletStore) {
+				store.credits = nil
+			},
+			want: []domain.AuditViolation{
+				{Seq: 2, EntryID: 2, Problem: "entry is missing from the ledger"},
+				{EntryID: 3, Problem: "balance after is 15.000 real and 0.000 bonus, want 25.000 and 0.000"},
+			},
+		},
+		{
+			name: "entry without record",
+			tamper: func(_ *testing.T, store *fakeWalletStore) {
+				store.audit = store.audit[:2]
+			},
+			want: []domain.AuditViolation{{EntryID: 3, Problem: "entry has no record"}},
+		},
+		{
+			name: "balance after changed",
+			tamper: func(t *testing.T, store *fakeWalletStore) {
+				store.credits[0].BalanceAfter = mustParse(t, "12")
+			},
+			want: []domain.AuditViolation{
+				{EntryID: 2, Problem: "balance after is 12.000 real and 0.000 bonus, want 10.000 and 0.000"},
+				{EntryID: 3, Problem: "balance after is 15.000 real and 0.000 bonus, want 17.000 and 0.000"},
+			},
+		},
+		{
+			name: "bonus balance after changed",
+			tamper: func(t *testing.T, store *fakeWalletStore) {
+				store.debits[1].BonusAfter = mustParse(t, "1")
+			},
+			want: []domain.AuditViolation{
+				{EntryID: 3, Problem: "balance after is 15.000 real and 1.000 bonus, want 15.000 and 0.000"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newFakeWalletStore(25, mustParse(t, "100"))
+			uc := domain.NewWalletUseCases(store)
+
+			require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, "20")}))
+			require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "10"), EntryDetails: domain.EntryDetails{
+				Reference: "round-1",
+				Metadata:  json.RawMessage(`{"round": "r-1"}`),
+			}}))
+			require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, "5")}))
+			require.Len(t, store.audit, 3)
+
+			tt.tamper(t, store)
+
+			report, err := uc.VerifyAudit(ctx, 25)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, report.Violations)
+			assert.Equal(t, store.audit[len(store.audit)-1].Hash, report.Head)
+		})
+	}
+}
+
+func TestNewAuditRecord(t *testing.T) {
+	amount := mustParse(t, "1.5")
+	first, err := domain.NewAuditRecord(nil, domain.Entry{ID: 7, WalletID: 25, Debit: &amount})
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.Seq)
+	assert.Nil(t, first.PrevHash)
+	assert.JSONEq(t, `{"id": 7, "walletId": 25, "debit": 1500, "credit": null, "bonus": 0, "operation": "",
+		"reference": "", "initiator": "", "metadata": null, "reversalOf": null, "createdAt": "0001-01-01T00:00:00Z"}`,
+		string(first.Payload))
+
+	second, err := domain.NewAuditRecord(&first, domain.Entry{ID: 8, WalletID: 25, Credit: &amount})
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.Seq)
+	assert.Equal(t, first.Hash, second.PrevHash)
+	assert.Equal(t, domain.AuditHash(first.Hash, second.Payload), second.Hash)
+	assert.NotEqual(t, first.Hash, second.Hash)
+}
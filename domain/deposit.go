@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+var (
+	ErrDepositNotFound = errors.New("deposit not found")
+	// ErrDepositMismatch is returned when a provider reports a deposit with
+	// a wallet or an amount that differs from the one it reported before.
+	ErrDepositMismatch = errors.New("deposit does not match the provider reference")
+	ErrInvalidDeposit  = errors.New("invalid deposit")
+	ErrWalletFlagged   = errors.New("wallet is flagged for a negative balance")
+)
+
+type DepositStatus string
+
+const (
+	DepositPending   DepositStatus = "pending"
+	DepositConfirmed DepositStatus = "confirmed"
+	DepositFailed    DepositStatus = "failed"
+	// DepositChargedBack is a deposit the player disputed with their bank. If
+	// it had been confirmed, the amount is taken back from the wallet.
+	DepositChargedBack DepositStatus = "charged_back"
+)
+
+// depositTransitions lists the statuses each status may change to. Payment
+// providers retry and reorder callbacks, so anything else, e.g. pending after
+// confirmed, is a duplicate or a stale event and is ignored.
+var depositTransitions = map[DepositStatus][]DepositStatus{
+	DepositPending:   {DepositConfirmed, DepositFailed, DepositChargedBack},
+	DepositConfirmed: {DepositChargedBack},
+}
+
+func (s DepositStatus) Valid() bool {
+	switch s {
+	case DepositPending, DepositConfirmed, DepositFailed, DepositChargedBack:
+		return true
+	}
+	return false
+}
+
+// CanChangeTo tells whether a deposit in status s may change to status to.
+func (s DepositStatus) CanChangeTo(to DepositStatus) bool {
+	for _, next := range depositTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Deposit is money paid in through a payment provider. It is identified by
+// the provider and the reference the provider gave it. ID, CreatedAt and
+// UpdatedAt are set by the store.
+type Deposit struct {
+	ID        int
+	WalletID  int
+	Provider  string
+	Reference string
+	Amount    money.Money
+	Status    DepositStatus
+	// EntryID is the debit entry that added the amount on confirmation.
+	EntryID int
+	// ReversalEntryID is the credit entry that took the amount back on a
+	// chargeback.
+	ReversalEntryID int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// entryDetails links an entry to the deposit by the provider reference.
+func (d Deposit) entryDetails(op OperationType) EntryDetails {
+	metadata, _ := json.Marshal(map[string]string{"provider": d.Provider})
+	return EntryDetails{Operation: op, Reference: d.Reference, Initiator: InitiatorService, Metadata: metadata}
+}
+
+// DepositEvent is a provider callback about a deposit.
+type DepositEvent struct {
+	Provider  string
+	Reference string
+	WalletID  int
+	Amount    money.Money
+	Status    DepositStatus
+}
+
+// ApplyDepositEvent moves the deposit the event refers to along, adding the
+// amount to the wallet on confirmation and taking it back on a chargeback.
+// Duplicate and stale events leave the deposit as it is, in which case
+// changed is false.
+//
+// Deposit limits are enforced when a deposit starts, i.e. on the pending
+// event that first reports it, which fails with *LimitExceededError so that
+// the provider declines the payment. Once the money is taken, the
+// confirmation is always credited and only counts towards the limits.
+func (c WalletUseCases) ApplyDepositEvent(ctx context.Context, event DepositEvent) (deposit *Deposit, changed bool, err error) {
+	if event.Provider == "" || event.Reference == "" || !event.Status.Valid() {
+		return nil, false, ErrInvalidDeposit
+	}
+	if !event.Amount.IsPositive() {
+		return nil, false, ErrInvalidAmount
+	}
+
+	deposit, err = c.storage.GetDeposit(ctx, event.Provider, event.Reference)
+	switch {
+	case errors.Is(err, ErrDepositNotFound):
+		if event.Status == DepositPending {
+			if err := c.checkLimits(ctx, event.WalletID, Activity{Deposits: event.Amount}); err != nil {
+				return nil, false, err
+			}
+		}
+		deposit = &Deposit{
+			WalletID:  event.WalletID,
+			Provider:  event.Provider,
+			Reference: event.Reference,
+			Amount:    event.Amount,
+			Status:    DepositPending,
+		}
+		if err := c.storage.AddDeposit(ctx, deposit); err != nil {
+			return nil, false, fmt.Errorf("add deposit: %w", err)
+		}
+		changed = true
+	case err != nil:
+		return nil, false, fmt.Errorf("get deposit: %w", err)
+	case deposit.WalletID != event.WalletID || !deposit.Amount.Equal(event.Amount):
+		return nil, false, ErrDepositMismatch
+	}
+
+	if !deposit.Status.CanChangeTo(event.Status) {
+		return deposit, changed, nil
+	}
+
+	switch {
+	case event.Status == DepositConfirmed:
+		entry := DebitEntry{WalletID: deposit.WalletID, Amount: deposit.Amount, EntryDetails: deposit.entryDetails(OperationDeposit)}
+		if err := c.recordActivity(ctx, deposit.WalletID, Activity{Deposits: deposit.Amount}); err != nil {
+			return nil, false, err
+		}
+		if err := c.addMoney(ctx, &entry); err != nil {
+			return nil, false, fmt.Errorf("add deposit: %w", err)
+		}
+		deposit.EntryID = entry.ID
+	case event.Status == DepositChargedBack && deposit.Status == DepositConfirmed:
+		entry := CreditEntry{WalletID: deposit.WalletID, Amount: deposit.Amount, EntryDetails: deposit.entryDetails(OperationChargeback)}
+		if err := c.reverseMoney(ctx, &entry); err != nil {
+			return nil, false, fmt.Errorf("reverse deposit: %w", err)
+		}
+		deposit.ReversalEntryID = entry.ID
+	}
+
+	deposit.Status = event.Status
+	if err := c.storage.SaveDeposit(ctx, deposit); err != nil {
+		return nil, false, fmt.Errorf("save deposit: %w", err)
+	}
+
+	return deposit, true, nil
+}
+
+// reverseMoney takes real money back from the wallet without the checks
+// CreditMoney makes. If there is not enough money, the balance goes negative
+// and the wallet is flagged.
+func (c WalletUseCases) reverseMoney(ctx context.Context, entry *CreditEntry) error {
+	balance, err := c.storage.GetBalance(ctx, entry.WalletID)
+	if err != nil {
+		return fmt.Errorf("get balance: %w", err)
+	}
+
+	balance.Amount = balance.Amount.Sub(entry.Amount)
+	if balance.Amount.IsNegative() {
+		balance.Flagged = true
+	}
+
+	if err := c.storage.SaveBalance(ctx, balance); err != nil {
+		return fmt.Errorf("save balance: %w", err)
+	}
+
+	if err := c.storage.AddCreditEntry(ctx, entry); err != nil {
+		return fmt.Errorf("add credit entry: %w", err)
+	}
+
+	return nil
+}
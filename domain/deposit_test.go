@@ -0,0 +1,202 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCases_ApplyDepositEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		events      []domain.DepositStatus
+		wantChanged []bool
+		wantStatus  domain.DepositStatus
+		wantBalance string
+		wantFlagged bool
+	}{
+		{
+			name:        "confirmed",
+			events:      []domain.DepositStatus{domain.DepositPending, domain.DepositConfirmed},
+			wantChanged: []bool{true, true},
+			wantStatus:  domain.DepositConfirmed,
+			wantBalance: "130.000",
+		},
+		{
+			name:        "confirmed without pending",
+			events:      []domain.DepositStatus{domain.DepositConfirmed},
+			wantChanged: []bool{true},
+			wantStatus:  domain.DepositConfirmed,
+			wantBalance: "130.000",
+		},
+		{
+			name:        "duplicate confirmation",
+			events:      []domain.DepositStatus{domain.DepositConfirmed, domain.DepositConfirmed},
+			wantChanged: []bool{true, false},
+			wantStatus:  domain.DepositConfirmed,
+			wantBalance: "130.000",
+		},
+		{
+			name:        "pending after confirmation",
+			events:      []domain.DepositStatus{domain.DepositConfirmed, domain.DepositPending},
+			wantChanged: []bool{true, false},
+			wantStatus:  domain.DepositConfirmed,
+			wantBalance: "130.000",
+		},
+		{
+			name:        "failed",
+			events:      []domain.DepositStatus{domain.DepositPending, domain.DepositFailed, domain.DepositConfirmed},
+			wantChanged: []bool{true, true, false},
+			wantStatus:  domain.DepositFailed,
+			wantBalance: "100.000",
+		},
+		{
+			name:        "charged back",
+			events:      []domain.DepositStatus{domain.DepositConfirmed, domain.DepositChargedBack},
+			wantChanged: []bool{true, true},
+			wantStatus:  domain.DepositChargedBack,
+			wantBalance: "100.000",
+		},
+		{
+			name:        "chargeback before confirmation",
+			events:      []domain.DepositStatus{domain.DepositChargedBack, domain.DepositConfirmed},
+			wantChanged: []bool{true, false},
+			wantStatus:  domain.DepositChargedBack,
+			wantBalance: "100.000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeWalletStore(25, mustParse(t, "100"))
+			uc := domain.NewWalletUseCases(store)
+
+			var deposit *domain.Deposit
+			for i, status := range tt.events {
+				var changed bool
+				var err error
+				deposit, changed, err = uc.ApplyDepositEvent(context.Background(), domain.DepositEvent{
+					Provider:  "fake",
+					Reference: "dep-1",
+					WalletID:  25,
+					Amount:    mustParse(t, "30"),
+					Status:    status,
+				})
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantChanged[i], changed, "event %d", i)
+			}
+
+			assert.Equal(t, tt.wantStatus, deposit.Status)
+			assert.Equal(t, tt.wantBalance, store.amount.String())
+			assert.Equal(t, tt.wantFlagged, store.flagged)
+			assert.Len(t, store.deposits, 1)
+		})
+	}
+}
+
+func TestWalletUseCases_ChargebackFlagsWallet(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeWalletStore(25, mustParse(t, "0"))
+	uc := domain.NewWalletUseCases(store)
+
+	event := domain.DepositEvent{
+		Provider:  "fake",
+		Reference: "dep-1",
+		WalletID:  25,
+		Amount:    mustParse(t, "30"),
+		Status:    domain.DepositConfirmed,
+	}
+	_, _, err := uc.ApplyDepositEvent(ctx, event)
+	require.NoError(t, err)
+
+	// The player stakes most of the deposit before the chargeback arrives.
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "20")}))
+
+	event.Status = domain.DepositChargedBack
+	deposit, _, err := uc.ApplyDepositEvent(ctx, event)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DepositChargedBack, deposit.Status)
+	assert.Equal(t, "-20.000", store.amount.String())
+	assert.True(t, store.flagged)
+
+	err = uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "1")})
+	require.ErrorIs(t, err, domain.ErrWalletFlagged)
+
+	// A deposit that covers the debt clears the flag.
+	require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, "25"), EntryDetails: domain.EntryDetails{Operation: domain.OperationDeposit}}))
+	assert.Equal(t, "5.000", store.amount.String())
+	assert.False(t, store.flagged)
+}
+
+func TestWalletUseCases_ApplyDepositEventMismatch(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeWalletStore(25, mustParse(t, "100"))
+	uc := domain.NewWalletUseCases(store)
+
+	event := domain.DepositEvent{
+		Provider:  "fake",
+		Reference: "dep-1",
+		WalletID:  25,
+		Amount:    mustParse(t, "30"),
+		Status:    domain.DepositPending,
+	}
+	_, _, err := uc.ApplyDepositEvent(ctx, event)
+	require.NoError(t, err)
+
+	event.Status, event.Amount = domain.DepositConfirmed, mustParse(t, "300")
+	_, _, err = uc.ApplyDepositEvent(ctx, event)
+	require.ErrorIs(t, err, domain.ErrDepositMismatch)
+	assert.Equal(t, "100.000", store.amount.String())
+
+	event.Amount, event.Status = mustParse(t, "30"), "settled"
+	_, _, err = uc.ApplyDepositEvent(ctx, event)
+	require.ErrorIs(t, err, domain.ErrInvalidDeposit)
+}
+
+func TestWalletUseCases_ApplyDepositEventOverLimit(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeWalletStore(25, mustParse(t, "100"))
+	uc := domain.NewWalletUseCases(store)
+
+	limit := mustParse(t, "20")
+	_, err := uc.SetLimit(ctx, 25, domain.LimitDeposit, domain.LimitDay, &limit)
+	require.NoError(t, err)
+
+	event := domain.DepositEvent{
+		Provider:  "fake",
+		Reference: "dep-1",
+		WalletID:  25,
+		Amount:    mustParse(t, "30"),
+		Status:    domain.DepositPending,
+	}
+
+	// A deposit over the limit cannot be started.
+	_, _, err = uc.ApplyDepositEvent(ctx, event)
+	var limitErr *domain.LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, domain.LimitDeposit, limitErr.Kind)
+	assert.Empty(t, store.deposits)
+
+	// The provider took the money anyway, so the confirmation is credited
+	// and counts towards the limit.
+	event.Status = domain.DepositConfirmed
+	deposit, changed, err := uc.ApplyDepositEvent(ctx, event)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, domain.DepositConfirmed, deposit.Status)
+	assert.Equal(t, "130.000", store.amount.String())
+
+	limits, err := uc.ListLimits(ctx, 25)
+	require.NoError(t, err)
+	require.Len(t, limits, 1)
+	assert.Equal(t, "30.000", limits[0].Used.String())
+
+	// So is a deposit the provider confirms without reporting it pending
+	// first, even though the limit is already used up.
+	event.Reference, event.Status = "dep-2", domain.DepositConfirmed
+	_, _, err = uc.ApplyDepositEvent(ctx, event)
+	require.NoError(t, err)
+	assert.Equal(t, "160.000", store.amount.String())
+}
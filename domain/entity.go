@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/pprishchepa/go-casino-example/domain/money"
 )
 
@@ -8,17 +10,64 @@ type Wallet struct {
 	ID int
 }
 
+// WalletBalance keeps real money and bonus money in separate buckets.
 type WalletBalance struct {
 	WalletID int
-	Amount   money.Money
+	// Amount is real money.
+	Amount money.Money
+	// Bonus is money granted as a bonus.
+	Bonus money.Money
+	// Version increases every time the balance is saved.
+	Version int
+	// Flagged marks real money pushed below zero by a chargeback. A flagged
+	// wallet cannot bet or withdraw until deposits bring it back to zero.
+	Flagged bool
+	// Frozen is set by an admin. A frozen wallet cannot bet or withdraw until
+	// it is unfrozen.
+	Frozen bool
+}
+
+// Total is what the player can spend: real and bonus money together.
+func (b WalletBalance) Total() money.Money {
+	return b.Amount.Add(b.Bonus)
 }
 
+// DebitEntry adds money to the wallet. ID, the balance after and CreatedAt
+// are set by the store when the entry is added.
 type DebitEntry struct {
+	ID       int
 	WalletID int
 	Amount   money.Money
+	// Bonus is the part of Amount that goes to the bonus bucket, e.g. a win
+	// from a bonus stake. The rest is real money.
+	Bonus money.Money
+	// Operation is OperationWin unless set.
+	EntryDetails
+	// BalanceAfter and BonusAfter are real and bonus money of the wallet as
+	// the ledger has it after the entry.
+	BalanceAfter money.Money
+	BonusAfter   money.Money
+	CreatedAt    time.Time
 }
 
+// CreditEntry withdraws money from the wallet. ID, the balance after and
+// CreatedAt are set by the store when the entry is added.
 type CreditEntry struct {
+	ID       int
 	WalletID int
 	Amount   money.Money
+	// Bonus is the part of Amount taken from the bonus bucket. It is set by
+	// WalletUseCases according to the spend order.
+	Bonus money.Money
+	// GameType is the game a stake is placed on. It decides how much of the
+	// stake counts towards wagering requirements.
+	GameType GameType
+	// Operation is OperationBet unless set. A withdrawal is paid from real
+	// money only and forfeits active bonuses.
+	EntryDetails
+	// BalanceAfter and BonusAfter are real and bonus money of the wallet as
+	// the ledger has it after the entry.
+	BalanceAfter money.Money
+	BonusAfter   money.Money
+	CreatedAt    time.Time
 }
@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+var ErrInvalidEntry = errors.New("invalid entry")
+
+// OperationType says what an entry was made for.
+type OperationType string
+
+const (
+	OperationBet OperationType = "bet"
+	OperationWin OperationType = "win"
+	// OperationRefund returns a stake, e.g. of a cancelled game round.
+	OperationRefund     OperationType = "refund"
+	OperationDeposit    OperationType = "deposit"
+	OperationWithdrawal OperationType = "withdrawal"
+	// OperationWithdrawalRelease returns the funds held for a withdrawal that
+	// failed or was cancelled.
+	OperationWithdrawalRelease OperationType = "withdrawal_release"
+	// OperationChargeback takes back a deposit disputed with the provider.
+	OperationChargeback OperationType = "chargeback"
+	OperationBonusGrant OperationType = "bonus_grant"
+	// OperationBonusRelease takes money out of the bonus bucket when a bonus
+	// is completed, expired or forfeited.
+	OperationBonusRelease OperationType = "bonus_release"
+	// OperationBonusConversion adds a completed bonus back as real money.
+	OperationBonusConversion OperationType = "bonus_conversion"
+	// OperationReversal undoes the entry it references.
+	OperationReversal OperationType = "reversal"
+	// OperationAdjustment is a manual correction of real money by an admin.
+	OperationAdjustment OperationType = "adjustment"
+	// OperationLegacy is an entry made before operations were recorded that
+	// could not be told apart from the others of its transaction.
+	OperationLegacy OperationType = "legacy"
+)
+
+// debitOperations and creditOperations are the operations callers may pass to
+// DebitMoney and CreditMoney. The rest are only made by the wallet itself.
+var (
+	debitOperations  = []OperationType{OperationWin, OperationRefund, OperationDeposit}
+	creditOperations = []OperationType{OperationBet, OperationWithdrawal}
+)
+
+func (t OperationType) Valid() bool {
+	switch t {
+	case OperationBet, OperationWin, OperationRefund, OperationDeposit, OperationWithdrawal,
+		OperationWithdrawalRelease, OperationChargeback,
+		OperationBonusGrant, OperationBonusRelease, OperationBonusConversion, OperationReversal, OperationAdjustment,
+		OperationLegacy:
+		return true
+	}
+	return false
+}
+
+// Initiator is who an entry was made on behalf of.
+type Initiator string
+
+const (
+	// InitiatorUser is the player.
+	InitiatorUser Initiator = "user"
+	// InitiatorService is a game, a payment provider or the wallet itself.
+	InitiatorService Initiator = "service"
+	InitiatorAdmin   Initiator = "admin"
+)
+
+func (i Initiator) Valid() bool {
+	return i == InitiatorUser || i == InitiatorService || i == InitiatorAdmin
+}
+
+// EntryDetails describes what an entry was made for. It is common to debit and
+// credit entries.
+type EntryDetails struct {
+	Operation OperationType
+	// Reference links the entry to an external object, such as a game round
+	// or a payment. It is not unique.
+	Reference string
+	// Initiator is InitiatorService unless set.
+	Initiator Initiator
+	// Metadata is a JSON object with anything else the caller wants to keep.
+	Metadata json.RawMessage
+	// ReversalOf is the ID of the entry a reversal undoes, zero otherwise.
+	ReversalOf int
+}
+
+// normalize fills in the defaults and checks the details. Operation must be
+// one of allowed, or it defaults to the first of them.
+func (d *EntryDetails) normalize(allowed []OperationType) error {
+	if d.Operation == "" {
+		d.Operation = allowed[0]
+	}
+	if !containsOperation(allowed, d.Operation) {
+		return fmt.Errorf("%w: operation %q is not allowed", ErrInvalidEntry, d.Operation)
+	}
+
+	if d.Initiator == "" {
+		d.Initiator = InitiatorService
+	}
+	if !d.Initiator.Valid() {
+		return fmt.Errorf("%w: unknown initiator %q", ErrInvalidEntry, d.Initiator)
+	}
+
+	if len(d.Metadata) > 0 {
+		var obj map[string]any
+		if err := json.Unmarshal(d.Metadata, &obj); err != nil || obj == nil {
+			return fmt.Errorf("%w: metadata must be a JSON object", ErrInvalidEntry)
+		}
+	}
+
+	return nil
+}
+
+func containsOperation(ops []OperationType, op OperationType) bool {
+	for _, v := range ops {
+		if v == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Entry is a committed ledger entry as it is read back. Exactly one of Debit
+// and Credit is set. Bonus is the part of it that belongs to the bonus bucket.
+type Entry struct {
+	ID       int
+	WalletID int
+	Debit    *money.Money
+	Credit   *money.Money
+	Bonus    money.Money
+	EntryDetails
+	// ReversedBy is the ID of the reversal that undid the entry, zero if it
+	// has not been reversed.
+	ReversedBy int
+	// BalanceAfter and BonusAfter are real and bonus money of the wallet as
+	// the ledger has it after the entry.
+	BalanceAfter money.Money
+	BonusAfter   money.Money
+	CreatedAt    time.Time
+}
+
+// EntryFilter selects entries of a wallet. Zero fields match any entry.
+type EntryFilter struct {
+	WalletID   int
+	Operations []OperationType
+	Reference  string
+	Initiator  Initiator
+	// Metadata matches entries whose metadata has all of these top-level keys
+	// set to these string values.
+	Metadata map[string]string
+	// From and To select entries created in [From, To).
+	From time.Time
+	To   time.Time
+	// AfterID skips entries up to and including this ID, so that a listing
+	// can continue where the previous page ended.
+	AfterID int
+	// Limit is the maximum number of entries to return, no limit if zero.
+	Limit int
+}
+
+func (f EntryFilter) validate() error {
+	for _, op := range f.Operations {
+		if !op.Valid() {
+			return fmt.Errorf("%w: unknown operation %q", ErrInvalidEntry, op)
+		}
+	}
+	if f.Initiator != "" && !f.Initiator.Valid() {
+		return fmt.Errorf("%w: unknown initiator %q", ErrInvalidEntry, f.Initiator)
+	}
+	if f.AfterID < 0 || f.Limit < 0 {
+		return fmt.Errorf("%w: negative cursor or limit", ErrInvalidEntry)
+	}
+	return nil
+}
+
+// ListEntries returns entries of the wallet that match the filter, oldest
+// first.
+func (c WalletUseCases) ListEntries(ctx context.Context, filter EntryFilter) ([]Entry, error) {
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := c.storage.GetBalance(ctx, filter.WalletID); err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+	return c.storage.ListEntries(ctx, filter)
+}
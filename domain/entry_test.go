@@ -0,0 +1,115 @@
+package domain_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCases_EntryDetails(t *testing.T) {
+	tests := []struct {
+		name          string
+		debit         bool
+		details       domain.EntryDetails
+		wantErr       error
+		wantOperation domain.OperationType
+	}{
+		{
+			name:          "debit defaults to a win",
+			debit:         true,
+			wantOperation: domain.OperationWin,
+		},
+		{
+			name:          "credit defaults to a bet",
+			wantOperation: domain.OperationBet,
+		},
+		{
+			name:          "refund",
+			debit:         true,
+			details:       domain.EntryDetails{Operation: domain.OperationRefund, Reference: "round-7"},
+			wantOperation: domain.OperationRefund,
+		},
+		{
+			name:    "credit operation on debit",
+			debit:   true,
+			details: domain.EntryDetails{Operation: domain.OperationBet},
+			wantErr: domain.ErrInvalidEntry,
+		},
+		{
+			name:    "internal operation",
+			details: domain.EntryDetails{Operation: domain.OperationChargeback},
+			wantErr: domain.ErrInvalidEntry,
+		},
+		{
+			name:    "unknown initiator",
+			details: domain.EntryDetails{Initiator: "robot"},
+			wantErr: domain.ErrInvalidEntry,
+		},
+		{
+			name:    "metadata is not an object",
+			details: domain.EntryDetails{Metadata: json.RawMessage(`[1, 2]`)},
+			wantErr: domain.ErrInvalidEntry,
+		},
+		{
+			name:          "metadata",
+			details:       domain.EntryDetails{Metadata: json.RawMessage(`{"round": "r-1"}`), Initiator: domain.InitiatorUser},
+			wantOperation: domain.OperationBet,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newFakeWalletStore(25, mustParse(t, "100"))
+			uc := domain.NewWalletUseCases(store)
+
+			var err error
+			if tt.debit {
+				err = uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, "1"), EntryDetails: tt.details})
+			} else {
+				err = uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "1"), EntryDetails: tt.details})
+			}
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, store.debits)
+				assert.Empty(t, store.credits)
+				return
+			}
+			require.NoError(t, err)
+
+			entries, err := uc.ListEntries(ctx, domain.EntryFilter{WalletID: 25})
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.wantOperation, entries[0].Operation)
+			assert.Equal(t, tt.details.Reference, entries[0].Reference)
+			if tt.details.Initiator == "" {
+				assert.Equal(t, domain.InitiatorService, entries[0].Initiator)
+			} else {
+				assert.Equal(t, tt.details.Initiator, entries[0].Initiator)
+			}
+		})
+	}
+}
+
+func TestWalletUseCases_ListEntries(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeWalletStore(25, mustParse(t, "100"))
+	uc := domain.NewWalletUseCases(store)
+
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "10")}))
+	require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, "20")}))
+
+	entries, err := uc.ListEntries(ctx, domain.EntryFilter{WalletID: 25, Operations: []domain.OperationType{domain.OperationWin}})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "20.000", entries[0].Debit.String())
+
+	_, err = uc.ListEntries(ctx, domain.EntryFilter{WalletID: 25, Operations: []domain.OperationType{"jackpot"}})
+	require.ErrorIs(t, err, domain.ErrInvalidEntry)
+
+	_, err = uc.ListEntries(ctx, domain.EntryFilter{WalletID: 26})
+	require.ErrorIs(t, err, domain.ErrWalletNotFound)
+}
@@ -7,3 +7,4 @@ import (
 var ErrWalletNotFound = errors.New("wallet not found")
 var ErrInsufficientFunds = errors.New("insufficient funds")
 var ErrInvalidAmount = errors.New("invalid amount")
+var ErrInvalidExpiry = errors.New("invalid expiry")
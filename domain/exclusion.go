@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrSelfExcluded     = errors.New("wallet is self-excluded")
+	ErrInvalidExclusion = errors.New("invalid exclusion")
+	ErrNotExcluded      = errors.New("wallet is not self-excluded")
+)
+
+type ExclusionPeriod string
+
+const (
+	ExclusionDay       ExclusionPeriod = "24h"
+	ExclusionHalfYear  ExclusionPeriod = "6m"
+	ExclusionPermanent ExclusionPeriod = "permanent"
+)
+
+func (p ExclusionPeriod) Valid() bool {
+	switch p {
+	case ExclusionDay, ExclusionHalfYear, ExclusionPermanent:
+		return true
+	}
+	return false
+}
+
+// Until returns the end of an exclusion that starts at from, or the zero time
+// for a permanent exclusion.
+func (p ExclusionPeriod) Until(from time.Time) time.Time {
+	switch p {
+	case ExclusionDay:
+		return from.Add(24 * time.Hour)
+	case ExclusionHalfYear:
+		return from.AddDate(0, 6, 0)
+	}
+	return time.Time{}
+}
+
+// Exclusion is a period the player has excluded themselves from betting.
+// Withdrawals keep working. Exclusions are never deleted: they expire, or
+// are lifted by an admin, which is recorded in the Lifted fields. ID and
+// CreatedAt are set by the store when the exclusion is added.
+type Exclusion struct {
+	ID       int
+	WalletID int
+	Period   ExclusionPeriod
+	// Until is the zero time for a permanent exclusion.
+	Until      time.Time
+	CreatedAt  time.Time
+	LiftedAt   time.Time
+	LiftedBy   string
+	LiftReason string
+}
+
+// ActiveAt tells whether bets are refused at t.
+func (e Exclusion) ActiveAt(t time.Time) bool {
+	return e.LiftedAt.IsZero() && (e.Until.IsZero() || t.Before(e.Until))
+}
+
+// endsBefore tells whether e ends before other does.
+func (e Exclusion) endsBefore(other Exclusion) bool {
+	return !e.Until.IsZero() && (other.Until.IsZero() || e.Until.Before(other.Until))
+}
+
+// WalletStatus is the balance of the wallet together with the exclusion in
+// effect, if any.
+type WalletStatus struct {
+	Balance   WalletBalance
+	Exclusion *Exclusion
+}
+
+func (c WalletUseCases) RetrieveStatus(ctx context.Context, walletID int) (*WalletStatus, error) {
+	balance, err := c.storage.GetBalance(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+
+	exclusion, err := c.exclusion(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WalletStatus{Balance: *balance, Exclusion: exclusion}, nil
+}
+
+// SelfExclude refuses bets of the wallet for the given period. An exclusion
+// cannot be shortened: if the exclusion in effect lasts at least as long, it
+// is returned unchanged.
+func (c WalletUseCases) SelfExclude(ctx context.Context, walletID int, period ExclusionPeriod) (*Exclusion, error) {
+	if !period.Valid() {
+		return nil, ErrInvalidExclusion
+	}
+
+	if _, err := c.storage.GetBalance(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+
+	current, err := c.exclusion(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	exclusion := Exclusion{WalletID: walletID, Period: period, Until: period.Until(c.now())}
+	if current != nil && !current.endsBefore(exclusion) {
+		return current, nil
+	}
+
+	if err := c.storage.AddExclusion(ctx, &exclusion); err != nil {
+		return nil, fmt.Errorf("add exclusion: %w", err)
+	}
+
+	return &exclusion, nil
+}
+
+// LiftExclusion is an admin override that ends every exclusion of the wallet
+// in effect. The actor and the reason are kept with the exclusions.
+func (c WalletUseCases) LiftExclusion(ctx context.Context, walletID int, actor, reason string) (*Exclusion, error) {
+	if actor == "" || reason == "" {
+		return nil, ErrInvalidExclusion
+	}
+
+	if _, err := c.storage.GetBalance(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+
+	exclusions, err := c.storage.ListExclusions(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("list exclusions: %w", err)
+	}
+
+	now := c.now()
+	var lifted *Exclusion
+	for i := range exclusions {
+		exclusion := &exclusions[i]
+		if !exclusion.ActiveAt(now) {
+			continue
+		}
+		exclusion.LiftedAt, exclusion.LiftedBy, exclusion.LiftReason = now, actor, reason
+		if err := c.storage.SaveExclusion(ctx, exclusion); err != nil {
+			return nil, fmt.Errorf("save exclusion: %w", err)
+		}
+		if lifted == nil || lifted.endsBefore(*exclusion) {
+			lifted = exclusion
+		}
+	}
+	if lifted == nil {
+		return nil, ErrNotExcluded
+	}
+
+	err = c.recordAction(ctx, walletID, AdminLiftExclusion, actor, reason, map[string]any{"exclusionId": lifted.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return lifted, nil
+}
+
+// exclusion returns the exclusion of the wallet in effect that ends last, or
+// nil. Exclusions expire by time, so there is nothing to clean up.
+func (c WalletUseCases) exclusion(ctx context.Context, walletID int) (*Exclusion, error) {
+	exclusions, err := c.storage.ListExclusions(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("list exclusions: %w", err)
+	}
+
+	now := c.now()
+	var res *Exclusion
+	for i := range exclusions {
+		if exclusions[i].ActiveAt(now) && (res == nil || res.endsBefore(exclusions[i])) {
+			res = &exclusions[i]
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,161 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCases_SelfExclude(t *testing.T) {
+	now := time.Date(2024, 4, 27, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		current   *domain.Exclusion
+		period    domain.ExclusionPeriod
+		wantErr   error
+		wantUntil time.Time
+		wantAdded bool
+	}{
+		{
+			name:      "for a day",
+			period:    domain.ExclusionDay,
+			wantUntil: now.Add(24 * time.Hour),
+			wantAdded: true,
+		},
+		{
+			name:      "for six months",
+			period:    domain.ExclusionHalfYear,
+			wantUntil: time.Date(2024, 10, 27, 12, 0, 0, 0, time.UTC),
+			wantAdded: true,
+		},
+		{
+			name:      "permanently",
+			period:    domain.ExclusionPermanent,
+			wantAdded: true,
+		},
+		{
+			name:      "extends the exclusion in effect",
+			current:   &domain.Exclusion{Period: domain.ExclusionDay, Until: now.Add(time.Hour)},
+			period:    domain.ExclusionHalfYear,
+			wantUntil: time.Date(2024, 10, 27, 12, 0, 0, 0, time.UTC),
+			wantAdded: true,
+		},
+		{
+			name:      "does not shorten the exclusion in effect",
+			current:   &domain.Exclusion{Period: domain.ExclusionPermanent},
+			period:    domain.ExclusionDay,
+			wantUntil: time.Time{},
+		},
+		{
+			name:      "ignores a lifted exclusion",
+			current:   &domain.Exclusion{Period: domain.ExclusionPermanent, LiftedAt: now.Add(-time.Hour)},
+			period:    domain.ExclusionDay,
+			wantUntil: now.Add(24 * time.Hour),
+			wantAdded: true,
+		},
+		{
+			name:    "unknown period",
+			period:  "1w",
+			wantErr: domain.ErrInvalidExclusion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeWalletStore(25, mustParse(t, "10"))
+			if tt.current != nil {
+				current := *tt.current
+				current.ID, current.WalletID = 1, 25
+				store.exclusions = append(store.exclusions, current)
+			}
+			before := len(store.exclusions)
+			uc := domain.NewWalletUseCases(store, domain.WithClock(func() time.Time { return now }))
+
+			exclusion, err := uc.SelfExclude(context.Background(), 25, tt.period)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantUntil, exclusion.Until)
+			if tt.wantAdded {
+				assert.Len(t, store.exclusions, before+1)
+			} else {
+				assert.Len(t, store.exclusions, before)
+			}
+		})
+	}
+}
+
+func TestWalletUseCases_SelfExcludedWalletCannotBet(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 27, 12, 0, 0, 0, time.UTC)
+
+	store := newFakeWalletStore(25, mustParse(t, "10"))
+	uc := domain.NewWalletUseCases(store, domain.WithClock(func() time.Time { return now }))
+
+	_, err := uc.SelfExclude(ctx, 25, domain.ExclusionDay)
+	require.NoError(t, err)
+
+	err = uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "1")})
+	require.ErrorIs(t, err, domain.ErrSelfExcluded)
+	assert.Empty(t, store.credits)
+
+	// Withdrawals and wins keep working.
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "1"), EntryDetails: domain.EntryDetails{Operation: domain.OperationWithdrawal}}))
+	require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, "1")}))
+
+	status, err := uc.RetrieveStatus(ctx, 25)
+	require.NoError(t, err)
+	require.NotNil(t, status.Exclusion)
+	assert.Equal(t, now.Add(24*time.Hour), status.Exclusion.Until)
+
+	// The exclusion expires by itself.
+	now = now.Add(24 * time.Hour)
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "1")}))
+
+	status, err = uc.RetrieveStatus(ctx, 25)
+	require.NoError(t, err)
+	assert.Nil(t, status.Exclusion)
+}
+
+func TestWalletUseCases_LiftExclusion(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 27, 12, 0, 0, 0, time.UTC)
+
+	store := newFakeWalletStore(25, mustParse(t, "10"))
+	uc := domain.NewWalletUseCases(store, domain.WithClock(func() time.Time { return now }))
+
+	_, err := uc.LiftExclusion(ctx, 25, "support", "player request")
+	require.ErrorIs(t, err, domain.ErrNotExcluded)
+
+	_, err = uc.SelfExclude(ctx, 25, domain.ExclusionDay)
+	require.NoError(t, err)
+	_, err = uc.SelfExclude(ctx, 25, domain.ExclusionPermanent)
+	require.NoError(t, err)
+
+	_, err = uc.LiftExclusion(ctx, 25, "support", "")
+	require.ErrorIs(t, err, domain.ErrInvalidExclusion)
+
+	lifted, err := uc.LiftExclusion(ctx, 25, "support", "opened by mistake")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ExclusionPermanent, lifted.Period)
+
+	for _, exclusion := range store.exclusions {
+		assert.Equal(t, now, exclusion.LiftedAt)
+		assert.Equal(t, "support", exclusion.LiftedBy)
+		assert.Equal(t, "opened by mistake", exclusion.LiftReason)
+	}
+
+	require.Len(t, store.actions, 1)
+	assert.Equal(t, domain.AdminLiftExclusion, store.actions[0].Kind)
+	assert.Equal(t, "support", store.actions[0].Actor)
+	assert.JSONEq(t, `{"exclusionId": 2}`, string(store.actions[0].Details))
+
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "1")}))
+}
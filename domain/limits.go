@@ -0,0 +1,270 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+// ErrLimitExceeded is matched by every *LimitExceededError.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+var ErrInvalidLimit = errors.New("invalid limit")
+
+// LimitExceededError tells how much the player may still spend under the
+// limit that has been hit.
+type LimitExceededError struct {
+	Kind      LimitKind
+	Period    LimitPeriod
+	Limit     money.Money
+	Remaining money.Money
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s %s limit of %s exceeded, %s remaining", e.Period, e.Kind, e.Limit, e.Remaining)
+}
+
+func (e *LimitExceededError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+type LimitKind string
+
+const (
+	LimitDeposit LimitKind = "deposit"
+	// LimitLoss caps stakes minus wins.
+	LimitLoss  LimitKind = "loss"
+	LimitWager LimitKind = "wager"
+)
+
+func (k LimitKind) Valid() bool {
+	switch k {
+	case LimitDeposit, LimitLoss, LimitWager:
+		return true
+	}
+	return false
+}
+
+type LimitPeriod string
+
+const (
+	LimitDay   LimitPeriod = "day"
+	LimitWeek  LimitPeriod = "week"
+	LimitMonth LimitPeriod = "month"
+)
+
+func (p LimitPeriod) Valid() bool {
+	return p.Window() > 0
+}
+
+// Window is the length of the rolling window the limit applies to.
+func (p LimitPeriod) Window() time.Duration {
+	switch p {
+	case LimitDay:
+		return 24 * time.Hour
+	case LimitWeek:
+		return 7 * 24 * time.Hour
+	case LimitMonth:
+		return 30 * 24 * time.Hour
+	}
+	return 0
+}
+
+// Limit is a player-set cap on deposits, losses or stakes in a rolling
+// window. Lowering a limit applies at once, raising or removing it only
+// after a cooling-off period.
+type Limit struct {
+	WalletID int
+	Kind     LimitKind
+	Period   LimitPeriod
+	// Amount is the limit in effect, nil if there is none.
+	Amount *money.Money
+	// PendingAmount replaces Amount at PendingAt. A nil PendingAmount with
+	// PendingAt set removes the limit.
+	PendingAmount *money.Money
+	PendingAt     time.Time
+}
+
+// settle applies the pending change if its cooling-off period is over.
+func (l *Limit) settle(now time.Time) {
+	if l.PendingAt.IsZero() || now.Before(l.PendingAt) {
+		return
+	}
+	l.Amount, l.PendingAmount, l.PendingAt = l.PendingAmount, nil, time.Time{}
+}
+
+// Activity sums up deposits, stakes and wins in a period.
+type Activity struct {
+	Deposits money.Money
+	Wagers   money.Money
+	Wins     money.Money
+}
+
+func (a Activity) Add(b Activity) Activity {
+	return Activity{
+		Deposits: a.Deposits.Add(b.Deposits),
+		Wagers:   a.Wagers.Add(b.Wagers),
+		Wins:     a.Wins.Add(b.Wins),
+	}
+}
+
+// Of returns the part of the activity the limit kind applies to.
+func (a Activity) Of(kind LimitKind) money.Money {
+	switch kind {
+	case LimitDeposit:
+		return a.Deposits
+	case LimitWager:
+		return a.Wagers
+	case LimitLoss:
+		if loss := a.Wagers.Sub(a.Wins); loss.IsPositive() {
+			return loss
+		}
+	}
+	return money.Money{}
+}
+
+// LimitUsage is a limit together with how much of it has been used in the
+// current window.
+type LimitUsage struct {
+	Limit
+	Used money.Money
+}
+
+// ListLimits returns the limits of the wallet with their usage.
+func (c WalletUseCases) ListLimits(ctx context.Context, walletID int) ([]LimitUsage, error) {
+	if _, err := c.storage.GetBalance(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+
+	limits, err := c.limits(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	res := make([]LimitUsage, 0, len(limits))
+	for _, limit := range limits {
+		activity, err := c.storage.GetActivity(ctx, walletID, now.Add(-limit.Period.Window()))
+		if err != nil {
+			return nil, fmt.Errorf("get activity: %w", err)
+		}
+		res = append(res, LimitUsage{Limit: limit, Used: activity.Of(limit.Kind)})
+	}
+
+	return res, nil
+}
+
+// SetLimit sets the limit of the given kind and period to amount, or removes
+// it if amount is nil. A lower limit applies at once and cancels a pending
+// increase. A higher limit or a removal waits for the cooling-off period.
+func (c WalletUseCases) SetLimit(ctx context.Context, walletID int, kind LimitKind, period LimitPeriod, amount *money.Money) (*Limit, error) {
+	if !kind.Valid() || !period.Valid() {
+		return nil, ErrInvalidLimit
+	}
+	if amount != nil && amount.IsNegative() {
+		return nil, ErrInvalidAmount
+	}
+
+	if _, err := c.storage.GetBalance(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+
+	limits, err := c.limits(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := Limit{WalletID: walletID, Kind: kind, Period: period}
+	for _, l := range limits {
+		if l.Kind == kind && l.Period == period {
+			limit = l
+		}
+	}
+
+	if amount != nil && (limit.Amount == nil || amount.Cmp(*limit.Amount) <= 0) {
+		limit.Amount, limit.PendingAmount, limit.PendingAt = amount, nil, time.Time{}
+	} else if limit.Amount != nil {
+		limit.PendingAmount, limit.PendingAt = amount, c.now().Add(c.coolingOff)
+	}
+
+	if err := c.storage.SaveLimit(ctx, &limit); err != nil {
+		return nil, fmt.Errorf("save limit: %w", err)
+	}
+
+	return &limit, nil
+}
+
+// trackActivity fails with *LimitExceededError if adding delta to the
+// wallet activity breaks a limit, and records delta otherwise.
+func (c WalletUseCases) trackActivity(ctx context.Context, walletID int, delta Activity) error {
+	if err := c.checkLimits(ctx, walletID, delta); err != nil {
+		return err
+	}
+	return c.recordActivity(ctx, walletID, delta)
+}
+
+// checkLimits fails with *LimitExceededError if adding delta to the wallet
+// activity would break a limit.
+func (c WalletUseCases) checkLimits(ctx context.Context, walletID int, delta Activity) error {
+	limits, err := c.limits(ctx, walletID)
+	if err != nil {
+		return err
+	}
+
+	now := c.now()
+	for _, limit := range limits {
+		if limit.Amount == nil || !delta.Of(limit.Kind).IsPositive() {
+			continue
+		}
+
+		activity, err := c.storage.GetActivity(ctx, walletID, now.Add(-limit.Period.Window()))
+		if err != nil {
+			return fmt.Errorf("get activity: %w", err)
+		}
+
+		if activity.Add(delta).Of(limit.Kind).Cmp(*limit.Amount) > 0 {
+			remaining := limit.Amount.Sub(activity.Of(limit.Kind))
+			if remaining.IsNegative() {
+				remaining = money.Money{}
+			}
+			return &LimitExceededError{
+				Kind:      limit.Kind,
+				Period:    limit.Period,
+				Limit:     *limit.Amount,
+				Remaining: remaining,
+			}
+		}
+	}
+
+	return nil
+}
+
+// recordActivity adds delta to the wallet activity whether or not it breaks
+// a limit.
+func (c WalletUseCases) recordActivity(ctx context.Context, walletID int, delta Activity) error {
+	if err := c.storage.AddActivity(ctx, walletID, c.now(), delta); err != nil {
+		return fmt.Errorf("add activity: %w", err)
+	}
+	return nil
+}
+
+// limits returns the limits of the wallet with due changes applied.
+func (c WalletUseCases) limits(ctx context.Context, walletID int) ([]Limit, error) {
+	limits, err := c.storage.GetLimits(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("get limits: %w", err)
+	}
+
+	now := c.now()
+	res := limits[:0]
+	for _, limit := range limits {
+		limit.settle(now)
+		if limit.Amount != nil || !limit.PendingAt.IsZero() {
+			res = append(res, limit)
+		}
+	}
+	return res, nil
+}
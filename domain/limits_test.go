@@ -0,0 +1,276 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCases_SetLimit(t *testing.T) {
+	now := time.Date(2024, 4, 20, 12, 0, 0, 0, time.UTC)
+	amount := func(s string) *money.Money {
+		m := mustParse(t, s)
+		return &m
+	}
+
+	tests := []struct {
+		name        string
+		current     *domain.Limit
+		kind        domain.LimitKind
+		period      domain.LimitPeriod
+		amount      *money.Money
+		wantErr     error
+		wantAmount  string
+		wantPending string
+		wantNone    bool
+	}{
+		{
+			name:   "first limit applies at once",
+			kind:   domain.LimitDeposit,
+			period: domain.LimitDay,
+			amount: amount("100"), wantAmount: "100.000",
+		},
+		{
+			name:    "lower limit applies at once",
+			current: &domain.Limit{Kind: domain.LimitDeposit, Period: domain.LimitDay, Amount: amount("100")},
+			kind:    domain.LimitDeposit,
+			period:  domain.LimitDay,
+			amount:  amount("50"), wantAmount: "50.000",
+		},
+		{
+			name:    "higher limit waits",
+			current: &domain.Limit{Kind: domain.LimitLoss, Period: domain.LimitWeek, Amount: amount("100")},
+			kind:    domain.LimitLoss,
+			period:  domain.LimitWeek,
+			amount:  amount("150"), wantAmount: "100.000", wantPending: "150.000",
+		},
+		{
+			name:    "removal waits",
+			current: &domain.Limit{Kind: domain.LimitWager, Period: domain.LimitMonth, Amount: amount("100")},
+			kind:    domain.LimitWager,
+			period:  domain.LimitMonth,
+			amount:  nil, wantAmount: "100.000", wantPending: "none",
+		},
+		{
+			name: "lower limit cancels pending increase",
+			current: &domain.Limit{
+				Kind:          domain.LimitDeposit,
+				Period:        domain.LimitDay,
+				Amount:        amount("100"),
+				PendingAmount: amount("500"),
+				PendingAt:     now.Add(time.Hour),
+			},
+			kind:   domain.LimitDeposit,
+			period: domain.LimitDay,
+			amount: amount("80"), wantAmount: "80.000",
+		},
+		{
+			name:   "removing missing limit",
+			kind:   domain.LimitDeposit,
+			period: domain.LimitDay,
+			amount: nil, wantNone: true,
+		},
+		{
+			name:    "unknown kind",
+			kind:    "bets",
+			period:  domain.LimitDay,
+			amount:  amount("100"),
+			wantErr: domain.ErrInvalidLimit,
+		},
+		{
+			name:    "unknown period",
+			kind:    domain.LimitDeposit,
+			period:  "year",
+			amount:  amount("100"),
+			wantErr: domain.ErrInvalidLimit,
+		},
+		{
+			name:    "negative amount",
+			kind:    domain.LimitDeposit,
+			period:  domain.LimitDay,
+			amount:  amount("-1"),
+			wantErr: domain.ErrInvalidAmount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeWalletStore(25, money.Money{})
+			if tt.current != nil {
+				current := *tt.current
+				current.WalletID = 25
+				store.limits = append(store.limits, current)
+			}
+			uc := domain.NewWalletUseCases(store, domain.WithClock(func() time.Time { return now }))
+
+			limit, err := uc.SetLimit(context.Background(), 25, tt.kind, tt.period, tt.amount)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.wantNone {
+				assert.Nil(t, limit.Amount)
+				assert.Empty(t, store.limits)
+				return
+			}
+			require.Len(t, store.limits, 1)
+			require.NotNil(t, limit.Amount)
+			assert.Equal(t, tt.wantAmount, limit.Amount.String())
+
+			switch tt.wantPending {
+			case "":
+				assert.True(t, limit.PendingAt.IsZero())
+			case "none":
+				assert.Nil(t, limit.PendingAmount)
+				assert.Equal(t, now.Add(24*time.Hour), limit.PendingAt)
+			default:
+				require.NotNil(t, limit.PendingAmount)
+				assert.Equal(t, tt.wantPending, limit.PendingAmount.String())
+				assert.Equal(t, now.Add(24*time.Hour), limit.PendingAt)
+			}
+		})
+	}
+
+	t.Run("unknown wallet", func(t *testing.T) {
+		uc := domain.NewWalletUseCases(newFakeWalletStore(25, money.Money{}))
+		_, err := uc.SetLimit(context.Background(), 26, domain.LimitDeposit, domain.LimitDay, amount("1"))
+		require.ErrorIs(t, err, domain.ErrWalletNotFound)
+	})
+}
+
+func TestWalletUseCases_LimitIncreaseAppliesAfterCoolingOff(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 20, 12, 0, 0, 0, time.UTC)
+
+	store := newFakeWalletStore(25, money.Money{})
+	uc := domain.NewWalletUseCases(store,
+		domain.WithClock(func() time.Time { return now }),
+		domain.WithLimitCoolingOff(time.Hour),
+	)
+
+	low, high := mustParse(t, "10"), mustParse(t, "20")
+	_, err := uc.SetLimit(ctx, 25, domain.LimitDeposit, domain.LimitDay, &low)
+	require.NoError(t, err)
+	_, err = uc.SetLimit(ctx, 25, domain.LimitDeposit, domain.LimitDay, &high)
+	require.NoError(t, err)
+
+	err = uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, "15"), EntryDetails: domain.EntryDetails{Operation: domain.OperationDeposit}})
+	require.ErrorIs(t, err, domain.ErrLimitExceeded)
+
+	now = now.Add(time.Hour)
+	require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, "15"), EntryDetails: domain.EntryDetails{Operation: domain.OperationDeposit}}))
+
+	limits, err := uc.ListLimits(ctx, 25)
+	require.NoError(t, err)
+	require.Len(t, limits, 1)
+	assert.Equal(t, "20.000", limits[0].Amount.String())
+	assert.True(t, limits[0].PendingAt.IsZero())
+	assert.Equal(t, "15.000", limits[0].Used.String())
+}
+
+func TestWalletUseCases_Limits(t *testing.T) {
+	now := time.Date(2024, 4, 20, 12, 0, 0, 0, time.UTC)
+
+	type op struct {
+		ago     time.Duration
+		deposit string
+		win     string
+		stake   string
+	}
+	tests := []struct {
+		name          string
+		kind          domain.LimitKind
+		period        domain.LimitPeriod
+		limit         string
+		history       []op
+		next          op
+		wantRemaining string
+	}{
+		{
+			name: "deposit within limit", kind: domain.LimitDeposit, period: domain.LimitDay, limit: "100",
+			history: []op{{deposit: "60"}, {win: "500"}},
+			next:    op{deposit: "40"},
+		},
+		{
+			name: "deposit over limit", kind: domain.LimitDeposit, period: domain.LimitDay, limit: "100",
+			history: []op{{deposit: "60"}},
+			next:    op{deposit: "40.001"}, wantRemaining: "40.000",
+		},
+		{
+			name: "deposit out of window", kind: domain.LimitDeposit, period: domain.LimitDay, limit: "100",
+			history: []op{{ago: 25 * time.Hour, deposit: "100"}},
+			next:    op{deposit: "100"},
+		},
+		{
+			name: "wins do not count towards wager limit", kind: domain.LimitWager, period: domain.LimitWeek, limit: "50",
+			history: []op{{ago: 6 * 24 * time.Hour, stake: "30"}, {win: "100"}},
+			next:    op{stake: "30"}, wantRemaining: "20.000",
+		},
+		{
+			name: "wins reduce loss", kind: domain.LimitLoss, period: domain.LimitMonth, limit: "100",
+			history: []op{{deposit: "500"}, {stake: "150"}, {win: "80"}},
+			next:    op{stake: "30"},
+		},
+		{
+			name: "loss over limit", kind: domain.LimitLoss, period: domain.LimitMonth, limit: "100",
+			history: []op{{deposit: "500"}, {stake: "150"}, {win: "80"}},
+			next:    op{stake: "31"}, wantRemaining: "30.000",
+		},
+		{
+			name: "deposits do not count towards loss", kind: domain.LimitLoss, period: domain.LimitDay, limit: "0",
+			history: []op{},
+			next:    op{deposit: "100"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newFakeWalletStore(25, mustParse(t, "1000"))
+			clock := now
+			uc := domain.NewWalletUseCases(store, domain.WithClock(func() time.Time { return clock }))
+
+			apply := func(o op) error {
+				clock = now.Add(-o.ago)
+				switch {
+				case o.deposit != "":
+					return uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, o.deposit), EntryDetails: domain.EntryDetails{Operation: domain.OperationDeposit}})
+				case o.win != "":
+					return uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, o.win)})
+				default:
+					return uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, o.stake)})
+				}
+			}
+			for _, o := range tt.history {
+				require.NoError(t, apply(o))
+			}
+
+			limit := mustParse(t, tt.limit)
+			clock = now
+			_, err := uc.SetLimit(ctx, 25, tt.kind, tt.period, &limit)
+			require.NoError(t, err)
+
+			balance := store.amount
+			err = apply(tt.next)
+			if tt.wantRemaining == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var limitErr *domain.LimitExceededError
+			require.True(t, errors.As(err, &limitErr), "want *LimitExceededError, got %v", err)
+			require.ErrorIs(t, err, domain.ErrLimitExceeded)
+			assert.Equal(t, tt.kind, limitErr.Kind)
+			assert.Equal(t, tt.period, limitErr.Period)
+			assert.Equal(t, mustParse(t, tt.limit), limitErr.Limit)
+			assert.Equal(t, tt.wantRemaining, limitErr.Remaining.String())
+			assert.Equal(t, balance, store.amount, "balance must not change")
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode decides how results are rounded to the minor unit, which is
+// 0.001.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds ties to the even neighbour (banker's rounding).
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds ties away from zero.
+	RoundHalfUp
+	// RoundDown rounds towards zero.
+	RoundDown
+)
+
+var ErrInvalidRatios = errors.New("ratios must not be negative and must not all be zero")
+
+var (
+	bigOne     = big.NewInt(1)
+	minorUnits = big.NewInt(int64(decimalVal.IntPart()))
+)
+
+// Mul multiplies the amount by an exact rational factor, e.g. big.NewRat(3, 4),
+// and rounds the result to the minor unit.
+func (m Money) Mul(factor *big.Rat, mode RoundingMode) Money {
+	v := new(big.Rat).Mul(m.dec.Rat(), factor)
+	v.Mul(v, new(big.Rat).SetInt(minorUnits))
+	return fromMinorUnits(roundQuo(v.Num(), v.Denom(), mode))
+}
+
+// Percent returns pct percent of the amount, e.g. big.NewRat(5, 2) for 2.5%,
+// rounded to the minor unit.
+func (m Money) Percent(pct *big.Rat, mode RoundingMode) Money {
+	return m.Mul(new(big.Rat).Quo(pct, big.NewRat(100, 1)), mode)
+}
+
+// Allocate splits the amount into parts proportional to ratios. The parts
+// always sum to the amount: minor units left after rounding down are given
+// one by one to the parts with the largest remainders, earlier parts first on
+// ties. The amount must not have more than 3 decimal places.
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	var total int64
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, ErrInvalidRatios
+		}
+		total += int64(r)
+	}
+	if total == 0 {
+		return nil, ErrInvalidRatios
+	}
+	if !m.dec.Equal(m.dec.Truncate(decimalLen)) {
+		return nil, ErrUnrepresentable
+	}
+
+	// Allocate the absolute value so that remainders are never negative.
+	amount := m.dec.Shift(decimalLen).BigInt()
+	negative := amount.Sign() < 0
+	amount.Abs(amount)
+
+	denom := big.NewInt(total)
+	shares := make([]*big.Int, len(ratios))
+	rems := make([]*big.Int, len(ratios))
+	left := new(big.Int).Set(amount)
+
+	for i, r := range ratios {
+		shares[i], rems[i] = new(big.Int).QuoRem(new(big.Int).Mul(amount, big.NewInt(int64(r))), denom, new(big.Int))
+		left.Sub(left, shares[i])
+	}
+
+	// left is smaller than len(ratios) because every share lost less than one
+	// minor unit.
+	order := make([]int, len(ratios))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return rems[order[a]].Cmp(rems[order[b]]) > 0
+	})
+	for _, i := range order[:left.Int64()] {
+		shares[i].Add(shares[i], bigOne)
+	}
+
+	res := make([]Money, len(shares))
+	for i, share := range shares {
+		if negative {
+			share.Neg(share)
+		}
+		res[i] = fromMinorUnits(share)
+	}
+	return res, nil
+}
+
+// roundQuo divides n by d and rounds the quotient to an integer.
+func roundQuo(n, d *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() == 0 || mode == RoundDown {
+		return q
+	}
+
+	// Compare 2|r| with |d| to find out which side of the half we are on.
+	twiceRem := new(big.Int).Abs(r)
+	twiceRem.Lsh(twiceRem, 1)
+	half := twiceRem.Cmp(new(big.Int).Abs(d))
+
+	if half > 0 || half == 0 && (mode == RoundHalfUp || q.Bit(0) == 1) {
+		if n.Sign()*d.Sign() < 0 {
+			return q.Sub(q, bigOne)
+		}
+		return q.Add(q, bigOne)
+	}
+	return q
+}
+
+func fromMinorUnits(v *big.Int) Money {
+	return Money{decimal.NewFromBigInt(v, -decimalLen)}
+}
@@ -0,0 +1,150 @@
+package money_test
+
+import (
+	"math/big"
+	"testing"
+	"testing/quick"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_Mul(t *testing.T) {
+	tests := []struct {
+		amount string
+		factor *big.Rat
+		mode   money.RoundingMode
+		want   string
+	}{
+		{amount: "10", factor: big.NewRat(1, 3), mode: money.RoundHalfEven, want: "3.333"},
+		{amount: "20", factor: big.NewRat(1, 3), mode: money.RoundHalfEven, want: "6.667"},
+		{amount: "20", factor: big.NewRat(1, 3), mode: money.RoundDown, want: "6.666"},
+		{amount: "0.005", factor: big.NewRat(1, 2), mode: money.RoundHalfEven, want: "0.002"},
+		{amount: "0.007", factor: big.NewRat(1, 2), mode: money.RoundHalfEven, want: "0.004"},
+		{amount: "0.005", factor: big.NewRat(1, 2), mode: money.RoundHalfUp, want: "0.003"},
+		{amount: "-0.005", factor: big.NewRat(1, 2), mode: money.RoundHalfUp, want: "-0.003"},
+		{amount: "-0.005", factor: big.NewRat(1, 2), mode: money.RoundHalfEven, want: "-0.002"},
+		{amount: "-20", factor: big.NewRat(1, 3), mode: money.RoundDown, want: "-6.666"},
+		{amount: "12.345", factor: big.NewRat(3, 1), mode: money.RoundDown, want: "37.035"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.amount+"*"+tt.factor.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, mustParse(t, tt.amount).Mul(tt.factor, tt.mode).String())
+		})
+	}
+}
+
+func TestMoney_Percent(t *testing.T) {
+	bet := mustParse(t, "12.5")
+
+	assert.Equal(t, "0.313", bet.Percent(big.NewRat(5, 2), money.RoundHalfUp).String())
+	assert.Equal(t, "0.312", bet.Percent(big.NewRat(5, 2), money.RoundHalfEven).String())
+	assert.Equal(t, "0.312", bet.Percent(big.NewRat(5, 2), money.RoundDown).String())
+	assert.Equal(t, "12.500", bet.Percent(big.NewRat(100, 1), money.RoundDown).String())
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	tests := []struct {
+		amount  string
+		ratios  []int
+		want    []string
+		wantErr error
+	}{
+		{amount: "0.010", ratios: []int{1, 1, 1}, want: []string{"0.004", "0.003", "0.003"}},
+		{amount: "100", ratios: []int{70, 20, 10}, want: []string{"70.000", "20.000", "10.000"}},
+		{amount: "0.005", ratios: []int{3, 7}, want: []string{"0.002", "0.003"}},
+		{amount: "0.004", ratios: []int{3, 7}, want: []string{"0.001", "0.003"}},
+		{amount: "-0.010", ratios: []int{1, 1, 1}, want: []string{"-0.004", "-0.003", "-0.003"}},
+		{amount: "5", ratios: []int{0, 1}, want: []string{"0.000", "5.000"}},
+		{amount: "5", ratios: []int{0, 0}, wantErr: money.ErrInvalidRatios},
+		{amount: "5", ratios: []int{1, -1}, wantErr: money.ErrInvalidRatios},
+		{amount: "5", wantErr: money.ErrInvalidRatios},
+	}
+	for _, tt := range tests {
+		t.Run(tt.amount, func(t *testing.T) {
+			parts, err := mustParse(t, tt.amount).Allocate(tt.ratios...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []string
+			for _, p := range parts {
+				got = append(got, p.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMoney_AllocateProperties(t *testing.T) {
+	property := func(minor int64, ratios []uint16) bool {
+		amount := money.NewFromInt(int(minor))
+
+		rs := make([]int, len(ratios))
+		var total int64
+		for i, r := range ratios {
+			rs[i] = int(r)
+			total += int64(r)
+		}
+
+		parts, err := amount.Allocate(rs...)
+		if total == 0 {
+			return err != nil
+		}
+		if err != nil || len(parts) != len(rs) {
+			return false
+		}
+
+		sum := money.Money{}
+		for i, p := range parts {
+			sum = sum.Add(p)
+
+			// Every part is within one minor unit of its exact share.
+			exact := new(big.Rat).SetFrac64(minor*int64(rs[i]), total)
+			diff := new(big.Rat).Sub(new(big.Rat).SetInt64(int64(p.AsInt())), exact)
+			if diff.Abs(diff).Cmp(big.NewRat(1, 1)) >= 0 {
+				return false
+			}
+		}
+		return sum.Equal(amount)
+	}
+
+	// int32 amounts and uint16 ratios keep minor*ratio within int64.
+	require.NoError(t, quick.Check(func(minor int32, ratios []uint16) bool {
+		return property(int64(minor), ratios)
+	}, &quick.Config{MaxCount: 5000}))
+}
+
+func TestMoney_MulProperties(t *testing.T) {
+	modes := []money.RoundingMode{money.RoundHalfEven, money.RoundHalfUp, money.RoundDown}
+
+	require.NoError(t, quick.Check(func(minor int32, num int16, den uint8) bool {
+		if den == 0 {
+			return true
+		}
+		amount := money.NewFromInt(int(minor))
+		exact := new(big.Rat).SetFrac64(int64(minor)*int64(num), int64(den))
+
+		for _, mode := range modes {
+			got := new(big.Rat).SetInt64(int64(amount.Mul(big.NewRat(int64(num), int64(den)), mode).AsInt()))
+			diff := new(big.Rat).Sub(got, exact)
+
+			// Rounding never moves the result by a whole minor unit or more.
+			if new(big.Rat).Abs(diff).Cmp(big.NewRat(1, 1)) >= 0 {
+				return false
+			}
+			// Rounding down never moves away from zero.
+			if mode == money.RoundDown && new(big.Rat).Abs(got).Cmp(new(big.Rat).Abs(exact)) > 0 {
+				return false
+			}
+			// Rounding to nearest never moves by more than half a minor unit.
+			if mode != money.RoundDown && new(big.Rat).Abs(diff).Cmp(big.NewRat(1, 2)) > 0 {
+				return false
+			}
+		}
+		return true
+	}, &quick.Config{MaxCount: 5000}))
+}
@@ -0,0 +1,76 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var ErrUnrepresentable = errors.New("money has more than 3 decimal places")
+
+// MarshalJSON encodes money as a decimal string, e.g. "12.345", so that
+// clients never have to deal with floating point numbers.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts decimal strings only. JSON numbers are rejected on
+// purpose: integers used to mean thousandths and floats lose precision.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("money must be a decimal string: %w", ErrInvalidFormat)
+	}
+	return m.UnmarshalText([]byte(s))
+}
+
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalText(text []byte) error {
+	v, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Value stores money as an integer number of thousandths, which is how
+// amounts are kept in the database.
+func (m Money) Value() (driver.Value, error) {
+	if !m.dec.Equal(m.dec.Truncate(decimalLen)) {
+		return nil, ErrUnrepresentable
+	}
+	return int64(m.AsInt()), nil
+}
+
+// Scan reads an integer number of thousandths. Strings are accepted because
+// aggregates such as SUM over integer columns are returned as numeric.
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		*m = NewFromInt(int(v))
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case nil:
+		return errors.New("cannot scan NULL into money")
+	default:
+		return fmt.Errorf("cannot scan %T into money", src)
+	}
+	return nil
+}
+
+func (m *Money) scanString(s string) error {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("scan money: %w", err)
+	}
+	*m = NewFromInt(int(v))
+	return nil
+}
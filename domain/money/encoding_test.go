@@ -0,0 +1,115 @@
+package money_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_JSON(t *testing.T) {
+	type wrapper struct {
+		Amount money.Money `json:"amount"`
+	}
+
+	data, err := json.Marshal(wrapper{Amount: money.NewFromInt(12345)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"12.345"}`, string(data))
+
+	var got wrapper
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"0.5"}`), &got))
+	assert.Equal(t, 500, got.Amount.AsInt())
+
+	for _, in := range []string{`{"amount":12345}`, `{"amount":"1.2345"}`, `{"amount":null}`, `{"amount":"1e3"}`} {
+		assert.Error(t, json.Unmarshal([]byte(in), &got), in)
+	}
+}
+
+func TestMoney_Text(t *testing.T) {
+	text, err := money.NewFromInt(-1500).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "-1.500", string(text))
+
+	var got money.Money
+	require.NoError(t, got.UnmarshalText([]byte("42.001")))
+	assert.Equal(t, 42001, got.AsInt())
+	assert.ErrorIs(t, got.UnmarshalText([]byte("42.0001")), money.ErrInvalidScale)
+}
+
+func TestMoney_SQL(t *testing.T) {
+	v, err := money.NewFromInt(1860043).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1860043), v)
+
+	tests := []struct {
+		name    string
+		src     any
+		want    int
+		wantErr bool
+	}{
+		{name: "int64", src: int64(1860043), want: 1860043},
+		{name: "numeric as bytes", src: []byte("-250"), want: -250},
+		{name: "numeric as string", src: "4277", want: 4277},
+		{name: "fractional numeric", src: "1.5", wantErr: true},
+		{name: "null", src: nil, wantErr: true},
+		{name: "float", src: 1.5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got money.Money
+			err := got.Scan(tt.src)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.AsInt())
+		})
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{"0", "12.345", "-0.001", "1000000", "12.3", "1e3", "", "-", "00.10"} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		m, err := money.Parse(s)
+		if err != nil {
+			return
+		}
+
+		again, err := money.Parse(m.String())
+		require.NoError(t, err)
+		require.True(t, m.Equal(again), "%q -> %q", s, m.String())
+
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		var fromJSON money.Money
+		require.NoError(t, json.Unmarshal(data, &fromJSON))
+		require.True(t, m.Equal(fromJSON), "%q -> %s", s, data)
+
+		text, err := m.MarshalText()
+		require.NoError(t, err)
+		var fromText money.Money
+		require.NoError(t, fromText.UnmarshalText(text))
+		require.True(t, m.Equal(fromText), "%q -> %s", s, text)
+	})
+}
+
+func FuzzSQL(f *testing.F) {
+	for _, v := range []int64{0, 1, -1, 1860043, 1 << 40} {
+		f.Add(v)
+	}
+
+	f.Fuzz(func(t *testing.T, v int64) {
+		var m money.Money
+		require.NoError(t, m.Scan(v))
+
+		got, err := m.Value()
+		require.NoError(t, err)
+		require.Equal(t, v, got)
+	})
+}
@@ -0,0 +1,63 @@
+package money
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// separators holds decimal and group separators by language. Languages that
+// group digits with a space use a non-breaking one so amounts never wrap.
+// Languages not listed here are formatted like English.
+var separators = map[string]struct{ decimal, group string }{
+	"en": {".", ","},
+	"de": {",", "."},
+	"es": {",", "."},
+	"it": {",", "."},
+	"nl": {",", "."},
+	"pt": {",", "."},
+	"tr": {",", "."},
+	"fr": {",", "\u202f"},
+	"pl": {",", "\u00a0"},
+	"ru": {",", "\u00a0"},
+	"uk": {",", "\u00a0"},
+	"sv": {",", "\u00a0"},
+	"fi": {",", "\u00a0"},
+	"ja": {".", ","},
+	"zh": {".", ","},
+}
+
+// Format returns the amount for display in the given locale, e.g. "1,234.500"
+// for "en-US" and "1.234,500" for "de-DE". Unknown or malformed locales fall
+// back to English. Use String for machine-readable output.
+func (m Money) Format(locale string) string {
+	sep := separators["en"]
+	if tag, err := language.Parse(locale); err == nil {
+		base, _ := tag.Base()
+		if v, ok := separators[base.String()]; ok {
+			sep = v
+		}
+	}
+
+	s := m.String()
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	intPart, frac, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(sep.group)
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString(sep.decimal)
+	b.WriteString(frac)
+
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package money_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney_Format(t *testing.T) {
+	tests := []struct {
+		amount string
+		locale string
+		want   string
+	}{
+		{amount: "1234567.5", locale: "en-US", want: "1,234,567.500"},
+		{amount: "1234567.5", locale: "de-DE", want: "1.234.567,500"},
+		{amount: "1234567.5", locale: "de_AT", want: "1.234.567,500"},
+		{amount: "1234567.5", locale: "fr-FR", want: "1\u202f234\u202f567,500"},
+		{amount: "1234567.5", locale: "ru", want: "1\u00a0234\u00a0567,500"},
+		{amount: "-1234.001", locale: "en", want: "-1,234.001"},
+		{amount: "999", locale: "en", want: "999.000"},
+		{amount: "0", locale: "de", want: "0,000"},
+		{amount: "1234", locale: "xx-invalid-locale", want: "1,234.000"},
+		{amount: "1234", locale: "", want: "1,234.000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.amount+"/"+tt.locale, func(t *testing.T) {
+			assert.Equal(t, tt.want, mustParse(t, tt.amount).Format(tt.locale))
+		})
+	}
+}
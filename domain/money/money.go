@@ -1,13 +1,25 @@
 package money
 
 import (
+	"errors"
 	"math"
+	"regexp"
+	"strings"
 
 	"github.com/shopspring/decimal"
 )
 
 const decimalLen = 3
 
+var (
+	ErrInvalidFormat = errors.New("invalid money format")
+	ErrInvalidScale  = errors.New("too many decimal places")
+)
+
+// decimalPattern accepts plain decimal numbers only: no exponent, no leading
+// plus sign, no surrounding spaces.
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
 var decimalVal = decimal.NewFromInt(int64(math.Pow(10, float64(decimalLen))))
 
 type Money struct {
@@ -22,6 +34,20 @@ func (m Money) IsNegative() bool {
 	return m.dec.IsNegative()
 }
 
+func (m Money) IsZero() bool {
+	return m.dec.IsZero()
+}
+
+// Cmp returns -1 if m < v, 0 if m == v and +1 if m > v.
+func (m Money) Cmp(v Money) int {
+	return m.dec.Cmp(v.dec)
+}
+
+// Equal compares values, so "1.5" equals "1.500".
+func (m Money) Equal(v Money) bool {
+	return m.dec.Equal(v.dec)
+}
+
 func (m Money) Add(v Money) Money {
 	return Money{m.dec.Add(v.dec)}
 }
@@ -37,3 +63,26 @@ func (m Money) AsInt() int {
 func NewFromInt(v int) Money {
 	return Money{decimal.NewFromInt(int64(v)).Div(decimalVal)}
 }
+
+// Parse parses a decimal string such as "12.345". It rejects values with more
+// than 3 decimal places instead of rounding them.
+func Parse(s string) (Money, error) {
+	if !decimalPattern.MatchString(s) {
+		return Money{}, ErrInvalidFormat
+	}
+	if _, frac, ok := strings.Cut(s, "."); ok && len(frac) > decimalLen {
+		return Money{}, ErrInvalidScale
+	}
+
+	dec, err := decimal.NewFromString(s)
+	if err != nil {
+		return Money{}, ErrInvalidFormat
+	}
+
+	return Money{dec}, nil
+}
+
+// String returns the amount with exactly 3 decimal places, e.g. "12.340".
+func (m Money) String() string {
+	return m.dec.StringFixed(decimalLen)
+}
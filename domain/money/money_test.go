@@ -16,3 +16,61 @@ func TestMoney(t *testing.T) {
 	assert.True(t, m.IsPositive())
 	assert.False(t, m.IsNegative())
 }
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantStr string
+		wantErr error
+	}{
+		{in: "12.345", want: 12345, wantStr: "12.345"},
+		{in: "12.3", want: 12300, wantStr: "12.300"},
+		{in: "12", want: 12000, wantStr: "12.000"},
+		{in: "0.001", want: 1, wantStr: "0.001"},
+		{in: "-5.5", want: -5500, wantStr: "-5.500"},
+		{in: "12.3456", wantErr: money.ErrInvalidScale},
+		{in: "12.3450", wantErr: money.ErrInvalidScale},
+		{in: "", wantErr: money.ErrInvalidFormat},
+		{in: "1e3", wantErr: money.ErrInvalidFormat},
+		{in: "+1", wantErr: money.ErrInvalidFormat},
+		{in: " 1", wantErr: money.ErrInvalidFormat},
+		{in: "1.", wantErr: money.ErrInvalidFormat},
+		{in: ".5", wantErr: money.ErrInvalidFormat},
+		{in: "ten", wantErr: money.ErrInvalidFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			m, err := money.Parse(tt.in)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, m.AsInt())
+			assert.Equal(t, tt.wantStr, m.String())
+		})
+	}
+}
+
+func TestMoney_Cmp(t *testing.T) {
+	a, b := mustParse(t, "1.5"), mustParse(t, "1.500")
+
+	assert.True(t, a.Equal(b))
+	assert.Zero(t, a.Cmp(b))
+	assert.Equal(t, -1, a.Cmp(mustParse(t, "1.501")))
+	assert.Equal(t, 1, a.Cmp(mustParse(t, "-2")))
+	assert.True(t, money.Money{}.IsZero())
+	assert.True(t, mustParse(t, "0.000").IsZero())
+	assert.False(t, a.IsZero())
+}
+
+func mustParse(t *testing.T, s string) money.Money {
+	t.Helper()
+
+	m, err := money.Parse(s)
+	if err != nil {
+		t.Fatalf("parse %q: %v", s, err)
+	}
+	return m
+}
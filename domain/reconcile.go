@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+)
+
+// Reconciliation is the outcome of checking a wallet against its ledger.
+type Reconciliation struct {
+	WalletID int
+	// Balance is the balance of the wallet as stored and Ledger is what its
+	// entries add up to. Day is not set on either.
+	Balance EntrySum
+	Ledger  EntrySum
+	Entries int
+	Audit   AuditReport
+}
+
+// Balanced tells whether the stored balance is what the ledger adds up to.
+func (r Reconciliation) Balanced() bool {
+	return r.Balance.Amount.Equal(r.Ledger.Amount) && r.Balance.Bonus.Equal(r.Ledger.Bonus)
+}
+
+// Consistent tells whether the balance adds up and the audit log of the
+// wallet checks out.
+func (r Reconciliation) Consistent() bool {
+	return r.Balanced() && len(r.Audit.Violations) == 0
+}
+
+// Reconcile sums up the ledger of the wallet and compares it with the stored
+// balance. It also verifies the audit log, like VerifyAudit, so that a
+// balance that adds up because an entry was changed along with it does not
+// go unnoticed.
+func (c WalletUseCases) Reconcile(ctx context.Context, walletID int) (*Reconciliation, error) {
+	balance, err := c.storage.GetBalance(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+	entries, err := c.storage.ListEntries(ctx, EntryFilter{WalletID: walletID})
+	if err != nil {
+		return nil, fmt.Errorf("list entries: %w", err)
+	}
+
+	audit, err := c.verifyAudit(ctx, walletID, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	var ledger EntrySum
+	for _, e := range entries {
+		ledger = ledger.add(e.Change())
+	}
+
+	return &Reconciliation{
+		WalletID: walletID,
+		Balance:  EntrySum{Amount: balance.Amount, Bonus: balance.Bonus},
+		Ledger:   ledger,
+		Entries:  len(entries),
+		Audit:    *audit,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCases_Reconcile(t *testing.T) {
+	tests := []struct {
+		name           string
+		tamper         func(t *testing.T, store *fakeWalletStore)
+		wantBalanced   bool
+		wantConsistent bool
+	}{
+		{
+			name:           "consistent",
+			tamper:         func(*testing.T, *fakeWalletStore) {},
+			wantBalanced:   true,
+			wantConsistent: true,
+		},
+		{
+			name: "balance changed",
+			tamper: func(t *testing.T, store *fakeWalletStore) {
+				store.amount = mustParse(t, "100")
+			},
+		},
+		{
+			name: "entry changed along with the balance",
+			tamper: func(t *testing.T, store *fakeWalletStore) {
+				store.debits[0].Amount = mustParse(t, "95")
+				store.amount = mustParse(t, "90")
+			},
+			wantBalanced: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newFakeWalletStore(25, mustParse(t, "0"))
+			uc := domain.NewWalletUseCases(store)
+
+			require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, "20")}))
+			require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "5")}))
+
+			tt.tamper(t, store)
+
+			got, err := uc.Reconcile(ctx, 25)
+			require.NoError(t, err)
+			assert.Equal(t, 2, got.Entries)
+			assert.Equal(t, tt.wantBalanced, got.Balanced())
+			assert.Equal(t, tt.wantConsistent, got.Consistent())
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrEntryNotFound      = errors.New("entry not found")
+	ErrEntryNotReversible = errors.New("entry cannot be reversed")
+	ErrAlreadyReversed    = errors.New("entry is already reversed")
+)
+
+// reversibleOperations are the entries support may undo. Deposits and
+// withdrawals have their own flows: chargebacks and failed payouts.
+var reversibleOperations = []OperationType{OperationBet, OperationWin, OperationRefund}
+
+// ReversalPolicy decides what happens when the wallet no longer has the money
+// a reversed debit added.
+type ReversalPolicy int
+
+const (
+	// ReversalReject refuses the reversal with ErrInsufficientFunds.
+	ReversalReject ReversalPolicy = iota
+	// ReversalAllowNegative takes the money anyway. Real money goes below
+	// zero and the wallet is flagged, like after a chargeback.
+	ReversalAllowNegative
+)
+
+func ParseReversalPolicy(s string) (ReversalPolicy, error) {
+	switch s {
+	case "reject":
+		return ReversalReject, nil
+	case "allow_negative":
+		return ReversalAllowNegative, nil
+	default:
+		return 0, fmt.Errorf("unknown reversal policy: %q", s)
+	}
+}
+
+// ReverseEntry undoes an entry of the wallet with a compensating entry that
+// references it. An entry is reversed at most once. Actor and reason are kept
+// in the metadata of the compensating entry, which is returned.
+//
+// The reversal restores the buckets the entry touched. Activity and wagering
+// progress are left as they are.
+func (c WalletUseCases) ReverseEntry(ctx context.Context, walletID, entryID int, actor, reason string) (*Entry, error) {
+	if actor == "" || reason == "" {
+		return nil, fmt.Errorf("%w: actor and reason are required", ErrEntryNotReversible)
+	}
+
+	original, err := c.storage.GetEntry(ctx, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("get entry: %w", err)
+	}
+	if original.WalletID != walletID {
+		return nil, ErrEntryNotFound
+	}
+	if original.ReversedBy != 0 {
+		return nil, ErrAlreadyReversed
+	}
+	if !containsOperation(reversibleOperations, original.Operation) {
+		return nil, fmt.Errorf("%w: %s", ErrEntryNotReversible, original.Operation)
+	}
+
+	metadata, err := json.Marshal(map[string]string{"actor": actor, "reason": reason})
+	if err != nil {
+		return nil, fmt.Errorf("marshal metadata: %w", err)
+	}
+	details := EntryDetails{
+		Operation:  OperationReversal,
+		Reference:  original.Reference,
+		Initiator:  InitiatorAdmin,
+		Metadata:   metadata,
+		ReversalOf: original.ID,
+	}
+
+	reversal := Entry{WalletID: walletID, EntryDetails: details}
+	if original.Credit != nil {
+		entry := DebitEntry{WalletID: walletID, Amount: *original.Credit, Bonus: original.Bonus, EntryDetails: details}
+		if err := c.addMoney(ctx, &entry); err != nil {
+			return nil, err
+		}
+		reversal.ID, reversal.Debit, reversal.Bonus, reversal.CreatedAt = entry.ID, &entry.Amount, entry.Bonus, entry.CreatedAt
+		return c.reversed(ctx, &reversal, actor, reason)
+	}
+
+	balance, err := c.storage.GetBalance(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+
+	// The bonus part may have been spent or released since, in which case
+	// the rest is taken from real money.
+	amount := *original.Debit
+	bonus := minMoney(original.Bonus, balance.Bonus)
+	if balance.Amount.Cmp(amount.Sub(bonus)) < 0 && c.reversals == ReversalReject {
+		return nil, ErrInsufficientFunds
+	}
+
+	balance.Amount = balance.Amount.Sub(amount.Sub(bonus))
+	balance.Bonus = balance.Bonus.Sub(bonus)
+	if balance.Amount.IsNegative() {
+		balance.Flagged = true
+	}
+	if err := c.storage.SaveBalance(ctx, balance); err != nil {
+		return nil, fmt.Errorf("save balance: %w", err)
+	}
+
+	entry := CreditEntry{WalletID: walletID, Amount: amount, Bonus: bonus, EntryDetails: details}
+	if err := c.storage.AddCreditEntry(ctx, &entry); err != nil {
+		return nil, fmt.Errorf("add credit entry: %w", err)
+	}
+	reversal.ID, reversal.Credit, reversal.Bonus, reversal.CreatedAt = entry.ID, &entry.Amount, entry.Bonus, entry.CreatedAt
+
+	return c.reversed(ctx, &reversal, actor, reason)
+}
+
+// reversed adds the reversal to the audit trail of admin actions.
+func (c WalletUseCases) reversed(ctx context.Context, reversal *Entry, actor, reason string) (*Entry, error) {
+	err := c.recordAction(ctx, reversal.WalletID, AdminReverseEntry, actor, reason, map[string]any{
+		"entryId":    reversal.ReversalOf,
+		"reversalId": reversal.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reversal, nil
+}
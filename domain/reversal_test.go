@@ -0,0 +1,113 @@
+package domain_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReversalPolicy(t *testing.T) {
+	policy, err := domain.ParseReversalPolicy("reject")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReversalReject, policy)
+
+	policy, err = domain.ParseReversalPolicy("allow_negative")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReversalAllowNegative, policy)
+
+	_, err = domain.ParseReversalPolicy("ignore")
+	require.Error(t, err)
+}
+
+func TestWalletUseCases_ReverseEntry(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      domain.ReversalPolicy
+		win         string
+		bet         string
+		reverse     int
+		wantErr     error
+		wantAmount  string
+		wantFlagged bool
+	}{
+		{name: "bet", win: "10", bet: "30", reverse: 2, wantAmount: "110.000"},
+		{name: "win", win: "10", bet: "5", reverse: 1, wantAmount: "95.000"},
+		{name: "win already spent", win: "10", bet: "105", reverse: 1, wantErr: domain.ErrInsufficientFunds, wantAmount: "5.000"},
+		{
+			name:        "win already spent, negative allowed",
+			policy:      domain.ReversalAllowNegative,
+			win:         "10",
+			bet:         "105",
+			reverse:     1,
+			wantAmount:  "-5.000",
+			wantFlagged: true,
+		},
+		{name: "unknown entry", win: "10", bet: "5", reverse: 3, wantErr: domain.ErrEntryNotFound, wantAmount: "105.000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newFakeWalletStore(25, mustParse(t, "100"))
+			uc := domain.NewWalletUseCases(store, domain.WithReversalPolicy(tt.policy))
+
+			require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, tt.win), EntryDetails: domain.EntryDetails{Reference: "round-1"}}))
+			require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, tt.bet)}))
+
+			reversal, err := uc.ReverseEntry(ctx, 25, tt.reverse, "support", "duplicate round")
+			assert.Equal(t, tt.wantAmount, store.amount.String())
+			assert.Equal(t, tt.wantFlagged, store.flagged)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, domain.OperationReversal, reversal.Operation)
+			assert.Equal(t, domain.InitiatorAdmin, reversal.Initiator)
+			assert.Equal(t, tt.reverse, reversal.ReversalOf)
+			assert.JSONEq(t, `{"actor": "support", "reason": "duplicate round"}`, string(reversal.Metadata))
+
+			original, err := store.GetEntry(ctx, tt.reverse)
+			require.NoError(t, err)
+			assert.Equal(t, reversal.ID, original.ReversedBy)
+
+			require.Len(t, store.actions, 1)
+			assert.Equal(t, domain.AdminReverseEntry, store.actions[0].Kind)
+			assert.Equal(t, "duplicate round", store.actions[0].Reason)
+			assert.JSONEq(t, `{"entryId": `+strconv.Itoa(tt.reverse)+`, "reversalId": 3}`, string(store.actions[0].Details))
+		})
+	}
+}
+
+func TestWalletUseCases_ReverseEntryRefused(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeWalletStore(25, mustParse(t, "100"))
+	uc := domain.NewWalletUseCases(store)
+
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "10")}))
+	require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, "20"), EntryDetails: domain.EntryDetails{Operation: domain.OperationDeposit}}))
+
+	_, err := uc.ReverseEntry(ctx, 25, 1, "support", "")
+	require.ErrorIs(t, err, domain.ErrEntryNotReversible, "the reason is required")
+
+	_, err = uc.ReverseEntry(ctx, 26, 1, "support", "wrong wallet")
+	require.ErrorIs(t, err, domain.ErrEntryNotFound)
+
+	_, err = uc.ReverseEntry(ctx, 25, 2, "support", "deposit")
+	require.ErrorIs(t, err, domain.ErrEntryNotReversible)
+
+	reversal, err := uc.ReverseEntry(ctx, 25, 1, "support", "first")
+	require.NoError(t, err)
+
+	_, err = uc.ReverseEntry(ctx, 25, 1, "support", "second")
+	require.ErrorIs(t, err, domain.ErrAlreadyReversed)
+
+	_, err = uc.ReverseEntry(ctx, 25, reversal.ID, "support", "reversal")
+	require.ErrorIs(t, err, domain.ErrEntryNotReversible)
+
+	assert.Equal(t, "120.000", store.amount.String())
+}
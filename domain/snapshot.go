@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// snapshotDay is the period of balance snapshots. Days are UTC.
+const snapshotDay = 24 * time.Hour
+
+// BalanceSnapshot is the balance of a wallet as the ledger has it at a point
+// in time: every entry created before At added up. Snapshots are taken at the
+// end of each UTC day the wallet has entries on. CreatedAt is set by the
+// store when the snapshot is added.
+type BalanceSnapshot struct {
+	WalletID int
+	At       time.Time
+	// Amount is real money.
+	Amount    money.Money
+	Bonus     money.Money
+	CreatedAt time.Time
+}
+
+// Total is real and bonus money together.
+func (s BalanceSnapshot) Total() money.Money {
+	return s.Amount.Add(s.Bonus)
+}
+
+// EntrySum is how much entries changed real and bonus money of a wallet. Day
+// is the UTC day of the entries if they are summed up by day.
+type EntrySum struct {
+	Day    time.Time
+	Amount money.Money
+	Bonus  money.Money
+}
+
+func (s EntrySum) add(other EntrySum) EntrySum {
+	return EntrySum{Day: s.Day, Amount: s.Amount.Add(other.Amount), Bonus: s.Bonus.Add(other.Bonus)}
+}
+
+// Change is how the entry changed real and bonus money of its wallet. A debit
+// adds its amount and a credit takes it, the bonus part from the bonus bucket
+// and the rest from real money.
+func (e Entry) Change() EntrySum {
+	if e.Debit != nil {
+		return EntrySum{Amount: e.Debit.Sub(e.Bonus), Bonus: e.Bonus}
+	}
+	var zero money.Money
+	return EntrySum{Amount: zero.Sub(e.Credit.Sub(e.Bonus)), Bonus: zero.Sub(e.Bonus)}
+}
+
+// BalanceAt returns the balance of the wallet at the given time: the nearest
+// snapshot taken at or before it plus the entries created since, up to but
+// not including at. Like snapshots, it follows the ledger rather than the
+// stored balance.
+func (c WalletUseCases) BalanceAt(ctx context.Context, walletID int, at time.Time) (*BalanceSnapshot, error) {
+	if _, err := c.storage.GetBalance(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+
+	snapshot, err := c.storage.GetSnapshot(ctx, walletID, at)
+	if errors.Is(err, ErrSnapshotNotFound) {
+		snapshot = &BalanceSnapshot{WalletID: walletID}
+	} else if err != nil {
+		return nil, fmt.Errorf("get snapshot: %w", err)
+	}
+
+	sum, err := c.storage.SumEntries(ctx, walletID, snapshot.At, at)
+	if err != nil {
+		return nil, fmt.Errorf("sum entries: %w", err)
+	}
+
+	return &BalanceSnapshot{
+		WalletID: walletID,
+		At:       at,
+		Amount:   snapshot.Amount.Add(sum.Amount),
+		Bonus:    snapshot.Bonus.Add(sum.Bonus),
+	}, nil
+}
+
+// TakeSnapshots snapshots the balance of the wallet at the end of every UTC
+// day since its last snapshot that ends by until and has entries. Days that
+// have not ended are left for a later run, as are days whose entries may still
+// be committing. It returns the snapshots taken.
+func (c WalletUseCases) TakeSnapshots(ctx context.Context, walletID int, until time.Time) ([]BalanceSnapshot, error) {
+	until = until.UTC().Truncate(snapshotDay)
+
+	last, err := c.storage.GetSnapshot(ctx, walletID, until)
+	if errors.Is(err, ErrSnapshotNotFound) {
+		last = &BalanceSnapshot{WalletID: walletID}
+	} else if err != nil {
+		return nil, fmt.Errorf("get snapshot: %w", err)
+	}
+
+	days, err := c.storage.SumEntriesByDay(ctx, walletID, last.At, until)
+	if err != nil {
+		return nil, fmt.Errorf("sum entries by day: %w", err)
+	}
+
+	running := EntrySum{Amount: last.Amount, Bonus: last.Bonus}
+	snapshots := make([]BalanceSnapshot, 0, len(days))
+	for _, day := range days {
+		running = running.add(day)
+		snapshot := BalanceSnapshot{
+			WalletID: walletID,
+			At:       day.Day.Add(snapshotDay),
+			Amount:   running.Amount,
+			Bonus:    running.Bonus,
+		}
+		if err := c.storage.AddSnapshot(ctx, &snapshot); err != nil {
+			return nil, fmt.Errorf("add snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
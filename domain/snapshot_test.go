@@ -0,0 +1,130 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWalletUseCases_BalanceAt(t *testing.T) {
+	snapshotAt := time.Date(2024, 6, 19, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2024, 6, 19, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		mockStore  func(store *MockWalletStore)
+		wantErr    error
+		wantAmount string
+		wantBonus  string
+	}{
+		{
+			name: "snapshot and entries since",
+			mockStore: func(store *MockWalletStore) {
+				store.EXPECT().GetBalance(gomock.Any(), 25).Return(&domain.WalletBalance{WalletID: 25}, nil)
+				store.EXPECT().GetSnapshot(gomock.Any(), 25, at).Return(&domain.BalanceSnapshot{
+					WalletID: 25,
+					At:       snapshotAt,
+					Amount:   money.NewFromInt(10000),
+					Bonus:    money.NewFromInt(2000),
+				}, nil)
+				store.EXPECT().SumEntries(gomock.Any(), 25, snapshotAt, at).Return(domain.EntrySum{
+					Amount: money.NewFromInt(-3000),
+					Bonus:  money.NewFromInt(500),
+				}, nil)
+			},
+			wantAmount: "7.000",
+			wantBonus:  "2.500",
+		},
+		{
+			name: "no snapshot yet",
+			mockStore: func(store *MockWalletStore) {
+				store.EXPECT().GetBalance(gomock.Any(), 25).Return(&domain.WalletBalance{WalletID: 25}, nil)
+				store.EXPECT().GetSnapshot(gomock.Any(), 25, at).Return(nil, domain.ErrSnapshotNotFound)
+				store.EXPECT().SumEntries(gomock.Any(), 25, time.Time{}, at).Return(domain.EntrySum{
+					Amount: money.NewFromInt(4000),
+				}, nil)
+			},
+			wantAmount: "4.000",
+			wantBonus:  "0.000",
+		},
+		{
+			name: "wallet not found",
+			mockStore: func(store *MockWalletStore) {
+				store.EXPECT().GetBalance(gomock.Any(), 25).Return(nil, domain.ErrWalletNotFound)
+			},
+			wantErr: domain.ErrWalletNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			store := NewMockWalletStore(mockCtrl)
+			tt.mockStore(store)
+
+			balance, err := domain.NewWalletUseCases(store).BalanceAt(context.Background(), 25, at)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, at, balance.At)
+			assert.Equal(t, tt.wantAmount, balance.Amount.String())
+			assert.Equal(t, tt.wantBonus, balance.Bonus.String())
+		})
+	}
+}
+
+func TestWalletUseCases_TakeSnapshots(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC) }
+
+	mockCtrl := gomock.NewController(t)
+	store := NewMockWalletStore(mockCtrl)
+
+	// The run is at noon, so the day it falls into is left for later.
+	store.EXPECT().GetSnapshot(gomock.Any(), 25, day(20)).Return(&domain.BalanceSnapshot{
+		WalletID: 25,
+		At:       day(17),
+		Amount:   money.NewFromInt(10000),
+	}, nil)
+	store.EXPECT().SumEntriesByDay(gomock.Any(), 25, day(17), day(20)).Return([]domain.EntrySum{
+		{Day: day(17), Amount: money.NewFromInt(-4000), Bonus: money.NewFromInt(1000)},
+		{Day: day(19), Amount: money.NewFromInt(500)},
+	}, nil)
+	var added []domain.BalanceSnapshot
+	store.EXPECT().AddSnapshot(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, snapshot *domain.BalanceSnapshot) error {
+			added = append(added, *snapshot)
+			return nil
+		}).Times(2)
+
+	until := day(20).Add(12 * time.Hour)
+	snapshots, err := domain.NewWalletUseCases(store).TakeSnapshots(context.Background(), 25, until)
+	require.NoError(t, err)
+	assert.Equal(t, added, snapshots)
+
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, day(18), snapshots[0].At)
+	assert.Equal(t, "6.000", snapshots[0].Amount.String())
+	assert.Equal(t, "1.000", snapshots[0].Bonus.String())
+	assert.Equal(t, day(20), snapshots[1].At)
+	assert.Equal(t, "6.500", snapshots[1].Amount.String())
+	assert.Equal(t, "1.000", snapshots[1].Bonus.String())
+}
+
+func TestEntry_Change(t *testing.T) {
+	amount, bonus := money.NewFromInt(5000), money.NewFromInt(2000)
+
+	debit := domain.Entry{Debit: &amount, Bonus: bonus}.Change()
+	assert.Equal(t, "3.000", debit.Amount.String())
+	assert.Equal(t, "2.000", debit.Bonus.String())
+
+	credit := domain.Entry{Credit: &amount, Bonus: bonus}.Change()
+	assert.Equal(t, "-3.000", credit.Amount.String())
+	assert.Equal(t, "-2.000", credit.Bonus.String())
+}
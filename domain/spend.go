@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+// SpendOrder decides which bucket a withdrawal is taken from first.
+type SpendOrder int
+
+const (
+	// SpendBonusLast spends real money first and bonus money only when real
+	// money runs out.
+	SpendBonusLast SpendOrder = iota
+	// SpendBonusFirst spends bonus money first.
+	SpendBonusFirst
+)
+
+func ParseSpendOrder(s string) (SpendOrder, error) {
+	switch s {
+	case "bonus_last":
+		return SpendBonusLast, nil
+	case "bonus_first":
+		return SpendBonusFirst, nil
+	default:
+		return 0, fmt.Errorf("unknown spend order: %q", s)
+	}
+}
+
+// BonusPart returns how much of amount is taken from the bonus bucket. The
+// rest is taken from real money.
+func (o SpendOrder) BonusPart(balance WalletBalance, amount money.Money) (money.Money, error) {
+	if balance.Total().Cmp(amount) < 0 {
+		return money.Money{}, ErrInsufficientFunds
+	}
+
+	if o == SpendBonusFirst {
+		return minMoney(balance.Bonus, amount), nil
+	}
+	return amount.Sub(minMoney(balance.Amount, amount)), nil
+}
+
+func minMoney(a, b money.Money) money.Money {
+	if a.Cmp(b) < 0 {
+		return a
+	}
+	return b
+}
@@ -1,12 +1,68 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 //go:generate go run go.uber.org/mock/mockgen -source=storage.go -destination=storage_mock_test.go -package=domain_test
 
 type WalletStore interface {
 	GetBalance(ctx context.Context, walletID int) (*WalletBalance, error)
 	SaveBalance(ctx context.Context, balance *WalletBalance) error
-	AddDebitEntry(ctx context.Context, entry DebitEntry) error
-	AddCreditEntry(ctx context.Context, entry CreditEntry) error
+	AddDebitEntry(ctx context.Context, entry *DebitEntry) error
+	AddCreditEntry(ctx context.Context, entry *CreditEntry) error
+	// GetEntry returns ErrEntryNotFound if there is no such entry.
+	GetEntry(ctx context.Context, entryID int) (*Entry, error)
+	// ListEntries returns entries that match the filter, oldest first.
+	ListEntries(ctx context.Context, filter EntryFilter) ([]Entry, error)
+	// SumEntries sums up entries of the wallet created in [from, to). A zero
+	// from sums up the ledger from its start.
+	SumEntries(ctx context.Context, walletID int, from, to time.Time) (EntrySum, error)
+	// SumEntriesByDay is SumEntries by UTC day, for days with entries only,
+	// oldest first.
+	SumEntriesByDay(ctx context.Context, walletID int, from, to time.Time) ([]EntrySum, error)
+	// GetSnapshot returns the latest snapshot of the wallet taken at or before
+	// at, or ErrSnapshotNotFound if there is none.
+	GetSnapshot(ctx context.Context, walletID int, at time.Time) (*BalanceSnapshot, error)
+	AddSnapshot(ctx context.Context, snapshot *BalanceSnapshot) error
+	// ListAuditRecords returns the audit log of the wallet ordered by Seq.
+	ListAuditRecords(ctx context.Context, walletID int) ([]AuditRecord, error)
+	AddBonusGrant(ctx context.Context, grant *BonusGrant) error
+	SaveBonusGrant(ctx context.Context, grant *BonusGrant) error
+	// ListBonusGrants returns grants of the wallet, oldest first.
+	ListBonusGrants(ctx context.Context, walletID int) ([]BonusGrant, error)
+	GetLimits(ctx context.Context, walletID int) ([]Limit, error)
+	// SaveLimit adds or replaces the limit of its kind and period. A limit
+	// with neither an amount nor a pending change is deleted.
+	SaveLimit(ctx context.Context, limit *Limit) error
+	// GetActivity sums up the activity of the wallet since the given time.
+	// Stores may aggregate activity in buckets and include the whole bucket
+	// that since falls into.
+	GetActivity(ctx context.Context, walletID int, since time.Time) (Activity, error)
+	AddActivity(ctx context.Context, walletID int, at time.Time, activity Activity) error
+	AddExclusion(ctx context.Context, exclusion *Exclusion) error
+	// SaveExclusion saves the Lifted fields of the exclusion.
+	SaveExclusion(ctx context.Context, exclusion *Exclusion) error
+	// ListExclusions returns exclusions of the wallet, oldest first.
+	ListExclusions(ctx context.Context, walletID int) ([]Exclusion, error)
+	AddWithdrawal(ctx context.Context, withdrawal *Withdrawal) error
+	// SaveWithdrawal saves the status of the withdrawal.
+	SaveWithdrawal(ctx context.Context, withdrawal *Withdrawal) error
+	GetWithdrawal(ctx context.Context, withdrawalID int) (*Withdrawal, error)
+	// ListWithdrawals returns withdrawals of the wallet, oldest first.
+	ListWithdrawals(ctx context.Context, walletID int) ([]Withdrawal, error)
+	AddWithdrawalTransition(ctx context.Context, transition *WithdrawalTransition) error
+	// ListWithdrawalTransitions returns transitions of the withdrawal in the
+	// order they were made.
+	ListWithdrawalTransitions(ctx context.Context, withdrawalID int) ([]WithdrawalTransition, error)
+	AddDeposit(ctx context.Context, deposit *Deposit) error
+	// SaveDeposit saves the status and the entry IDs of the deposit.
+	SaveDeposit(ctx context.Context, deposit *Deposit) error
+	// GetDeposit returns ErrDepositNotFound if the provider has not reported
+	// the reference before.
+	GetDeposit(ctx context.Context, provider, reference string) (*Deposit, error)
+	AddAdminAction(ctx context.Context, action *AdminAction) error
+	// ListAdminActions returns actions that match the filter, oldest first.
+	ListAdminActions(ctx context.Context, filter AdminActionFilter) ([]AdminAction, error)
 }
@@ -12,6 +12,7 @@ package domain_test
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/pprishchepa/go-casino-example/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -40,8 +41,50 @@ func (m *MockWalletStore) EXPECT() *MockWalletStoreMockRecorder {
 	return m.recorder
 }
 
+// AddActivity mocks base method.
+func (m *MockWalletStore) AddActivity(ctx context.Context, walletID int, at time.Time, activity domain.Activity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddActivity", ctx, walletID, at, activity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddActivity indicates an expected call of AddActivity.
+func (mr *MockWalletStoreMockRecorder) AddActivity(ctx, walletID, at, activity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActivity", reflect.TypeOf((*MockWalletStore)(nil).AddActivity), ctx, walletID, at, activity)
+}
+
+// AddAdminAction mocks base method.
+func (m *MockWalletStore) AddAdminAction(ctx context.Context, action *domain.AdminAction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAdminAction", ctx, action)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAdminAction indicates an expected call of AddAdminAction.
+func (mr *MockWalletStoreMockRecorder) AddAdminAction(ctx, action any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdminAction", reflect.TypeOf((*MockWalletStore)(nil).AddAdminAction), ctx, action)
+}
+
+// AddBonusGrant mocks base method.
+func (m *MockWalletStore) AddBonusGrant(ctx context.Context, grant *domain.BonusGrant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBonusGrant", ctx, grant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBonusGrant indicates an expected call of AddBonusGrant.
+func (mr *MockWalletStoreMockRecorder) AddBonusGrant(ctx, grant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBonusGrant", reflect.TypeOf((*MockWalletStore)(nil).AddBonusGrant), ctx, grant)
+}
+
 // AddCreditEntry mocks base method.
-func (m *MockWalletStore) AddCreditEntry(ctx context.Context, entry domain.CreditEntry) error {
+func (m *MockWalletStore) AddCreditEntry(ctx context.Context, entry *domain.CreditEntry) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddCreditEntry", ctx, entry)
 	ret0, _ := ret[0].(error)
@@ -55,7 +98,7 @@ func (mr *MockWalletStoreMockRecorder) AddCreditEntry(ctx, entry any) *gomock.Ca
 }
 
 // AddDebitEntry mocks base method.
-func (m *MockWalletStore) AddDebitEntry(ctx context.Context, entry domain.DebitEntry) error {
+func (m *MockWalletStore) AddDebitEntry(ctx context.Context, entry *domain.DebitEntry) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddDebitEntry", ctx, entry)
 	ret0, _ := ret[0].(error)
@@ -68,6 +111,91 @@ func (mr *MockWalletStoreMockRecorder) AddDebitEntry(ctx, entry any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDebitEntry", reflect.TypeOf((*MockWalletStore)(nil).AddDebitEntry), ctx, entry)
 }
 
+// AddDeposit mocks base method.
+func (m *MockWalletStore) AddDeposit(ctx context.Context, deposit *domain.Deposit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeposit", ctx, deposit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDeposit indicates an expected call of AddDeposit.
+func (mr *MockWalletStoreMockRecorder) AddDeposit(ctx, deposit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeposit", reflect.TypeOf((*MockWalletStore)(nil).AddDeposit), ctx, deposit)
+}
+
+// AddExclusion mocks base method.
+func (m *MockWalletStore) AddExclusion(ctx context.Context, exclusion *domain.Exclusion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddExclusion", ctx, exclusion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddExclusion indicates an expected call of AddExclusion.
+func (mr *MockWalletStoreMockRecorder) AddExclusion(ctx, exclusion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddExclusion", reflect.TypeOf((*MockWalletStore)(nil).AddExclusion), ctx, exclusion)
+}
+
+// AddSnapshot mocks base method.
+func (m *MockWalletStore) AddSnapshot(ctx context.Context, snapshot *domain.BalanceSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSnapshot", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSnapshot indicates an expected call of AddSnapshot.
+func (mr *MockWalletStoreMockRecorder) AddSnapshot(ctx, snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSnapshot", reflect.TypeOf((*MockWalletStore)(nil).AddSnapshot), ctx, snapshot)
+}
+
+// AddWithdrawal mocks base method.
+func (m *MockWalletStore) AddWithdrawal(ctx context.Context, withdrawal *domain.Withdrawal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWithdrawal", ctx, withdrawal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWithdrawal indicates an expected call of AddWithdrawal.
+func (mr *MockWalletStoreMockRecorder) AddWithdrawal(ctx, withdrawal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWithdrawal", reflect.TypeOf((*MockWalletStore)(nil).AddWithdrawal), ctx, withdrawal)
+}
+
+// AddWithdrawalTransition mocks base method.
+func (m *MockWalletStore) AddWithdrawalTransition(ctx context.Context, transition *domain.WithdrawalTransition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWithdrawalTransition", ctx, transition)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWithdrawalTransition indicates an expected call of AddWithdrawalTransition.
+func (mr *MockWalletStoreMockRecorder) AddWithdrawalTransition(ctx, transition any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWithdrawalTransition", reflect.TypeOf((*MockWalletStore)(nil).AddWithdrawalTransition), ctx, transition)
+}
+
+// GetActivity mocks base method.
+func (m *MockWalletStore) GetActivity(ctx context.Context, walletID int, since time.Time) (domain.Activity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivity", ctx, walletID, since)
+	ret0, _ := ret[0].(domain.Activity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivity indicates an expected call of GetActivity.
+func (mr *MockWalletStoreMockRecorder) GetActivity(ctx, walletID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivity", reflect.TypeOf((*MockWalletStore)(nil).GetActivity), ctx, walletID, since)
+}
+
 // GetBalance mocks base method.
 func (m *MockWalletStore) GetBalance(ctx context.Context, walletID int) (*domain.WalletBalance, error) {
 	m.ctrl.T.Helper()
@@ -83,6 +211,186 @@ func (mr *MockWalletStoreMockRecorder) GetBalance(ctx, walletID any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockWalletStore)(nil).GetBalance), ctx, walletID)
 }
 
+// GetDeposit mocks base method.
+func (m *MockWalletStore) GetDeposit(ctx context.Context, provider, reference string) (*domain.Deposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeposit", ctx, provider, reference)
+	ret0, _ := ret[0].(*domain.Deposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeposit indicates an expected call of GetDeposit.
+func (mr *MockWalletStoreMockRecorder) GetDeposit(ctx, provider, reference any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeposit", reflect.TypeOf((*MockWalletStore)(nil).GetDeposit), ctx, provider, reference)
+}
+
+// GetEntry mocks base method.
+func (m *MockWalletStore) GetEntry(ctx context.Context, entryID int) (*domain.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", ctx, entryID)
+	ret0, _ := ret[0].(*domain.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockWalletStoreMockRecorder) GetEntry(ctx, entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockWalletStore)(nil).GetEntry), ctx, entryID)
+}
+
+// GetLimits mocks base method.
+func (m *MockWalletStore) GetLimits(ctx context.Context, walletID int) ([]domain.Limit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimits", ctx, walletID)
+	ret0, _ := ret[0].([]domain.Limit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimits indicates an expected call of GetLimits.
+func (mr *MockWalletStoreMockRecorder) GetLimits(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimits", reflect.TypeOf((*MockWalletStore)(nil).GetLimits), ctx, walletID)
+}
+
+// GetSnapshot mocks base method.
+func (m *MockWalletStore) GetSnapshot(ctx context.Context, walletID int, at time.Time) (*domain.BalanceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSnapshot", ctx, walletID, at)
+	ret0, _ := ret[0].(*domain.BalanceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSnapshot indicates an expected call of GetSnapshot.
+func (mr *MockWalletStoreMockRecorder) GetSnapshot(ctx, walletID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSnapshot", reflect.TypeOf((*MockWalletStore)(nil).GetSnapshot), ctx, walletID, at)
+}
+
+// GetWithdrawal mocks base method.
+func (m *MockWalletStore) GetWithdrawal(ctx context.Context, withdrawalID int) (*domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithdrawal", ctx, withdrawalID)
+	ret0, _ := ret[0].(*domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWithdrawal indicates an expected call of GetWithdrawal.
+func (mr *MockWalletStoreMockRecorder) GetWithdrawal(ctx, withdrawalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawal", reflect.TypeOf((*MockWalletStore)(nil).GetWithdrawal), ctx, withdrawalID)
+}
+
+// ListAdminActions mocks base method.
+func (m *MockWalletStore) ListAdminActions(ctx context.Context, filter domain.AdminActionFilter) ([]domain.AdminAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAdminActions", ctx, filter)
+	ret0, _ := ret[0].([]domain.AdminAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAdminActions indicates an expected call of ListAdminActions.
+func (mr *MockWalletStoreMockRecorder) ListAdminActions(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAdminActions", reflect.TypeOf((*MockWalletStore)(nil).ListAdminActions), ctx, filter)
+}
+
+// ListAuditRecords mocks base method.
+func (m *MockWalletStore) ListAuditRecords(ctx context.Context, walletID int) ([]domain.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditRecords", ctx, walletID)
+	ret0, _ := ret[0].([]domain.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditRecords indicates an expected call of ListAuditRecords.
+func (mr *MockWalletStoreMockRecorder) ListAuditRecords(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditRecords", reflect.TypeOf((*MockWalletStore)(nil).ListAuditRecords), ctx, walletID)
+}
+
+// ListBonusGrants mocks base method.
+func (m *MockWalletStore) ListBonusGrants(ctx context.Context, walletID int) ([]domain.BonusGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBonusGrants", ctx, walletID)
+	ret0, _ := ret[0].([]domain.BonusGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBonusGrants indicates an expected call of ListBonusGrants.
+func (mr *MockWalletStoreMockRecorder) ListBonusGrants(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBonusGrants", reflect.TypeOf((*MockWalletStore)(nil).ListBonusGrants), ctx, walletID)
+}
+
+// ListEntries mocks base method.
+func (m *MockWalletStore) ListEntries(ctx context.Context, filter domain.EntryFilter) ([]domain.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", ctx, filter)
+	ret0, _ := ret[0].([]domain.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockWalletStoreMockRecorder) ListEntries(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockWalletStore)(nil).ListEntries), ctx, filter)
+}
+
+// ListExclusions mocks base method.
+func (m *MockWalletStore) ListExclusions(ctx context.Context, walletID int) ([]domain.Exclusion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExclusions", ctx, walletID)
+	ret0, _ := ret[0].([]domain.Exclusion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExclusions indicates an expected call of ListExclusions.
+func (mr *MockWalletStoreMockRecorder) ListExclusions(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExclusions", reflect.TypeOf((*MockWalletStore)(nil).ListExclusions), ctx, walletID)
+}
+
+// ListWithdrawalTransitions mocks base method.
+func (m *MockWalletStore) ListWithdrawalTransitions(ctx context.Context, withdrawalID int) ([]domain.WithdrawalTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithdrawalTransitions", ctx, withdrawalID)
+	ret0, _ := ret[0].([]domain.WithdrawalTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithdrawalTransitions indicates an expected call of ListWithdrawalTransitions.
+func (mr *MockWalletStoreMockRecorder) ListWithdrawalTransitions(ctx, withdrawalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithdrawalTransitions", reflect.TypeOf((*MockWalletStore)(nil).ListWithdrawalTransitions), ctx, withdrawalID)
+}
+
+// ListWithdrawals mocks base method.
+func (m *MockWalletStore) ListWithdrawals(ctx context.Context, walletID int) ([]domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithdrawals", ctx, walletID)
+	ret0, _ := ret[0].([]domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithdrawals indicates an expected call of ListWithdrawals.
+func (mr *MockWalletStoreMockRecorder) ListWithdrawals(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithdrawals", reflect.TypeOf((*MockWalletStore)(nil).ListWithdrawals), ctx, walletID)
+}
+
 // SaveBalance mocks base method.
 func (m *MockWalletStore) SaveBalance(ctx context.Context, balance *domain.WalletBalance) error {
 	m.ctrl.T.Helper()
@@ -96,3 +404,103 @@ func (mr *MockWalletStoreMockRecorder) SaveBalance(ctx, balance any) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBalance", reflect.TypeOf((*MockWalletStore)(nil).SaveBalance), ctx, balance)
 }
+
+// SaveBonusGrant mocks base method.
+func (m *MockWalletStore) SaveBonusGrant(ctx context.Context, grant *domain.BonusGrant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBonusGrant", ctx, grant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBonusGrant indicates an expected call of SaveBonusGrant.
+func (mr *MockWalletStoreMockRecorder) SaveBonusGrant(ctx, grant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBonusGrant", reflect.TypeOf((*MockWalletStore)(nil).SaveBonusGrant), ctx, grant)
+}
+
+// SaveDeposit mocks base method.
+func (m *MockWalletStore) SaveDeposit(ctx context.Context, deposit *domain.Deposit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeposit", ctx, deposit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeposit indicates an expected call of SaveDeposit.
+func (mr *MockWalletStoreMockRecorder) SaveDeposit(ctx, deposit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeposit", reflect.TypeOf((*MockWalletStore)(nil).SaveDeposit), ctx, deposit)
+}
+
+// SaveExclusion mocks base method.
+func (m *MockWalletStore) SaveExclusion(ctx context.Context, exclusion *domain.Exclusion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveExclusion", ctx, exclusion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveExclusion indicates an expected call of SaveExclusion.
+func (mr *MockWalletStoreMockRecorder) SaveExclusion(ctx, exclusion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveExclusion", reflect.TypeOf((*MockWalletStore)(nil).SaveExclusion), ctx, exclusion)
+}
+
+// SaveLimit mocks base method.
+func (m *MockWalletStore) SaveLimit(ctx context.Context, limit *domain.Limit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLimit", ctx, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLimit indicates an expected call of SaveLimit.
+func (mr *MockWalletStoreMockRecorder) SaveLimit(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLimit", reflect.TypeOf((*MockWalletStore)(nil).SaveLimit), ctx, limit)
+}
+
+// SaveWithdrawal mocks base method.
+func (m *MockWalletStore) SaveWithdrawal(ctx context.Context, withdrawal *domain.Withdrawal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWithdrawal", ctx, withdrawal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWithdrawal indicates an expected call of SaveWithdrawal.
+func (mr *MockWalletStoreMockRecorder) SaveWithdrawal(ctx, withdrawal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWithdrawal", reflect.TypeOf((*MockWalletStore)(nil).SaveWithdrawal), ctx, withdrawal)
+}
+
+// SumEntries mocks base method.
+func (m *MockWalletStore) SumEntries(ctx context.Context, walletID int, from, to time.Time) (domain.EntrySum, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntries", ctx, walletID, from, to)
+	ret0, _ := ret[0].(domain.EntrySum)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntries indicates an expected call of SumEntries.
+func (mr *MockWalletStoreMockRecorder) SumEntries(ctx, walletID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntries", reflect.TypeOf((*MockWalletStore)(nil).SumEntries), ctx, walletID, from, to)
+}
+
+// SumEntriesByDay mocks base method.
+func (m *MockWalletStore) SumEntriesByDay(ctx context.Context, walletID int, from, to time.Time) ([]domain.EntrySum, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesByDay", ctx, walletID, from, to)
+	ret0, _ := ret[0].([]domain.EntrySum)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesByDay indicates an expected call of SumEntriesByDay.
+func (mr *MockWalletStoreMockRecorder) SumEntriesByDay(ctx, walletID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesByDay", reflect.TypeOf((*MockWalletStore)(nil).SumEntriesByDay), ctx, walletID, from, to)
+}
@@ -3,31 +3,125 @@ package domain
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
 )
 
 type WalletUseCases struct {
-	storage WalletStore
+	storage      WalletStore
+	spendOrder   SpendOrder
+	weights      ContributionWeights
+	coolingOff   time.Duration
+	autoApproval money.Money
+	reversals    ReversalPolicy
+	now          func() time.Time
+}
+
+type WalletUseCasesOption func(c *WalletUseCases)
+
+// WithSpendOrder sets the order in which buckets are spent. Real money is
+// spent first by default.
+func WithSpendOrder(order SpendOrder) WalletUseCasesOption {
+	return func(c *WalletUseCases) {
+		c.spendOrder = order
+	}
+}
+
+// WithContributionWeights sets how much of a stake on each game type counts
+// towards wagering requirements. DefaultContributionWeights are used by
+// default.
+func WithContributionWeights(weights ContributionWeights) WalletUseCasesOption {
+	return func(c *WalletUseCases) {
+		c.weights = weights
+	}
+}
+
+// WithLimitCoolingOff sets how long raising or removing a limit takes to
+// apply. It is 24 hours by default.
+func WithLimitCoolingOff(d time.Duration) WalletUseCasesOption {
+	return func(c *WalletUseCases) {
+		c.coolingOff = d
+	}
 }
 
-func NewWalletUseCases(storage WalletStore) *WalletUseCases {
-	return &WalletUseCases{storage: storage}
+// WithWithdrawalAutoApproval approves withdrawals below limit without review.
+// Every withdrawal is reviewed by default.
+func WithWithdrawalAutoApproval(limit money.Money) WalletUseCasesOption {
+	return func(c *WalletUseCases) {
+		c.autoApproval = limit
+	}
+}
+
+// WithReversalPolicy sets what happens when reversing a debit needs more
+// money than the wallet has. Such reversals are rejected by default.
+func WithReversalPolicy(policy ReversalPolicy) WalletUseCasesOption {
+	return func(c *WalletUseCases) {
+		c.reversals = policy
+	}
+}
+
+// WithClock sets the clock bonus expiry, limits and exclusions are checked
+// against.
+func WithClock(now func() time.Time) WalletUseCasesOption {
+	return func(c *WalletUseCases) {
+		c.now = now
+	}
+}
+
+func NewWalletUseCases(storage WalletStore, opts ...WalletUseCasesOption) *WalletUseCases {
+	c := &WalletUseCases{
+		storage:    storage,
+		weights:    DefaultContributionWeights,
+		coolingOff: 24 * time.Hour,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func (c WalletUseCases) RetrieveBalance(ctx context.Context, walletID int) (*WalletBalance, error) {
 	return c.storage.GetBalance(ctx, walletID)
 }
 
-func (c WalletUseCases) DebitMoney(ctx context.Context, entry DebitEntry) error {
+func (c WalletUseCases) DebitMoney(ctx context.Context, entry *DebitEntry) error {
 	if !entry.Amount.IsPositive() {
 		return ErrInvalidAmount
 	}
+	if entry.Bonus.IsNegative() || entry.Bonus.Cmp(entry.Amount) > 0 {
+		return ErrInvalidAmount
+	}
+	if err := entry.normalize(debitOperations); err != nil {
+		return err
+	}
+
+	activity := Activity{Wins: entry.Amount}
+	if entry.Operation == OperationDeposit {
+		activity = Activity{Deposits: entry.Amount}
+	}
+	if err := c.trackActivity(ctx, entry.WalletID, activity); err != nil {
+		return err
+	}
 
+	return c.addMoney(ctx, entry)
+}
+
+// addMoney adds the entry to the balance without tracking it as player
+// activity.
+func (c WalletUseCases) addMoney(ctx context.Context, entry *DebitEntry) error {
 	balance, err := c.storage.GetBalance(ctx, entry.WalletID)
 	if err != nil {
 		return fmt.Errorf("get balance: %w", err)
 	}
 
-	balance.Amount = balance.Amount.Add(entry.Amount)
+	balance.Amount = balance.Amount.Add(entry.Amount.Sub(entry.Bonus))
+	balance.Bonus = balance.Bonus.Add(entry.Bonus)
+	if !balance.Amount.IsNegative() {
+		balance.Flagged = false
+	}
+
 	if err := c.storage.SaveBalance(ctx, balance); err != nil {
 		return fmt.Errorf("save balance: %w", err)
 	}
@@ -39,22 +133,64 @@ func (c WalletUseCases) DebitMoney(ctx context.Context, entry DebitEntry) error
 	return nil
 }
 
-func (c WalletUseCases) CreditMoney(ctx context.Context, entry CreditEntry) error {
+func (c WalletUseCases) CreditMoney(ctx context.Context, entry *CreditEntry) error {
 	if !entry.Amount.IsPositive() {
 		return ErrInvalidAmount
 	}
+	if err := entry.normalize(creditOperations); err != nil {
+		return err
+	}
+	withdrawal := entry.Operation == OperationWithdrawal
+
+	if !withdrawal {
+		exclusion, err := c.exclusion(ctx, entry.WalletID)
+		if err != nil {
+			return err
+		}
+		if exclusion != nil {
+			return ErrSelfExcluded
+		}
+	}
+
+	if err := c.ExpireBonuses(ctx, entry.WalletID); err != nil {
+		return fmt.Errorf("expire bonuses: %w", err)
+	}
+	if withdrawal {
+		if err := c.forfeitBonuses(ctx, entry.WalletID); err != nil {
+			return fmt.Errorf("forfeit bonuses: %w", err)
+		}
+	}
 
 	balance, err := c.storage.GetBalance(ctx, entry.WalletID)
 	if err != nil {
 		return fmt.Errorf("get balance: %w", err)
 	}
+	if balance.Frozen {
+		return ErrWalletFrozen
+	}
+	if balance.Flagged {
+		return ErrWalletFlagged
+	}
 
-	newAmount := balance.Amount.Sub(entry.Amount)
-	if newAmount.IsNegative() {
-		return ErrInsufficientFunds
+	if !withdrawal {
+		if err := c.trackActivity(ctx, entry.WalletID, Activity{Wagers: entry.Amount}); err != nil {
+			return err
+		}
 	}
 
-	balance.Amount = newAmount
+	var bonus money.Money
+	if withdrawal {
+		if balance.Amount.Cmp(entry.Amount) < 0 {
+			return ErrInsufficientFunds
+		}
+	} else if bonus, err = c.spendOrder.BonusPart(*balance, entry.Amount); err != nil {
+		return err
+	}
+	entry.Bonus = bonus
+
+	balance.Amount = balance.Amount.Sub(entry.Amount.Sub(bonus))
+	balance.Bonus = balance.Bonus.Sub(bonus)
+
 	if err := c.storage.SaveBalance(ctx, balance); err != nil {
 		return fmt.Errorf("save balance: %w", err)
 	}
@@ -63,5 +199,11 @@ func (c WalletUseCases) CreditMoney(ctx context.Context, entry CreditEntry) erro
 		return fmt.Errorf("add credit entry: %w", err)
 	}
 
+	if !withdrawal {
+		if err := c.wager(ctx, entry.WalletID, entry.GameType, entry.Amount); err != nil {
+			return fmt.Errorf("wager: %w", err)
+		}
+	}
+
 	return nil
 }
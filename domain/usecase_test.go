@@ -3,6 +3,7 @@ package domain_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/pprishchepa/go-casino-example/domain"
 	"github.com/pprishchepa/go-casino-example/domain/money"
@@ -14,7 +15,7 @@ import (
 func TestWalletUseCases_OnlyPositiveDebitAllowed(t *testing.T) {
 	store := newFakeWalletStore(25, money.NewFromInt(1000))
 	uc := domain.NewWalletUseCases(store)
-	err := uc.DebitMoney(context.Background(), domain.DebitEntry{
+	err := uc.DebitMoney(context.Background(), &domain.DebitEntry{
 		WalletID: 25,
 		Amount:   money.NewFromInt(-100),
 	})
@@ -24,7 +25,7 @@ func TestWalletUseCases_OnlyPositiveDebitAllowed(t *testing.T) {
 func TestWalletUseCases_OnlyPositiveCreditAllowed(t *testing.T) {
 	store := newFakeWalletStore(25, money.NewFromInt(1000))
 	uc := domain.NewWalletUseCases(store)
-	err := uc.CreditMoney(context.Background(), domain.CreditEntry{
+	err := uc.CreditMoney(context.Background(), &domain.CreditEntry{
 		WalletID: 25,
 		Amount:   money.NewFromInt(-100),
 	})
@@ -34,13 +35,113 @@ func TestWalletUseCases_OnlyPositiveCreditAllowed(t *testing.T) {
 func TestWalletUseCases_NegativeBalanceIsNotAllowed(t *testing.T) {
 	store := newFakeWalletStore(25, money.NewFromInt(1000))
 	uc := domain.NewWalletUseCases(store)
-	err := uc.CreditMoney(context.Background(), domain.CreditEntry{
+	err := uc.CreditMoney(context.Background(), &domain.CreditEntry{
 		WalletID: 25,
 		Amount:   money.NewFromInt(2500),
 	})
 	require.ErrorIs(t, err, domain.ErrInsufficientFunds)
 }
 
+func TestWalletUseCases_Buckets(t *testing.T) {
+	tests := []struct {
+		name       string
+		order      domain.SpendOrder
+		real       string
+		bonus      string
+		credit     string
+		wantErr    error
+		wantReal   string
+		wantBonus  string
+		wantCharge string
+	}{
+		{
+			name:  "bonus last, covered by real money",
+			order: domain.SpendBonusLast, real: "10", bonus: "5", credit: "4",
+			wantReal: "6.000", wantBonus: "5.000", wantCharge: "0.000",
+		},
+		{
+			name:  "bonus last, real money runs out",
+			order: domain.SpendBonusLast, real: "10", bonus: "5", credit: "12",
+			wantReal: "0.000", wantBonus: "3.000", wantCharge: "2.000",
+		},
+		{
+			name:  "bonus first, covered by bonus",
+			order: domain.SpendBonusFirst, real: "10", bonus: "5", credit: "4",
+			wantReal: "10.000", wantBonus: "1.000", wantCharge: "4.000",
+		},
+		{
+			name:  "bonus first, bonus runs out",
+			order: domain.SpendBonusFirst, real: "10", bonus: "5", credit: "7",
+			wantReal: "8.000", wantBonus: "0.000", wantCharge: "5.000",
+		},
+		{
+			name:  "whole balance",
+			order: domain.SpendBonusLast, real: "10", bonus: "5", credit: "15",
+			wantReal: "0.000", wantBonus: "0.000", wantCharge: "5.000",
+		},
+		{
+			name:  "more than both buckets",
+			order: domain.SpendBonusFirst, real: "10", bonus: "5", credit: "15.001",
+			wantErr: domain.ErrInsufficientFunds,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeWalletStore(25, mustParse(t, tt.real))
+			store.bonus = mustParse(t, tt.bonus)
+
+			entry := &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, tt.credit)}
+			err := domain.NewWalletUseCases(store, domain.WithSpendOrder(tt.order)).
+				CreditMoney(context.Background(), entry)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, store.credits)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantReal, store.amount.String())
+			assert.Equal(t, tt.wantBonus, store.bonus.String())
+			require.Len(t, store.credits, 1)
+			assert.Equal(t, tt.wantCharge, store.credits[0].Bonus.String())
+		})
+	}
+}
+
+func TestWalletUseCases_DebitToBonus(t *testing.T) {
+	store := newFakeWalletStore(25, mustParse(t, "10"))
+	uc := domain.NewWalletUseCases(store)
+
+	err := uc.DebitMoney(context.Background(), &domain.DebitEntry{
+		WalletID: 25,
+		Amount:   mustParse(t, "3"),
+		Bonus:    mustParse(t, "2"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "11.000", store.amount.String())
+	assert.Equal(t, "2.000", store.bonus.String())
+
+	err = uc.DebitMoney(context.Background(), &domain.DebitEntry{
+		WalletID: 25,
+		Amount:   mustParse(t, "3"),
+		Bonus:    mustParse(t, "3.001"),
+	})
+	require.ErrorIs(t, err, domain.ErrInvalidAmount)
+}
+
+func TestParseSpendOrder(t *testing.T) {
+	order, err := domain.ParseSpendOrder("bonus_first")
+	require.NoError(t, err)
+	assert.Equal(t, domain.SpendBonusFirst, order)
+
+	order, err = domain.ParseSpendOrder("bonus_last")
+	require.NoError(t, err)
+	assert.Equal(t, domain.SpendBonusLast, order)
+
+	_, err = domain.ParseSpendOrder("random")
+	require.Error(t, err)
+}
+
 func TestWalletUseCases_RetrieveBalance(t *testing.T) {
 	type args struct {
 		walletID  int
@@ -107,15 +208,40 @@ func TestWalletUseCases_RetrieveBalance(t *testing.T) {
 }
 
 type fakeWalletStore struct {
-	walletID int
-	amount   money.Money
+	walletID    int
+	amount      money.Money
+	bonus       money.Money
+	flagged     bool
+	frozen      bool
+	debits      []domain.DebitEntry
+	credits     []domain.CreditEntry
+	lastEntryID int
+	// balanceAfter and bonusAfter are the running balance after the last
+	// entry.
+	balanceAfter money.Money
+	bonusAfter   money.Money
+	audit        []domain.AuditRecord
+	grants       []domain.BonusGrant
+	limits       []domain.Limit
+	activity     []fakeActivity
+	exclusions   []domain.Exclusion
+	withdrawals  []domain.Withdrawal
+	transitions  []domain.WithdrawalTransition
+	deposits     []domain.Deposit
+	actions      []domain.AdminAction
+	snapshots    []domain.BalanceSnapshot
+}
+
+type fakeActivity struct {
+	at       time.Time
+	activity domain.Activity
 }
 
 func (f *fakeWalletStore) GetBalance(_ context.Context, walletID int) (*domain.WalletBalance, error) {
 	if walletID != f.walletID {
 		return nil, domain.ErrWalletNotFound
 	}
-	return &domain.WalletBalance{WalletID: walletID, Amount: f.amount}, nil
+	return &domain.WalletBalance{WalletID: walletID, Amount: f.amount, Bonus: f.bonus, Flagged: f.flagged, Frozen: f.frozen}, nil
 }
 
 func (f *fakeWalletStore) SaveBalance(_ context.Context, balance *domain.WalletBalance) error {
@@ -123,28 +249,330 @@ func (f *fakeWalletStore) SaveBalance(_ context.Context, balance *domain.WalletB
 		return domain.ErrWalletNotFound
 	}
 	f.amount = balance.Amount
+	f.bonus = balance.Bonus
+	f.flagged = balance.Flagged
+	f.frozen = balance.Frozen
 	return nil
 }
 
-func (f *fakeWalletStore) AddDebitEntry(_ context.Context, entry domain.DebitEntry) error {
+func (f *fakeWalletStore) AddDebitEntry(_ context.Context, entry *domain.DebitEntry) error {
 	if entry.WalletID != f.walletID {
 		return domain.ErrWalletNotFound
 	}
-	f.amount = f.amount.Add(entry.Amount)
-	return nil
+	f.lastEntryID++
+	entry.ID = f.lastEntryID
+	amount := entry.Amount
+	e := domain.Entry{ID: entry.ID, WalletID: entry.WalletID, Debit: &amount, Bonus: entry.Bonus, EntryDetails: entry.EntryDetails}
+	entry.BalanceAfter, entry.BonusAfter = f.runBalance(e)
+	f.debits = append(f.debits, *entry)
+	return f.addAuditRecord(e)
 }
 
-func (f *fakeWalletStore) AddCreditEntry(_ context.Context, entry domain.CreditEntry) error {
+func (f *fakeWalletStore) AddCreditEntry(_ context.Context, entry *domain.CreditEntry) error {
 	if entry.WalletID != f.walletID {
 		return domain.ErrWalletNotFound
 	}
-	f.amount = f.amount.Sub(entry.Amount)
+	f.lastEntryID++
+	entry.ID = f.lastEntryID
+	amount := entry.Amount
+	e := domain.Entry{ID: entry.ID, WalletID: entry.WalletID, Credit: &amount, Bonus: entry.Bonus, EntryDetails: entry.EntryDetails}
+	entry.BalanceAfter, entry.BonusAfter = f.runBalance(e)
+	f.credits = append(f.credits, *entry)
+	return f.addAuditRecord(e)
+}
+
+func (f *fakeWalletStore) runBalance(entry domain.Entry) (money.Money, money.Money) {
+	change := entry.Change()
+	f.balanceAfter, f.bonusAfter = f.balanceAfter.Add(change.Amount), f.bonusAfter.Add(change.Bonus)
+	return f.balanceAfter, f.bonusAfter
+}
+
+// ListEntries lists debits before credits, the fake does not keep the order
+// between them.
+func (f *fakeWalletStore) ListEntries(_ context.Context, filter domain.EntryFilter) ([]domain.Entry, error) {
+	var res []domain.Entry
+	for _, e := range f.debits {
+		amount := e.Amount
+		res = append(res, domain.Entry{ID: e.ID, WalletID: e.WalletID, Debit: &amount, Bonus: e.Bonus, EntryDetails: e.EntryDetails,
+			BalanceAfter: e.BalanceAfter, BonusAfter: e.BonusAfter})
+	}
+	for _, e := range f.credits {
+		amount := e.Amount
+		res = append(res, domain.Entry{ID: e.ID, WalletID: e.WalletID, Credit: &amount, Bonus: e.Bonus, EntryDetails: e.EntryDetails,
+			BalanceAfter: e.BalanceAfter, BonusAfter: e.BonusAfter})
+	}
+	for i := range res {
+		for _, e := range res {
+			if e.ReversalOf == res[i].ID {
+				res[i].ReversedBy = e.ID
+			}
+		}
+	}
+	if len(filter.Operations) == 0 {
+		return res, nil
+	}
+
+	var filtered []domain.Entry
+	for _, e := range res {
+		for _, op := range filter.Operations {
+			if e.Operation == op {
+				filtered = append(filtered, e)
+			}
+		}
+	}
+	return filtered, nil
+}
+
+// SumEntries sums up every entry, the fake does not keep when entries were
+// created.
+func (f *fakeWalletStore) SumEntries(ctx context.Context, walletID int, _, _ time.Time) (domain.EntrySum, error) {
+	entries, err := f.ListEntries(ctx, domain.EntryFilter{WalletID: walletID})
+	if err != nil {
+		return domain.EntrySum{}, err
+	}
+	var sum domain.EntrySum
+	for _, e := range entries {
+		change := e.Change()
+		sum.Amount, sum.Bonus = sum.Amount.Add(change.Amount), sum.Bonus.Add(change.Bonus)
+	}
+	return sum, nil
+}
+
+func (f *fakeWalletStore) SumEntriesByDay(ctx context.Context, walletID int, from, to time.Time) ([]domain.EntrySum, error) {
+	sum, err := f.SumEntries(ctx, walletID, from, to)
+	if err != nil || len(f.debits)+len(f.credits) == 0 {
+		return nil, err
+	}
+	return []domain.EntrySum{sum}, nil
+}
+
+func (f *fakeWalletStore) GetSnapshot(_ context.Context, walletID int, at time.Time) (*domain.BalanceSnapshot, error) {
+	for i := len(f.snapshots) - 1; i >= 0; i-- {
+		if f.snapshots[i].WalletID == walletID && !f.snapshots[i].At.After(at) {
+			return &f.snapshots[i], nil
+		}
+	}
+	return nil, domain.ErrSnapshotNotFound
+}
+
+func (f *fakeWalletStore) AddSnapshot(_ context.Context, snapshot *domain.BalanceSnapshot) error {
+	if snapshot.WalletID != f.walletID {
+		return domain.ErrWalletNotFound
+	}
+	f.snapshots = append(f.snapshots, *snapshot)
+	return nil
+}
+
+func (f *fakeWalletStore) addAuditRecord(entry domain.Entry) error {
+	var prev *domain.AuditRecord
+	if len(f.audit) > 0 {
+		prev = &f.audit[len(f.audit)-1]
+	}
+	record, err := domain.NewAuditRecord(prev, entry)
+	if err != nil {
+		return err
+	}
+	f.audit = append(f.audit, record)
+	return nil
+}
+
+func (f *fakeWalletStore) ListAuditRecords(_ context.Context, walletID int) ([]domain.AuditRecord, error) {
+	if walletID != f.walletID {
+		return nil, domain.ErrWalletNotFound
+	}
+	return append([]domain.AuditRecord(nil), f.audit...), nil
+}
+
+func (f *fakeWalletStore) GetEntry(ctx context.Context, entryID int) (*domain.Entry, error) {
+	entries, _ := f.ListEntries(ctx, domain.EntryFilter{})
+	for _, e := range entries {
+		if e.ID == entryID {
+			return &e, nil
+		}
+	}
+	return nil, domain.ErrEntryNotFound
+}
+
+func (f *fakeWalletStore) AddBonusGrant(_ context.Context, grant *domain.BonusGrant) error {
+	if grant.WalletID != f.walletID {
+		return domain.ErrWalletNotFound
+	}
+	grant.ID = len(f.grants) + 1
+	f.grants = append(f.grants, *grant)
 	return nil
 }
 
+func (f *fakeWalletStore) SaveBonusGrant(_ context.Context, grant *domain.BonusGrant) error {
+	f.grants[grant.ID-1] = *grant
+	return nil
+}
+
+func (f *fakeWalletStore) ListBonusGrants(_ context.Context, walletID int) ([]domain.BonusGrant, error) {
+	if walletID != f.walletID {
+		return nil, nil
+	}
+	return append([]domain.BonusGrant(nil), f.grants...), nil
+}
+
+func (f *fakeWalletStore) GetLimits(_ context.Context, walletID int) ([]domain.Limit, error) {
+	if walletID != f.walletID {
+		return nil, nil
+	}
+	return append([]domain.Limit(nil), f.limits...), nil
+}
+
+func (f *fakeWalletStore) SaveLimit(_ context.Context, limit *domain.Limit) error {
+	for i, l := range f.limits {
+		if l.Kind == limit.Kind && l.Period == limit.Period {
+			f.limits = append(f.limits[:i], f.limits[i+1:]...)
+			break
+		}
+	}
+	if limit.Amount != nil || !limit.PendingAt.IsZero() {
+		f.limits = append(f.limits, *limit)
+	}
+	return nil
+}
+
+func (f *fakeWalletStore) GetActivity(_ context.Context, _ int, since time.Time) (domain.Activity, error) {
+	var res domain.Activity
+	for _, a := range f.activity {
+		if !a.at.Before(since) {
+			res = res.Add(a.activity)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeWalletStore) AddActivity(_ context.Context, walletID int, at time.Time, activity domain.Activity) error {
+	if walletID != f.walletID {
+		return domain.ErrWalletNotFound
+	}
+	f.activity = append(f.activity, fakeActivity{at: at, activity: activity})
+	return nil
+}
+
+func (f *fakeWalletStore) AddExclusion(_ context.Context, exclusion *domain.Exclusion) error {
+	if exclusion.WalletID != f.walletID {
+		return domain.ErrWalletNotFound
+	}
+	exclusion.ID = len(f.exclusions) + 1
+	f.exclusions = append(f.exclusions, *exclusion)
+	return nil
+}
+
+func (f *fakeWalletStore) SaveExclusion(_ context.Context, exclusion *domain.Exclusion) error {
+	f.exclusions[exclusion.ID-1] = *exclusion
+	return nil
+}
+
+func (f *fakeWalletStore) ListExclusions(_ context.Context, walletID int) ([]domain.Exclusion, error) {
+	if walletID != f.walletID {
+		return nil, nil
+	}
+	return append([]domain.Exclusion(nil), f.exclusions...), nil
+}
+
+func (f *fakeWalletStore) AddWithdrawal(_ context.Context, withdrawal *domain.Withdrawal) error {
+	if withdrawal.WalletID != f.walletID {
+		return domain.ErrWalletNotFound
+	}
+	withdrawal.ID = len(f.withdrawals) + 1
+	f.withdrawals = append(f.withdrawals, *withdrawal)
+	return nil
+}
+
+func (f *fakeWalletStore) SaveWithdrawal(_ context.Context, withdrawal *domain.Withdrawal) error {
+	f.withdrawals[withdrawal.ID-1] = *withdrawal
+	return nil
+}
+
+func (f *fakeWalletStore) GetWithdrawal(_ context.Context, withdrawalID int) (*domain.Withdrawal, error) {
+	if withdrawalID < 1 || withdrawalID > len(f.withdrawals) {
+		return nil, domain.ErrWithdrawalNotFound
+	}
+	withdrawal := f.withdrawals[withdrawalID-1]
+	return &withdrawal, nil
+}
+
+func (f *fakeWalletStore) ListWithdrawals(_ context.Context, walletID int) ([]domain.Withdrawal, error) {
+	if walletID != f.walletID {
+		return nil, nil
+	}
+	return append([]domain.Withdrawal(nil), f.withdrawals...), nil
+}
+
+func (f *fakeWalletStore) AddWithdrawalTransition(_ context.Context, transition *domain.WithdrawalTransition) error {
+	f.transitions = append(f.transitions, *transition)
+	return nil
+}
+
+func (f *fakeWalletStore) ListWithdrawalTransitions(_ context.Context, withdrawalID int) ([]domain.WithdrawalTransition, error) {
+	var res []domain.WithdrawalTransition
+	for _, t := range f.transitions {
+		if t.WithdrawalID == withdrawalID {
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeWalletStore) AddDeposit(_ context.Context, deposit *domain.Deposit) error {
+	if deposit.WalletID != f.walletID {
+		return domain.ErrWalletNotFound
+	}
+	deposit.ID = len(f.deposits) + 1
+	f.deposits = append(f.deposits, *deposit)
+	return nil
+}
+
+func (f *fakeWalletStore) SaveDeposit(_ context.Context, deposit *domain.Deposit) error {
+	f.deposits[deposit.ID-1] = *deposit
+	return nil
+}
+
+func (f *fakeWalletStore) GetDeposit(_ context.Context, provider, reference string) (*domain.Deposit, error) {
+	for _, d := range f.deposits {
+		if d.Provider == provider && d.Reference == reference {
+			return &d, nil
+		}
+	}
+	return nil, domain.ErrDepositNotFound
+}
+
+func (f *fakeWalletStore) AddAdminAction(_ context.Context, action *domain.AdminAction) error {
+	if action.WalletID != f.walletID {
+		return domain.ErrWalletNotFound
+	}
+	action.ID = len(f.actions) + 1
+	f.actions = append(f.actions, *action)
+	return nil
+}
+
+func (f *fakeWalletStore) ListAdminActions(_ context.Context, filter domain.AdminActionFilter) ([]domain.AdminAction, error) {
+	var res []domain.AdminAction
+	for _, a := range f.actions {
+		if (filter.WalletID == 0 || a.WalletID == filter.WalletID) &&
+			(filter.Actor == "" || a.Actor == filter.Actor) &&
+			(filter.Kind == "" || a.Kind == filter.Kind) {
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+
 func newFakeWalletStore(walletID int, amount money.Money) *fakeWalletStore {
 	return &fakeWalletStore{
 		walletID: walletID,
 		amount:   amount,
 	}
 }
+
+func mustParse(t *testing.T, s string) money.Money {
+	t.Helper()
+
+	m, err := money.Parse(s)
+	require.NoError(t, err)
+
+	return m
+}
@@ -0,0 +1,258 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+type BonusStatus string
+
+const (
+	// BonusActive is a bonus that is still being wagered.
+	BonusActive BonusStatus = "active"
+	// BonusCompleted is a bonus whose requirement has been wagered. It has
+	// been converted to real money.
+	BonusCompleted BonusStatus = "completed"
+	// BonusForfeited is a bonus lost because of a withdrawal.
+	BonusForfeited BonusStatus = "forfeited"
+	// BonusExpired is a bonus lost because it was not wagered in time.
+	BonusExpired BonusStatus = "expired"
+)
+
+// BonusGrant is bonus money granted to a wallet. It stays in the bonus bucket
+// until Multiplier times Amount has been wagered. ID and CreatedAt are set by
+// the store when the grant is added.
+type BonusGrant struct {
+	ID         int
+	WalletID   int
+	Amount     money.Money
+	Multiplier int
+	Wagered    money.Money
+	Status     BonusStatus
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+}
+
+// Requirement is how much has to be wagered before the bonus converts to
+// real money.
+func (g BonusGrant) Requirement() money.Money {
+	return g.Amount.Mul(big.NewRat(int64(g.Multiplier), 1), money.RoundHalfEven)
+}
+
+// Remaining is how much is still to be wagered.
+func (g BonusGrant) Remaining() money.Money {
+	if g.Wagered.Cmp(g.Requirement()) >= 0 {
+		return money.Money{}
+	}
+	return g.Requirement().Sub(g.Wagered)
+}
+
+// GameType is the kind of game a stake is placed on.
+type GameType string
+
+// ContributionWeights maps game types to the percentage of a stake that
+// counts towards wagering requirements.
+type ContributionWeights map[GameType]int
+
+// DefaultContributionWeights count slots in full and table games at 10%.
+var DefaultContributionWeights = ContributionWeights{
+	"slots": 100,
+	"table": 10,
+	"live":  10,
+}
+
+// Contribution returns how much of stake counts towards wagering. A stake
+// without a game type counts in full, a stake on an unknown game type does
+// not count at all.
+func (w ContributionWeights) Contribution(gameType GameType, stake money.Money) money.Money {
+	if gameType == "" {
+		return stake
+	}
+	return stake.Percent(big.NewRat(int64(w[gameType]), 1), money.RoundDown)
+}
+
+// GrantBonus adds grant.Amount to the bonus bucket and starts tracking its
+// wagering requirement.
+func (c WalletUseCases) GrantBonus(ctx context.Context, grant *BonusGrant) error {
+	if !grant.Amount.IsPositive() || grant.Multiplier <= 0 {
+		return ErrInvalidAmount
+	}
+	if !grant.ExpiresAt.After(c.now()) {
+		return ErrInvalidExpiry
+	}
+
+	if err := c.ExpireBonuses(ctx, grant.WalletID); err != nil {
+		return fmt.Errorf("expire bonuses: %w", err)
+	}
+
+	if err := c.addMoney(ctx, &DebitEntry{
+		WalletID:     grant.WalletID,
+		Amount:       grant.Amount,
+		Bonus:        grant.Amount,
+		EntryDetails: EntryDetails{Operation: OperationBonusGrant, Initiator: InitiatorService},
+	}); err != nil {
+		return fmt.Errorf("debit bonus: %w", err)
+	}
+
+	grant.Wagered = money.Money{}
+	grant.Status = BonusActive
+
+	if err := c.storage.AddBonusGrant(ctx, grant); err != nil {
+		return fmt.Errorf("add bonus grant: %w", err)
+	}
+
+	return nil
+}
+
+// ListBonuses returns all bonuses of the wallet, oldest first.
+func (c WalletUseCases) ListBonuses(ctx context.Context, walletID int) ([]BonusGrant, error) {
+	return c.storage.ListBonusGrants(ctx, walletID)
+}
+
+// ExpireBonuses takes the bonus money of overdue grants away. Grants are
+// expired lazily, when the wallet is used next.
+func (c WalletUseCases) ExpireBonuses(ctx context.Context, walletID int) error {
+	grants, err := c.activeBonuses(ctx, walletID)
+	if err != nil {
+		return err
+	}
+
+	now, active := c.now(), len(grants)
+	for i := range grants {
+		if grants[i].ExpiresAt.After(now) {
+			continue
+		}
+		if err := c.closeBonus(ctx, &grants[i], BonusExpired, active == 1); err != nil {
+			return err
+		}
+		active--
+	}
+
+	return nil
+}
+
+// forfeitBonuses takes the bonus money of all active grants away.
+func (c WalletUseCases) forfeitBonuses(ctx context.Context, walletID int) error {
+	grants, err := c.activeBonuses(ctx, walletID)
+	if err != nil {
+		return err
+	}
+
+	for i := range grants {
+		if err := c.closeBonus(ctx, &grants[i], BonusForfeited, i == len(grants)-1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// wager counts a stake towards active grants, oldest first. The part of the
+// stake beyond the requirement of one grant counts towards the next one.
+func (c WalletUseCases) wager(ctx context.Context, walletID int, gameType GameType, stake money.Money) error {
+	grants, err := c.activeBonuses(ctx, walletID)
+	if err != nil {
+		return err
+	}
+
+	contribution := c.weights.Contribution(gameType, stake)
+	for i := range grants {
+		if !contribution.IsPositive() {
+			break
+		}
+
+		grant := &grants[i]
+		counted := minMoney(contribution, grant.Remaining())
+		grant.Wagered = grant.Wagered.Add(counted)
+		contribution = contribution.Sub(counted)
+
+		if grant.Remaining().IsPositive() {
+			if err := c.storage.SaveBonusGrant(ctx, grant); err != nil {
+				return fmt.Errorf("save bonus grant: %w", err)
+			}
+			continue
+		}
+		// Grants are wagered oldest first, so every grant before this one
+		// has been completed already.
+		if err := c.closeBonus(ctx, grant, BonusCompleted, i == len(grants)-1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// closeBonus moves the bonus money of the grant out of the bonus bucket:
+// into real money when the grant is completed, out of the wallet otherwise.
+// The grant's own amount is moved, or the whole bonus bucket if no other
+// grant stays active, so that bonus wins follow the bonus they were won with.
+func (c WalletUseCases) closeBonus(ctx context.Context, grant *BonusGrant, status BonusStatus, last bool) error {
+	balance, err := c.storage.GetBalance(ctx, grant.WalletID)
+	if err != nil {
+		return fmt.Errorf("get balance: %w", err)
+	}
+
+	released := minMoney(grant.Amount, balance.Bonus)
+	if last {
+		released = balance.Bonus
+	}
+
+	grant.Status = status
+	if err := c.storage.SaveBonusGrant(ctx, grant); err != nil {
+		return fmt.Errorf("save bonus grant: %w", err)
+	}
+
+	if !released.IsPositive() {
+		return nil
+	}
+
+	balance.Bonus = balance.Bonus.Sub(released)
+	if status == BonusCompleted {
+		balance.Amount = balance.Amount.Add(released)
+	}
+	if err := c.storage.SaveBalance(ctx, balance); err != nil {
+		return fmt.Errorf("save balance: %w", err)
+	}
+
+	// The ledger shows the bonus leaving the bonus bucket and, on completion,
+	// coming back as real money.
+	if err := c.storage.AddCreditEntry(ctx, &CreditEntry{
+		WalletID:     grant.WalletID,
+		Amount:       released,
+		Bonus:        released,
+		EntryDetails: EntryDetails{Operation: OperationBonusRelease, Initiator: InitiatorService},
+	}); err != nil {
+		return fmt.Errorf("add credit entry: %w", err)
+	}
+	if status != BonusCompleted {
+		return nil
+	}
+	if err := c.storage.AddDebitEntry(ctx, &DebitEntry{
+		WalletID:     grant.WalletID,
+		Amount:       released,
+		EntryDetails: EntryDetails{Operation: OperationBonusConversion, Initiator: InitiatorService},
+	}); err != nil {
+		return fmt.Errorf("add debit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (c WalletUseCases) activeBonuses(ctx context.Context, walletID int) ([]BonusGrant, error) {
+	grants, err := c.storage.ListBonusGrants(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("list bonus grants: %w", err)
+	}
+
+	var active []BonusGrant
+	for _, grant := range grants {
+		if grant.Status == BonusActive {
+			active = append(active, grant)
+		}
+	}
+	return active, nil
+}
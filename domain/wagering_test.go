@@ -0,0 +1,235 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContributionWeights_Contribution(t *testing.T) {
+	tests := []struct {
+		name     string
+		gameType domain.GameType
+		stake    string
+		want     string
+	}{
+		{name: "slots count in full", gameType: "slots", stake: "10", want: "10.000"},
+		{name: "table games count partly", gameType: "table", stake: "10", want: "1.000"},
+		{name: "partial contribution rounds down", gameType: "live", stake: "0.019", want: "0.001"},
+		{name: "unknown game type does not count", gameType: "lottery", stake: "10", want: "0.000"},
+		{name: "no game type counts in full", stake: "10", want: "10.000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := domain.DefaultContributionWeights.Contribution(tt.gameType, mustParse(t, tt.stake))
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestWalletUseCases_GrantBonus(t *testing.T) {
+	now := time.Date(2024, 4, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		grant   domain.BonusGrant
+		wantErr error
+	}{
+		{
+			name:  "granted",
+			grant: domain.BonusGrant{WalletID: 25, Amount: mustParse(t, "5"), Multiplier: 30, ExpiresAt: now.Add(time.Hour)},
+		},
+		{
+			name:    "no multiplier",
+			grant:   domain.BonusGrant{WalletID: 25, Amount: mustParse(t, "5"), ExpiresAt: now.Add(time.Hour)},
+			wantErr: domain.ErrInvalidAmount,
+		},
+		{
+			name:    "no amount",
+			grant:   domain.BonusGrant{WalletID: 25, Multiplier: 30, ExpiresAt: now.Add(time.Hour)},
+			wantErr: domain.ErrInvalidAmount,
+		},
+		{
+			name:    "already expired",
+			grant:   domain.BonusGrant{WalletID: 25, Amount: mustParse(t, "5"), Multiplier: 30, ExpiresAt: now},
+			wantErr: domain.ErrInvalidExpiry,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeWalletStore(25, mustParse(t, "10"))
+			uc := domain.NewWalletUseCases(store, domain.WithClock(func() time.Time { return now }))
+
+			grant := tt.grant
+			err := uc.GrantBonus(context.Background(), &grant)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, store.grants)
+				assert.Empty(t, store.debits)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, "10.000", store.amount.String())
+			assert.Equal(t, "5.000", store.bonus.String())
+			require.Len(t, store.grants, 1)
+			assert.Equal(t, domain.BonusActive, store.grants[0].Status)
+			assert.Equal(t, "150.000", store.grants[0].Requirement().String())
+			require.Len(t, store.debits, 1)
+			assert.Equal(t, "5.000", store.debits[0].Bonus.String())
+		})
+	}
+}
+
+func TestWalletUseCases_Wagering(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 17, 12, 0, 0, 0, time.UTC)
+
+	store := newFakeWalletStore(25, mustParse(t, "10"))
+	uc := domain.NewWalletUseCases(store, domain.WithClock(func() time.Time { return now }))
+
+	require.NoError(t, uc.GrantBonus(ctx, &domain.BonusGrant{
+		WalletID:   25,
+		Amount:     mustParse(t, "5"),
+		Multiplier: 2,
+		ExpiresAt:  now.Add(time.Hour),
+	}))
+
+	// 10% of a table stake counts.
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "10"), GameType: "table"}))
+	assert.Equal(t, "1.000", store.grants[0].Wagered.String())
+
+	// A win from a bonus stake.
+	require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: 25, Amount: mustParse(t, "10"), Bonus: mustParse(t, "10")}))
+	assert.Equal(t, "0.000", store.amount.String())
+	assert.Equal(t, "15.000", store.bonus.String())
+
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "5"), GameType: "slots"}))
+	assert.Equal(t, "6.000", store.grants[0].Wagered.String())
+	assert.Equal(t, domain.BonusActive, store.grants[0].Status)
+
+	// The stake beyond the requirement does not count anywhere.
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "6"), GameType: "slots"}))
+	assert.Equal(t, domain.BonusCompleted, store.grants[0].Status)
+	assert.Equal(t, "0.000", store.grants[0].Remaining().String())
+
+	// The whole bonus bucket, wins included, converts to real money.
+	assert.Equal(t, "4.000", store.amount.String())
+	assert.Equal(t, "0.000", store.bonus.String())
+	conversion := store.credits[len(store.credits)-1]
+	assert.Equal(t, "4.000", conversion.Amount.String())
+	assert.Equal(t, "4.000", conversion.Bonus.String())
+	assert.Equal(t, "4.000", store.debits[len(store.debits)-1].Amount.String())
+	assert.Equal(t, "0.000", store.debits[len(store.debits)-1].Bonus.String())
+}
+
+func TestWalletUseCases_WageringOverflowsToNextGrant(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 17, 12, 0, 0, 0, time.UTC)
+
+	store := newFakeWalletStore(25, mustParse(t, "20"))
+	uc := domain.NewWalletUseCases(store, domain.WithClock(func() time.Time { return now }))
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, uc.GrantBonus(ctx, &domain.BonusGrant{
+			WalletID:   25,
+			Amount:     mustParse(t, "5"),
+			Multiplier: 1,
+			ExpiresAt:  now.Add(time.Hour),
+		}))
+	}
+
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "8")}))
+
+	assert.Equal(t, domain.BonusCompleted, store.grants[0].Status)
+	assert.Equal(t, domain.BonusActive, store.grants[1].Status)
+	assert.Equal(t, "3.000", store.grants[1].Wagered.String())
+
+	// Only the completed grant's own amount converts.
+	assert.Equal(t, "17.000", store.amount.String())
+	assert.Equal(t, "5.000", store.bonus.String())
+}
+
+func TestWalletUseCases_BonusExpires(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 17, 12, 0, 0, 0, time.UTC)
+
+	store := newFakeWalletStore(25, mustParse(t, "10"))
+	uc := domain.NewWalletUseCases(store, domain.WithClock(func() time.Time { return now }))
+
+	require.NoError(t, uc.GrantBonus(ctx, &domain.BonusGrant{
+		WalletID:   25,
+		Amount:     mustParse(t, "5"),
+		Multiplier: 30,
+		ExpiresAt:  now.Add(time.Hour),
+	}))
+
+	now = now.Add(time.Hour)
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: 25, Amount: mustParse(t, "1")}))
+
+	assert.Equal(t, domain.BonusExpired, store.grants[0].Status)
+	assert.Equal(t, "0.000", store.grants[0].Wagered.String(), "expired grants do not take stakes")
+	assert.Equal(t, "9.000", store.amount.String())
+	assert.Equal(t, "0.000", store.bonus.String())
+}
+
+func TestWalletUseCases_WithdrawalForfeitsBonus(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		withdrawal string
+		wantErr    error
+		wantReal   string
+		wantBonus  string
+		wantStatus domain.BonusStatus
+	}{
+		{
+			name:       "paid from real money",
+			withdrawal: "4",
+			wantReal:   "6.000",
+			wantBonus:  "0.000",
+			wantStatus: domain.BonusForfeited,
+		},
+		{
+			name:       "bonus money cannot be withdrawn",
+			withdrawal: "12",
+			wantErr:    domain.ErrInsufficientFunds,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeWalletStore(25, mustParse(t, "10"))
+			uc := domain.NewWalletUseCases(store, domain.WithClock(func() time.Time { return now }))
+
+			require.NoError(t, uc.GrantBonus(ctx, &domain.BonusGrant{
+				WalletID:   25,
+				Amount:     mustParse(t, "5"),
+				Multiplier: 30,
+				ExpiresAt:  now.Add(time.Hour),
+			}))
+
+			err := uc.CreditMoney(ctx, &domain.CreditEntry{
+				WalletID:     25,
+				Amount:       mustParse(t, tt.withdrawal),
+				EntryDetails: domain.EntryDetails{Operation: domain.OperationWithdrawal},
+			})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				// The store is not transactional, so only the outcome
+				// reported to the caller is checked.
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantReal, store.amount.String())
+			assert.Equal(t, tt.wantBonus, store.bonus.String())
+			assert.Equal(t, tt.wantStatus, store.grants[0].Status)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+var (
+	ErrWithdrawalNotFound = errors.New("withdrawal not found")
+	ErrInvalidTransition  = errors.New("invalid withdrawal transition")
+)
+
+// ActorSystem is the actor of transitions made by the wallet itself, such as
+// automatic approval.
+const ActorSystem = "system"
+
+type WithdrawalStatus string
+
+const (
+	WithdrawalRequested     WithdrawalStatus = "requested"
+	WithdrawalPendingReview WithdrawalStatus = "pending_review"
+	WithdrawalApproved      WithdrawalStatus = "approved"
+	// WithdrawalSent is a withdrawal paid out to the player.
+	WithdrawalSent WithdrawalStatus = "sent"
+	// WithdrawalFailed is a payout that did not go through. The held funds
+	// are back in the wallet.
+	WithdrawalFailed WithdrawalStatus = "failed"
+	// WithdrawalCancelled is a withdrawal cancelled by the player or rejected
+	// on review. The held funds are back in the wallet.
+	WithdrawalCancelled WithdrawalStatus = "cancelled"
+)
+
+// withdrawalTransitions lists the statuses each status may change to.
+var withdrawalTransitions = map[WithdrawalStatus][]WithdrawalStatus{
+	WithdrawalRequested:     {WithdrawalPendingReview, WithdrawalApproved, WithdrawalCancelled},
+	WithdrawalPendingReview: {WithdrawalApproved, WithdrawalCancelled},
+	WithdrawalApproved:      {WithdrawalSent, WithdrawalFailed},
+	WithdrawalSent:          {WithdrawalFailed},
+}
+
+// CanChangeTo tells whether a withdrawal in status s may change to status to.
+func (s WithdrawalStatus) CanChangeTo(to WithdrawalStatus) bool {
+	for _, next := range withdrawalTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// releasesFunds tells whether the held funds go back to the wallet when a
+// withdrawal changes to s.
+func (s WithdrawalStatus) releasesFunds() bool {
+	return s == WithdrawalFailed || s == WithdrawalCancelled
+}
+
+// Withdrawal is a cash-out that goes through review before it is paid out.
+// The amount is held, i.e. taken from the balance, when the withdrawal is
+// requested. ID, CreatedAt and UpdatedAt are set by the store.
+type Withdrawal struct {
+	ID       int
+	WalletID int
+	Amount   money.Money
+	Status   WithdrawalStatus
+	// HoldEntryID is the credit entry that holds the amount.
+	HoldEntryID int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// WithdrawalTransition is an audit record of a status change. An empty From
+// marks the request itself.
+type WithdrawalTransition struct {
+	WithdrawalID int
+	WalletID     int
+	From         WithdrawalStatus
+	To           WithdrawalStatus
+	Actor        string
+	Reason       string
+	At           time.Time
+}
+
+// RequestWithdrawal holds withdrawal.Amount and puts the withdrawal up for
+// review. Withdrawals below the auto-approval limit are approved at once.
+// The transitions made are returned in order.
+func (c WalletUseCases) RequestWithdrawal(ctx context.Context, withdrawal *Withdrawal, actor string) ([]WithdrawalTransition, error) {
+	if actor == "" {
+		return nil, ErrInvalidTransition
+	}
+
+	hold := CreditEntry{
+		WalletID:     withdrawal.WalletID,
+		Amount:       withdrawal.Amount,
+		EntryDetails: EntryDetails{Operation: OperationWithdrawal, Initiator: InitiatorUser},
+	}
+	if err := c.CreditMoney(ctx, &hold); err != nil {
+		return nil, err
+	}
+
+	withdrawal.Status = WithdrawalRequested
+	withdrawal.HoldEntryID = hold.ID
+	if err := c.storage.AddWithdrawal(ctx, withdrawal); err != nil {
+		return nil, fmt.Errorf("add withdrawal: %w", err)
+	}
+
+	requested, err := c.addWithdrawalTransition(ctx, withdrawal, "", actor, "")
+	if err != nil {
+		return nil, err
+	}
+
+	next, reason := WithdrawalPendingReview, "above auto-approval limit"
+	if withdrawal.Amount.Cmp(c.autoApproval) < 0 {
+		next, reason = WithdrawalApproved, "below auto-approval limit"
+	}
+	reviewed, err := c.changeWithdrawal(ctx, withdrawal, next, InitiatorService, ActorSystem, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return []WithdrawalTransition{requested, reviewed}, nil
+}
+
+// TransitionWithdrawal changes the status of a withdrawal of the wallet. The
+// held funds go back to the wallet when the withdrawal fails or is cancelled,
+// in an entry made on behalf of initiator.
+func (c WalletUseCases) TransitionWithdrawal(
+	ctx context.Context,
+	walletID, withdrawalID int,
+	to WithdrawalStatus,
+	initiator Initiator,
+	actor, reason string,
+) (*Withdrawal, WithdrawalTransition, error) {
+	if actor == "" || !initiator.Valid() {
+		return nil, WithdrawalTransition{}, ErrInvalidTransition
+	}
+
+	withdrawal, err := c.GetWithdrawal(ctx, walletID, withdrawalID)
+	if err != nil {
+		return nil, WithdrawalTransition{}, err
+	}
+
+	transition, err := c.changeWithdrawal(ctx, withdrawal, to, initiator, actor, reason)
+	if err != nil {
+		return nil, WithdrawalTransition{}, err
+	}
+
+	return withdrawal, transition, nil
+}
+
+func (c WalletUseCases) GetWithdrawal(ctx context.Context, walletID, withdrawalID int) (*Withdrawal, error) {
+	withdrawal, err := c.storage.GetWithdrawal(ctx, withdrawalID)
+	if err != nil {
+		return nil, fmt.Errorf("get withdrawal: %w", err)
+	}
+	if withdrawal.WalletID != walletID {
+		return nil, ErrWithdrawalNotFound
+	}
+	return withdrawal, nil
+}
+
+// WithdrawalHistory returns the transitions of a withdrawal of the wallet in
+// the order they were made.
+func (c WalletUseCases) WithdrawalHistory(ctx context.Context, walletID, withdrawalID int) ([]WithdrawalTransition, error) {
+	if _, err := c.GetWithdrawal(ctx, walletID, withdrawalID); err != nil {
+		return nil, err
+	}
+	return c.storage.ListWithdrawalTransitions(ctx, withdrawalID)
+}
+
+// ListWithdrawals returns withdrawals of the wallet, oldest first.
+func (c WalletUseCases) ListWithdrawals(ctx context.Context, walletID int) ([]Withdrawal, error) {
+	if _, err := c.storage.GetBalance(ctx, walletID); err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+	return c.storage.ListWithdrawals(ctx, walletID)
+}
+
+func (c WalletUseCases) changeWithdrawal(
+	ctx context.Context,
+	withdrawal *Withdrawal,
+	to WithdrawalStatus,
+	initiator Initiator,
+	actor, reason string,
+) (WithdrawalTransition, error) {
+	from := withdrawal.Status
+	if !from.CanChangeTo(to) {
+		return WithdrawalTransition{}, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+
+	if to.releasesFunds() {
+		if err := c.addMoney(ctx, &DebitEntry{
+			WalletID:     withdrawal.WalletID,
+			Amount:       withdrawal.Amount,
+			EntryDetails: EntryDetails{Operation: OperationWithdrawalRelease, Initiator: initiator},
+		}); err != nil {
+			return WithdrawalTransition{}, fmt.Errorf("release funds: %w", err)
+		}
+	}
+
+	withdrawal.Status = to
+	if err := c.storage.SaveWithdrawal(ctx, withdrawal); err != nil {
+		return WithdrawalTransition{}, fmt.Errorf("save withdrawal: %w", err)
+	}
+
+	return c.addWithdrawalTransition(ctx, withdrawal, from, actor, reason)
+}
+
+func (c WalletUseCases) addWithdrawalTransition(
+	ctx context.Context,
+	withdrawal *Withdrawal,
+	from WithdrawalStatus,
+	actor, reason string,
+) (WithdrawalTransition, error) {
+	transition := WithdrawalTransition{
+		WithdrawalID: withdrawal.ID,
+		WalletID:     withdrawal.WalletID,
+		From:         from,
+		To:           withdrawal.Status,
+		Actor:        actor,
+		Reason:       reason,
+		At:           c.now(),
+	}
+	if err := c.storage.AddWithdrawalTransition(ctx, &transition); err != nil {
+		return WithdrawalTransition{}, fmt.Errorf("add withdrawal transition: %w", err)
+	}
+	return transition, nil
+}
@@ -0,0 +1,160 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithdrawalStatus_CanChangeTo(t *testing.T) {
+	tests := []struct {
+		from domain.WithdrawalStatus
+		to   domain.WithdrawalStatus
+		want bool
+	}{
+		{from: domain.WithdrawalRequested, to: domain.WithdrawalPendingReview, want: true},
+		{from: domain.WithdrawalRequested, to: domain.WithdrawalApproved, want: true},
+		{from: domain.WithdrawalPendingReview, to: domain.WithdrawalApproved, want: true},
+		{from: domain.WithdrawalPendingReview, to: domain.WithdrawalCancelled, want: true},
+		{from: domain.WithdrawalPendingReview, to: domain.WithdrawalSent, want: false},
+		{from: domain.WithdrawalApproved, to: domain.WithdrawalSent, want: true},
+		{from: domain.WithdrawalApproved, to: domain.WithdrawalCancelled, want: false},
+		{from: domain.WithdrawalSent, to: domain.WithdrawalFailed, want: true},
+		{from: domain.WithdrawalFailed, to: domain.WithdrawalApproved, want: false},
+		{from: domain.WithdrawalCancelled, to: domain.WithdrawalApproved, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.from.CanChangeTo(tt.to))
+		})
+	}
+}
+
+func TestWalletUseCases_RequestWithdrawal(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     string
+		wantErr    error
+		wantStatus domain.WithdrawalStatus
+	}{
+		{name: "below auto-approval limit", amount: "49.999", wantStatus: domain.WithdrawalApproved},
+		{name: "at auto-approval limit", amount: "50", wantStatus: domain.WithdrawalPendingReview},
+		{name: "insufficient funds", amount: "100.001", wantErr: domain.ErrInsufficientFunds},
+		{name: "no amount", amount: "0", wantErr: domain.ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeWalletStore(25, mustParse(t, "100"))
+			uc := domain.NewWalletUseCases(store, domain.WithWithdrawalAutoApproval(mustParse(t, "50")))
+
+			withdrawal := domain.Withdrawal{WalletID: 25, Amount: mustParse(t, tt.amount)}
+			transitions, err := uc.RequestWithdrawal(context.Background(), &withdrawal, "player")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, store.withdrawals)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantStatus, withdrawal.Status)
+			require.Len(t, store.credits, 1, "the amount is held")
+			assert.Equal(t, domain.OperationWithdrawal, store.credits[0].Operation)
+			assert.Equal(t, domain.InitiatorUser, store.credits[0].Initiator)
+			assert.Equal(t, store.credits[0].ID, withdrawal.HoldEntryID)
+			assert.Equal(t, mustParse(t, "100").Sub(withdrawal.Amount).String(), store.amount.String())
+
+			require.Len(t, transitions, 2)
+			assert.Equal(t, domain.WithdrawalStatus(""), transitions[0].From)
+			assert.Equal(t, domain.WithdrawalRequested, transitions[0].To)
+			assert.Equal(t, "player", transitions[0].Actor)
+			assert.Equal(t, domain.WithdrawalRequested, transitions[1].From)
+			assert.Equal(t, tt.wantStatus, transitions[1].To)
+			assert.Equal(t, domain.ActorSystem, transitions[1].Actor)
+			assert.Equal(t, transitions, store.transitions)
+		})
+	}
+}
+
+func TestWalletUseCases_TransitionWithdrawal(t *testing.T) {
+	tests := []struct {
+		name      string
+		steps     []domain.WithdrawalStatus
+		wantErr   error
+		wantFunds string
+	}{
+		{
+			name:      "sent",
+			steps:     []domain.WithdrawalStatus{domain.WithdrawalApproved, domain.WithdrawalSent},
+			wantFunds: "40.000",
+		},
+		{
+			name:      "rejected on review",
+			steps:     []domain.WithdrawalStatus{domain.WithdrawalCancelled},
+			wantFunds: "100.000",
+		},
+		{
+			name:      "payout failed",
+			steps:     []domain.WithdrawalStatus{domain.WithdrawalApproved, domain.WithdrawalSent, domain.WithdrawalFailed},
+			wantFunds: "100.000",
+		},
+		{
+			name:    "sent without approval",
+			steps:   []domain.WithdrawalStatus{domain.WithdrawalSent},
+			wantErr: domain.ErrInvalidTransition,
+		},
+		{
+			name:    "cancelled twice",
+			steps:   []domain.WithdrawalStatus{domain.WithdrawalCancelled, domain.WithdrawalCancelled},
+			wantErr: domain.ErrInvalidTransition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newFakeWalletStore(25, mustParse(t, "100"))
+			uc := domain.NewWalletUseCases(store)
+
+			withdrawal := domain.Withdrawal{WalletID: 25, Amount: mustParse(t, "60")}
+			_, err := uc.RequestWithdrawal(ctx, &withdrawal, "player")
+			require.NoError(t, err)
+			require.Equal(t, domain.WithdrawalPendingReview, withdrawal.Status)
+
+			for i, to := range tt.steps {
+				got, transition, err := uc.TransitionWithdrawal(ctx, 25, withdrawal.ID, to, domain.InitiatorAdmin, "support", "checked")
+				if tt.wantErr != nil && i == len(tt.steps)-1 {
+					require.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, to, got.Status)
+				assert.Equal(t, to, transition.To)
+				assert.Equal(t, "support", transition.Actor)
+			}
+
+			assert.Equal(t, tt.wantFunds, store.amount.String())
+			for _, release := range store.debits {
+				assert.Equal(t, domain.OperationWithdrawalRelease, release.Operation)
+				assert.Equal(t, domain.InitiatorAdmin, release.Initiator)
+			}
+
+			history, err := uc.WithdrawalHistory(ctx, 25, withdrawal.ID)
+			require.NoError(t, err)
+			assert.Len(t, history, 2+len(tt.steps))
+		})
+	}
+
+	t.Run("withdrawal of another wallet", func(t *testing.T) {
+		store := newFakeWalletStore(25, mustParse(t, "100"))
+		uc := domain.NewWalletUseCases(store)
+
+		withdrawal := domain.Withdrawal{WalletID: 25, Amount: mustParse(t, "60")}
+		_, err := uc.RequestWithdrawal(context.Background(), &withdrawal, "player")
+		require.NoError(t, err)
+
+		_, _, err = uc.TransitionWithdrawal(context.Background(), 26, withdrawal.ID, domain.WithdrawalApproved, domain.InitiatorAdmin, "support", "")
+		require.ErrorIs(t, err, domain.ErrWithdrawalNotFound)
+	})
+}
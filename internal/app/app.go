@@ -4,8 +4,11 @@ import (
 	"net/http"
 
 	"github.com/pprishchepa/go-casino-example/internal/config"
+	grpcv1 "github.com/pprishchepa/go-casino-example/internal/controller/grpc/v1"
 	httpctrl "github.com/pprishchepa/go-casino-example/internal/controller/http"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/payments"
 	httpv1 "github.com/pprishchepa/go-casino-example/internal/controller/http/v1"
+	httpv2 "github.com/pprishchepa/go-casino-example/internal/controller/http/v2"
 	"github.com/pprishchepa/go-casino-example/internal/pkg/fxlog"
 	"github.com/pprishchepa/go-casino-example/internal/service"
 	"github.com/pprishchepa/go-casino-example/internal/storage/postgres"
@@ -15,34 +18,101 @@ import (
 	"go.uber.org/automaxprocs/maxprocs"
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxevent"
+	"google.golang.org/grpc"
 )
 
-func New() *fx.App {
+// ServeOptions change how the server starts.
+type ServeOptions struct {
+	// NoMigrate skips applying the migrations on start, for deployments that
+	// migrate in a step of their own.
+	NoMigrate bool
+}
+
+func New(conf config.Config, opts ServeOptions) *fx.App {
 	return fx.New(
+		fx.Supply(conf),
 		fx.Provide(
-			config.NewConfig,
 			newLogger,
 			newPostgresClient,
 			newRedisClient,
 			redis.NewWalletCacheStore,
 			postgres.NewWalletStoreTxFactory,
 			newWalletStoreTxFactory,
-			service.NewWalletService,
+			postgres.NewBatchStore,
+			service.NewBalanceBroker,
+			newBalancePubSub,
+			redis.NewWithdrawalPublisher,
+			postgres.NewWithdrawalOutboxStore,
+			newWithdrawalRelay,
+			newWalletService,
+			newBatchService,
+			newSnapshotService,
+			service.NewStatementService,
+			postgres.NewReportStore,
+			newReportService,
+			postgres.NewPartitionStore,
+			newPartitionService,
 			httpv1.NewWalletRoutes,
+			newBatchRoutes,
+			newBalanceStreamRoutes,
+			httpv1.NewStatementRoutes,
+			httpv2.NewWalletRoutes,
+			httpv2.NewBonusRoutes,
+			httpv2.NewLimitRoutes,
+			httpv2.NewExclusionRoutes,
+			httpv2.NewWithdrawalRoutes,
+			httpv2.NewAdminRoutes,
+			httpv2.NewReportRoutes,
+			newPaymentProviders,
+			payments.NewCallbackRoutes,
 			httpctrl.NewRouter,
 			newHTTPServer,
+			grpcv1.NewWalletServer,
+			newGRPCServer,
 			func(v *service.WalletService) httpv1.WalletService { return v },
+			func(v *service.StatementService) httpv1.StatementService { return v },
+			func(v *service.WalletService) httpv2.WalletService { return v },
+			func(v *service.WalletService) httpv2.BonusService { return v },
+			func(v *service.WalletService) httpv2.LimitService { return v },
+			func(v *service.WalletService) httpv2.ExclusionService { return v },
+			func(v *service.WalletService) httpv2.WithdrawalService { return v },
+			func(v *service.WalletService) httpv2.AdminService { return v },
+			func(v *service.ReportService) httpv2.ReportService { return v },
+			func(v *service.WalletService) payments.DepositService { return v },
+			func(v *service.WalletService) grpcv1.WalletService { return v },
+			func(v *service.BalanceBroker) grpcv1.BalanceWatcher { return v },
+			func(v *service.BalanceBroker) httpv1.BalanceWatcher { return v },
+			func(v *service.BalanceBroker) redis.BalanceNotifier { return v },
+			func(v *redis.BalancePubSub) service.BalanceNotifier { return v },
+			func(v *redis.WithdrawalPublisher) service.WithdrawalPublisher { return v },
+			func(v *postgres.WithdrawalOutboxStore) service.WithdrawalOutbox { return v },
 			func(v *redis.WalletCacheStore) service.WalletCacheStore { return v },
+			func(v *postgres.ReportStore) service.ReportStore { return v },
+			func(v *postgres.PartitionStore) service.PartitionStore { return v },
 		),
-		fx.WithLogger(func(logger zerolog.Logger) fxevent.Logger {
-			return fxlog.NewZerologAdapter(logger.With().Str("logger", "fx").Logger())
-		}),
+		fx.WithLogger(newFxLogger),
 		fx.Invoke(automaxprocs),
-		fx.Invoke(migrate),
+		migrateOnStart(opts),
 		fx.Invoke(func(*http.Server) {}),
+		fx.Invoke(func(*grpc.Server) {}),
+		fx.Invoke(func(*service.SnapshotService) {}),
+		fx.Invoke(func(*service.ReportService) {}),
+		fx.Invoke(func(*service.PartitionService) {}),
+		fx.Invoke(func(*service.WithdrawalRelay) {}),
 	)
 }
 
+func migrateOnStart(opts ServeOptions) fx.Option {
+	if opts.NoMigrate {
+		return fx.Options()
+	}
+	return fx.Invoke(migrate)
+}
+
+func newFxLogger(logger zerolog.Logger) fxevent.Logger {
+	return fxlog.NewZerologAdapter(logger.With().Str("logger", "fx").Logger())
+}
+
 func automaxprocs() error {
 	_, err := maxprocs.Set(maxprocs.Logger(func(s string, i ...interface{}) {
 		log.Info().Str("logger", "automaxprocs").Msgf(s, i...)
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+)
+
+// VerifyAudit verifies the audit log of every wallet against the ledger and
+// logs what it finds. It fails if anything does not check out.
+func VerifyAudit(ctx context.Context, conf config.Config) error {
+	svc, stop, err := startAuditService(ctx, conf)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	reports, err := svc.VerifyAudit(ctx)
+	if err != nil {
+		return fmt.Errorf("verify audit: %w", err)
+	}
+
+	var records, violations int
+	for _, report := range reports {
+		for _, v := range report.Violations {
+			log.Error().
+				Int("walletId", report.WalletID).
+				Int("seq", v.Seq).
+				Int("entryId", v.EntryID).
+				Msg(v.Problem)
+		}
+		// Heads are logged so that they can be kept elsewhere and compared
+		// with the next run.
+		log.Debug().
+			Int("walletId", report.WalletID).
+			Int("records", report.Records).
+			Str("head", hex.EncodeToString(report.Head)).
+			Msg("wallet verified")
+		records += report.Records
+		violations += len(report.Violations)
+	}
+
+	log.Info().
+		Int("wallets", len(reports)).
+		Int("records", records).
+		Int("violations", violations).
+		Msg("audit log verified")
+
+	if violations > 0 {
+		return fmt.Errorf("%w: audit log has %d violations", ErrInconsistent, violations)
+	}
+	return nil
+}
+
+// Reconcile checks that the balance of every wallet equals the sum of its
+// ledger and that the audit log matches the ledger, and logs what it finds.
+// It fails with ErrInconsistent if any wallet does not check out.
+func Reconcile(ctx context.Context, conf config.Config) error {
+	svc, stop, err := startAuditService(ctx, conf)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	reconciliations, err := svc.Reconcile(ctx)
+	if err != nil {
+		return fmt.Errorf("reconcile: %w", err)
+	}
+
+	var entries, inconsistent int
+	for _, r := range reconciliations {
+		entries += r.Entries
+		if r.Consistent() {
+			continue
+		}
+		inconsistent++
+		if !r.Balanced() {
+			log.Error().
+				Int("walletId", r.WalletID).
+				Str("balance", r.Balance.Amount.String()).
+				Str("ledger", r.Ledger.Amount.String()).
+				Str("bonusBalance", r.Balance.Bonus.String()).
+				Str("bonusLedger", r.Ledger.Bonus.String()).
+				Msg("balance does not match ledger")
+		}
+		for _, v := range r.Audit.Violations {
+			log.Error().
+				Int("walletId", r.WalletID).
+				Int("seq", v.Seq).
+				Int("entryId", v.EntryID).
+				Msg(v.Problem)
+		}
+	}
+
+	log.Info().
+		Int("wallets", len(reconciliations)).
+		Int("entries", entries).
+		Int("inconsistent", inconsistent).
+		Msg("ledger reconciled")
+
+	if inconsistent > 0 {
+		return fmt.Errorf("%w: %d of %d wallets", ErrInconsistent, inconsistent, len(reconciliations))
+	}
+	return nil
+}
+
+func startAuditService(ctx context.Context, conf config.Config) (*service.AuditService, func(), error) {
+	var svc *service.AuditService
+
+	stop, err := startCommand(ctx, conf,
+		fx.Provide(
+			newPostgresClient,
+			newWalletStoreTxFactory,
+			service.NewAuditService,
+		),
+		fx.Populate(&svc),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return svc, stop, nil
+}
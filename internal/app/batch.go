@@ -0,0 +1,24 @@
+package app
+
+import (
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	httpv1 "github.com/pprishchepa/go-casino-example/internal/controller/http/v1"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/storage/postgres"
+	"go.uber.org/fx"
+)
+
+func newBatchService(
+	lc fx.Lifecycle,
+	conf config.Config,
+	wallets *service.WalletService,
+	store *postgres.BatchStore,
+) *service.BatchService {
+	svc := service.NewBatchService(wallets, store, conf.Batch.Parallelism, conf.Batch.StaleAfter)
+	lc.Append(fx.StartStopHook(svc.Start, svc.Shutdown))
+	return svc
+}
+
+func newBatchRoutes(conf config.Config, svc *service.BatchService) *httpv1.BatchRoutes {
+	return httpv1.NewBatchRoutes(svc, conf.Batch.MaxItems)
+}
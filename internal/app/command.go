@@ -0,0 +1,35 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+)
+
+// ErrInconsistent is returned by the commands that check the ledger when the
+// check finds something wrong, as opposed to failing to run.
+var ErrInconsistent = errors.New("inconsistencies found")
+
+// startCommand starts an app of the given options for a command that does its
+// job and exits, unlike the server. Call stop when the job is done.
+func startCommand(ctx context.Context, conf config.Config, options ...fx.Option) (stop func(), err error) {
+	app := fx.New(
+		fx.Supply(conf),
+		fx.Provide(newLogger),
+		fx.WithLogger(newFxLogger),
+		fx.Options(options...),
+	)
+	if err := app.Start(ctx); err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+
+	return func() {
+		if err := app.Stop(context.WithoutCancel(ctx)); err != nil {
+			log.Warn().Err(err).Msg("could not stop")
+		}
+	}, nil
+}
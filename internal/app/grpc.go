@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	grpcctrl "github.com/pprishchepa/go-casino-example/internal/controller/grpc"
+	grpcv1 "github.com/pprishchepa/go-casino-example/internal/controller/grpc/v1"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+	"google.golang.org/grpc"
+)
+
+func newGRPCServer(lc fx.Lifecycle, conf config.Config, wallet *grpcv1.WalletServer) *grpc.Server {
+	srv := grpcctrl.NewServer(conf, wallet)
+	addr := fmt.Sprintf(":%d", conf.GRPC.Port)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			l, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			log.Info().Msgf("starting gRPC server on %s", addr)
+			go func() {
+				if err := srv.Serve(l); err != nil {
+					log.Error().Err(err).Msg("gRPC server failed")
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stopped := make(chan struct{})
+			go func() {
+				srv.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+				// Streaming calls never finish on their own.
+				srv.Stop()
+			}
+			return nil
+		},
+	})
+
+	return srv
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/pgxmigrator"
+	"github.com/pprishchepa/go-casino-example/migrations"
+	"go.uber.org/fx"
+)
+
+// MigrateUp applies all the migrations not applied yet.
+func MigrateUp(ctx context.Context, conf config.Config) error {
+	return withDB(ctx, conf, func(db *pgxpool.Pool) error {
+		return pgxmigrator.NewMigrator().Up(db, migrations.FS)
+	})
+}
+
+// MigrateDown rolls back the last steps migrations, all of them if steps is
+// zero.
+func MigrateDown(ctx context.Context, conf config.Config, steps int) error {
+	return withDB(ctx, conf, func(db *pgxpool.Pool) error {
+		return pgxmigrator.NewMigrator().Down(db, migrations.FS, steps)
+	})
+}
+
+// MigrateForce sets the migration version and clears the dirty flag without
+// running any migration, after a failed migration has been fixed by hand.
+func MigrateForce(ctx context.Context, conf config.Config, version int) error {
+	return withDB(ctx, conf, func(db *pgxpool.Pool) error {
+		return pgxmigrator.NewMigrator().Force(db, migrations.FS, version)
+	})
+}
+
+// MigrateStatus returns where the database is in the migrations.
+func MigrateStatus(ctx context.Context, conf config.Config) (pgxmigrator.Status, error) {
+	var status pgxmigrator.Status
+	err := withDB(ctx, conf, func(db *pgxpool.Pool) error {
+		var err error
+		status, err = pgxmigrator.NewMigrator().Status(db, migrations.FS)
+		return err
+	})
+	return status, err
+}
+
+func withDB(ctx context.Context, conf config.Config, fn func(db *pgxpool.Pool) error) error {
+	var db *pgxpool.Pool
+
+	stop, err := startCommand(ctx, conf,
+		fx.Provide(newPostgresClient),
+		fx.Populate(&db),
+	)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	return fn(db)
+}
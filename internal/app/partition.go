@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/storage/postgres"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+)
+
+func newPartitionService(lc fx.Lifecycle, conf config.Config, store service.PartitionStore) *service.PartitionService {
+	svc := service.NewPartitionService(store, conf.Partition.Interval, conf.Partition.Ahead,
+		conf.Partition.ArchiveAfter, conf.Partition.ArchiveDir)
+	lc.Append(fx.StartStopHook(svc.Start, svc.Shutdown))
+	return svc
+}
+
+// ArchiveEntries archives the ledger partitions of the months before the month
+// of before, whether or not archiving is turned on, and exports them to the
+// archive dir of the config.
+func ArchiveEntries(ctx context.Context, conf config.Config, before time.Time) error {
+	var svc *service.PartitionService
+
+	stop, err := startCommand(ctx, conf,
+		fx.Provide(
+			newPostgresClient,
+			postgres.NewPartitionStore,
+			func(conf config.Config, store *postgres.PartitionStore) *service.PartitionService {
+				// Not started, so the interval does not matter.
+				return service.NewPartitionService(store, time.Hour, conf.Partition.Ahead,
+					conf.Partition.ArchiveAfter, conf.Partition.ArchiveDir)
+			},
+		),
+		fx.Populate(&svc),
+	)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	archived, err := svc.Archive(ctx, before, time.Now())
+	if err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+
+	log.Info().
+		Int("partitions", len(archived)).
+		Str("before", before.Format("2006-01")).
+		Msg("entries archived")
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package app
+
+import (
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/payments"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/payments/fakeprovider"
+	"github.com/rs/zerolog/log"
+)
+
+func newPaymentProviders(conf config.Config) []payments.Provider {
+	var providers []payments.Provider
+	if conf.Payments.FakeSecret != "" {
+		log.Warn().Msg("fake payment provider is enabled")
+		providers = append(providers, fakeprovider.New(conf.Payments.FakeSecret))
+	}
+	return providers
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	storage "github.com/pprishchepa/go-casino-example/internal/storage/redis"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 	"go.uber.org/fx"
@@ -33,3 +35,31 @@ type redisLoggerAdapter struct{}
 func (r *redisLoggerAdapter) Printf(_ context.Context, format string, v ...interface{}) {
 	log.Warn().Msg(fmt.Sprintf(format, v...))
 }
+
+func newBalancePubSub(lc fx.Lifecycle, ring *redis.Ring, broker *service.BalanceBroker) *storage.BalancePubSub {
+	pubsub := storage.NewBalancePubSub(ring, broker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				pubsub.Run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+
+	return pubsub
+}
@@ -0,0 +1,13 @@
+package app
+
+import (
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"go.uber.org/fx"
+)
+
+func newReportService(lc fx.Lifecycle, conf config.Config, store service.ReportStore) *service.ReportService {
+	svc := service.NewReportService(store, conf.Report.Interval, conf.Report.Delay)
+	lc.Append(fx.StartStopHook(svc.Start, svc.Shutdown))
+	return svc
+}
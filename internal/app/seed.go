@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/storage/postgres"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+)
+
+// Seed creates count wallets of the brand in the currency with amount
+// deposited to each, for tests and demos.
+func Seed(ctx context.Context, conf config.Config, count int, amount money.Money, brand, currency string) error {
+	var svc *service.SeedService
+
+	stop, err := startCommand(ctx, conf,
+		fx.Provide(
+			newPostgresClient,
+			postgres.NewWalletStoreTxFactory,
+			newWalletStoreTxFactory,
+			service.NewSeedService,
+			func(v *postgres.WalletStoreTxFactory) service.WalletCreator { return v },
+		),
+		fx.Populate(&svc),
+	)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	walletIDs, err := svc.Seed(ctx, count, amount, brand, currency)
+	for _, walletID := range walletIDs {
+		log.Debug().Int("walletId", walletID).Msg("wallet seeded")
+	}
+	if err != nil {
+		return fmt.Errorf("seed: %w", err)
+	}
+
+	log.Info().
+		Int("wallets", len(walletIDs)).
+		Str("amount", amount.String()).
+		Str("brand", brand).
+		Str("currency", currency).
+		Msg("wallets seeded")
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package app
+
+import (
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"go.uber.org/fx"
+)
+
+func newSnapshotService(lc fx.Lifecycle, conf config.Config, wallets *service.WalletService) *service.SnapshotService {
+	svc := service.NewSnapshotService(wallets, conf.Snapshot.Interval, conf.Snapshot.Delay)
+	lc.Append(fx.StartStopHook(svc.Start, svc.Shutdown))
+	return svc
+}
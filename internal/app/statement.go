@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/statement"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+)
+
+// StatementsOptions says which statements GenerateStatements generates and
+// where it saves them.
+type StatementsOptions struct {
+	Period statement.Period
+	Format statement.Format
+	// Dir is the directory the files are saved to. It is created if missing.
+	Dir string
+	// WalletIDs are the wallets to generate statements of, every wallet if
+	// empty.
+	WalletIDs []int
+}
+
+// GenerateStatements saves a statement file per wallet. A wallet that fails is
+// logged and skipped, and GenerateStatements fails at the end if any did.
+func GenerateStatements(ctx context.Context, conf config.Config, opts StatementsOptions) error {
+	if err := opts.Period.Validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return fmt.Errorf("create dir: %w", err)
+	}
+
+	var svc *service.StatementService
+
+	stop, err := startCommand(ctx, conf,
+		fx.Provide(
+			newPostgresClient,
+			newWalletStoreTxFactory,
+			service.NewStatementService,
+		),
+		fx.Populate(&svc),
+	)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	walletIDs := opts.WalletIDs
+	if len(walletIDs) == 0 {
+		if walletIDs, err = svc.ListWalletIDs(ctx); err != nil {
+			return fmt.Errorf("list wallet ids: %w", err)
+		}
+	}
+
+	var failed int
+	for _, walletID := range walletIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		path, err := saveStatement(ctx, svc, walletID, opts)
+		if err != nil {
+			log.Error().Err(err).Int("walletId", walletID).Msg("could not generate statement")
+			failed++
+			continue
+		}
+		log.Debug().Int("walletId", walletID).Str("path", path).Msg("statement generated")
+	}
+
+	log.Info().
+		Int("wallets", len(walletIDs)).
+		Int("failed", failed).
+		Time("from", opts.Period.From).
+		Time("to", opts.Period.To).
+		Msg("statements generated")
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d statements failed", failed, len(walletIDs))
+	}
+	return nil
+}
+
+func saveStatement(ctx context.Context, svc *service.StatementService, walletID int, opts StatementsOptions) (string, error) {
+	st, err := svc.Statement(ctx, walletID, opts.Period)
+	if err != nil {
+		return "", fmt.Errorf("statement: %w", err)
+	}
+
+	path := filepath.Join(opts.Dir, st.FileName(opts.Format))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("create file: %w", err)
+	}
+
+	// A partly written file would pass for a statement, so it is removed.
+	if err := errors.Join(st.Render(f, opts.Format), f.Close()); err != nil {
+		_ = os.Remove(path)
+		return "", fmt.Errorf("render: %w", err)
+	}
+
+	return path, nil
+}
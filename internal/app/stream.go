@@ -0,0 +1,15 @@
+package app
+
+import (
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	httpv1 "github.com/pprishchepa/go-casino-example/internal/controller/http/v1"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+)
+
+func newBalanceStreamRoutes(
+	conf config.Config,
+	wallets *service.WalletService,
+	broker *service.BalanceBroker,
+) *httpv1.BalanceStreamRoutes {
+	return httpv1.NewBalanceStreamRoutes(wallets, broker, conf.Stream.Heartbeat)
+}
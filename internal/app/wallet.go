@@ -2,8 +2,12 @@ package app
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/config"
 	"github.com/pprishchepa/go-casino-example/internal/service"
 	"github.com/pprishchepa/go-casino-example/internal/storage/postgres"
 )
@@ -20,3 +24,44 @@ func (f walletStoreTxFactory) NewTx(ctx context.Context) (service.WalletStoreTx,
 func newWalletStoreTxFactory(db *pgxpool.Pool) service.WalletStoreTxFactory {
 	return &walletStoreTxFactory{factory: postgres.NewWalletStoreTxFactory(db)}
 }
+
+func newWalletService(
+	conf config.Config,
+	txFactory service.WalletStoreTxFactory,
+	cache service.WalletCacheStore,
+	notifier service.BalanceNotifier,
+) (*service.WalletService, error) {
+	spendOrder, err := domain.ParseSpendOrder(conf.Wallet.SpendOrder)
+	if err != nil {
+		return nil, fmt.Errorf("parse wallet spend order: %w", err)
+	}
+
+	weights := make(domain.ContributionWeights, len(conf.Wallet.WageringWeights))
+	for gameType, weight := range conf.Wallet.WageringWeights {
+		if weight < 0 || weight > 100 {
+			return nil, fmt.Errorf("wagering weight of %q must be between 0 and 100", gameType)
+		}
+		weights[domain.GameType(gameType)] = weight
+	}
+
+	autoApprove, err := money.Parse(conf.Wallet.WithdrawalAutoApprove)
+	if err != nil {
+		return nil, fmt.Errorf("parse withdrawal auto-approval limit: %w", err)
+	}
+	if autoApprove.IsNegative() {
+		return nil, fmt.Errorf("withdrawal auto-approval limit must not be negative")
+	}
+
+	reversalPolicy, err := domain.ParseReversalPolicy(conf.Wallet.ReversalPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("parse wallet reversal policy: %w", err)
+	}
+
+	return service.NewWalletService(txFactory, cache, notifier,
+		domain.WithSpendOrder(spendOrder),
+		domain.WithContributionWeights(weights),
+		domain.WithLimitCoolingOff(conf.Wallet.LimitCoolingOff),
+		domain.WithWithdrawalAutoApproval(autoApprove),
+		domain.WithReversalPolicy(reversalPolicy),
+	), nil
+}
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"go.uber.org/fx"
+)
+
+func newWithdrawalRelay(
+	lc fx.Lifecycle,
+	conf config.Config,
+	outbox service.WithdrawalOutbox,
+	publisher service.WithdrawalPublisher,
+) *service.WithdrawalRelay {
+	svc := service.NewWithdrawalRelay(outbox, publisher, conf.Withdrawal.RelayInterval)
+	lc.Append(fx.StartStopHook(svc.Start, svc.Shutdown))
+	return svc
+}
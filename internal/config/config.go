@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/hashicorp/go-envparse"
 	"github.com/sethvargo/go-envconfig"
@@ -14,6 +15,10 @@ type Config struct {
 		Port int `env:"HTTP_PORT, default=8081"`
 	}
 
+	GRPC struct {
+		Port int `env:"GRPC_PORT, default=9091"`
+	}
+
 	Auth struct {
 		JWTSecret string `env:"JWT_SECRET, default=CHANGE_ME"`
 	}
@@ -23,6 +28,78 @@ type Config struct {
 		Pretty bool   `env:"LOG_PRETTY, default=false"`
 	}
 
+	Stream struct {
+		Heartbeat time.Duration `env:"STREAM_HEARTBEAT, default=15s"`
+	}
+
+	Wallet struct {
+		// SpendOrder is either bonus_last or bonus_first.
+		SpendOrder string `env:"WALLET_SPEND_ORDER, default=bonus_last"`
+		// WageringWeights is the percentage of a stake on each game type that
+		// counts towards bonus wagering requirements.
+		WageringWeights map[string]int `env:"WALLET_WAGERING_WEIGHTS, default=slots:100,table:10,live:10"`
+		// LimitCoolingOff is how long raising or removing a player limit
+		// takes to apply.
+		LimitCoolingOff time.Duration `env:"WALLET_LIMIT_COOLING_OFF, default=24h"`
+		// WithdrawalAutoApprove is the amount below which withdrawals are
+		// approved without review. Zero sends every withdrawal to review.
+		WithdrawalAutoApprove string `env:"WALLET_WITHDRAWAL_AUTO_APPROVE, default=100"`
+		// ReversalPolicy is either reject or allow_negative. It decides if an
+		// entry can be reversed when the wallet no longer has the money.
+		ReversalPolicy string `env:"WALLET_REVERSAL_POLICY, default=reject"`
+	}
+
+	Payments struct {
+		// FakeSecret enables the fake payment provider, which is meant for
+		// development and tests, with the given signing secret.
+		FakeSecret string `env:"PAYMENTS_FAKE_SECRET"`
+	}
+
+	Batch struct {
+		Parallelism int `env:"BATCH_PARALLELISM, default=8"`
+		MaxItems    int `env:"BATCH_MAX_ITEMS, default=10000"`
+		// StaleAfter is how long a batch may stay pending, or running without
+		// a heartbeat from the process that runs it, before it is taken to be
+		// abandoned by a process that stopped. Running batches get a
+		// heartbeat every third of it.
+		StaleAfter time.Duration `env:"BATCH_STALE_AFTER, default=10m"`
+	}
+
+	Snapshot struct {
+		// Interval is how often balance snapshots are taken. Each run only
+		// snapshots days that ended at least Delay ago and are not
+		// snapshotted yet.
+		Interval time.Duration `env:"SNAPSHOT_INTERVAL, default=1h"`
+		Delay    time.Duration `env:"SNAPSHOT_DELAY, default=15m"`
+	}
+
+	Report struct {
+		// Interval is how often daily summaries are refreshed. Days that
+		// ended at least Delay ago are final and are only aggregated again
+		// by a rebuild.
+		Interval time.Duration `env:"REPORT_INTERVAL, default=1h"`
+		Delay    time.Duration `env:"REPORT_DELAY, default=15m"`
+	}
+
+	Partition struct {
+		// Interval is how often the monthly partitions of the ledger are
+		// maintained. Each run adds the partitions of the current month and
+		// of Ahead months after it.
+		Interval time.Duration `env:"PARTITION_INTERVAL, default=24h"`
+		Ahead    int           `env:"PARTITION_AHEAD, default=3"`
+		// ArchiveAfter is how many months before the current one stay live.
+		// Older ones are exported to ArchiveDir and archived. Zero turns
+		// archiving off.
+		ArchiveAfter int    `env:"PARTITION_ARCHIVE_AFTER, default=0"`
+		ArchiveDir   string `env:"PARTITION_ARCHIVE_DIR, default=archive"`
+	}
+
+	Withdrawal struct {
+		// RelayInterval is how often withdrawal transitions not published
+		// yet are looked for.
+		RelayInterval time.Duration `env:"WITHDRAWAL_RELAY_INTERVAL, default=1s"`
+	}
+
 	Postgres Postgres `env:", prefix=POSTGRES_"`
 	Redis    Redis    `env:", prefix=REDIS_"`
 }
@@ -0,0 +1,61 @@
+package jwt
+
+import (
+	"context"
+
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func UnaryAuthorize(secret string) grpc.UnaryServerInterceptor {
+	validator := jwtauth.NewValidator(secret)
+
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, validator)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamAuthorize(secret string) grpc.StreamServerInterceptor {
+	validator := jwtauth.NewValidator(secret)
+
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), validator)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authorize(ctx context.Context, validator *jwtauth.Validator) (context.Context, error) {
+	var authHeader string
+	if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+		authHeader = values[0]
+	}
+
+	claims, err := validator.ValidateHeader(authHeader)
+	if err != nil {
+		log.Debug().Err(err).Msg("unauthorized request")
+		return nil, status.Error(codes.Unauthenticated, codes.Unauthenticated.String())
+	}
+
+	return jwtauth.NewContext(ctx, claims), nil
+}
+
+// serverStream overrides the context of the wrapped stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,27 @@
+package grpc
+
+import (
+	walletv1 "github.com/pprishchepa/go-casino-example/api/wallet/v1"
+	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/controller/grpc/middleware/jwt"
+	"github.com/pprishchepa/go-casino-example/internal/controller/grpc/middleware/recovery"
+	grpcv1 "github.com/pprishchepa/go-casino-example/internal/controller/grpc/v1"
+	"google.golang.org/grpc"
+)
+
+func NewServer(conf config.Config, wallet *grpcv1.WalletServer) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			recovery.UnaryRecover(),
+			jwt.UnaryAuthorize(conf.Auth.JWTSecret),
+		),
+		grpc.ChainStreamInterceptor(
+			recovery.StreamRecover(),
+			jwt.StreamAuthorize(conf.Auth.JWTSecret),
+		),
+	)
+
+	walletv1.RegisterWalletServiceServer(srv, wallet)
+
+	return srv
+}
@@ -0,0 +1,203 @@
+package v1
+
+import (
+	"context"
+	"errors"
+
+	walletv1 "github.com/pprishchepa/go-casino-example/api/wallet/v1"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=wallet.go -destination=wallet_mock_test.go -package=v1_test
+
+type WalletService interface {
+	GetBalance(ctx context.Context, walletID int) (*domain.WalletBalance, error)
+	DebitMoney(ctx context.Context, entry domain.DebitEntry) (*entity.Operation, error)
+	CreditMoney(ctx context.Context, entry domain.CreditEntry) (*entity.Operation, error)
+}
+
+type BalanceWatcher interface {
+	Subscribe(walletID int) (<-chan domain.WalletBalance, func())
+}
+
+type WalletServer struct {
+	walletv1.UnimplementedWalletServiceServer
+	service WalletService
+	watcher BalanceWatcher
+}
+
+func NewWalletServer(service WalletService, watcher BalanceWatcher) *WalletServer {
+	return &WalletServer{service: service, watcher: watcher}
+}
+
+func (s *WalletServer) GetBalance(ctx context.Context, req *walletv1.GetBalanceRequest) (*walletv1.GetBalanceResponse, error) {
+	if req.GetWalletId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "wallet_id must be positive")
+	}
+	walletID := int(req.GetWalletId())
+	if err := authorizeWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+
+	balance, err := s.service.GetBalance(ctx, walletID)
+	if err != nil {
+		return nil, s.handleError(err, walletID, "could not get balance")
+	}
+
+	return &walletv1.GetBalanceResponse{Balance: newBalance(balance)}, nil
+}
+
+func (s *WalletServer) Debit(ctx context.Context, req *walletv1.DebitRequest) (*walletv1.DebitResponse, error) {
+	if err := validateOperation(req.GetWalletId(), req.GetAmount()); err != nil {
+		return nil, err
+	}
+	walletID := int(req.GetWalletId())
+
+	_, err := s.service.DebitMoney(ctx, domain.DebitEntry{
+		WalletID: walletID,
+		Amount:   money.NewFromInt(int(req.GetAmount())),
+	})
+	if err != nil {
+		return nil, s.handleError(err, walletID, "could not debit money")
+	}
+
+	return &walletv1.DebitResponse{}, nil
+}
+
+func (s *WalletServer) Credit(ctx context.Context, req *walletv1.CreditRequest) (*walletv1.CreditResponse, error) {
+	if err := validateOperation(req.GetWalletId(), req.GetAmount()); err != nil {
+		return nil, err
+	}
+	walletID := int(req.GetWalletId())
+
+	_, err := s.service.CreditMoney(ctx, domain.CreditEntry{
+		WalletID: walletID,
+		Amount:   money.NewFromInt(int(req.GetAmount())),
+	})
+	if err != nil {
+		return nil, s.handleError(err, walletID, "could not credit money")
+	}
+
+	return &walletv1.CreditResponse{}, nil
+}
+
+func (s *WalletServer) WatchBalance(req *walletv1.WatchBalanceRequest, stream walletv1.WalletService_WatchBalanceServer) error {
+	if req.GetWalletId() <= 0 {
+		return status.Error(codes.InvalidArgument, "wallet_id must be positive")
+	}
+	walletID := int(req.GetWalletId())
+	ctx := stream.Context()
+	if err := authorizeWallet(ctx, walletID); err != nil {
+		return err
+	}
+
+	// Subscribe first, so that no change committed after the initial read is lost.
+	changes, unsubscribe := s.watcher.Subscribe(walletID)
+	defer unsubscribe()
+
+	balance, err := s.service.GetBalance(ctx, walletID)
+	if err != nil {
+		return s.handleError(err, walletID, "could not get balance")
+	}
+	if err := stream.Send(&walletv1.WatchBalanceResponse{Balance: newBalance(balance)}); err != nil {
+		return err
+	}
+	lastVersion := balance.Version
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case balance, ok := <-changes:
+			if !ok {
+				return status.Error(codes.Unavailable, "balance updates stopped")
+			}
+			if balance.Version <= lastVersion {
+				continue // changes from other instances may arrive late
+			}
+			if err := stream.Send(&walletv1.WatchBalanceResponse{Balance: newBalance(&balance)}); err != nil {
+				return err
+			}
+			lastVersion = balance.Version
+		}
+	}
+}
+
+func (s *WalletServer) handleError(err error, walletID int, msg string) error {
+	if errors.Is(err, domain.ErrWalletNotFound) {
+		log.Debug().Err(err).Int("walletId", walletID).Msg(msg)
+		return status.Error(codes.NotFound, "wallet not found")
+	}
+
+	if errors.Is(err, domain.ErrInvalidAmount) {
+		log.Debug().Err(err).Int("walletId", walletID).Msg(msg)
+		return status.Error(codes.InvalidArgument, "invalid amount")
+	}
+
+	if errors.Is(err, domain.ErrInsufficientFunds) {
+		log.Debug().Err(err).Int("walletId", walletID).Msg(msg)
+		return status.Error(codes.FailedPrecondition, "insufficient funds")
+	}
+
+	if errors.Is(err, domain.ErrLimitExceeded) {
+		log.Debug().Err(err).Int("walletId", walletID).Msg(msg)
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	if errors.Is(err, domain.ErrSelfExcluded) {
+		log.Debug().Err(err).Int("walletId", walletID).Msg(msg)
+		return status.Error(codes.FailedPrecondition, "wallet is self-excluded")
+	}
+
+	if errors.Is(err, domain.ErrWalletFlagged) {
+		log.Debug().Err(err).Int("walletId", walletID).Msg(msg)
+		return status.Error(codes.FailedPrecondition, "wallet is flagged for a negative balance")
+	}
+
+	if errors.Is(err, domain.ErrWalletFrozen) {
+		log.Debug().Err(err).Int("walletId", walletID).Msg(msg)
+		return status.Error(codes.FailedPrecondition, "wallet is frozen")
+	}
+
+	if errors.Is(err, entity.ErrTxConflict) {
+		log.Warn().Err(err).Int("walletId", walletID).Msg(msg)
+		return status.Error(codes.Aborted, "concurrent update, retry later")
+	}
+
+	log.Err(err).Int("walletId", walletID).Msg(msg)
+	return status.Error(codes.Internal, codes.Internal.String())
+}
+
+// authorizeWallet returns a PermissionDenied error unless the token holder
+// owns the wallet or is an admin.
+func authorizeWallet(ctx context.Context, walletID int) error {
+	claims := jwtauth.FromContext(ctx)
+	if claims == nil || !(claims.OwnsWallet(walletID) || claims.HasScope(jwtauth.ScopeAdmin)) {
+		return status.Error(codes.PermissionDenied, "wallet access denied")
+	}
+	return nil
+}
+
+func validateOperation(walletID, amount int64) error {
+	if walletID <= 0 {
+		return status.Error(codes.InvalidArgument, "wallet_id must be positive")
+	}
+	if amount <= 0 {
+		return status.Error(codes.InvalidArgument, "amount must be positive")
+	}
+	return nil
+}
+
+func newBalance(balance *domain.WalletBalance) *walletv1.Balance {
+	return &walletv1.Balance{
+		WalletId: int64(balance.WalletID),
+		Amount:   int64(balance.Amount.AsInt()),
+		Bonus:    int64(balance.Bonus.AsInt()),
+	}
+}
@@ -0,0 +1,194 @@
+package v1_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	walletv1 "github.com/pprishchepa/go-casino-example/api/wallet/v1"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	grpcv1 "github.com/pprishchepa/go-casino-example/internal/controller/grpc/v1"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWalletServer_Debit(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *walletv1.DebitRequest
+		svcErr   error
+		wantCode codes.Code
+	}{
+		{
+			name:     "ok",
+			req:      &walletv1.DebitRequest{WalletId: 25, Amount: 100},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "invalid wallet",
+			req:      &walletv1.DebitRequest{WalletId: 0, Amount: 100},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "invalid amount",
+			req:      &walletv1.DebitRequest{WalletId: 25, Amount: -1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "wallet not found",
+			req:      &walletv1.DebitRequest{WalletId: 25, Amount: 100},
+			svcErr:   fmt.Errorf("get balance: %w", domain.ErrWalletNotFound),
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "tx conflict",
+			req:      &walletv1.DebitRequest{WalletId: 25, Amount: 100},
+			svcErr:   entity.ErrTxConflict,
+			wantCode: codes.Aborted,
+		},
+		{
+			name:     "unknown error",
+			req:      &walletv1.DebitRequest{WalletId: 25, Amount: 100},
+			svcErr:   errors.New("boom"),
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+
+			svc := NewMockWalletService(mockCtrl)
+			if tt.req.WalletId > 0 && tt.req.Amount > 0 {
+				svc.EXPECT().DebitMoney(gomock.Any(), domain.DebitEntry{
+					WalletID: int(tt.req.WalletId),
+					Amount:   money.NewFromInt(int(tt.req.Amount)),
+				}).Return(&entity.Operation{}, tt.svcErr)
+			}
+
+			_, err := grpcv1.NewWalletServer(svc, NewMockBalanceWatcher(mockCtrl)).Debit(context.Background(), tt.req)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestWalletServer_CreditInsufficientFunds(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	svc := NewMockWalletService(mockCtrl)
+	svc.EXPECT().CreditMoney(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInsufficientFunds)
+
+	_, err := grpcv1.NewWalletServer(svc, NewMockBalanceWatcher(mockCtrl)).
+		Credit(context.Background(), &walletv1.CreditRequest{WalletId: 25, Amount: 100})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestWalletServer_CreditSelfExcluded(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	svc := NewMockWalletService(mockCtrl)
+	svc.EXPECT().CreditMoney(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("credit money: %w", domain.ErrSelfExcluded))
+
+	_, err := grpcv1.NewWalletServer(svc, NewMockBalanceWatcher(mockCtrl)).
+		Credit(context.Background(), &walletv1.CreditRequest{WalletId: 25, Amount: 100})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestWalletServer_GetBalanceAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   *jwtauth.Claims
+		wantCode codes.Code
+	}{
+		{
+			name:     "owner",
+			claims:   &jwtauth.Claims{Wallets: []int{25}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "admin",
+			claims:   &jwtauth.Claims{Scopes: []string{jwtauth.ScopeAdmin}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "other wallet",
+			claims:   &jwtauth.Claims{Wallets: []int{26}},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "no claims",
+			wantCode: codes.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+
+			svc := NewMockWalletService(mockCtrl)
+			if tt.wantCode == codes.OK {
+				svc.EXPECT().GetBalance(gomock.Any(), 25).Return(&domain.WalletBalance{WalletID: 25}, nil)
+			}
+
+			ctx := context.Background()
+			if tt.claims != nil {
+				ctx = jwtauth.NewContext(ctx, tt.claims)
+			}
+			_, err := grpcv1.NewWalletServer(svc, NewMockBalanceWatcher(mockCtrl)).
+				GetBalance(ctx, &walletv1.GetBalanceRequest{WalletId: 25})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestWalletServer_WatchBalanceOtherWallet(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	stream := &watchStream{ctx: jwtauth.NewContext(context.Background(), &jwtauth.Claims{Wallets: []int{26}})}
+	err := grpcv1.NewWalletServer(NewMockWalletService(mockCtrl), NewMockBalanceWatcher(mockCtrl)).
+		WatchBalance(&walletv1.WatchBalanceRequest{WalletId: 25}, stream)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Empty(t, stream.sent)
+}
+
+func TestWalletServer_WatchBalanceClosedChanges(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	changes := make(chan domain.WalletBalance, 1)
+	changes <- domain.WalletBalance{WalletID: 25, Version: 2}
+	close(changes)
+
+	svc := NewMockWalletService(mockCtrl)
+	svc.EXPECT().GetBalance(gomock.Any(), 25).Return(&domain.WalletBalance{WalletID: 25, Version: 1}, nil)
+	watcher := NewMockBalanceWatcher(mockCtrl)
+	watcher.EXPECT().Subscribe(25).Return(changes, func() {})
+
+	stream := &watchStream{ctx: jwtauth.NewContext(context.Background(), &jwtauth.Claims{Wallets: []int{25}})}
+	err := grpcv1.NewWalletServer(svc, watcher).WatchBalance(&walletv1.WatchBalanceRequest{WalletId: 25}, stream)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Len(t, stream.sent, 2)
+}
+
+// watchStream records the balances sent to a WatchBalance client.
+type watchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*walletv1.WatchBalanceResponse
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchStream) Send(res *walletv1.WatchBalanceResponse) error {
+	s.sent = append(s.sent, res)
+	return nil
+}
@@ -0,0 +1,43 @@
+// Package docs serves an OpenAPI document together with a docs UI.
+package docs
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	swaggerfiles "github.com/swaggo/files/v2"
+)
+
+// swaggerInitializer replaces the one shipped with Swagger UI, which points to
+// the petstore example.
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "../openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// RegisterRoutes registers the document and the docs UI. Both are public.
+func RegisterRoutes(e *gin.RouterGroup, spec []byte) {
+	e.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", spec)
+	})
+
+	e.GET("/docs", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, c.Request.URL.Path+"/")
+	})
+
+	files := http.StripPrefix(e.BasePath()+"/docs", http.FileServer(http.FS(swaggerfiles.FS)))
+	e.GET("/docs/*filepath", func(c *gin.Context) {
+		if c.Param("filepath") == "/swagger-initializer.js" {
+			c.Data(http.StatusOK, "application/javascript", []byte(swaggerInitializer))
+			return
+		}
+		files.ServeHTTP(c.Writer, c.Request)
+	})
+}
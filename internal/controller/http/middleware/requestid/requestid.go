@@ -0,0 +1,44 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+const Header = "X-Request-ID"
+
+// validID limits what a client can put into logs and responses.
+var validID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+type ctxKey struct{}
+
+// New reuses a valid X-Request-ID header or generates a new ID, returns it
+// in the response header and stores it in the request context.
+func New() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if !validID.MatchString(id) {
+			id = newID()
+		}
+
+		c.Header(Header, id)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ctxKey{}, id))
+		c.Next()
+	}
+}
+
+// FromContext returns the request ID, or an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
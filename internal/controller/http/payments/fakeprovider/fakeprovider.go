@@ -0,0 +1,120 @@
+// Package fakeprovider is a payment provider for development and tests. It
+// signs callbacks with HMAC-SHA256 over the body and can produce the
+// callbacks itself.
+package fakeprovider
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/payments"
+)
+
+const (
+	Name            = "fake"
+	SignatureHeader = "X-Fake-Signature"
+	signaturePrefix = "sha256="
+)
+
+// Callback statuses. A chargeback reverses a confirmed deposit.
+const (
+	StatusPending    = "pending"
+	StatusConfirmed  = "confirmed"
+	StatusFailed     = "failed"
+	StatusChargeback = "chargeback"
+)
+
+var statuses = map[string]domain.DepositStatus{
+	StatusPending:    domain.DepositPending,
+	StatusConfirmed:  domain.DepositConfirmed,
+	StatusFailed:     domain.DepositFailed,
+	StatusChargeback: domain.DepositChargedBack,
+}
+
+type Callback struct {
+	Reference string `json:"reference"`
+	WalletID  int    `json:"walletId"`
+	Amount    string `json:"amount"`
+	Status    string `json:"status"`
+}
+
+type Provider struct {
+	secret []byte
+}
+
+func New(secret string) *Provider {
+	return &Provider{secret: []byte(secret)}
+}
+
+func (p *Provider) Name() string {
+	return Name
+}
+
+func (p *Provider) ParseCallback(header http.Header, body []byte) (domain.DepositEvent, error) {
+	signature, ok := strings.CutPrefix(header.Get(SignatureHeader), signaturePrefix)
+	if !ok {
+		return domain.DepositEvent{}, payments.ErrInvalidSignature
+	}
+	mac, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, p.mac(body)) {
+		return domain.DepositEvent{}, payments.ErrInvalidSignature
+	}
+
+	var cb Callback
+	if err := json.Unmarshal(body, &cb); err != nil {
+		return domain.DepositEvent{}, fmt.Errorf("decode callback: %w", err)
+	}
+
+	status, ok := statuses[cb.Status]
+	if !ok {
+		return domain.DepositEvent{}, fmt.Errorf("unknown callback status %q", cb.Status)
+	}
+	amount, err := money.Parse(cb.Amount)
+	if err != nil {
+		return domain.DepositEvent{}, fmt.Errorf("parse amount: %w", err)
+	}
+
+	return domain.DepositEvent{
+		Provider:  Name,
+		Reference: cb.Reference,
+		WalletID:  cb.WalletID,
+		Amount:    amount,
+		Status:    status,
+	}, nil
+}
+
+// Sign returns the signature header value of body.
+func (p *Provider) Sign(body []byte) string {
+	return signaturePrefix + hex.EncodeToString(p.mac(body))
+}
+
+// NewRequest returns a signed callback request to url.
+func (p *Provider) NewRequest(url string, cb Callback) (*http.Request, error) {
+	body, err := json.Marshal(cb)
+	if err != nil {
+		return nil, fmt.Errorf("encode callback: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, p.Sign(body))
+
+	return req, nil
+}
+
+func (p *Provider) mac(body []byte) []byte {
+	h := hmac.New(sha256.New, p.secret)
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,108 @@
+// Package payments receives deposit callbacks from payment providers. The
+// callbacks are authenticated by the signature of the provider rather than a
+// JWT.
+package payments
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=payments.go -destination=payments_mock_test.go -package=payments_test
+
+// maxCallbackSize caps the body that is read and verified.
+const maxCallbackSize = 64 << 10
+
+var ErrInvalidSignature = problem.New(http.StatusUnauthorized, problem.CodeInvalidSignature, "Invalid callback signature")
+
+type DepositService interface {
+	ApplyDepositEvent(ctx context.Context, event domain.DepositEvent) (*domain.Deposit, error)
+}
+
+// Provider verifies and decodes callbacks of a payment provider.
+type Provider interface {
+	// Name is the provider segment of the callback path.
+	Name() string
+	// ParseCallback returns ErrInvalidSignature unless the provider has
+	// signed body. The event has its Provider set.
+	ParseCallback(header http.Header, body []byte) (domain.DepositEvent, error)
+}
+
+type CallbackRoutes struct {
+	service   DepositService
+	providers map[string]Provider
+}
+
+func NewCallbackRoutes(service DepositService, providers []Provider) *CallbackRoutes {
+	r := &CallbackRoutes{service: service, providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+func (r CallbackRoutes) RegisterRoutes(e *gin.RouterGroup) {
+	e.POST("/callbacks/:provider", r.callback)
+}
+
+// DepositResponse is returned for duplicate callbacks too, so that providers
+// stop retrying them.
+type DepositResponse struct {
+	ID        int         `json:"id"`
+	WalletID  int         `json:"walletId"`
+	Provider  string      `json:"provider"`
+	Reference string      `json:"reference"`
+	Amount    money.Money `json:"amount"`
+	Status    string      `json:"status"`
+	CreatedAt time.Time   `json:"createdAt"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+}
+
+func (r CallbackRoutes) callback(c *gin.Context) {
+	provider, ok := r.providers[c.Param("provider")]
+	if !ok {
+		_ = c.Error(problem.ErrNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxCallbackSize))
+	if err != nil {
+		_ = c.Error(problem.ErrValidation.WithDetail("could not read callback body"))
+		return
+	}
+
+	event, err := provider.ParseCallback(c.Request.Header, body)
+	if err != nil {
+		var p *problem.Problem
+		if !errors.As(err, &p) {
+			p = problem.ErrValidation.WithDetail(err.Error())
+		}
+		_ = c.Error(p)
+		return
+	}
+
+	deposit, err := r.service.ApplyDepositEvent(c.Request.Context(), event)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": DepositResponse{
+		ID:        deposit.ID,
+		WalletID:  deposit.WalletID,
+		Provider:  deposit.Provider,
+		Reference: deposit.Reference,
+		Amount:    deposit.Amount,
+		Status:    string(deposit.Status),
+		CreatedAt: deposit.CreatedAt,
+		UpdatedAt: deposit.UpdatedAt,
+	}})
+}
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: payments.go
+//
+// Generated by this command:
+//
+//	mockgen -source=payments.go -destination=payments_mock_test.go -package=payments_test
+//
+
+// Package payments_test is a generated GoMock package.
+package payments_test
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	domain "github.com/pprishchepa/go-casino-example/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDepositService is a mock of DepositService interface.
+type MockDepositService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDepositServiceMockRecorder
+}
+
+// MockDepositServiceMockRecorder is the mock recorder for MockDepositService.
+type MockDepositServiceMockRecorder struct {
+	mock *MockDepositService
+}
+
+// NewMockDepositService creates a new mock instance.
+func NewMockDepositService(ctrl *gomock.Controller) *MockDepositService {
+	mock := &MockDepositService{ctrl: ctrl}
+	mock.recorder = &MockDepositServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDepositService) EXPECT() *MockDepositServiceMockRecorder {
+	return m.recorder
+}
+
+// ApplyDepositEvent mocks base method.
+func (m *MockDepositService) ApplyDepositEvent(ctx context.Context, event domain.DepositEvent) (*domain.Deposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyDepositEvent", ctx, event)
+	ret0, _ := ret[0].(*domain.Deposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyDepositEvent indicates an expected call of ApplyDepositEvent.
+func (mr *MockDepositServiceMockRecorder) ApplyDepositEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyDepositEvent", reflect.TypeOf((*MockDepositService)(nil).ApplyDepositEvent), ctx, event)
+}
+
+// MockProvider is a mock of Provider interface.
+type MockProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockProviderMockRecorder
+}
+
+// MockProviderMockRecorder is the mock recorder for MockProvider.
+type MockProviderMockRecorder struct {
+	mock *MockProvider
+}
+
+// NewMockProvider creates a new mock instance.
+func NewMockProvider(ctrl *gomock.Controller) *MockProvider {
+	mock := &MockProvider{ctrl: ctrl}
+	mock.recorder = &MockProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProvider) EXPECT() *MockProviderMockRecorder {
+	return m.recorder
+}
+
+// Name mocks base method.
+func (m *MockProvider) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockProviderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockProvider)(nil).Name))
+}
+
+// ParseCallback mocks base method.
+func (m *MockProvider) ParseCallback(header http.Header, body []byte) (domain.DepositEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseCallback", header, body)
+	ret0, _ := ret[0].(domain.DepositEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseCallback indicates an expected call of ParseCallback.
+func (mr *MockProviderMockRecorder) ParseCallback(header, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseCallback", reflect.TypeOf((*MockProvider)(nil).ParseCallback), header, body)
+}
@@ -0,0 +1,174 @@
+package payments_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/middleware/requestid"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/payments"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/payments/fakeprovider"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const callbackURL = "/api/payments/callbacks/fake"
+
+func TestCallbackRoutes(t *testing.T) {
+	provider := fakeprovider.New("test-secret")
+	createdAt := time.Date(2024, 5, 11, 12, 0, 0, 0, time.UTC)
+
+	signed := func(cb fakeprovider.Callback) *http.Request {
+		req, err := provider.NewRequest(callbackURL, cb)
+		require.NoError(t, err)
+		return req
+	}
+	confirmed := fakeprovider.Callback{Reference: "dep-1", WalletID: 25, Amount: "30", Status: fakeprovider.StatusConfirmed}
+
+	tests := []struct {
+		name       string
+		req        func() *http.Request
+		mock       func(svc *MockDepositService)
+		wantStatus int
+		wantCode   string
+		wantBody   string
+	}{
+		{
+			name: "confirmed",
+			req:  func() *http.Request { return signed(confirmed) },
+			mock: func(svc *MockDepositService) {
+				svc.EXPECT().ApplyDepositEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, event domain.DepositEvent) (*domain.Deposit, error) {
+						assert.Equal(t, "fake", event.Provider)
+						assert.Equal(t, "dep-1", event.Reference)
+						assert.Equal(t, 25, event.WalletID)
+						assert.Equal(t, "30.000", event.Amount.String())
+						assert.Equal(t, domain.DepositConfirmed, event.Status)
+						return &domain.Deposit{
+							ID:        7,
+							WalletID:  25,
+							Provider:  "fake",
+							Reference: "dep-1",
+							Amount:    money.NewFromInt(30000),
+							Status:    domain.DepositConfirmed,
+							CreatedAt: createdAt,
+							UpdatedAt: createdAt,
+						}, nil
+					})
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"id":7,"walletId":25,"provider":"fake","reference":"dep-1","amount":"30.000",` +
+				`"status":"confirmed","createdAt":"2024-05-11T12:00:00Z","updatedAt":"2024-05-11T12:00:00Z"}}`,
+		},
+		{
+			name: "chargeback",
+			req: func() *http.Request {
+				cb := confirmed
+				cb.Status = fakeprovider.StatusChargeback
+				return signed(cb)
+			},
+			mock: func(svc *MockDepositService) {
+				svc.EXPECT().ApplyDepositEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, event domain.DepositEvent) (*domain.Deposit, error) {
+						assert.Equal(t, domain.DepositChargedBack, event.Status)
+						return &domain.Deposit{ID: 7, Status: domain.DepositChargedBack}, nil
+					})
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "tampered body",
+			req: func() *http.Request {
+				req := signed(confirmed)
+				tampered := httptest.NewRequest(http.MethodPost, callbackURL,
+					strings.NewReader(`{"reference":"dep-1","walletId":25,"amount":"3000","status":"confirmed"}`))
+				tampered.Header = req.Header
+				return tampered
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   problem.CodeInvalidSignature,
+		},
+		{
+			name: "signed with another secret",
+			req: func() *http.Request {
+				req, err := fakeprovider.New("other-secret").NewRequest(callbackURL, confirmed)
+				require.NoError(t, err)
+				return req
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   problem.CodeInvalidSignature,
+		},
+		{
+			name: "unsigned",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, callbackURL, strings.NewReader(`{}`))
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   problem.CodeInvalidSignature,
+		},
+		{
+			name: "unknown status",
+			req: func() *http.Request {
+				cb := confirmed
+				cb.Status = "settled"
+				return signed(cb)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeValidationFailed,
+		},
+		{
+			name: "unknown provider",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/api/payments/callbacks/other", strings.NewReader(`{}`))
+			},
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeNotFound,
+		},
+		{
+			name: "mismatch",
+			req:  func() *http.Request { return signed(confirmed) },
+			mock: func(svc *MockDepositService) {
+				svc.EXPECT().ApplyDepositEvent(gomock.Any(), gomock.Any()).Return(nil, domain.ErrDepositMismatch)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeDepositMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			svc := NewMockDepositService(mockCtrl)
+			if tt.mock != nil {
+				tt.mock(svc)
+			}
+
+			rec := httptest.NewRecorder()
+			newEngine(svc, provider).ServeHTTP(rec, tt.req())
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantCode != "" {
+				assert.Contains(t, rec.Body.String(), `"code":"`+tt.wantCode+`"`)
+			}
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func newEngine(svc payments.DepositService, providers ...payments.Provider) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	e := gin.New()
+	e.Use(requestid.New(), problem.Handler())
+	payments.NewCallbackRoutes(svc, providers).RegisterRoutes(e.Group("/api/payments"))
+
+	return e
+}
@@ -0,0 +1,314 @@
+// Package problem renders errors as RFC 7807 problem details with stable,
+// machine-readable codes.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/middleware/requestid"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/statement"
+	"github.com/rs/zerolog/log"
+)
+
+const ContentType = "application/problem+json"
+
+const (
+	CodeValidationFailed   = "VALIDATION_FAILED"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeNotFound           = "NOT_FOUND"
+	CodeMethodNotAllowed   = "METHOD_NOT_ALLOWED"
+	CodeWalletNotFound     = "WALLET_NOT_FOUND"
+	CodeBatchNotFound      = "BATCH_NOT_FOUND"
+	CodeInsufficientFunds  = "INSUFFICIENT_FUNDS"
+	CodeInvalidAmount      = "INVALID_AMOUNT"
+	CodeInvalidExpiry      = "INVALID_EXPIRY"
+	CodeInvalidLimit       = "INVALID_LIMIT"
+	CodeLimitExceeded      = "LIMIT_EXCEEDED"
+	CodeSelfExcluded       = "SELF_EXCLUDED"
+	CodeInvalidExclusion   = "INVALID_EXCLUSION"
+	CodeNotExcluded        = "NOT_EXCLUDED"
+	CodeWithdrawalNotFound = "WITHDRAWAL_NOT_FOUND"
+	CodeInvalidTransition  = "INVALID_TRANSITION"
+	CodeInvalidEntry       = "INVALID_ENTRY"
+	CodeEntryNotFound      = "ENTRY_NOT_FOUND"
+	CodeEntryNotReversible = "ENTRY_NOT_REVERSIBLE"
+	CodeAlreadyReversed    = "ALREADY_REVERSED"
+	CodeInvalidSignature   = "INVALID_SIGNATURE"
+	CodeInvalidDeposit     = "INVALID_DEPOSIT"
+	CodeDepositMismatch    = "DEPOSIT_MISMATCH"
+	CodeWalletFlagged      = "WALLET_FLAGGED"
+	CodeWalletFrozen       = "WALLET_FROZEN"
+	CodeInvalidAdminAction = "INVALID_ADMIN_ACTION"
+	CodeInvalidPeriod      = "INVALID_PERIOD"
+	CodeInvalidReportRange = "INVALID_REPORT_RANGE"
+	CodeTxConflict         = "TX_CONFLICT"
+	CodeInternal           = "INTERNAL"
+)
+
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Code      string       `json:"code"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	// Limit is set when a responsible-gambling limit has been hit.
+	Limit *Limit `json:"limit,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type Limit struct {
+	Kind      string      `json:"kind"`
+	Period    string      `json:"period"`
+	Amount    money.Money `json:"amount"`
+	Remaining money.Money `json:"remaining"`
+}
+
+// New returns a problem that handlers can pass to gin.Context.Error when the
+// condition is not a domain error.
+func New(status int, code, title string) *Problem {
+	return &Problem{
+		Type:   typeURI(code),
+		Title:  title,
+		Status: status,
+		Code:   code,
+	}
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Title + ": " + p.Detail
+	}
+	return p.Title
+}
+
+// WithDetail returns a copy of the problem with the detail set.
+func (p *Problem) WithDetail(detail string) *Problem {
+	res := *p
+	res.Detail = detail
+	return &res
+}
+
+var (
+	ErrUnauthorized     = New(http.StatusUnauthorized, CodeUnauthorized, "Unauthorized")
+	ErrForbidden        = New(http.StatusForbidden, CodeForbidden, "Forbidden")
+	ErrNotFound         = New(http.StatusNotFound, CodeNotFound, "Not found")
+	ErrMethodNotAllowed = New(http.StatusMethodNotAllowed, CodeMethodNotAllowed, "Method not allowed")
+	ErrValidation       = New(http.StatusBadRequest, CodeValidationFailed, "Request validation failed")
+	ErrInternal         = New(http.StatusInternalServerError, CodeInternal, "Internal server error")
+)
+
+var errLimitExceeded = New(http.StatusUnprocessableEntity, CodeLimitExceeded, "Limit exceeded")
+
+// known maps errors of the lower layers to problems. It is the only place that
+// decides which status and code a domain error gets.
+var known = []struct {
+	err     error
+	problem *Problem
+}{
+	{domain.ErrWalletNotFound, New(http.StatusNotFound, CodeWalletNotFound, "Wallet not found")},
+	{domain.ErrInsufficientFunds, New(http.StatusUnprocessableEntity, CodeInsufficientFunds, "Insufficient funds")},
+	{domain.ErrInvalidAmount, New(http.StatusUnprocessableEntity, CodeInvalidAmount, "Invalid amount")},
+	{domain.ErrInvalidExpiry, New(http.StatusUnprocessableEntity, CodeInvalidExpiry, "Expiry must be in the future")},
+	{domain.ErrInvalidLimit, New(http.StatusUnprocessableEntity, CodeInvalidLimit, "Unknown limit kind or period")},
+	{domain.ErrLimitExceeded, errLimitExceeded},
+	{domain.ErrSelfExcluded, New(http.StatusUnprocessableEntity, CodeSelfExcluded, "Wallet is self-excluded from betting")},
+	{domain.ErrInvalidExclusion, New(http.StatusUnprocessableEntity, CodeInvalidExclusion, "Unknown exclusion period or missing reason")},
+	{domain.ErrNotExcluded, New(http.StatusUnprocessableEntity, CodeNotExcluded, "Wallet is not self-excluded")},
+	{domain.ErrWithdrawalNotFound, New(http.StatusNotFound, CodeWithdrawalNotFound, "Withdrawal not found")},
+	{domain.ErrInvalidTransition, New(http.StatusUnprocessableEntity, CodeInvalidTransition, "Withdrawal cannot change to this status")},
+	{domain.ErrInvalidEntry, New(http.StatusUnprocessableEntity, CodeInvalidEntry, "Operation not allowed, unknown initiator or metadata is not an object")},
+	{domain.ErrEntryNotFound, New(http.StatusNotFound, CodeEntryNotFound, "Entry not found")},
+	{domain.ErrEntryNotReversible, New(http.StatusUnprocessableEntity, CodeEntryNotReversible, "Entry cannot be reversed or missing reason")},
+	{domain.ErrAlreadyReversed, New(http.StatusUnprocessableEntity, CodeAlreadyReversed, "Entry is already reversed")},
+	{domain.ErrInvalidDeposit, New(http.StatusUnprocessableEntity, CodeInvalidDeposit, "Missing reference or unknown deposit status")},
+	{domain.ErrDepositMismatch, New(http.StatusUnprocessableEntity, CodeDepositMismatch, "Deposit does not match the provider reference")},
+	{domain.ErrWalletFlagged, New(http.StatusUnprocessableEntity, CodeWalletFlagged, "Wallet is flagged for a negative balance")},
+	{domain.ErrWalletFrozen, New(http.StatusUnprocessableEntity, CodeWalletFrozen, "Wallet is frozen")},
+	{domain.ErrInvalidAdminAction, New(http.StatusUnprocessableEntity, CodeInvalidAdminAction, "Missing reason, zero adjustment or wallet already in that state")},
+	{statement.ErrInvalidPeriod, New(http.StatusUnprocessableEntity, CodeInvalidPeriod, "Period must end after it starts and span at most a year")},
+	{entity.ErrInvalidReportRange, New(http.StatusUnprocessableEntity, CodeInvalidReportRange, "Report range must end after it starts and span at most a year")},
+	{entity.ErrTxConflict, New(http.StatusConflict, CodeTxConflict, "Concurrent update, retry the request")},
+	{entity.ErrBatchNotFound, New(http.StatusNotFound, CodeBatchNotFound, "Batch not found")},
+}
+
+// Handler renders the last error added to the context by a handler or
+// middleware, unless the response has already been written. Errors of type
+// gin.ErrorTypeBind are reported as validation failures.
+func Handler() gin.HandlerFunc {
+	registerFieldNames()
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		ginErr := c.Errors.Last()
+		if ginErr == nil || c.Writer.Written() {
+			return
+		}
+
+		var p *Problem
+		if ginErr.IsType(gin.ErrorTypeBind) {
+			p = fromBindError(ginErr.Err)
+		} else {
+			p = fromError(ginErr.Err)
+		}
+
+		res := *p
+		res.Instance = c.Request.URL.Path
+		res.RequestID = requestid.FromContext(c.Request.Context())
+
+		logger := log.With().Str("requestId", res.RequestID).Str("path", res.Instance).Logger()
+		if res.Status >= http.StatusInternalServerError {
+			logger.Err(ginErr.Err).Msg("request failed")
+		} else {
+			logger.Debug().Err(ginErr.Err).Msg("request rejected")
+		}
+
+		Render(c, &res)
+	}
+}
+
+// Render writes the problem and aborts the request.
+func Render(c *gin.Context, p *Problem) {
+	c.Abort()
+	c.Header("Content-Type", ContentType)
+	c.Status(p.Status)
+	if err := json.NewEncoder(c.Writer).Encode(p); err != nil {
+		log.Warn().Err(err).Msg("could not write problem")
+	}
+}
+
+func fromError(err error) *Problem {
+	var p *Problem
+	if errors.As(err, &p) {
+		return p
+	}
+
+	var limitErr *domain.LimitExceededError
+	if errors.As(err, &limitErr) {
+		res := errLimitExceeded.WithDetail(limitErr.Error())
+		res.Limit = &Limit{
+			Kind:      string(limitErr.Kind),
+			Period:    string(limitErr.Period),
+			Amount:    limitErr.Limit,
+			Remaining: limitErr.Remaining,
+		}
+		return res
+	}
+
+	for _, k := range known {
+		if errors.Is(err, k.err) {
+			return k.problem
+		}
+	}
+
+	return ErrInternal
+}
+
+func fromBindError(err error) *Problem {
+	var (
+		validationErrs validator.ValidationErrors
+		typeErr        *json.UnmarshalTypeError
+		syntaxErr      *json.SyntaxError
+	)
+
+	switch {
+	case errors.As(err, &validationErrs):
+		res := ErrValidation.WithDetail("one or more fields are invalid")
+		for _, fe := range validationErrs {
+			res.Errors = append(res.Errors, FieldError{Field: fieldName(fe), Message: validationMessage(fe)})
+		}
+		return res
+	case errors.As(err, &typeErr):
+		res := ErrValidation.WithDetail("one or more fields are invalid")
+		res.Errors = []FieldError{{Field: typeErr.Field, Message: "must be of type " + typeErr.Type.String()}}
+		return res
+	case errors.As(err, &syntaxErr):
+		return ErrValidation.WithDetail("request body is not valid JSON")
+	default:
+		return ErrValidation.WithDetail(err.Error())
+	}
+}
+
+func fieldName(fe validator.FieldError) string {
+	// Drop the struct name, keep the path, e.g. "items[0].amount".
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "min":
+		if isCollection(fe.Kind()) {
+			return "must have at least " + fe.Param() + " items"
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		if isCollection(fe.Kind()) {
+			return "must have at most " + fe.Param() + " items"
+		}
+		return "must be at most " + fe.Param()
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	default:
+		return fmt.Sprintf("failed on the %q rule", fe.Tag())
+	}
+}
+
+func isCollection(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+}
+
+var registerOnce sync.Once
+
+// registerFieldNames makes validation errors use the names clients send,
+// i.e. JSON, URI or query names instead of Go struct field names.
+func registerFieldNames() {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			for _, tag := range []string{"json", "uri", "form"} {
+				name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+				if name != "" && name != "-" {
+					return name
+				}
+			}
+			return f.Name
+		})
+	})
+}
+
+func typeURI(code string) string {
+	return "urn:casino:problem:" + code
+}
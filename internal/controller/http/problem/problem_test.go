@@ -0,0 +1,209 @@
+package problem_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/middleware/requestid"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/statement"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		bind       bool
+		body       string
+		wantStatus int
+		wantCode   string
+		wantFields []string
+		wantLimit  *problem.Limit
+	}{
+		{
+			name:       "wallet not found",
+			err:        fmt.Errorf("get balance: %w", domain.ErrWalletNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeWalletNotFound,
+		},
+		{
+			name:       "insufficient funds",
+			err:        domain.ErrInsufficientFunds,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeInsufficientFunds,
+		},
+		{
+			name:       "invalid amount",
+			err:        domain.ErrInvalidAmount,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeInvalidAmount,
+		},
+		{
+			name:       "self-excluded",
+			err:        fmt.Errorf("credit money: %w", domain.ErrSelfExcluded),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeSelfExcluded,
+		},
+		{
+			name:       "withdrawal not found",
+			err:        fmt.Errorf("get withdrawal: %w", domain.ErrWithdrawalNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeWithdrawalNotFound,
+		},
+		{
+			name:       "invalid transition",
+			err:        fmt.Errorf("transition withdrawal: %w", domain.ErrInvalidTransition),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeInvalidTransition,
+		},
+		{
+			name:       "wallet flagged",
+			err:        fmt.Errorf("credit money: %w", domain.ErrWalletFlagged),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeWalletFlagged,
+		},
+		{
+			name:       "wallet frozen",
+			err:        fmt.Errorf("credit money: %w", domain.ErrWalletFrozen),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeWalletFrozen,
+		},
+		{
+			name:       "invalid admin action",
+			err:        fmt.Errorf("adjust balance: %w", domain.ErrInvalidAdminAction),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeInvalidAdminAction,
+		},
+		{
+			name:       "invalid statement period",
+			err:        fmt.Errorf("statement: %w", statement.ErrInvalidPeriod),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeInvalidPeriod,
+		},
+		{
+			name:       "invalid report range",
+			err:        fmt.Errorf("list summaries: %w", entity.ErrInvalidReportRange),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeInvalidReportRange,
+		},
+		{
+			name: "limit exceeded",
+			err: fmt.Errorf("credit money: %w", &domain.LimitExceededError{
+				Kind:      domain.LimitLoss,
+				Period:    domain.LimitWeek,
+				Limit:     money.NewFromInt(100000),
+				Remaining: money.NewFromInt(2500),
+			}),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeLimitExceeded,
+			wantLimit: &problem.Limit{
+				Kind:      "loss",
+				Period:    "week",
+				Amount:    money.NewFromInt(100000),
+				Remaining: money.NewFromInt(2500),
+			},
+		},
+		{
+			name:       "tx conflict",
+			err:        fmt.Errorf("commit: %w", entity.ErrTxConflict),
+			wantStatus: http.StatusConflict,
+			wantCode:   problem.CodeTxConflict,
+		},
+		{
+			name:       "problem",
+			err:        problem.ErrForbidden,
+			wantStatus: http.StatusForbidden,
+			wantCode:   problem.CodeForbidden,
+		},
+		{
+			name:       "unknown error",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   problem.CodeInternal,
+		},
+		{
+			name:       "validation error",
+			bind:       true,
+			body:       `{"amount": 0}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeValidationFailed,
+			wantFields: []string{"amount"},
+		},
+		{
+			name:       "type error",
+			bind:       true,
+			body:       `{"amount": "ten"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeValidationFailed,
+			wantFields: []string{"amount"},
+		},
+		{
+			name:       "syntax error",
+			bind:       true,
+			body:       `{"amount":`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeValidationFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			e := gin.New()
+			e.Use(requestid.New(), problem.Handler())
+			e.POST("/test", func(c *gin.Context) {
+				if !tt.bind {
+					_ = c.Error(tt.err)
+					return
+				}
+				var req struct {
+					Amount int `json:"amount" binding:"required,gt=0"`
+				}
+				if err := c.ShouldBindJSON(&req); err != nil {
+					_ = c.Error(err).SetType(gin.ErrorTypeBind)
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(tt.body))
+			req.Header.Set(requestid.Header, "req-1")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+
+			var got problem.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantCode, got.Code)
+			assert.Equal(t, "/test", got.Instance)
+			assert.Equal(t, "req-1", got.RequestID)
+
+			var fields []string
+			for _, fe := range got.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+			if tt.wantLimit == nil {
+				assert.Nil(t, got.Limit)
+				return
+			}
+			require.NotNil(t, got.Limit)
+			assert.Equal(t, tt.wantLimit.Kind, got.Limit.Kind)
+			assert.Equal(t, tt.wantLimit.Period, got.Limit.Period)
+			assert.True(t, tt.wantLimit.Amount.Equal(got.Limit.Amount))
+			assert.True(t, tt.wantLimit.Remaining.Equal(got.Limit.Remaining))
+		})
+	}
+}
@@ -6,26 +6,86 @@ import (
 	"github.com/gin-contrib/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/pprishchepa/go-casino-example/internal/config"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/middleware/requestid"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/payments"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
 	httpv1 "github.com/pprishchepa/go-casino-example/internal/controller/http/v1"
 	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/middleware/jwt"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/openapi"
+	httpv2 "github.com/pprishchepa/go-casino-example/internal/controller/http/v2"
+	openapiv2 "github.com/pprishchepa/go-casino-example/internal/controller/http/v2/openapi"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
 )
 
-func NewRouter(conf config.Config, wallet *httpv1.WalletRoutes) http.Handler {
+func NewRouter(
+	conf config.Config,
+	wallet *httpv1.WalletRoutes,
+	batch *httpv1.BatchRoutes,
+	balanceStream *httpv1.BalanceStreamRoutes,
+	statement *httpv1.StatementRoutes,
+	walletV2 *httpv2.WalletRoutes,
+	bonusV2 *httpv2.BonusRoutes,
+	limitV2 *httpv2.LimitRoutes,
+	exclusionV2 *httpv2.ExclusionRoutes,
+	withdrawalV2 *httpv2.WithdrawalRoutes,
+	admin *httpv2.AdminRoutes,
+	report *httpv2.ReportRoutes,
+	paymentCallbacks *payments.CallbackRoutes,
+) http.Handler {
 	gin.SetMode(gin.ReleaseMode)
 
 	e := gin.New()
 	e.ContextWithFallback = true
 	e.HandleMethodNotAllowed = true
 
-	e.Use(gin.Recovery())
+	e.Use(requestid.New())
 	e.Use(logger.SetLogger())
+	e.Use(problem.Handler())
+	e.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		problem.Render(c, problem.ErrInternal)
+	}))
+
+	e.NoRoute(func(c *gin.Context) { _ = c.Error(problem.ErrNotFound) })
+	e.NoMethod(func(c *gin.Context) { _ = c.Error(problem.ErrMethodNotAllowed) })
 
 	e.GET("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
 
+	openapi.RegisterRoutes(e.Group("/api/v1"))
+
 	v1 := e.Group("/api/v1", jwt.Authorize(conf.Auth.JWTSecret))
 	{
 		wallet.RegisterRoutes(v1)
+		batch.RegisterRoutes(v1)
+		statement.RegisterRoutes(v1)
 	}
 
+	// Browsers cannot set headers on EventSource and WebSocket requests.
+	v1Stream := e.Group("/api/v1", jwt.Authorize(conf.Auth.JWTSecret, jwt.WithQueryToken("access_token")))
+	{
+		balanceStream.RegisterRoutes(v1Stream)
+	}
+
+	openapiv2.RegisterRoutes(e.Group("/api/v2"))
+
+	v2 := e.Group("/api/v2", jwt.Authorize(conf.Auth.JWTSecret))
+	{
+		walletV2.RegisterRoutes(v2)
+		bonusV2.RegisterRoutes(v2)
+		limitV2.RegisterRoutes(v2)
+		exclusionV2.RegisterRoutes(v2)
+		withdrawalV2.RegisterRoutes(v2)
+	}
+
+	// Back-office staff act on any wallet, so the whole group needs the admin
+	// scope.
+	adminGroup := e.Group("/api/admin", jwt.Authorize(conf.Auth.JWTSecret), jwt.RequireScope(jwtauth.ScopeAdmin))
+	{
+		admin.RegisterRoutes(adminGroup)
+		report.RegisterRoutes(adminGroup)
+	}
+
+	// Payment providers sign their callbacks instead of sending a JWT.
+	paymentCallbacks.RegisterRoutes(e.Group("/api/payments"))
+
 	return e
 }
@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/model"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=batch.go -destination=batch_mock_test.go -package=v1_test
+
+type BatchService interface {
+	SubmitBatch(ctx context.Context, batch *entity.Batch) error
+	GetBatch(ctx context.Context, batchID int) (*entity.Batch, error)
+}
+
+type BatchRoutes struct {
+	service  BatchService
+	maxItems int
+}
+
+func NewBatchRoutes(service BatchService, maxItems int) *BatchRoutes {
+	return &BatchRoutes{service: service, maxItems: maxItems}
+}
+
+func (r BatchRoutes) RegisterRoutes(e *gin.RouterGroup) {
+	e.POST("/batches", r.submitBatch)
+	e.GET("/batches/:batch", r.retrieveBatch)
+}
+
+func (r BatchRoutes) submitBatch(c *gin.Context) {
+	var reqBody model.SubmitBatchRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if r.maxItems > 0 && len(reqBody.Items) > r.maxItems {
+		p := problem.ErrValidation.WithDetail("too many items")
+		p.Errors = []problem.FieldError{{Field: "items", Message: fmt.Sprintf("must have at most %d items", r.maxItems)}}
+		_ = c.Error(p)
+		return
+	}
+
+	batch := entity.Batch{
+		Mode:  entity.BatchMode(reqBody.Mode),
+		Items: make([]entity.BatchItem, 0, len(reqBody.Items)),
+	}
+	for _, item := range reqBody.Items {
+		batch.Items = append(batch.Items, entity.BatchItem{
+			WalletID:  item.WalletID,
+			Operation: entity.BatchOperation(item.Operation),
+			Amount:    money.NewFromInt(item.Amount),
+		})
+	}
+
+	if err := r.service.SubmitBatch(c.Request.Context(), &batch); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": newBatchResponse(&batch)})
+}
+
+func (r BatchRoutes) retrieveBatch(c *gin.Context) {
+	var reqBatch model.BatchRequest
+	if err := c.ShouldBindUri(&reqBatch); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	batch, err := r.service.GetBatch(c.Request.Context(), reqBatch.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newBatchResponse(batch)})
+}
+
+func newBatchResponse(batch *entity.Batch) model.BatchResponse {
+	resp := model.BatchResponse{
+		ID:        batch.ID,
+		Mode:      string(batch.Mode),
+		Status:    string(batch.Status),
+		Items:     make([]model.BatchItemResponse, 0, len(batch.Items)),
+		CreatedAt: batch.CreatedAt,
+		UpdatedAt: batch.UpdatedAt,
+	}
+
+	for _, item := range batch.Items {
+		switch item.Status {
+		case entity.BatchItemStatusSucceeded:
+			resp.Succeeded++
+		case entity.BatchItemStatusFailed:
+			resp.Failed++
+		}
+		resp.Items = append(resp.Items, model.BatchItemResponse{
+			WalletID:  item.WalletID,
+			Operation: string(item.Operation),
+			Amount:    item.Amount.AsInt(),
+			Status:    string(item.Status),
+			Error:     item.Error,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: batch.go
+//
+// Generated by this command:
+//
+//	mockgen -source=batch.go -destination=batch_mock_test.go -package=v1_test
+//
+
+// Package v1_test is a generated GoMock package.
+package v1_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/pprishchepa/go-casino-example/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBatchService is a mock of BatchService interface.
+type MockBatchService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchServiceMockRecorder
+}
+
+// MockBatchServiceMockRecorder is the mock recorder for MockBatchService.
+type MockBatchServiceMockRecorder struct {
+	mock *MockBatchService
+}
+
+// NewMockBatchService creates a new mock instance.
+func NewMockBatchService(ctrl *gomock.Controller) *MockBatchService {
+	mock := &MockBatchService{ctrl: ctrl}
+	mock.recorder = &MockBatchServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchService) EXPECT() *MockBatchServiceMockRecorder {
+	return m.recorder
+}
+
+// GetBatch mocks base method.
+func (m *MockBatchService) GetBatch(ctx context.Context, batchID int) (*entity.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, batchID)
+	ret0, _ := ret[0].(*entity.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockBatchServiceMockRecorder) GetBatch(ctx, batchID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockBatchService)(nil).GetBatch), ctx, batchID)
+}
+
+// SubmitBatch mocks base method.
+func (m *MockBatchService) SubmitBatch(ctx context.Context, batch *entity.Batch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitBatch", ctx, batch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubmitBatch indicates an expected call of SubmitBatch.
+func (mr *MockBatchServiceMockRecorder) SubmitBatch(ctx, batch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitBatch", reflect.TypeOf((*MockBatchService)(nil).SubmitBatch), ctx, batch)
+}
@@ -1,76 +1,62 @@
 package jwt
 
 import (
-	"errors"
-	"fmt"
-	"net/http"
-	"strings"
-
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
-	"github.com/rs/zerolog/log"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
 )
 
-func Authorize(secret string) gin.HandlerFunc {
-	keyFunc := hmacKeyFunc([]byte(secret))
+type options struct {
+	queryParam string
+}
+
+type Option func(*options)
+
+// WithQueryToken also accepts the token from the given query parameter. It is
+// meant for browser EventSource and WebSocket clients that cannot set headers.
+func WithQueryToken(param string) Option {
+	return func(o *options) {
+		o.queryParam = param
+	}
+}
+
+func Authorize(secret string, opts ...Option) gin.HandlerFunc {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	validator := jwtauth.NewValidator(secret)
 
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			unauthorizedResp(c, errors.New("authorization header required"))
-			return
+		if authHeader == "" && o.queryParam != "" && c.Query(o.queryParam) != "" {
+			authHeader = "Bearer " + c.Query(o.queryParam)
 		}
 
-		scheme, tokenString := parseAuthHeader(authHeader)
-		if scheme != "bearer" {
-			unauthorizedResp(c, errors.New("unexpected auth scheme"))
-			return
-		}
-		if tokenString == "" {
-			unauthorizedResp(c, errors.New("empty token"))
-			return
-		}
-
-		token, err := jwt.Parse(tokenString, keyFunc)
+		claims, err := validator.ValidateHeader(authHeader)
 		if err != nil {
-			unauthorizedResp(c, fmt.Errorf("parse token: %w", err))
-			return
-		}
-		if !token.Valid {
-			unauthorizedResp(c, fmt.Errorf("invalid token"))
+			_ = c.Error(problem.ErrUnauthorized.WithDetail(err.Error()))
+			c.Abort()
 			return
 		}
 
+		c.Request = c.Request.WithContext(jwtauth.NewContext(c.Request.Context(), claims))
 		c.Next()
 	}
 }
 
-func unauthorizedResp(c *gin.Context, err error) {
-	log.Debug().Err(err).Msg("unauthorized request")
-	c.JSON(http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
-	c.Abort()
-}
-
-func parseAuthHeader(s string) (scheme, token string) {
-	chunks := strings.Split(strings.Trim(s, " "), " ")
-	if len(chunks) == 2 {
-		scheme = strings.ToLower(chunks[0])
-		token = chunks[1]
-	}
-	return
-}
-
-func hmacKeyFunc(hmacSecret []byte) jwt.Keyfunc {
-	if len(hmacSecret) == 0 {
-		return func(token *jwt.Token) (interface{}, error) {
-			return nil, fmt.Errorf("no HMAC secret configured")
+// RequireScope rejects requests whose token lacks the scope. It must run after
+// Authorize.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := jwtauth.FromContext(c.Request.Context())
+		if claims == nil || !claims.HasScope(scope) {
+			_ = c.Error(problem.ErrForbidden.WithDetail(scope + " scope required"))
+			c.Abort()
+			return
 		}
-	}
 
-	return func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return hmacSecret, nil
+		c.Next()
 	}
 }
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type WalletRequest struct {
 	ID int `uri:"wallet" binding:"required,gt=0"`
 }
@@ -16,3 +18,52 @@ type BalanceResponse struct {
 	WalletID int `json:"walletId"`
 	Amount   int `json:"amount"`
 }
+
+type BatchRequest struct {
+	ID int `uri:"batch" binding:"required,gt=0"`
+}
+
+type SubmitBatchRequest struct {
+	Mode  string             `json:"mode" binding:"required,oneof=atomic best_effort"`
+	Items []BatchItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type BatchItemRequest struct {
+	WalletID  int    `json:"walletId" binding:"required,gt=0"`
+	Operation string `json:"operation" binding:"required,oneof=debit credit"`
+	Amount    int    `json:"amount" binding:"gt=0"`
+}
+
+type BatchResponse struct {
+	ID        int                 `json:"id"`
+	Mode      string              `json:"mode"`
+	Status    string              `json:"status"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Items     []BatchItemResponse `json:"items"`
+	CreatedAt time.Time           `json:"createdAt"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+}
+
+type BatchItemResponse struct {
+	WalletID  int    `json:"walletId"`
+	Operation string `json:"operation"`
+	Amount    int    `json:"amount"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+type BalanceEvent struct {
+	ID       int `json:"id"`
+	WalletID int `json:"walletId"`
+	Amount   int `json:"amount"`
+}
+
+// StatementRequest selects the period of a statement, either a calendar month
+// or from and to.
+type StatementRequest struct {
+	Month  string     `form:"month" binding:"omitempty,datetime=2006-01"`
+	From   *time.Time `form:"from" binding:"required_without=Month" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     *time.Time `form:"to" binding:"required_without=Month" time_format:"2006-01-02T15:04:05Z07:00"`
+	Format string     `form:"format" binding:"omitempty,oneof=csv json pdf"`
+}
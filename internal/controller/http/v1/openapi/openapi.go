@@ -0,0 +1,17 @@
+// Package openapi serves the OpenAPI document of the v1 API and a docs UI.
+package openapi
+
+import (
+	_ "embed"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/docs"
+)
+
+//go:embed openapi.json
+var Spec []byte
+
+// RegisterRoutes registers the document and the docs UI. Both are public.
+func RegisterRoutes(e *gin.RouterGroup) {
+	docs.RegisterRoutes(e, Spec)
+}
@@ -0,0 +1,365 @@
+package v1_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/middleware/requestid"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+	httpv1 "github.com/pprishchepa/go-casino-example/internal/controller/http/v1"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/middleware/jwt"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/openapi"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/statement"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testJWTSecret = "test-secret"
+
+type contractMocks struct {
+	wallet    *MockWalletService
+	batch     *MockBatchService
+	statement *MockStatementService
+}
+
+// TestOpenAPIContract sends requests to the real handlers and validates every
+// response against the OpenAPI document, so that handlers and the document
+// cannot drift apart unnoticed.
+func TestOpenAPIContract(t *testing.T) {
+	router := newSpecRouter(t)
+
+	batch := &entity.Batch{
+		ID:        7,
+		Mode:      entity.BatchModeBestEffort,
+		Status:    entity.BatchStatusCompleted,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Items: []entity.BatchItem{
+			{WalletID: 25, Operation: entity.BatchOperationDebit, Amount: money.NewFromInt(100), Status: entity.BatchItemStatusSucceeded},
+			{WalletID: 26, Operation: entity.BatchOperationCredit, Amount: money.NewFromInt(100), Status: entity.BatchItemStatusFailed, Error: "insufficient funds"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		noAuth     bool
+		mock       func(m contractMocks)
+		wantStatus int
+	}{
+		{
+			name:   "balance",
+			method: http.MethodGet,
+			path:   "/api/v1/wallets/25/balance",
+			mock: func(m contractMocks) {
+				m.wallet.EXPECT().GetBalance(gomock.Any(), 25).
+					Return(&domain.WalletBalance{WalletID: 25, Amount: money.NewFromInt(1000), Version: 2}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "balance of invalid wallet",
+			method:     http.MethodGet,
+			path:       "/api/v1/wallets/0/balance",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "balance of unknown wallet",
+			method: http.MethodGet,
+			path:   "/api/v1/wallets/25/balance",
+			mock: func(m contractMocks) {
+				m.wallet.EXPECT().GetBalance(gomock.Any(), 25).Return(nil, domain.ErrWalletNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "balance with internal error",
+			method: http.MethodGet,
+			path:   "/api/v1/wallets/25/balance",
+			mock: func(m contractMocks) {
+				m.wallet.EXPECT().GetBalance(gomock.Any(), 25).Return(nil, errors.New("boom"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "balance without token",
+			method:     http.MethodGet,
+			path:       "/api/v1/wallets/25/balance",
+			noAuth:     true,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "debit",
+			method: http.MethodPost,
+			path:   "/api/v1/wallets/25/debit",
+			body:   `{"amount": 100}`,
+			mock: func(m contractMocks) {
+				m.wallet.EXPECT().DebitMoney(gomock.Any(), gomock.Any()).Return(&entity.Operation{}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "debit with invalid amount",
+			method:     http.MethodPost,
+			path:       "/api/v1/wallets/25/debit",
+			body:       `{"amount": 0}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "credit with insufficient funds",
+			method: http.MethodPost,
+			path:   "/api/v1/wallets/25/credit",
+			body:   `{"amount": 100}`,
+			mock: func(m contractMocks) {
+				m.wallet.EXPECT().CreditMoney(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInsufficientFunds)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "credit with conflict",
+			method: http.MethodPost,
+			path:   "/api/v1/wallets/25/credit",
+			body:   `{"amount": 100}`,
+			mock: func(m contractMocks) {
+				m.wallet.EXPECT().CreditMoney(gomock.Any(), gomock.Any()).Return(nil, entity.ErrTxConflict)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:   "submit batch",
+			method: http.MethodPost,
+			path:   "/api/v1/batches",
+			body:   `{"mode": "atomic", "items": [{"walletId": 25, "operation": "debit", "amount": 100}]}`,
+			mock: func(m contractMocks) {
+				m.batch.EXPECT().SubmitBatch(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, b *entity.Batch) error {
+						b.ID = 7
+						b.Status = entity.BatchStatusPending
+						b.Items[0].Status = entity.BatchItemStatusPending
+						b.CreatedAt, b.UpdatedAt = time.Now(), time.Now()
+						return nil
+					})
+			},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "submit invalid batch",
+			method:     http.MethodPost,
+			path:       "/api/v1/batches",
+			body:       `{"mode": "sometimes", "items": []}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "batch",
+			method: http.MethodGet,
+			path:   "/api/v1/batches/7",
+			mock: func(m contractMocks) {
+				m.batch.EXPECT().GetBatch(gomock.Any(), 7).Return(batch, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "unknown batch",
+			method: http.MethodGet,
+			path:   "/api/v1/batches/8",
+			mock: func(m contractMocks) {
+				m.batch.EXPECT().GetBatch(gomock.Any(), 8).Return(nil, entity.ErrBatchNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "stream of foreign wallet",
+			method:     http.MethodGet,
+			path:       "/api/v1/wallets/99/balance/stream",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "statement",
+			method: http.MethodGet,
+			path:   "/api/v1/wallets/25/statements?month=2024-06",
+			mock: func(m contractMocks) {
+				period := statement.Month(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+				amount := money.NewFromInt(5000)
+				m.statement.EXPECT().Statement(gomock.Any(), 25, period).Return(statement.New(25, period, statement.Balance{}, []domain.Entry{{
+					ID: 4, WalletID: 25, Debit: &amount, BalanceAfter: amount, CreatedAt: period.From.Add(time.Hour),
+					EntryDetails: domain.EntryDetails{Operation: domain.OperationDeposit, Reference: "dep-1", Initiator: domain.InitiatorService},
+				}}), nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "statement without period",
+			method:     http.MethodGet,
+			path:       "/api/v1/wallets/25/statements",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "statement in unknown format",
+			method:     http.MethodGet,
+			path:       "/api/v1/wallets/25/statements?month=2024-06&format=xlsx",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "statement of backwards period",
+			method: http.MethodGet,
+			path:   "/api/v1/wallets/25/statements?from=2024-06-02T00:00:00Z&to=2024-06-01T00:00:00Z",
+			mock: func(m contractMocks) {
+				m.statement.EXPECT().Statement(gomock.Any(), 25, gomock.Any()).
+					Return(nil, fmt.Errorf("statement: %w", statement.ErrInvalidPeriod))
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "statement of unknown wallet",
+			method: http.MethodGet,
+			path:   "/api/v1/wallets/25/statements?month=2024-06",
+			mock: func(m contractMocks) {
+				m.statement.EXPECT().Statement(gomock.Any(), 25, gomock.Any()).Return(nil, domain.ErrWalletNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "statement of foreign wallet",
+			method:     http.MethodGet,
+			path:       "/api/v1/wallets/99/statements?month=2024-06",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mocks := contractMocks{
+				wallet:    NewMockWalletService(mockCtrl),
+				batch:     NewMockBatchService(mockCtrl),
+				statement: NewMockStatementService(mockCtrl),
+			}
+			if tt.mock != nil {
+				tt.mock(mocks)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if !tt.noAuth {
+				req.Header.Set("Authorization", "Bearer "+newTestToken(t, 25))
+			}
+
+			rec := httptest.NewRecorder()
+			newContractEngine(mocks).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			validateResponse(t, router, req, rec)
+		})
+	}
+}
+
+// TestOpenAPIRoutesDocumented fails when a route is added without documenting it.
+func TestOpenAPIRoutesDocumented(t *testing.T) {
+	router := newSpecRouter(t)
+
+	mockCtrl := gomock.NewController(t)
+	e := newContractEngine(contractMocks{
+		wallet:    NewMockWalletService(mockCtrl),
+		batch:     NewMockBatchService(mockCtrl),
+		statement: NewMockStatementService(mockCtrl),
+	})
+
+	for _, route := range e.Routes() {
+		path := strings.ReplaceAll(route.Path, ":wallet", "1")
+		path = strings.ReplaceAll(path, ":batch", "1")
+		req := httptest.NewRequest(route.Method, "http://localhost"+path, nil)
+
+		_, _, err := router.FindRoute(req)
+		assert.NoError(t, err, "%s %s is not documented", route.Method, route.Path)
+	}
+}
+
+func newContractEngine(m contractMocks) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	e := gin.New()
+	e.Use(requestid.New(), problem.Handler())
+	v1 := e.Group("/api/v1", jwt.Authorize(testJWTSecret))
+	{
+		httpv1.NewWalletRoutes(m.wallet).RegisterRoutes(v1)
+		httpv1.NewBatchRoutes(m.batch, 100).RegisterRoutes(v1)
+		httpv1.NewBalanceStreamRoutes(m.wallet, service.NewBalanceBroker(), time.Second).RegisterRoutes(v1)
+		httpv1.NewStatementRoutes(m.statement).RegisterRoutes(v1)
+	}
+
+	return e
+}
+
+func newSpecRouter(t *testing.T) routers.Router {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromData(openapi.Spec)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+
+	doc.Servers = openapi3.Servers{{URL: "http://localhost/api/v1"}}
+
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	return router
+}
+
+func validateResponse(t *testing.T, router routers.Router, req *http.Request, rec *httptest.ResponseRecorder) {
+	t.Helper()
+
+	specReq := req.Clone(context.Background())
+	specReq.URL.Scheme, specReq.URL.Host = "http", "localhost"
+
+	route, pathParams, err := router.FindRoute(specReq)
+	require.NoError(t, err)
+
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    specReq,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status: rec.Code,
+		Header: rec.Header(),
+		Body:   io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+		},
+	})
+	require.NoError(t, err, rec.Body.String())
+}
+
+func newTestToken(t *testing.T, wallets ...int) string {
+	t.Helper()
+
+	token, err := gojwt.NewWithClaims(gojwt.SigningMethodHS256, gojwt.MapClaims{
+		"sub":     "player",
+		"wallets": wallets,
+	}).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+
+	return token
+}
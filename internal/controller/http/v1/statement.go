@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/model"
+	"github.com/pprishchepa/go-casino-example/internal/statement"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=statement.go -destination=statement_mock_test.go -package=v1_test
+
+type StatementService interface {
+	Statement(ctx context.Context, walletID int, period statement.Period) (*statement.Statement, error)
+}
+
+type StatementRoutes struct {
+	service StatementService
+}
+
+func NewStatementRoutes(service StatementService) *StatementRoutes {
+	return &StatementRoutes{service: service}
+}
+
+func (r StatementRoutes) RegisterRoutes(e *gin.RouterGroup) {
+	e.GET("/wallets/:wallet/statements", r.retrieveStatement)
+}
+
+// retrieveStatement renders the statement of the wallet as a file. Players get
+// statements of their own wallets only, support staff of any.
+func (r StatementRoutes) retrieveStatement(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var req model.StatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if !authorizeWallet(c, reqWallet.ID) {
+		return
+	}
+
+	var period statement.Period
+	if req.Month != "" {
+		// The binding has checked the layout already.
+		month, _ := time.Parse("2006-01", req.Month)
+		period = statement.Month(month)
+	} else {
+		period = statement.Period{From: *req.From, To: *req.To}
+	}
+
+	format := statement.FormatJSON
+	if req.Format != "" {
+		format = statement.Format(req.Format)
+	}
+
+	st, err := r.service.Statement(c.Request.Context(), reqWallet.ID, period)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// Render in full first, so that a failure is still reported as a problem.
+	var buf bytes.Buffer
+	if err := st.Render(&buf, format); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+st.FileName(format)+`"`)
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
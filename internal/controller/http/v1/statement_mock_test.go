@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: statement.go
+//
+// Generated by this command:
+//
+//	mockgen -source=statement.go -destination=statement_mock_test.go -package=v1_test
+//
+
+// Package v1_test is a generated GoMock package.
+package v1_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	statement "github.com/pprishchepa/go-casino-example/internal/statement"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStatementService is a mock of StatementService interface.
+type MockStatementService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatementServiceMockRecorder
+}
+
+// MockStatementServiceMockRecorder is the mock recorder for MockStatementService.
+type MockStatementServiceMockRecorder struct {
+	mock *MockStatementService
+}
+
+// NewMockStatementService creates a new mock instance.
+func NewMockStatementService(ctrl *gomock.Controller) *MockStatementService {
+	mock := &MockStatementService{ctrl: ctrl}
+	mock.recorder = &MockStatementServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatementService) EXPECT() *MockStatementServiceMockRecorder {
+	return m.recorder
+}
+
+// Statement mocks base method.
+func (m *MockStatementService) Statement(ctx context.Context, walletID int, period statement.Period) (*statement.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Statement", ctx, walletID, period)
+	ret0, _ := ret[0].(*statement.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Statement indicates an expected call of Statement.
+func (mr *MockStatementServiceMockRecorder) Statement(ctx, walletID, period any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Statement", reflect.TypeOf((*MockStatementService)(nil).Statement), ctx, walletID, period)
+}
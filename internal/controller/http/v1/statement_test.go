@@ -0,0 +1,63 @@
+package v1_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
+	"github.com/pprishchepa/go-casino-example/internal/statement"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStatementRoutes_Formats(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	period := statement.Period{From: from, To: to}
+
+	// Support staff are not owners of the wallet.
+	adminToken, err := gojwt.NewWithClaims(gojwt.SigningMethodHS256, gojwt.MapClaims{
+		"sub":    "support",
+		"scopes": []string{jwtauth.ScopeAdmin},
+	}).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+
+	tests := []struct {
+		format          string
+		wantContentType string
+		wantBody        string
+	}{
+		{format: "csv", wantContentType: "text/csv", wantBody: "kind,wallet_id,"},
+		{format: "pdf", wantContentType: "application/pdf", wantBody: "%PDF-1.4"},
+		{format: "json", wantContentType: "application/json", wantBody: `{"walletId":99,`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			svc := NewMockStatementService(mockCtrl)
+			svc.EXPECT().Statement(gomock.Any(), 99, period).Return(statement.New(99, period, statement.Balance{}, nil), nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/wallets/99/statements?format="+tt.format+
+				"&from=2024-06-01T00:00:00Z&to=2024-06-15T00:00:00Z", nil)
+			req.Header.Set("Authorization", "Bearer "+adminToken)
+
+			rec := httptest.NewRecorder()
+			newContractEngine(contractMocks{
+				wallet:    NewMockWalletService(mockCtrl),
+				batch:     NewMockBatchService(mockCtrl),
+				statement: svc,
+			}).ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.wantContentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="statement-99-2024-06-01.`+tt.format+`"`,
+				rec.Header().Get("Content-Disposition"))
+			assert.True(t, strings.HasPrefix(rec.Body.String(), tt.wantBody), rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/model"
+	"github.com/rs/zerolog/log"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=stream.go -destination=stream_mock_test.go -package=v1_test
+
+type BalanceWatcher interface {
+	Subscribe(walletID int) (<-chan domain.WalletBalance, func())
+}
+
+// BalanceStreamRoutes push balance changes to clients over Server-Sent Events
+// and WebSocket. Event IDs are balance versions, so a reconnecting client
+// that passes the last seen ID only gets a balance newer than that.
+type BalanceStreamRoutes struct {
+	service   WalletService
+	watcher   BalanceWatcher
+	heartbeat time.Duration
+	upgrader  websocket.Upgrader
+}
+
+func NewBalanceStreamRoutes(service WalletService, watcher BalanceWatcher, heartbeat time.Duration) *BalanceStreamRoutes {
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+	return &BalanceStreamRoutes{
+		service:   service,
+		watcher:   watcher,
+		heartbeat: heartbeat,
+	}
+}
+
+func (r *BalanceStreamRoutes) RegisterRoutes(e *gin.RouterGroup) {
+	e.GET("/wallets/:wallet/balance/stream", r.streamBalance)
+	e.GET("/wallets/:wallet/balance/ws", r.streamBalanceWS)
+}
+
+func (r *BalanceStreamRoutes) streamBalance(c *gin.Context) {
+	walletID, lastEventID, ok := r.bindStreamRequest(c, c.GetHeader("Last-Event-ID"))
+	if !ok {
+		return
+	}
+
+	// Streams outlive the server write timeout.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn().Err(err).Msg("could not reset write deadline")
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	err := r.watch(c.Request.Context(), walletID, lastEventID,
+		func(balance *domain.WalletBalance) error {
+			if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: balance\ndata: ", balance.Version); err != nil {
+				return err
+			}
+			if err := json.NewEncoder(c.Writer).Encode(newBalanceEvent(balance)); err != nil {
+				return err
+			}
+			if _, err := c.Writer.WriteString("\n"); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+			return nil
+		},
+		func() error {
+			if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+			return nil
+		},
+	)
+	if err != nil {
+		log.Debug().Err(err).Int("walletId", walletID).Msg("balance stream closed")
+	}
+}
+
+func (r *BalanceStreamRoutes) streamBalanceWS(c *gin.Context) {
+	walletID, lastEventID, ok := r.bindStreamRequest(c, c.Query("lastEventId"))
+	if !ok {
+		return
+	}
+
+	conn, err := r.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Debug().Err(err).Int("walletId", walletID).Msg("could not upgrade to websocket")
+		return
+	}
+	defer conn.Close()
+
+	// The client is not expected to send anything; reading detects disconnects
+	// and handles control frames.
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	writeTimeout := max(r.heartbeat, time.Second)
+
+	err = r.watch(ctx, walletID, lastEventID,
+		func(balance *domain.WalletBalance) error {
+			if err := conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+				return err
+			}
+			return conn.WriteJSON(newBalanceEvent(balance))
+		},
+		func() error {
+			return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout))
+		},
+	)
+	if err != nil {
+		log.Debug().Err(err).Int("walletId", walletID).Msg("balance websocket closed")
+		return
+	}
+
+	_ = conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(time.Second))
+}
+
+// bindStreamRequest validates the wallet and that the token holder owns it or
+// is an admin. It writes the
+// response and returns false if the stream must not be opened.
+func (r *BalanceStreamRoutes) bindStreamRequest(c *gin.Context, lastEventID string) (int, int, bool) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return 0, 0, false
+	}
+
+	if !authorizeWallet(c, reqWallet.ID) {
+		return 0, 0, false
+	}
+
+	var version int
+	if lastEventID != "" {
+		var err error
+		if version, err = strconv.Atoi(lastEventID); err != nil || version < 0 {
+			_ = c.Error(problem.ErrValidation.WithDetail("invalid last event id"))
+			return 0, 0, false
+		}
+	}
+
+	// Fail before the stream starts if the wallet does not exist.
+	if _, err := r.service.GetBalance(c.Request.Context(), reqWallet.ID); err != nil {
+		_ = c.Error(err)
+		return 0, 0, false
+	}
+
+	return reqWallet.ID, version, true
+}
+
+// watch sends the current balance unless the client has already seen it, then
+// every newer balance, and calls heartbeat when nothing happens for a while.
+func (r *BalanceStreamRoutes) watch(
+	ctx context.Context,
+	walletID int,
+	lastVersion int,
+	send func(balance *domain.WalletBalance) error,
+	heartbeat func() error,
+) error {
+	changes, unsubscribe := r.watcher.Subscribe(walletID)
+	defer unsubscribe()
+
+	balance, err := r.service.GetBalance(ctx, walletID)
+	if err != nil {
+		return fmt.Errorf("get balance: %w", err)
+	}
+	if lastVersion == 0 || balance.Version > lastVersion {
+		if err := send(balance); err != nil {
+			return fmt.Errorf("send balance: %w", err)
+		}
+		lastVersion = balance.Version
+	}
+
+	ticker := time.NewTicker(r.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := heartbeat(); err != nil {
+				return fmt.Errorf("send heartbeat: %w", err)
+			}
+		case balance, ok := <-changes:
+			if !ok {
+				return nil // the watcher has stopped, e.g. on shutdown
+			}
+			if balance.Version <= lastVersion {
+				continue
+			}
+			if err := send(&balance); err != nil {
+				return fmt.Errorf("send balance: %w", err)
+			}
+			lastVersion = balance.Version
+			ticker.Reset(r.heartbeat)
+		}
+	}
+}
+
+func newBalanceEvent(balance *domain.WalletBalance) model.BalanceEvent {
+	return model.BalanceEvent{
+		ID:       balance.Version,
+		WalletID: balance.WalletID,
+		Amount:   balance.Total().AsInt(),
+	}
+}
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: stream.go
+//
+// Generated by this command:
+//
+//	mockgen -source=stream.go -destination=stream_mock_test.go -package=v1_test
+//
+
+// Package v1_test is a generated GoMock package.
+package v1_test
+
+import (
+	reflect "reflect"
+
+	domain "github.com/pprishchepa/go-casino-example/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBalanceWatcher is a mock of BalanceWatcher interface.
+type MockBalanceWatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceWatcherMockRecorder
+}
+
+// MockBalanceWatcherMockRecorder is the mock recorder for MockBalanceWatcher.
+type MockBalanceWatcherMockRecorder struct {
+	mock *MockBalanceWatcher
+}
+
+// NewMockBalanceWatcher creates a new mock instance.
+func NewMockBalanceWatcher(ctrl *gomock.Controller) *MockBalanceWatcher {
+	mock := &MockBalanceWatcher{ctrl: ctrl}
+	mock.recorder = &MockBalanceWatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceWatcher) EXPECT() *MockBalanceWatcherMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockBalanceWatcher) Subscribe(walletID int) (<-chan domain.WalletBalance, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", walletID)
+	ret0, _ := ret[0].(<-chan domain.WalletBalance)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockBalanceWatcherMockRecorder) Subscribe(walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockBalanceWatcher)(nil).Subscribe), walletID)
+}
@@ -0,0 +1,201 @@
+package v1_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+	httpv1 "github.com/pprishchepa/go-casino-example/internal/controller/http/v1"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBalanceStreamRoutes_SSE(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	svc := NewMockWalletService(mockCtrl)
+	svc.EXPECT().GetBalance(gomock.Any(), 25).Return(&domain.WalletBalance{
+		WalletID: 25,
+		Amount:   money.NewFromInt(1000),
+		Version:  3,
+	}, nil).AnyTimes()
+
+	broker := service.NewBalanceBroker()
+	srv := httptest.NewServer(newStreamRouter(broker, svc, &jwtauth.Claims{Wallets: []int{25}}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/wallets/25/balance/stream", nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := readEvents(resp)
+
+	assert.Equal(t, "id: 3\nevent: balance\ndata: {\"id\":3,\"walletId\":25,\"amount\":1000}", nextBalanceEvent(events))
+
+	broker.NotifyBalance(ctx, &domain.WalletBalance{WalletID: 25, Amount: money.NewFromInt(900), Version: 2})
+	broker.NotifyBalance(ctx, &domain.WalletBalance{WalletID: 25, Amount: money.NewFromInt(1100), Version: 4})
+
+	assert.Equal(t, "id: 4\nevent: balance\ndata: {\"id\":4,\"walletId\":25,\"amount\":1100}", nextBalanceEvent(events))
+}
+
+func TestBalanceStreamRoutes_SSEResume(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	svc := NewMockWalletService(mockCtrl)
+	svc.EXPECT().GetBalance(gomock.Any(), 25).Return(&domain.WalletBalance{
+		WalletID: 25,
+		Amount:   money.NewFromInt(1000),
+		Version:  3,
+	}, nil).AnyTimes()
+
+	broker := service.NewBalanceBroker()
+	srv := httptest.NewServer(newStreamRouter(broker, svc, &jwtauth.Claims{Wallets: []int{25}}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/wallets/25/balance/stream", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "3")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	events := readEvents(resp)
+
+	// The client has already seen version 3, so the first event is a heartbeat.
+	assert.Equal(t, ": heartbeat", <-events)
+}
+
+func TestBalanceStreamRoutes_ForeignWallet(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	srv := httptest.NewServer(newStreamRouter(service.NewBalanceBroker(), NewMockWalletService(mockCtrl), &jwtauth.Claims{Wallets: []int{26}}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/wallets/25/balance/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestBalanceStreamRoutes_AdminForeignWallet(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	svc := NewMockWalletService(mockCtrl)
+	svc.EXPECT().GetBalance(gomock.Any(), 25).Return(&domain.WalletBalance{
+		WalletID: 25,
+		Amount:   money.NewFromInt(1000),
+		Version:  3,
+	}, nil).AnyTimes()
+
+	srv := httptest.NewServer(newStreamRouter(service.NewBalanceBroker(), svc, &jwtauth.Claims{Scopes: []string{jwtauth.ScopeAdmin}}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/wallets/25/balance/stream", nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "id: 3\nevent: balance\ndata: {\"id\":3,\"walletId\":25,\"amount\":1000}", nextBalanceEvent(readEvents(resp)))
+}
+
+func TestBalanceStreamRoutes_WatcherStopped(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	svc := NewMockWalletService(mockCtrl)
+	svc.EXPECT().GetBalance(gomock.Any(), 25).Return(&domain.WalletBalance{
+		WalletID: 25,
+		Amount:   money.NewFromInt(1000),
+		Version:  3,
+	}, nil).AnyTimes()
+
+	changes := make(chan domain.WalletBalance)
+	close(changes)
+	watcher := NewMockBalanceWatcher(mockCtrl)
+	watcher.EXPECT().Subscribe(25).Return(changes, func() {})
+
+	srv := httptest.NewServer(newStreamRouter(watcher, svc, &jwtauth.Claims{Wallets: []int{25}}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/wallets/25/balance/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// The stream ends after the current balance instead of spinning on the
+	// closed channel.
+	events := readEvents(resp)
+	assert.Equal(t, "id: 3\nevent: balance\ndata: {\"id\":3,\"walletId\":25,\"amount\":1000}", <-events)
+	_, open := <-events
+	assert.False(t, open)
+}
+
+func newStreamRouter(watcher httpv1.BalanceWatcher, svc httpv1.WalletService, claims *jwtauth.Claims) http.Handler {
+	gin.SetMode(gin.TestMode)
+
+	e := gin.New()
+	e.Use(problem.Handler())
+	e.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(jwtauth.NewContext(c.Request.Context(), claims))
+	})
+	httpv1.NewBalanceStreamRoutes(svc, watcher, 50*time.Millisecond).RegisterRoutes(e.Group(""))
+
+	return e
+}
+
+// readEvents splits the SSE body into events without the trailing blank line.
+func readEvents(resp *http.Response) <-chan string {
+	events := make(chan string)
+	go func() {
+		defer close(events)
+		var lines []string
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if scanner.Text() != "" {
+				lines = append(lines, scanner.Text())
+				continue
+			}
+			events <- strings.Join(lines, "\n")
+			lines = nil
+		}
+	}()
+	return events
+}
+
+func nextBalanceEvent(events <-chan string) string {
+	for event := range events {
+		if event != ": heartbeat" {
+			return event
+		}
+	}
+	return ""
+}
@@ -2,22 +2,23 @@ package v1
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pprishchepa/go-casino-example/domain"
 	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
 	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/model"
-	"github.com/rs/zerolog/log"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
 )
 
 //go:generate go run go.uber.org/mock/mockgen -source=wallet.go -destination=wallet_mock_test.go -package=v1_test
 
 type WalletService interface {
 	GetBalance(ctx context.Context, walletID int) (*domain.WalletBalance, error)
-	DebitMoney(ctx context.Context, entry domain.DebitEntry) error
-	CreditMoney(ctx context.Context, entry domain.CreditEntry) error
+	DebitMoney(ctx context.Context, entry domain.DebitEntry) (*entity.Operation, error)
+	CreditMoney(ctx context.Context, entry domain.CreditEntry) (*entity.Operation, error)
 }
 
 type WalletRoutes struct {
@@ -37,41 +38,41 @@ func (r WalletRoutes) RegisterRoutes(e *gin.RouterGroup) {
 func (r WalletRoutes) retrieveBalance(c *gin.Context) {
 	var reqWallet model.WalletRequest
 	if err := c.ShouldBindUri(&reqWallet); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	balance, err := r.service.GetBalance(c.Request.Context(), reqWallet.ID)
 	if err != nil {
-		r.handleError(c, err, reqWallet.ID, "could not get balance")
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"data": model.BalanceResponse{
 		WalletID: balance.WalletID,
-		Amount:   balance.Amount.AsInt(),
+		Amount:   balance.Total().AsInt(),
 	}})
 }
 
 func (r WalletRoutes) debitMoney(c *gin.Context) {
 	var reqWallet model.WalletRequest
 	if err := c.ShouldBindUri(&reqWallet); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	var reqBody model.DebitMoneyRequest
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	err := r.service.DebitMoney(c.Request.Context(), domain.DebitEntry{
+	_, err := r.service.DebitMoney(c.Request.Context(), domain.DebitEntry{
 		WalletID: reqWallet.ID,
 		Amount:   money.NewFromInt(reqBody.Amount),
 	})
 	if err != nil {
-		r.handleError(c, err, reqWallet.ID, "could not debit money")
+		_ = c.Error(err)
 		return
 	}
 
@@ -81,47 +82,35 @@ func (r WalletRoutes) debitMoney(c *gin.Context) {
 func (r WalletRoutes) creditMoney(c *gin.Context) {
 	var reqWallet model.WalletRequest
 	if err := c.ShouldBindUri(&reqWallet); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	var reqBody model.CreditMoneyRequest
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	err := r.service.CreditMoney(c.Request.Context(), domain.CreditEntry{
+	_, err := r.service.CreditMoney(c.Request.Context(), domain.CreditEntry{
 		WalletID: reqWallet.ID,
 		Amount:   money.NewFromInt(reqBody.Amount),
 	})
 	if err != nil {
-		r.handleError(c, err, reqWallet.ID, "could not credit money")
+		_ = c.Error(err)
 		return
 	}
 
 	c.Status(http.StatusOK)
 }
 
-func (r WalletRoutes) handleError(c *gin.Context, err error, walletID int, msg string) {
-	if errors.Is(err, domain.ErrWalletNotFound) {
-		log.Debug().Err(err).Int("walletId", walletID).Msg(msg)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "wallet not found"})
-		return
-	}
-
-	if errors.Is(err, domain.ErrInvalidAmount) {
-		log.Debug().Err(err).Int("walletId", walletID).Msg(msg)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid amount"})
-		return
+// authorizeWallet reports whether the token holder owns the wallet or is an
+// admin, and adds a problem to the request if neither.
+func authorizeWallet(c *gin.Context, walletID int) bool {
+	claims := jwtauth.FromContext(c.Request.Context())
+	if claims == nil || !(claims.OwnsWallet(walletID) || claims.HasScope(jwtauth.ScopeAdmin)) {
+		_ = c.Error(problem.ErrForbidden.WithDetail("wallet access denied"))
+		return false
 	}
-
-	if errors.Is(err, domain.ErrInsufficientFunds) {
-		log.Debug().Err(err).Int("walletId", walletID).Msg(msg)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "insufficient funds"})
-		return
-	}
-
-	log.Err(err).Int("walletId", walletID).Msg(msg)
-	c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+	return true
 }
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	domain "github.com/pprishchepa/go-casino-example/domain"
+	entity "github.com/pprishchepa/go-casino-example/internal/entity"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,11 +42,12 @@ func (m *MockWalletService) EXPECT() *MockWalletServiceMockRecorder {
 }
 
 // CreditMoney mocks base method.
-func (m *MockWalletService) CreditMoney(ctx context.Context, entry domain.CreditEntry) error {
+func (m *MockWalletService) CreditMoney(ctx context.Context, entry domain.CreditEntry) (*entity.Operation, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreditMoney", ctx, entry)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*entity.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreditMoney indicates an expected call of CreditMoney.
@@ -55,11 +57,12 @@ func (mr *MockWalletServiceMockRecorder) CreditMoney(ctx, entry any) *gomock.Cal
 }
 
 // DebitMoney mocks base method.
-func (m *MockWalletService) DebitMoney(ctx context.Context, entry domain.DebitEntry) error {
+func (m *MockWalletService) DebitMoney(ctx context.Context, entry domain.DebitEntry) (*entity.Operation, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DebitMoney", ctx, entry)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*entity.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DebitMoney indicates an expected call of DebitMoney.
@@ -0,0 +1,312 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v2/model"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=admin.go -destination=admin_mock_test.go -package=v2_test
+
+type AdminService interface {
+	SearchWallets(ctx context.Context, filter domain.WalletFilter) ([]domain.WalletBalance, error)
+	GetStatus(ctx context.Context, walletID int) (*domain.WalletStatus, error)
+	ListEntries(ctx context.Context, filter domain.EntryFilter) ([]domain.Entry, error)
+	AdjustBalance(ctx context.Context, walletID int, amount money.Money, actor, reason string) (*domain.Entry, error)
+	FreezeWallet(ctx context.Context, walletID int, actor, reason string) (*domain.WalletBalance, error)
+	UnfreezeWallet(ctx context.Context, walletID int, actor, reason string) (*domain.WalletBalance, error)
+	OverrideLimit(
+		ctx context.Context,
+		walletID int,
+		kind domain.LimitKind,
+		period domain.LimitPeriod,
+		amount *money.Money,
+		actor, reason string,
+	) (*domain.Limit, error)
+	EvictBalance(ctx context.Context, walletID int, actor, reason string) error
+	ListAdminActions(ctx context.Context, filter domain.AdminActionFilter) ([]domain.AdminAction, error)
+}
+
+// defaultAdminLimit is the page size of wallet and action listings when none
+// is given.
+const defaultAdminLimit = 100
+
+// AdminRoutes are the back-office routes. They must be registered on a group
+// that requires the admin scope, as every change is recorded with the
+// subject of the token as its actor.
+type AdminRoutes struct {
+	service AdminService
+}
+
+func NewAdminRoutes(service AdminService) *AdminRoutes {
+	return &AdminRoutes{service: service}
+}
+
+func (r AdminRoutes) RegisterRoutes(e *gin.RouterGroup) {
+	e.GET("/wallets", r.searchWallets)
+	e.GET("/wallets/:wallet", r.getWallet)
+	e.GET("/wallets/:wallet/entries", r.listEntries)
+	e.POST("/wallets/:wallet/adjustments", r.adjustBalance)
+	e.POST("/wallets/:wallet/freeze", r.freezeWallet)
+	e.POST("/wallets/:wallet/unfreeze", r.unfreezeWallet)
+	e.PUT("/wallets/:wallet/limits/:kind/:period", r.overrideLimit)
+	e.POST("/wallets/:wallet/cache/evict", r.evictBalance)
+	e.GET("/actions", r.listActions)
+}
+
+func (r AdminRoutes) searchWallets(c *gin.Context) {
+	var req model.SearchWalletsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var fields amountFields
+	filter := domain.WalletFilter{
+		Flagged:   req.Flagged,
+		Frozen:    req.Frozen,
+		MinAmount: parseBound(&fields, "minAmount", req.MinAmount),
+		MaxAmount: parseBound(&fields, "maxAmount", req.MaxAmount),
+		AfterID:   req.After,
+		Limit:     req.Limit,
+	}
+	if len(fields) > 0 {
+		_ = c.Error(fields.problem())
+		return
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAdminLimit
+	}
+
+	balances, err := r.service.SearchWallets(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res := make([]model.AdminWalletResponse, 0, len(balances))
+	for i := range balances {
+		res = append(res, newAdminWalletResponse(&balances[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// getWallet returns the status of the wallet as the wallet itself sees it,
+// including the freeze.
+func (r AdminRoutes) getWallet(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	status, err := r.service.GetStatus(c.Request.Context(), reqWallet.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newWalletStatusResponse(status)})
+}
+
+func (r AdminRoutes) listEntries(c *gin.Context) {
+	filter, ok := bindEntryFilter(c)
+	if !ok {
+		return
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultEntriesLimit
+	}
+
+	entries, err := r.service.ListEntries(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res := make([]model.EntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		res = append(res, newEntryResponse(entry))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func (r AdminRoutes) adjustBalance(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var reqBody model.AdjustBalanceRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var fields amountFields
+	amount := fields.parseSigned("amount", reqBody.Amount)
+	if len(fields) > 0 {
+		_ = c.Error(fields.problem())
+		return
+	}
+
+	entry, err := r.service.AdjustBalance(c.Request.Context(), reqWallet.ID, amount, actor(c), reqBody.Reason)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": newEntryResponse(*entry)})
+}
+
+func (r AdminRoutes) freezeWallet(c *gin.Context) {
+	r.setFrozen(c, r.service.FreezeWallet)
+}
+
+func (r AdminRoutes) unfreezeWallet(c *gin.Context) {
+	r.setFrozen(c, r.service.UnfreezeWallet)
+}
+
+func (r AdminRoutes) setFrozen(
+	c *gin.Context,
+	set func(ctx context.Context, walletID int, actor, reason string) (*domain.WalletBalance, error),
+) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var reqBody model.AdminReasonRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	balance, err := set(c.Request.Context(), reqWallet.ID, actor(c), reqBody.Reason)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newAdminWalletResponse(balance)})
+}
+
+// overrideLimit sets or removes a limit at once, skipping the cooling-off
+// period a player has to wait for.
+func (r AdminRoutes) overrideLimit(c *gin.Context) {
+	var reqLimit model.LimitRequest
+	if err := c.ShouldBindUri(&reqLimit); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var reqBody model.OverrideLimitRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var fields amountFields
+	amount := parseBound(&fields, "amount", reqBody.Amount)
+	if len(fields) > 0 {
+		_ = c.Error(fields.problem())
+		return
+	}
+
+	limit, err := r.service.OverrideLimit(c.Request.Context(), reqLimit.WalletID,
+		domain.LimitKind(reqLimit.Kind), domain.LimitPeriod(reqLimit.Period), amount, actor(c), reqBody.Reason)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newLimitResponse(*limit)})
+}
+
+func (r AdminRoutes) evictBalance(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var reqBody model.EvictBalanceRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			_ = c.Error(err).SetType(gin.ErrorTypeBind)
+			return
+		}
+	}
+
+	if err := r.service.EvictBalance(c.Request.Context(), reqWallet.ID, actor(c), reqBody.Reason); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (r AdminRoutes) listActions(c *gin.Context) {
+	var req model.ListAdminActionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	filter := domain.AdminActionFilter{
+		WalletID: req.WalletID,
+		Actor:    req.Actor,
+		Kind:     domain.AdminActionKind(req.Kind),
+		AfterID:  req.After,
+		Limit:    req.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAdminLimit
+	}
+
+	actions, err := r.service.ListAdminActions(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res := make([]model.AdminActionResponse, 0, len(actions))
+	for _, action := range actions {
+		res = append(res, model.AdminActionResponse{
+			ID:        action.ID,
+			WalletID:  action.WalletID,
+			Kind:      string(action.Kind),
+			Actor:     action.Actor,
+			Reason:    action.Reason,
+			Details:   action.Details,
+			CreatedAt: action.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// parseBound parses an optional non-negative amount that is nil if not given.
+func parseBound(fields *amountFields, field, s string) *money.Money {
+	if s == "" {
+		return nil
+	}
+	amount := fields.parse(field, s, true)
+	return &amount
+}
+
+func newAdminWalletResponse(balance *domain.WalletBalance) model.AdminWalletResponse {
+	return model.AdminWalletResponse{
+		BalanceResponse: newBalanceResponse(balance),
+		Flagged:         balance.Flagged,
+		Frozen:          balance.Frozen,
+	}
+}
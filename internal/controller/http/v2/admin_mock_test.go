@@ -0,0 +1,176 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: admin.go
+//
+// Generated by this command:
+//
+//	mockgen -source=admin.go -destination=admin_mock_test.go -package=v2_test
+//
+
+// Package v2_test is a generated GoMock package.
+package v2_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/pprishchepa/go-casino-example/domain"
+	money "github.com/pprishchepa/go-casino-example/domain/money"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAdminService is a mock of AdminService interface.
+type MockAdminService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminServiceMockRecorder
+}
+
+// MockAdminServiceMockRecorder is the mock recorder for MockAdminService.
+type MockAdminServiceMockRecorder struct {
+	mock *MockAdminService
+}
+
+// NewMockAdminService creates a new mock instance.
+func NewMockAdminService(ctrl *gomock.Controller) *MockAdminService {
+	mock := &MockAdminService{ctrl: ctrl}
+	mock.recorder = &MockAdminServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminService) EXPECT() *MockAdminServiceMockRecorder {
+	return m.recorder
+}
+
+// AdjustBalance mocks base method.
+func (m *MockAdminService) AdjustBalance(ctx context.Context, walletID int, amount money.Money, actor, reason string) (*domain.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustBalance", ctx, walletID, amount, actor, reason)
+	ret0, _ := ret[0].(*domain.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustBalance indicates an expected call of AdjustBalance.
+func (mr *MockAdminServiceMockRecorder) AdjustBalance(ctx, walletID, amount, actor, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustBalance", reflect.TypeOf((*MockAdminService)(nil).AdjustBalance), ctx, walletID, amount, actor, reason)
+}
+
+// EvictBalance mocks base method.
+func (m *MockAdminService) EvictBalance(ctx context.Context, walletID int, actor, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvictBalance", ctx, walletID, actor, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EvictBalance indicates an expected call of EvictBalance.
+func (mr *MockAdminServiceMockRecorder) EvictBalance(ctx, walletID, actor, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictBalance", reflect.TypeOf((*MockAdminService)(nil).EvictBalance), ctx, walletID, actor, reason)
+}
+
+// FreezeWallet mocks base method.
+func (m *MockAdminService) FreezeWallet(ctx context.Context, walletID int, actor, reason string) (*domain.WalletBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeWallet", ctx, walletID, actor, reason)
+	ret0, _ := ret[0].(*domain.WalletBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeWallet indicates an expected call of FreezeWallet.
+func (mr *MockAdminServiceMockRecorder) FreezeWallet(ctx, walletID, actor, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeWallet", reflect.TypeOf((*MockAdminService)(nil).FreezeWallet), ctx, walletID, actor, reason)
+}
+
+// GetStatus mocks base method.
+func (m *MockAdminService) GetStatus(ctx context.Context, walletID int) (*domain.WalletStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", ctx, walletID)
+	ret0, _ := ret[0].(*domain.WalletStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockAdminServiceMockRecorder) GetStatus(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockAdminService)(nil).GetStatus), ctx, walletID)
+}
+
+// ListAdminActions mocks base method.
+func (m *MockAdminService) ListAdminActions(ctx context.Context, filter domain.AdminActionFilter) ([]domain.AdminAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAdminActions", ctx, filter)
+	ret0, _ := ret[0].([]domain.AdminAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAdminActions indicates an expected call of ListAdminActions.
+func (mr *MockAdminServiceMockRecorder) ListAdminActions(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAdminActions", reflect.TypeOf((*MockAdminService)(nil).ListAdminActions), ctx, filter)
+}
+
+// ListEntries mocks base method.
+func (m *MockAdminService) ListEntries(ctx context.Context, filter domain.EntryFilter) ([]domain.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", ctx, filter)
+	ret0, _ := ret[0].([]domain.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockAdminServiceMockRecorder) ListEntries(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockAdminService)(nil).ListEntries), ctx, filter)
+}
+
+// OverrideLimit mocks base method.
+func (m *MockAdminService) OverrideLimit(ctx context.Context, walletID int, kind domain.LimitKind, period domain.LimitPeriod, amount *money.Money, actor, reason string) (*domain.Limit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OverrideLimit", ctx, walletID, kind, period, amount, actor, reason)
+	ret0, _ := ret[0].(*domain.Limit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OverrideLimit indicates an expected call of OverrideLimit.
+func (mr *MockAdminServiceMockRecorder) OverrideLimit(ctx, walletID, kind, period, amount, actor, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OverrideLimit", reflect.TypeOf((*MockAdminService)(nil).OverrideLimit), ctx, walletID, kind, period, amount, actor, reason)
+}
+
+// SearchWallets mocks base method.
+func (m *MockAdminService) SearchWallets(ctx context.Context, filter domain.WalletFilter) ([]domain.WalletBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchWallets", ctx, filter)
+	ret0, _ := ret[0].([]domain.WalletBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchWallets indicates an expected call of SearchWallets.
+func (mr *MockAdminServiceMockRecorder) SearchWallets(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchWallets", reflect.TypeOf((*MockAdminService)(nil).SearchWallets), ctx, filter)
+}
+
+// UnfreezeWallet mocks base method.
+func (m *MockAdminService) UnfreezeWallet(ctx context.Context, walletID int, actor, reason string) (*domain.WalletBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfreezeWallet", ctx, walletID, actor, reason)
+	ret0, _ := ret[0].(*domain.WalletBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnfreezeWallet indicates an expected call of UnfreezeWallet.
+func (mr *MockAdminServiceMockRecorder) UnfreezeWallet(ctx, walletID, actor, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfreezeWallet", reflect.TypeOf((*MockAdminService)(nil).UnfreezeWallet), ctx, walletID, actor, reason)
+}
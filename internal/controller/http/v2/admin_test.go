@@ -0,0 +1,260 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/middleware/requestid"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/middleware/jwt"
+	httpv2 "github.com/pprishchepa/go-casino-example/internal/controller/http/v2"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAdminRoutes(t *testing.T) {
+	createdAt := time.Date(2024, 6, 12, 12, 0, 0, 0, time.UTC)
+	yes := true
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		player     bool
+		mock       func(svc *MockAdminService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "search wallets",
+			method: http.MethodGet,
+			path:   "/api/admin/wallets?frozen=true&minAmount=5&after=10",
+			mock: func(svc *MockAdminService) {
+				minAmount := mustParse(t, "5")
+				svc.EXPECT().SearchWallets(gomock.Any(), domain.WalletFilter{
+					Frozen:    &yes,
+					MinAmount: &minAmount,
+					AfterID:   10,
+					Limit:     100,
+				}).Return([]domain.WalletBalance{
+					{WalletID: 25, Amount: money.NewFromInt(10000), Version: 3, Frozen: true},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":[{"walletId":25,"amount":"10.000","real":"10.000","bonus":"0.000","version":3,` +
+				`"flagged":false,"frozen":true}]}`,
+		},
+		{
+			name:       "search wallets with malformed amount",
+			method:     http.MethodGet,
+			path:       "/api/admin/wallets?maxAmount=ten",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "search wallets without admin scope",
+			method:     http.MethodGet,
+			path:       "/api/admin/wallets",
+			player:     true,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "wallet status",
+			method: http.MethodGet,
+			path:   "/api/admin/wallets/25",
+			mock: func(svc *MockAdminService) {
+				svc.EXPECT().GetStatus(gomock.Any(), 25).Return(&domain.WalletStatus{
+					Balance: domain.WalletBalance{WalletID: 25, Amount: money.NewFromInt(10000), Version: 3, Frozen: true},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"walletId":25,"amount":"10.000","real":"10.000","bonus":"0.000","version":3,` +
+				`"flagged":false,"frozen":true,"exclusion":null}}`,
+		},
+		{
+			name:   "entries",
+			method: http.MethodGet,
+			path:   "/api/admin/wallets/25/entries?operation=adjustment",
+			mock: func(svc *MockAdminService) {
+				svc.EXPECT().ListEntries(gomock.Any(), domain.EntryFilter{
+					WalletID:   25,
+					Operations: []domain.OperationType{domain.OperationAdjustment},
+					Limit:      100,
+				}).Return(nil, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":[]}`,
+		},
+		{
+			name:   "take money",
+			method: http.MethodPost,
+			path:   "/api/admin/wallets/25/adjustments",
+			body:   `{"amount": "-1.500", "reason": "duplicate payout"}`,
+			mock: func(svc *MockAdminService) {
+				credit := mustParse(t, "1.500")
+				svc.EXPECT().AdjustBalance(gomock.Any(), 25, mustParse(t, "-1.500"), "support", "duplicate payout").
+					Return(&domain.Entry{
+						ID:       40,
+						WalletID: 25,
+						Credit:   &credit,
+						EntryDetails: domain.EntryDetails{
+							Operation: domain.OperationAdjustment,
+							Initiator: domain.InitiatorAdmin,
+							Metadata:  json.RawMessage(`{"actor":"support","reason":"duplicate payout"}`),
+						},
+						BalanceAfter: mustParse(t, "8.500"),
+						CreatedAt:    createdAt,
+					}, nil)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody: `{"data":{"id":40,"walletId":25,"debit":null,"credit":"1.500","bonusAmount":"0.000",` +
+				`"operation":"adjustment","reference":"","initiator":"admin",` +
+				`"metadata":{"actor":"support","reason":"duplicate payout"},"reversalOf":null,"reversedBy":null,` +
+				`"balance":"8.500","realBalance":"8.500","bonusBalance":"0.000","createdAt":"2024-06-12T12:00:00Z"}}`,
+		},
+		{
+			name:       "zero adjustment",
+			method:     http.MethodPost,
+			path:       "/api/admin/wallets/25/adjustments",
+			body:       `{"amount": "0", "reason": "nothing"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "adjustment without reason",
+			method:     http.MethodPost,
+			path:       "/api/admin/wallets/25/adjustments",
+			body:       `{"amount": "1.000"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "freeze",
+			method: http.MethodPost,
+			path:   "/api/admin/wallets/25/freeze",
+			body:   `{"reason": "fraud check"}`,
+			mock: func(svc *MockAdminService) {
+				svc.EXPECT().FreezeWallet(gomock.Any(), 25, "support", "fraud check").
+					Return(&domain.WalletBalance{WalletID: 25, Amount: money.NewFromInt(10000), Version: 4, Frozen: true}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"walletId":25,"amount":"10.000","real":"10.000","bonus":"0.000","version":4,` +
+				`"flagged":false,"frozen":true}}`,
+		},
+		{
+			name:   "unfreeze when not frozen",
+			method: http.MethodPost,
+			path:   "/api/admin/wallets/25/unfreeze",
+			body:   `{"reason": "check passed"}`,
+			mock: func(svc *MockAdminService) {
+				svc.EXPECT().UnfreezeWallet(gomock.Any(), 25, "support", "check passed").
+					Return(nil, domain.ErrInvalidAdminAction)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "override limit",
+			method: http.MethodPut,
+			path:   "/api/admin/wallets/25/limits/loss/week",
+			body:   `{"amount": "50", "reason": "player request by phone"}`,
+			mock: func(svc *MockAdminService) {
+				amount := mustParse(t, "50")
+				svc.EXPECT().OverrideLimit(gomock.Any(), 25, domain.LimitLoss, domain.LimitWeek, &amount,
+					"support", "player request by phone").
+					Return(&domain.Limit{WalletID: 25, Kind: domain.LimitLoss, Period: domain.LimitWeek, Amount: &amount}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"kind":"loss","period":"week","amount":"50.000","pendingAmount":null,"pendingAt":null}}`,
+		},
+		{
+			name:   "remove limit",
+			method: http.MethodPut,
+			path:   "/api/admin/wallets/25/limits/loss/week",
+			body:   `{"reason": "player request by phone"}`,
+			mock: func(svc *MockAdminService) {
+				svc.EXPECT().OverrideLimit(gomock.Any(), 25, domain.LimitLoss, domain.LimitWeek, (*money.Money)(nil),
+					"support", "player request by phone").
+					Return(&domain.Limit{WalletID: 25, Kind: domain.LimitLoss, Period: domain.LimitWeek}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"kind":"loss","period":"week","amount":null,"pendingAmount":null,"pendingAt":null}}`,
+		},
+		{
+			name:   "evict balance",
+			method: http.MethodPost,
+			path:   "/api/admin/wallets/25/cache/evict",
+			mock: func(svc *MockAdminService) {
+				svc.EXPECT().EvictBalance(gomock.Any(), 25, "support", "").Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "actions",
+			method: http.MethodGet,
+			path:   "/api/admin/actions?actor=support&kind=freeze",
+			mock: func(svc *MockAdminService) {
+				svc.EXPECT().ListAdminActions(gomock.Any(), domain.AdminActionFilter{
+					Actor: "support",
+					Kind:  domain.AdminFreeze,
+					Limit: 100,
+				}).Return([]domain.AdminAction{
+					{ID: 7, WalletID: 25, Kind: domain.AdminFreeze, Actor: "support", Reason: "fraud check", CreatedAt: createdAt},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":[{"id":7,"walletId":25,"kind":"freeze","actor":"support","reason":"fraud check",` +
+				`"details":null,"createdAt":"2024-06-12T12:00:00Z"}]}`,
+		},
+		{
+			name:       "actions of unknown kind",
+			method:     http.MethodGet,
+			path:       "/api/admin/actions?kind=delete",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			svc := NewMockAdminService(mockCtrl)
+			if tt.mock != nil {
+				tt.mock(svc)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			token := newAdminToken(t)
+			if tt.player {
+				token = newTestToken(t)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			rec := httptest.NewRecorder()
+			newAdminEngine(svc).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func newAdminEngine(svc httpv2.AdminService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	e := gin.New()
+	e.Use(requestid.New(), problem.Handler())
+	admin := e.Group("/api/admin", jwt.Authorize(testJWTSecret), jwt.RequireScope(jwtauth.ScopeAdmin))
+	httpv2.NewAdminRoutes(svc).RegisterRoutes(admin)
+
+	return e
+}
@@ -0,0 +1,105 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/middleware/jwt"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v2/model"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=bonus.go -destination=bonus_mock_test.go -package=v2_test
+
+type BonusService interface {
+	GrantBonus(ctx context.Context, grant domain.BonusGrant) (*domain.BonusGrant, error)
+	ListBonuses(ctx context.Context, walletID int) ([]domain.BonusGrant, error)
+}
+
+type BonusRoutes struct {
+	service BonusService
+}
+
+func NewBonusRoutes(service BonusService) *BonusRoutes {
+	return &BonusRoutes{service: service}
+}
+
+func (r BonusRoutes) RegisterRoutes(e *gin.RouterGroup) {
+	e.POST("/wallets/:wallet/bonuses", jwt.RequireScope(jwtauth.ScopeAdmin), r.grantBonus)
+	e.GET("/wallets/:wallet/bonuses", r.listBonuses)
+}
+
+func (r BonusRoutes) grantBonus(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var reqBody model.GrantBonusRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var fields amountFields
+	amount := fields.parse("amount", reqBody.Amount, false)
+	if len(fields) > 0 {
+		_ = c.Error(fields.problem())
+		return
+	}
+
+	grant, err := r.service.GrantBonus(c.Request.Context(), domain.BonusGrant{
+		WalletID:   reqWallet.ID,
+		Amount:     amount,
+		Multiplier: reqBody.Multiplier,
+		ExpiresAt:  reqBody.ExpiresAt,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": newBonusResponse(grant)})
+}
+
+func (r BonusRoutes) listBonuses(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, reqWallet.ID) {
+		return
+	}
+
+	grants, err := r.service.ListBonuses(c.Request.Context(), reqWallet.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res := make([]model.BonusResponse, 0, len(grants))
+	for i := range grants {
+		res = append(res, newBonusResponse(&grants[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func newBonusResponse(grant *domain.BonusGrant) model.BonusResponse {
+	return model.BonusResponse{
+		ID:          grant.ID,
+		WalletID:    grant.WalletID,
+		Amount:      grant.Amount,
+		Multiplier:  grant.Multiplier,
+		Requirement: grant.Requirement(),
+		Wagered:     grant.Wagered,
+		Remaining:   grant.Remaining(),
+		Status:      string(grant.Status),
+		ExpiresAt:   grant.ExpiresAt,
+		CreatedAt:   grant.CreatedAt,
+	}
+}
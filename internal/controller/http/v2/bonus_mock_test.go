@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: bonus.go
+//
+// Generated by this command:
+//
+//	mockgen -source=bonus.go -destination=bonus_mock_test.go -package=v2_test
+//
+
+// Package v2_test is a generated GoMock package.
+package v2_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/pprishchepa/go-casino-example/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBonusService is a mock of BonusService interface.
+type MockBonusService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBonusServiceMockRecorder
+}
+
+// MockBonusServiceMockRecorder is the mock recorder for MockBonusService.
+type MockBonusServiceMockRecorder struct {
+	mock *MockBonusService
+}
+
+// NewMockBonusService creates a new mock instance.
+func NewMockBonusService(ctrl *gomock.Controller) *MockBonusService {
+	mock := &MockBonusService{ctrl: ctrl}
+	mock.recorder = &MockBonusServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBonusService) EXPECT() *MockBonusServiceMockRecorder {
+	return m.recorder
+}
+
+// GrantBonus mocks base method.
+func (m *MockBonusService) GrantBonus(ctx context.Context, grant domain.BonusGrant) (*domain.BonusGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantBonus", ctx, grant)
+	ret0, _ := ret[0].(*domain.BonusGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GrantBonus indicates an expected call of GrantBonus.
+func (mr *MockBonusServiceMockRecorder) GrantBonus(ctx, grant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantBonus", reflect.TypeOf((*MockBonusService)(nil).GrantBonus), ctx, grant)
+}
+
+// ListBonuses mocks base method.
+func (m *MockBonusService) ListBonuses(ctx context.Context, walletID int) ([]domain.BonusGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBonuses", ctx, walletID)
+	ret0, _ := ret[0].([]domain.BonusGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBonuses indicates an expected call of ListBonuses.
+func (mr *MockBonusServiceMockRecorder) ListBonuses(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBonuses", reflect.TypeOf((*MockBonusService)(nil).ListBonuses), ctx, walletID)
+}
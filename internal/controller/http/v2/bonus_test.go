@@ -0,0 +1,166 @@
+package v2_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBonusRoutes(t *testing.T) {
+	router := newSpecRouter(t)
+
+	createdAt := time.Date(2024, 4, 17, 12, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(7 * 24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		admin      bool
+		mock       func(svc *MockBonusService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "grant",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/bonuses",
+			body:   `{"amount": "5", "multiplier": 30, "expiresAt": "2024-04-24T12:00:00Z"}`,
+			admin:  true,
+			mock: func(svc *MockBonusService) {
+				svc.EXPECT().GrantBonus(gomock.Any(), domain.BonusGrant{
+					WalletID:   25,
+					Amount:     mustParse(t, "5"),
+					Multiplier: 30,
+					ExpiresAt:  expiresAt,
+				}).Return(&domain.BonusGrant{
+					ID:         3,
+					WalletID:   25,
+					Amount:     money.NewFromInt(5000),
+					Multiplier: 30,
+					Status:     domain.BonusActive,
+					ExpiresAt:  expiresAt,
+					CreatedAt:  createdAt,
+				}, nil)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody: `{"data":{"id":3,"walletId":25,"amount":"5.000","multiplier":30,"requirement":"150.000",` +
+				`"wagered":"0.000","remaining":"150.000","status":"active",` +
+				`"expiresAt":"2024-04-24T12:00:00Z","createdAt":"2024-04-17T12:00:00Z"}}`,
+		},
+		{
+			name:       "grant without multiplier",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/bonuses",
+			body:       `{"amount": "5", "expiresAt": "2024-04-24T12:00:00Z"}`,
+			admin:      true,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "grant that has expired",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/bonuses",
+			body:   `{"amount": "5", "multiplier": 30, "expiresAt": "2024-04-24T12:00:00Z"}`,
+			admin:  true,
+			mock: func(svc *MockBonusService) {
+				svc.EXPECT().GrantBonus(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInvalidExpiry)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "grant without admin scope",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/bonuses",
+			body:       `{"amount": "5", "multiplier": 30, "expiresAt": "2024-04-24T12:00:00Z"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/bonuses",
+			mock: func(svc *MockBonusService) {
+				svc.EXPECT().ListBonuses(gomock.Any(), 25).Return([]domain.BonusGrant{
+					{
+						ID:         3,
+						WalletID:   25,
+						Amount:     money.NewFromInt(5000),
+						Multiplier: 2,
+						Wagered:    money.NewFromInt(4500),
+						Status:     domain.BonusActive,
+						ExpiresAt:  expiresAt,
+						CreatedAt:  createdAt,
+					},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":[{"id":3,"walletId":25,"amount":"5.000","multiplier":2,"requirement":"10.000",` +
+				`"wagered":"4.500","remaining":"5.500","status":"active",` +
+				`"expiresAt":"2024-04-24T12:00:00Z","createdAt":"2024-04-17T12:00:00Z"}]}`,
+		},
+		{
+			name:   "list of wallet without bonuses",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/bonuses",
+			mock: func(svc *MockBonusService) {
+				svc.EXPECT().ListBonuses(gomock.Any(), 25).Return(nil, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":[]}`,
+		},
+		{
+			name:       "list of wallet of another player",
+			method:     http.MethodGet,
+			path:       "/api/v2/wallets/26/bonuses",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "list of any wallet by admin",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/26/bonuses",
+			admin:  true,
+			mock: func(svc *MockBonusService) {
+				svc.EXPECT().ListBonuses(gomock.Any(), 26).Return(nil, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":[]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			svc := NewMockBonusService(mockCtrl)
+			if tt.mock != nil {
+				tt.mock(svc)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			token := newTestToken(t)
+			if tt.admin {
+				token = newAdminToken(t)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			rec := httptest.NewRecorder()
+			newContractEngine(NewMockWalletService(mockCtrl), svc, NewMockLimitService(mockCtrl),
+				NewMockExclusionService(mockCtrl), NewMockWithdrawalService(mockCtrl)).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+			validateResponse(t, router, req, rec)
+		})
+	}
+}
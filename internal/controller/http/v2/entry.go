@@ -0,0 +1,189 @@
+package v2
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v2/model"
+)
+
+// defaultEntriesLimit is the page size of listEntries when none is given.
+const defaultEntriesLimit = 100
+
+func (r WalletRoutes) listEntries(c *gin.Context) {
+	filter, ok := bindEntryFilter(c)
+	if !ok {
+		return
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultEntriesLimit
+	}
+
+	entries, err := r.service.ListEntries(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res := make([]model.EntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		res = append(res, newEntryResponse(entry))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// exportEntries writes every entry that matches the filter as CSV, unless a
+// limit is given.
+func (r WalletRoutes) exportEntries(c *gin.Context) {
+	filter, ok := bindEntryFilter(c)
+	if !ok {
+		return
+	}
+
+	entries, err := r.service.ListEntries(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="wallet-`+strconv.Itoa(filter.WalletID)+`-entries.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{
+		"id", "created_at", "operation", "debit", "credit", "bonus_amount", "reference", "initiator", "metadata",
+		"reversal_of", "reversed_by", "balance", "real_balance", "bonus_balance",
+	})
+	for _, entry := range entries {
+		var debit, credit string
+		if entry.Debit != nil {
+			debit = entry.Debit.String()
+		}
+		if entry.Credit != nil {
+			credit = entry.Credit.String()
+		}
+		_ = w.Write([]string{
+			strconv.Itoa(entry.ID),
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			string(entry.Operation),
+			debit,
+			credit,
+			entry.Bonus.String(),
+			entry.Reference,
+			string(entry.Initiator),
+			string(entry.Metadata),
+			optionalID(entry.ReversalOf),
+			optionalID(entry.ReversedBy),
+			entry.BalanceAfter.Add(entry.BonusAfter).String(),
+			entry.BalanceAfter.String(),
+			entry.BonusAfter.String(),
+		})
+	}
+	w.Flush()
+}
+
+// reverseEntry undoes an entry with a compensating entry on behalf of the
+// admin the request is authorized by.
+func (r WalletRoutes) reverseEntry(c *gin.Context) {
+	var req model.EntryRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var reqBody model.ReverseEntryRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	reversal, err := r.service.ReverseEntry(c.Request.Context(), req.WalletID, req.ID, actor(c), reqBody.Reason)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": newEntryResponse(*reversal)})
+}
+
+// bindEntryFilter binds the wallet and the query of an entry listing. It
+// reports a failure to the context and returns false.
+func bindEntryFilter(c *gin.Context) (domain.EntryFilter, bool) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return domain.EntryFilter{}, false
+	}
+	if !authorizeWallet(c, reqWallet.ID) {
+		return domain.EntryFilter{}, false
+	}
+
+	var req model.ListEntriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return domain.EntryFilter{}, false
+	}
+
+	filter := domain.EntryFilter{
+		WalletID:  reqWallet.ID,
+		Reference: req.Reference,
+		Initiator: domain.Initiator(req.Initiator),
+		AfterID:   req.After,
+		Limit:     req.Limit,
+	}
+	for _, op := range req.Operations {
+		filter.Operations = append(filter.Operations, domain.OperationType(op))
+	}
+	if metadata := c.QueryMap("metadata"); len(metadata) > 0 {
+		filter.Metadata = metadata
+	}
+	if req.From != nil {
+		filter.From = *req.From
+	}
+	if req.To != nil {
+		filter.To = *req.To
+	}
+
+	return filter, true
+}
+
+func newEntryResponse(entry domain.Entry) model.EntryResponse {
+	return model.EntryResponse{
+		ID:           entry.ID,
+		WalletID:     entry.WalletID,
+		Debit:        entry.Debit,
+		Credit:       entry.Credit,
+		BonusAmount:  entry.Bonus,
+		Operation:    string(entry.Operation),
+		Reference:    entry.Reference,
+		Initiator:    string(entry.Initiator),
+		Metadata:     entry.Metadata,
+		ReversalOf:   nullableID(entry.ReversalOf),
+		ReversedBy:   nullableID(entry.ReversedBy),
+		Balance:      entry.BalanceAfter.Add(entry.BonusAfter),
+		RealBalance:  entry.BalanceAfter,
+		BonusBalance: entry.BonusAfter,
+		CreatedAt:    entry.CreatedAt,
+	}
+}
+
+// nullableID and optionalID leave out an unset ID in JSON and CSV.
+func nullableID(id int) *int {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+func optionalID(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
@@ -0,0 +1,133 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/middleware/jwt"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v2/model"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=exclusion.go -destination=exclusion_mock_test.go -package=v2_test
+
+type ExclusionService interface {
+	GetStatus(ctx context.Context, walletID int) (*domain.WalletStatus, error)
+	SelfExclude(ctx context.Context, walletID int, period domain.ExclusionPeriod) (*domain.Exclusion, error)
+	LiftExclusion(ctx context.Context, walletID int, actor, reason string) (*domain.Exclusion, error)
+}
+
+type ExclusionRoutes struct {
+	service ExclusionService
+}
+
+func NewExclusionRoutes(service ExclusionService) *ExclusionRoutes {
+	return &ExclusionRoutes{service: service}
+}
+
+func (r ExclusionRoutes) RegisterRoutes(e *gin.RouterGroup) {
+	e.GET("/wallets/:wallet/status", r.getStatus)
+	e.POST("/wallets/:wallet/exclusion", r.selfExclude)
+	e.POST("/wallets/:wallet/exclusion/lift", jwt.RequireScope(jwtauth.ScopeAdmin), r.liftExclusion)
+}
+
+func (r ExclusionRoutes) getStatus(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, reqWallet.ID) {
+		return
+	}
+
+	status, err := r.service.GetStatus(c.Request.Context(), reqWallet.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newWalletStatusResponse(status)})
+}
+
+func (r ExclusionRoutes) selfExclude(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, reqWallet.ID) {
+		return
+	}
+
+	var reqBody model.SelfExcludeRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	exclusion, err := r.service.SelfExclude(c.Request.Context(), reqWallet.ID, domain.ExclusionPeriod(reqBody.Period))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newExclusionResponse(exclusion)})
+}
+
+func (r ExclusionRoutes) liftExclusion(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var reqBody model.LiftExclusionRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// RequireScope has checked that there are claims.
+	actor := jwtauth.FromContext(c.Request.Context()).Subject
+
+	exclusion, err := r.service.LiftExclusion(c.Request.Context(), reqWallet.ID, actor, reqBody.Reason)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newExclusionResponse(exclusion)})
+}
+
+func newWalletStatusResponse(status *domain.WalletStatus) model.WalletStatusResponse {
+	res := model.WalletStatusResponse{
+		BalanceResponse: newBalanceResponse(&status.Balance),
+		Flagged:         status.Balance.Flagged,
+		Frozen:          status.Balance.Frozen,
+	}
+	if status.Exclusion != nil {
+		exclusion := newExclusionResponse(status.Exclusion)
+		res.Exclusion = &exclusion
+	}
+	return res
+}
+
+func newExclusionResponse(exclusion *domain.Exclusion) model.ExclusionResponse {
+	res := model.ExclusionResponse{
+		ID:         exclusion.ID,
+		Period:     string(exclusion.Period),
+		CreatedAt:  exclusion.CreatedAt,
+		LiftedBy:   exclusion.LiftedBy,
+		LiftReason: exclusion.LiftReason,
+	}
+	if !exclusion.Until.IsZero() {
+		res.Until = &exclusion.Until
+	}
+	if !exclusion.LiftedAt.IsZero() {
+		res.LiftedAt = &exclusion.LiftedAt
+	}
+	return res
+}
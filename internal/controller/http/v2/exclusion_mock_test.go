@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: exclusion.go
+//
+// Generated by this command:
+//
+//	mockgen -source=exclusion.go -destination=exclusion_mock_test.go -package=v2_test
+//
+
+// Package v2_test is a generated GoMock package.
+package v2_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/pprishchepa/go-casino-example/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExclusionService is a mock of ExclusionService interface.
+type MockExclusionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockExclusionServiceMockRecorder
+}
+
+// MockExclusionServiceMockRecorder is the mock recorder for MockExclusionService.
+type MockExclusionServiceMockRecorder struct {
+	mock *MockExclusionService
+}
+
+// NewMockExclusionService creates a new mock instance.
+func NewMockExclusionService(ctrl *gomock.Controller) *MockExclusionService {
+	mock := &MockExclusionService{ctrl: ctrl}
+	mock.recorder = &MockExclusionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExclusionService) EXPECT() *MockExclusionServiceMockRecorder {
+	return m.recorder
+}
+
+// GetStatus mocks base method.
+func (m *MockExclusionService) GetStatus(ctx context.Context, walletID int) (*domain.WalletStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", ctx, walletID)
+	ret0, _ := ret[0].(*domain.WalletStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockExclusionServiceMockRecorder) GetStatus(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockExclusionService)(nil).GetStatus), ctx, walletID)
+}
+
+// LiftExclusion mocks base method.
+func (m *MockExclusionService) LiftExclusion(ctx context.Context, walletID int, actor, reason string) (*domain.Exclusion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LiftExclusion", ctx, walletID, actor, reason)
+	ret0, _ := ret[0].(*domain.Exclusion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LiftExclusion indicates an expected call of LiftExclusion.
+func (mr *MockExclusionServiceMockRecorder) LiftExclusion(ctx, walletID, actor, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LiftExclusion", reflect.TypeOf((*MockExclusionService)(nil).LiftExclusion), ctx, walletID, actor, reason)
+}
+
+// SelfExclude mocks base method.
+func (m *MockExclusionService) SelfExclude(ctx context.Context, walletID int, period domain.ExclusionPeriod) (*domain.Exclusion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelfExclude", ctx, walletID, period)
+	ret0, _ := ret[0].(*domain.Exclusion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelfExclude indicates an expected call of SelfExclude.
+func (mr *MockExclusionServiceMockRecorder) SelfExclude(ctx, walletID, period any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelfExclude", reflect.TypeOf((*MockExclusionService)(nil).SelfExclude), ctx, walletID, period)
+}
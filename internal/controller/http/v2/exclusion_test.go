@@ -0,0 +1,208 @@
+package v2_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExclusionRoutes(t *testing.T) {
+	router := newSpecRouter(t)
+
+	createdAt := time.Date(2024, 4, 27, 12, 0, 0, 0, time.UTC)
+	until := createdAt.Add(24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		admin      bool
+		mock       func(svc *MockExclusionService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "status of excluded wallet",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/status",
+			mock: func(svc *MockExclusionService) {
+				svc.EXPECT().GetStatus(gomock.Any(), 25).Return(&domain.WalletStatus{
+					Balance: domain.WalletBalance{WalletID: 25, Amount: money.NewFromInt(10000), Version: 3},
+					Exclusion: &domain.Exclusion{
+						ID:        4,
+						WalletID:  25,
+						Period:    domain.ExclusionPermanent,
+						CreatedAt: createdAt,
+					},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"walletId":25,"amount":"10.000","real":"10.000","bonus":"0.000","version":3,` +
+				`"flagged":false,"frozen":false,"exclusion":{"id":4,"period":"permanent","until":null,"createdAt":"2024-04-27T12:00:00Z"}}}`,
+		},
+		{
+			name:   "status of wallet that may bet",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/status",
+			mock: func(svc *MockExclusionService) {
+				svc.EXPECT().GetStatus(gomock.Any(), 25).Return(&domain.WalletStatus{
+					Balance: domain.WalletBalance{WalletID: 25, Amount: money.NewFromInt(10000), Version: 3},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"walletId":25,"amount":"10.000","real":"10.000","bonus":"0.000","version":3,` +
+				`"flagged":false,"frozen":false,"exclusion":null}}`,
+		},
+		{
+			name:   "self-exclude",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/exclusion",
+			body:   `{"period": "24h"}`,
+			mock: func(svc *MockExclusionService) {
+				svc.EXPECT().SelfExclude(gomock.Any(), 25, domain.ExclusionDay).Return(&domain.Exclusion{
+					ID:        5,
+					WalletID:  25,
+					Period:    domain.ExclusionDay,
+					Until:     until,
+					CreatedAt: createdAt,
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"id":5,"period":"24h","until":"2024-04-28T12:00:00Z",` +
+				`"createdAt":"2024-04-27T12:00:00Z"}}`,
+		},
+		{
+			name:       "self-exclude for unknown period",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/exclusion",
+			body:       `{"period": "1w"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "status of wallet of another player",
+			method:     http.MethodGet,
+			path:       "/api/v2/wallets/26/status",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "self-exclude wallet of another player",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/26/exclusion",
+			body:       `{"period": "permanent"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "status of any wallet by admin",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/26/status",
+			admin:  true,
+			mock: func(svc *MockExclusionService) {
+				svc.EXPECT().GetStatus(gomock.Any(), 26).Return(&domain.WalletStatus{
+					Balance: domain.WalletBalance{WalletID: 26, Version: 1},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "lift",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/exclusion/lift",
+			body:   `{"reason": "opened by mistake"}`,
+			admin:  true,
+			mock: func(svc *MockExclusionService) {
+				svc.EXPECT().LiftExclusion(gomock.Any(), 25, "support", "opened by mistake").Return(&domain.Exclusion{
+					ID:         5,
+					WalletID:   25,
+					Period:     domain.ExclusionDay,
+					Until:      until,
+					CreatedAt:  createdAt,
+					LiftedAt:   createdAt.Add(time.Hour),
+					LiftedBy:   "support",
+					LiftReason: "opened by mistake",
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"id":5,"period":"24h","until":"2024-04-28T12:00:00Z",` +
+				`"createdAt":"2024-04-27T12:00:00Z","liftedAt":"2024-04-27T13:00:00Z",` +
+				`"liftedBy":"support","liftReason":"opened by mistake"}}`,
+		},
+		{
+			name:       "lift without admin scope",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/exclusion/lift",
+			body:       `{"reason": "opened by mistake"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "lift without reason",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/exclusion/lift",
+			body:       `{}`,
+			admin:      true,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "lift when not excluded",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/exclusion/lift",
+			body:   `{"reason": "opened by mistake"}`,
+			admin:  true,
+			mock: func(svc *MockExclusionService) {
+				svc.EXPECT().LiftExclusion(gomock.Any(), 25, "support", "opened by mistake").
+					Return(nil, domain.ErrNotExcluded)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			svc := NewMockExclusionService(mockCtrl)
+			if tt.mock != nil {
+				tt.mock(svc)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			token := newTestToken(t)
+			if tt.admin {
+				token = newAdminToken(t)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			rec := httptest.NewRecorder()
+			newContractEngine(NewMockWalletService(mockCtrl), NewMockBonusService(mockCtrl),
+				NewMockLimitService(mockCtrl), svc, NewMockWithdrawalService(mockCtrl)).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+			validateResponse(t, router, req, rec)
+		})
+	}
+}
+
+func newAdminToken(t *testing.T) string {
+	t.Helper()
+
+	token, err := gojwt.NewWithClaims(gojwt.SigningMethodHS256, gojwt.MapClaims{
+		"sub":    "support",
+		"scopes": []string{"admin"},
+	}).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+
+	return token
+}
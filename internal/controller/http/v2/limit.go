@@ -0,0 +1,140 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v2/model"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=limit.go -destination=limit_mock_test.go -package=v2_test
+
+type LimitService interface {
+	ListLimits(ctx context.Context, walletID int) ([]domain.LimitUsage, error)
+	SetLimit(
+		ctx context.Context,
+		walletID int,
+		kind domain.LimitKind,
+		period domain.LimitPeriod,
+		amount *money.Money,
+	) (*domain.Limit, error)
+}
+
+type LimitRoutes struct {
+	service LimitService
+}
+
+func NewLimitRoutes(service LimitService) *LimitRoutes {
+	return &LimitRoutes{service: service}
+}
+
+func (r LimitRoutes) RegisterRoutes(e *gin.RouterGroup) {
+	e.GET("/wallets/:wallet/limits", r.listLimits)
+	e.PUT("/wallets/:wallet/limits/:kind/:period", r.setLimit)
+	e.DELETE("/wallets/:wallet/limits/:kind/:period", r.removeLimit)
+}
+
+func (r LimitRoutes) listLimits(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, reqWallet.ID) {
+		return
+	}
+
+	limits, err := r.service.ListLimits(c.Request.Context(), reqWallet.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res := make([]model.LimitUsageResponse, 0, len(limits))
+	for _, limit := range limits {
+		res = append(res, newLimitUsageResponse(limit))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func (r LimitRoutes) setLimit(c *gin.Context) {
+	var reqLimit model.LimitRequest
+	if err := c.ShouldBindUri(&reqLimit); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, reqLimit.WalletID) {
+		return
+	}
+
+	var reqBody model.SetLimitRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var fields amountFields
+	amount := fields.parse("amount", reqBody.Amount, true)
+	if len(fields) > 0 {
+		_ = c.Error(fields.problem())
+		return
+	}
+
+	r.saveLimit(c, reqLimit, &amount)
+}
+
+func (r LimitRoutes) removeLimit(c *gin.Context) {
+	var reqLimit model.LimitRequest
+	if err := c.ShouldBindUri(&reqLimit); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, reqLimit.WalletID) {
+		return
+	}
+
+	r.saveLimit(c, reqLimit, nil)
+}
+
+func (r LimitRoutes) saveLimit(c *gin.Context, req model.LimitRequest, amount *money.Money) {
+	limit, err := r.service.SetLimit(c.Request.Context(), req.WalletID,
+		domain.LimitKind(req.Kind), domain.LimitPeriod(req.Period), amount)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newLimitResponse(*limit)})
+}
+
+func newLimitResponse(limit domain.Limit) model.LimitResponse {
+	res := model.LimitResponse{
+		Kind:          string(limit.Kind),
+		Period:        string(limit.Period),
+		Amount:        limit.Amount,
+		PendingAmount: limit.PendingAmount,
+	}
+	if !limit.PendingAt.IsZero() {
+		res.PendingAt = &limit.PendingAt
+	}
+	return res
+}
+
+func newLimitUsageResponse(limit domain.LimitUsage) model.LimitUsageResponse {
+	res := model.LimitUsageResponse{
+		LimitResponse: newLimitResponse(limit.Limit),
+		Used:          limit.Used,
+	}
+	if limit.Amount != nil {
+		remaining := limit.Amount.Sub(limit.Used)
+		if remaining.IsNegative() {
+			remaining = money.Money{}
+		}
+		res.Remaining = &remaining
+	}
+	return res
+}
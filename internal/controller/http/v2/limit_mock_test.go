@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: limit.go
+//
+// Generated by this command:
+//
+//	mockgen -source=limit.go -destination=limit_mock_test.go -package=v2_test
+//
+
+// Package v2_test is a generated GoMock package.
+package v2_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/pprishchepa/go-casino-example/domain"
+	money "github.com/pprishchepa/go-casino-example/domain/money"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLimitService is a mock of LimitService interface.
+type MockLimitService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimitServiceMockRecorder
+}
+
+// MockLimitServiceMockRecorder is the mock recorder for MockLimitService.
+type MockLimitServiceMockRecorder struct {
+	mock *MockLimitService
+}
+
+// NewMockLimitService creates a new mock instance.
+func NewMockLimitService(ctrl *gomock.Controller) *MockLimitService {
+	mock := &MockLimitService{ctrl: ctrl}
+	mock.recorder = &MockLimitServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLimitService) EXPECT() *MockLimitServiceMockRecorder {
+	return m.recorder
+}
+
+// ListLimits mocks base method.
+func (m *MockLimitService) ListLimits(ctx context.Context, walletID int) ([]domain.LimitUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLimits", ctx, walletID)
+	ret0, _ := ret[0].([]domain.LimitUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLimits indicates an expected call of ListLimits.
+func (mr *MockLimitServiceMockRecorder) ListLimits(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLimits", reflect.TypeOf((*MockLimitService)(nil).ListLimits), ctx, walletID)
+}
+
+// SetLimit mocks base method.
+func (m *MockLimitService) SetLimit(ctx context.Context, walletID int, kind domain.LimitKind, period domain.LimitPeriod, amount *money.Money) (*domain.Limit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimit", ctx, walletID, kind, period, amount)
+	ret0, _ := ret[0].(*domain.Limit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLimit indicates an expected call of SetLimit.
+func (mr *MockLimitServiceMockRecorder) SetLimit(ctx, walletID, kind, period, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimit", reflect.TypeOf((*MockLimitService)(nil).SetLimit), ctx, walletID, kind, period, amount)
+}
@@ -0,0 +1,218 @@
+package v2_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLimitRoutes(t *testing.T) {
+	router := newSpecRouter(t)
+
+	pendingAt := time.Date(2024, 4, 21, 12, 0, 0, 0, time.UTC)
+	amount := func(s string) *money.Money {
+		m := mustParse(t, s)
+		return &m
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		admin      bool
+		mock       func(svc *MockLimitService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/limits",
+			mock: func(svc *MockLimitService) {
+				svc.EXPECT().ListLimits(gomock.Any(), 25).Return([]domain.LimitUsage{
+					{
+						Limit: domain.Limit{
+							WalletID: 25,
+							Kind:     domain.LimitDeposit,
+							Period:   domain.LimitDay,
+							Amount:   amount("100"),
+						},
+						Used: money.NewFromInt(40000),
+					},
+					{
+						Limit: domain.Limit{
+							WalletID:  25,
+							Kind:      domain.LimitLoss,
+							Period:    domain.LimitWeek,
+							Amount:    amount("50"),
+							PendingAt: pendingAt,
+						},
+						Used: money.NewFromInt(70000),
+					},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":[` +
+				`{"kind":"deposit","period":"day","amount":"100.000","pendingAmount":null,"pendingAt":null,` +
+				`"used":"40.000","remaining":"60.000"},` +
+				`{"kind":"loss","period":"week","amount":"50.000","pendingAmount":null,"pendingAt":"2024-04-21T12:00:00Z",` +
+				`"used":"70.000","remaining":"0.000"}]}`,
+		},
+		{
+			name:   "list of wallet without limits",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/limits",
+			mock: func(svc *MockLimitService) {
+				svc.EXPECT().ListLimits(gomock.Any(), 25).Return(nil, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":[]}`,
+		},
+		{
+			name:   "set",
+			method: http.MethodPut,
+			path:   "/api/v2/wallets/25/limits/wager/month",
+			body:   `{"amount": "200"}`,
+			mock: func(svc *MockLimitService) {
+				svc.EXPECT().SetLimit(gomock.Any(), 25, domain.LimitWager, domain.LimitMonth, amount("200")).
+					Return(&domain.Limit{
+						WalletID:      25,
+						Kind:          domain.LimitWager,
+						Period:        domain.LimitMonth,
+						Amount:        amount("100"),
+						PendingAmount: amount("200"),
+						PendingAt:     pendingAt,
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"kind":"wager","period":"month","amount":"100.000",` +
+				`"pendingAmount":"200.000","pendingAt":"2024-04-21T12:00:00Z"}}`,
+		},
+		{
+			name:   "set to zero",
+			method: http.MethodPut,
+			path:   "/api/v2/wallets/25/limits/deposit/day",
+			body:   `{"amount": "0"}`,
+			mock: func(svc *MockLimitService) {
+				svc.EXPECT().SetLimit(gomock.Any(), 25, domain.LimitDeposit, domain.LimitDay, amount("0")).
+					Return(&domain.Limit{
+						WalletID: 25,
+						Kind:     domain.LimitDeposit,
+						Period:   domain.LimitDay,
+						Amount:   amount("0"),
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "set with negative amount",
+			method:     http.MethodPut,
+			path:       "/api/v2/wallets/25/limits/deposit/day",
+			body:       `{"amount": "-1"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "set unknown kind",
+			method:     http.MethodPut,
+			path:       "/api/v2/wallets/25/limits/bets/day",
+			body:       `{"amount": "100"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "remove",
+			method: http.MethodDelete,
+			path:   "/api/v2/wallets/25/limits/deposit/week",
+			mock: func(svc *MockLimitService) {
+				svc.EXPECT().SetLimit(gomock.Any(), 25, domain.LimitDeposit, domain.LimitWeek, nil).
+					Return(&domain.Limit{
+						WalletID:  25,
+						Kind:      domain.LimitDeposit,
+						Period:    domain.LimitWeek,
+						Amount:    amount("100"),
+						PendingAt: pendingAt,
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"kind":"deposit","period":"week","amount":"100.000",` +
+				`"pendingAmount":null,"pendingAt":"2024-04-21T12:00:00Z"}}`,
+		},
+		{
+			name:   "remove from unknown wallet",
+			method: http.MethodDelete,
+			path:   "/api/v2/wallets/25/limits/deposit/week",
+			mock: func(svc *MockLimitService) {
+				svc.EXPECT().SetLimit(gomock.Any(), 25, domain.LimitDeposit, domain.LimitWeek, nil).
+					Return(nil, domain.ErrWalletNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "list of wallet of another player",
+			method:     http.MethodGet,
+			path:       "/api/v2/wallets/26/limits",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "set on wallet of another player",
+			method:     http.MethodPut,
+			path:       "/api/v2/wallets/26/limits/loss/day",
+			body:       `{"amount": "1000"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "remove from wallet of another player",
+			method:     http.MethodDelete,
+			path:       "/api/v2/wallets/26/limits/loss/day",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "remove from any wallet by admin",
+			method: http.MethodDelete,
+			path:   "/api/v2/wallets/26/limits/deposit/week",
+			admin:  true,
+			mock: func(svc *MockLimitService) {
+				svc.EXPECT().SetLimit(gomock.Any(), 26, domain.LimitDeposit, domain.LimitWeek, nil).
+					Return(&domain.Limit{Kind: domain.LimitDeposit, Period: domain.LimitWeek}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			svc := NewMockLimitService(mockCtrl)
+			if tt.mock != nil {
+				tt.mock(svc)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			token := newTestToken(t)
+			if tt.admin {
+				token = newAdminToken(t)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			rec := httptest.NewRecorder()
+			newContractEngine(NewMockWalletService(mockCtrl), NewMockBonusService(mockCtrl), svc,
+				NewMockExclusionService(mockCtrl), NewMockWithdrawalService(mockCtrl)).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+			validateResponse(t, router, req, rec)
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+type WalletRequest struct {
+	ID int `uri:"wallet" binding:"required,gt=0"`
+}
+
+// Requests keep amounts as strings so that a malformed amount is reported as
+// a field error rather than a JSON decoding error.
+
+type DebitMoneyRequest struct {
+	Amount string `json:"amount" binding:"required"`
+	// BonusAmount is the part of Amount that goes to the bonus bucket.
+	BonusAmount string `json:"bonusAmount"`
+	// Operation is win unless set. Deposits are only credited when their
+	// payment provider confirms them, never here.
+	Operation string `json:"operation" binding:"omitempty,oneof=win refund"`
+	EntryDetailsRequest
+}
+
+type CreditMoneyRequest struct {
+	Amount string `json:"amount" binding:"required"`
+	// GameType is the game a stake is placed on, e.g. slots or table.
+	GameType string `json:"gameType" binding:"omitempty,max=32"`
+	// Operation is bet unless set. Cash-outs are requested as withdrawals,
+	// which are held and reviewed, never credited here.
+	Operation string `json:"operation" binding:"omitempty,oneof=bet"`
+	EntryDetailsRequest
+}
+
+type EntryDetailsRequest struct {
+	// Reference links the entry to a game round, a payment and so on.
+	Reference string `json:"reference" binding:"max=128"`
+	// Metadata is a JSON object kept with the entry as it is.
+	Metadata json.RawMessage `json:"metadata"`
+}
+
+// ListEntriesRequest filters entries of a wallet. Metadata is bound from
+// metadata[key]=value query parameters separately.
+type ListEntriesRequest struct {
+	Operations []string   `form:"operation" binding:"dive,oneof=bet win refund deposit withdrawal withdrawal_release chargeback bonus_grant bonus_release bonus_conversion reversal adjustment legacy"`
+	Reference  string     `form:"reference" binding:"max=128"`
+	Initiator  string     `form:"initiator" binding:"omitempty,oneof=user service admin"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	// After is the ID of the last entry of the previous page.
+	After int `form:"after" binding:"gte=0"`
+	Limit int `form:"limit" binding:"omitempty,gt=0,lte=1000"`
+}
+
+type EntryRequest struct {
+	WalletID int `uri:"wallet" binding:"required,gt=0"`
+	ID       int `uri:"entry" binding:"required,gt=0"`
+}
+
+type ReverseEntryRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type GrantBonusRequest struct {
+	Amount     string    `json:"amount" binding:"required"`
+	Multiplier int       `json:"multiplier" binding:"required,gt=0"`
+	ExpiresAt  time.Time `json:"expiresAt" binding:"required"`
+}
+
+// BalanceAtRequest asks for the balance as the ledger has it at a point in
+// time rather than the current one.
+type BalanceAtRequest struct {
+	At *time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type BalanceResponse struct {
+	WalletID int `json:"walletId"`
+	// Amount is real and bonus money together.
+	Amount  money.Money `json:"amount"`
+	Real    money.Money `json:"real"`
+	Bonus   money.Money `json:"bonus"`
+	Version int         `json:"version"`
+}
+
+// BalanceAtResponse is the balance after every entry created before At. It
+// has no version as it is not read from the stored balance.
+type BalanceAtResponse struct {
+	WalletID int `json:"walletId"`
+	// Amount is real and bonus money together.
+	Amount money.Money `json:"amount"`
+	Real   money.Money `json:"real"`
+	Bonus  money.Money `json:"bonus"`
+	At     time.Time   `json:"at"`
+}
+
+type OperationResponse struct {
+	EntryID     int         `json:"entryId"`
+	WalletID    int         `json:"walletId"`
+	Amount      money.Money `json:"amount"`
+	BonusAmount money.Money `json:"bonusAmount"`
+	// Balance is real and bonus money together after the operation.
+	Balance      money.Money `json:"balance"`
+	RealBalance  money.Money `json:"realBalance"`
+	BonusBalance money.Money `json:"bonusBalance"`
+	CreatedAt    time.Time   `json:"createdAt"`
+}
+
+// EntryResponse has either a debit or a credit amount. Metadata is null if
+// none was given. ReversalOf is only set on reversals and ReversedBy on the
+// entries they undo.
+type EntryResponse struct {
+	ID          int             `json:"id"`
+	WalletID    int             `json:"walletId"`
+	Debit       *money.Money    `json:"debit"`
+	Credit      *money.Money    `json:"credit"`
+	BonusAmount money.Money     `json:"bonusAmount"`
+	Operation   string          `json:"operation"`
+	Reference   string          `json:"reference"`
+	Initiator   string          `json:"initiator"`
+	Metadata    json.RawMessage `json:"metadata"`
+	ReversalOf  *int            `json:"reversalOf"`
+	ReversedBy  *int            `json:"reversedBy"`
+	// Balance is real and bonus money together after the entry.
+	Balance      money.Money `json:"balance"`
+	RealBalance  money.Money `json:"realBalance"`
+	BonusBalance money.Money `json:"bonusBalance"`
+	CreatedAt    time.Time   `json:"createdAt"`
+}
+
+type BonusResponse struct {
+	ID          int         `json:"id"`
+	WalletID    int         `json:"walletId"`
+	Amount      money.Money `json:"amount"`
+	Multiplier  int         `json:"multiplier"`
+	Requirement money.Money `json:"requirement"`
+	Wagered     money.Money `json:"wagered"`
+	Remaining   money.Money `json:"remaining"`
+	Status      string      `json:"status"`
+	ExpiresAt   time.Time   `json:"expiresAt"`
+	CreatedAt   time.Time   `json:"createdAt"`
+}
+
+type LimitRequest struct {
+	WalletID int    `uri:"wallet" binding:"required,gt=0"`
+	Kind     string `uri:"kind" binding:"required,oneof=deposit loss wager"`
+	Period   string `uri:"period" binding:"required,oneof=day week month"`
+}
+
+type SetLimitRequest struct {
+	Amount string `json:"amount" binding:"required"`
+}
+
+// LimitResponse has no amount if the limit is only pending and no pending
+// amount if it is being removed.
+type LimitResponse struct {
+	Kind          string       `json:"kind"`
+	Period        string       `json:"period"`
+	Amount        *money.Money `json:"amount"`
+	PendingAmount *money.Money `json:"pendingAmount"`
+	PendingAt     *time.Time   `json:"pendingAt"`
+}
+
+type LimitUsageResponse struct {
+	LimitResponse
+	Used      money.Money  `json:"used"`
+	Remaining *money.Money `json:"remaining"`
+}
+
+type SelfExcludeRequest struct {
+	Period string `json:"period" binding:"required,oneof=24h 6m permanent"`
+}
+
+type LiftExclusionRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// ExclusionResponse has no until if the exclusion is permanent. The lifted
+// fields are only set once an admin has lifted the exclusion.
+type ExclusionResponse struct {
+	ID         int        `json:"id"`
+	Period     string     `json:"period"`
+	Until      *time.Time `json:"until"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LiftedAt   *time.Time `json:"liftedAt,omitempty"`
+	LiftedBy   string     `json:"liftedBy,omitempty"`
+	LiftReason string     `json:"liftReason,omitempty"`
+}
+
+type WalletStatusResponse struct {
+	BalanceResponse
+	// Flagged is set while a chargeback leaves real money below zero.
+	Flagged bool `json:"flagged"`
+	// Frozen is set while an admin has frozen the wallet.
+	Frozen bool `json:"frozen"`
+	// Exclusion is the exclusion in effect, nil if the wallet may bet.
+	Exclusion *ExclusionResponse `json:"exclusion"`
+}
+
+type WithdrawalRequest struct {
+	WalletID int `uri:"wallet" binding:"required,gt=0"`
+	ID       int `uri:"withdrawal" binding:"required,gt=0"`
+}
+
+type RequestWithdrawalRequest struct {
+	Amount string `json:"amount" binding:"required"`
+}
+
+// ChangeWithdrawalRequest is an admin review or payout outcome. Requested
+// and pending_review are only set by the wallet itself.
+type ChangeWithdrawalRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved cancelled sent failed"`
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type WithdrawalResponse struct {
+	ID        int         `json:"id"`
+	WalletID  int         `json:"walletId"`
+	Amount    money.Money `json:"amount"`
+	Status    string      `json:"status"`
+	CreatedAt time.Time   `json:"createdAt"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+}
+
+// WithdrawalTransitionResponse has no from for the request itself.
+type WithdrawalTransitionResponse struct {
+	From   string    `json:"from,omitempty"`
+	To     string    `json:"to"`
+	Actor  string    `json:"actor"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+type WithdrawalDetailsResponse struct {
+	WithdrawalResponse
+	History []WithdrawalTransitionResponse `json:"history"`
+}
+
+// SearchWalletsRequest filters wallets by the state of their balance. Amounts
+// bound real money and are kept as strings like in bodies.
+type SearchWalletsRequest struct {
+	Flagged   *bool  `form:"flagged"`
+	Frozen    *bool  `form:"frozen"`
+	MinAmount string `form:"minAmount"`
+	MaxAmount string `form:"maxAmount"`
+	// After is the ID of the last wallet of the previous page.
+	After int `form:"after" binding:"gte=0"`
+	Limit int `form:"limit" binding:"omitempty,gt=0,lte=1000"`
+}
+
+type AdminWalletResponse struct {
+	BalanceResponse
+	Flagged bool `json:"flagged"`
+	Frozen  bool `json:"frozen"`
+}
+
+// AdjustBalanceRequest adds a positive amount to real money and takes a
+// negative one.
+type AdjustBalanceRequest struct {
+	Amount string `json:"amount" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type AdminReasonRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// OverrideLimitRequest removes the limit if the amount is not set.
+type OverrideLimitRequest struct {
+	Amount string `json:"amount"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// EvictBalanceRequest may be sent without a body.
+type EvictBalanceRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type ListAdminActionsRequest struct {
+	WalletID int    `form:"walletId" binding:"gte=0"`
+	Actor    string `form:"actor" binding:"max=128"`
+	Kind     string `form:"kind" binding:"omitempty,oneof=adjust_balance freeze unfreeze override_limit evict_cache reverse_entry lift_exclusion"`
+	// After is the ID of the last action of the previous page.
+	After int `form:"after" binding:"gte=0"`
+	Limit int `form:"limit" binding:"omitempty,gt=0,lte=1000"`
+}
+
+// AdminActionResponse has null details if the action changed nothing but
+// its subject, such as a freeze.
+type AdminActionResponse struct {
+	ID        int             `json:"id"`
+	WalletID  int             `json:"walletId"`
+	Kind      string          `json:"kind"`
+	Actor     string          `json:"actor"`
+	Reason    string          `json:"reason"`
+	Details   json.RawMessage `json:"details"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// DailyReportRequest selects UTC days in [from, to).
+type DailyReportRequest struct {
+	From *time.Time `form:"from" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	To   *time.Time `form:"to" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+}
+
+// RebuildReportRequest has dates like "2024-06-01" that cannot be bound from
+// JSON into time.Time, so they are kept as strings.
+type RebuildReportRequest struct {
+	From string `json:"from" binding:"required,datetime=2006-01-02"`
+	To   string `json:"to" binding:"required,datetime=2006-01-02"`
+}
+
+type DailySummaryResponse struct {
+	Day            string      `json:"day"`
+	Brand          string      `json:"brand"`
+	Currency       string      `json:"currency"`
+	Bets           money.Money `json:"bets"`
+	Wins           money.Money `json:"wins"`
+	Refunds        money.Money `json:"refunds"`
+	GGR            money.Money `json:"ggr"`
+	Deposits       money.Money `json:"deposits"`
+	Chargebacks    money.Money `json:"chargebacks"`
+	Withdrawals    money.Money `json:"withdrawals"`
+	BonusGranted   money.Money `json:"bonusGranted"`
+	BonusConverted money.Money `json:"bonusConverted"`
+	BonusReleased  money.Money `json:"bonusReleased"`
+	BonusCost      money.Money `json:"bonusCost"`
+	NGR            money.Money `json:"ngr"`
+	Adjustments    money.Money `json:"adjustments"`
+	Entries        int         `json:"entries"`
+	RefreshedAt    time.Time   `json:"refreshedAt"`
+}
+
+type RebuildReportResponse struct {
+	Days int `json:"days"`
+}
@@ -0,0 +1,652 @@
+package v2_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/middleware/requestid"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/middleware/jwt"
+	httpv2 "github.com/pprishchepa/go-casino-example/internal/controller/http/v2"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v2/openapi"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testJWTSecret = "test-secret"
+
+// TestOpenAPIContract sends requests to the real handlers and validates every
+// response against the v2 OpenAPI document.
+func TestOpenAPIContract(t *testing.T) {
+	router := newSpecRouter(t)
+
+	createdAt := time.Date(2024, 4, 5, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		noAuth     bool
+		admin      bool
+		mock       func(svc *MockWalletService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "balance",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/balance",
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().GetBalance(gomock.Any(), 25).
+					Return(&domain.WalletBalance{
+						WalletID: 25,
+						Amount:   money.NewFromInt(12340),
+						Bonus:    money.NewFromInt(5000),
+						Version:  3,
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"walletId":25,"amount":"17.340","real":"12.340","bonus":"5.000","version":3}}`,
+		},
+		{
+			name:   "balance of unknown wallet",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/balance",
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().GetBalance(gomock.Any(), 25).Return(nil, domain.ErrWalletNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "balance at a point in time",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/balance?at=2024-04-05T23:59:59Z",
+			mock: func(svc *MockWalletService) {
+				at := time.Date(2024, 4, 5, 23, 59, 59, 0, time.UTC)
+				svc.EXPECT().BalanceAt(gomock.Any(), 25, at).
+					Return(&domain.BalanceSnapshot{
+						WalletID: 25,
+						At:       at,
+						Amount:   money.NewFromInt(12340),
+						Bonus:    money.NewFromInt(5000),
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"walletId":25,"amount":"17.340","real":"12.340","bonus":"5.000","at":"2024-04-05T23:59:59Z"}}`,
+		},
+		{
+			name:       "balance at a malformed time",
+			method:     http.MethodGet,
+			path:       "/api/v2/wallets/25/balance?at=yesterday",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "balance without token",
+			method:     http.MethodGet,
+			path:       "/api/v2/wallets/25/balance",
+			noAuth:     true,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "balance of another wallet",
+			method:     http.MethodGet,
+			path:       "/api/v2/wallets/26/balance",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "balance of another wallet as admin",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/26/balance",
+			admin:  true,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().GetBalance(gomock.Any(), 26).Return(&domain.WalletBalance{WalletID: 26}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "debit of another wallet",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/26/debit",
+			body:       `{"amount": "10"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "credit of another wallet",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/26/credit",
+			body:       `{"amount": "10"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "entries of another wallet",
+			method:     http.MethodGet,
+			path:       "/api/v2/wallets/26/entries",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "debit",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/debit",
+			body:   `{"amount": "12.345"}`,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().DebitMoney(gomock.Any(), domain.DebitEntry{
+					WalletID:     25,
+					Amount:       mustParse(t, "12.345"),
+					EntryDetails: domain.EntryDetails{Initiator: domain.InitiatorService},
+				}).
+					Return(&entity.Operation{
+						EntryID:   7,
+						Balance:   domain.WalletBalance{WalletID: 25, Amount: money.NewFromInt(20345), Version: 4},
+						CreatedAt: createdAt,
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"entryId":7,"walletId":25,"amount":"12.345","bonusAmount":"0.000",` +
+				`"balance":"20.345","realBalance":"20.345","bonusBalance":"0.000","createdAt":"2024-04-05T12:00:00Z"}}`,
+		},
+		{
+			name:   "debit to bonus",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/debit",
+			body:   `{"amount": "10", "bonusAmount": "4"}`,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().DebitMoney(gomock.Any(), domain.DebitEntry{
+					WalletID:     25,
+					Amount:       mustParse(t, "10"),
+					Bonus:        mustParse(t, "4"),
+					EntryDetails: domain.EntryDetails{Initiator: domain.InitiatorService},
+				}).
+					Return(&entity.Operation{
+						EntryID: 9,
+						Bonus:   money.NewFromInt(4000),
+						Balance: domain.WalletBalance{
+							WalletID: 25,
+							Amount:   money.NewFromInt(6000),
+							Bonus:    money.NewFromInt(4000),
+							Version:  1,
+						},
+						CreatedAt: createdAt,
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"entryId":9,"walletId":25,"amount":"10.000","bonusAmount":"4.000",` +
+				`"balance":"10.000","realBalance":"6.000","bonusBalance":"4.000","createdAt":"2024-04-05T12:00:00Z"}}`,
+		},
+		{
+			name:       "deposit through debit",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/debit",
+			body:       `{"amount": "10", "operation": "deposit"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "refund of a game round",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/debit",
+			body:   `{"amount": "2", "operation": "refund", "reference": "round-7", "metadata": {"game": "slots"}}`,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().DebitMoney(gomock.Any(), domain.DebitEntry{
+					WalletID: 25,
+					Amount:   mustParse(t, "2"),
+					EntryDetails: domain.EntryDetails{
+						Operation: domain.OperationRefund,
+						Reference: "round-7",
+						Initiator: domain.InitiatorService,
+						Metadata:  json.RawMessage(`{"game": "slots"}`),
+					},
+				}).
+					Return(&entity.Operation{
+						EntryID:   12,
+						Balance:   domain.WalletBalance{WalletID: 25, Amount: money.NewFromInt(2000), Version: 1},
+						CreatedAt: createdAt,
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "debit with a credit operation",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/debit",
+			body:       `{"amount": "2", "operation": "bet"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "debit with metadata that is not an object",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/debit",
+			body:   `{"amount": "2", "metadata": [1]}`,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().DebitMoney(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInvalidEntry)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "debit with bonus exceeding amount",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/debit",
+			body:       `{"amount": "1", "bonusAmount": "2"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "credit",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/credit",
+			body:   `{"amount": "0.5"}`,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().CreditMoney(gomock.Any(), domain.CreditEntry{
+					WalletID:     25,
+					Amount:       mustParse(t, "0.5"),
+					EntryDetails: domain.EntryDetails{Initiator: domain.InitiatorService},
+				}).
+					Return(&entity.Operation{
+						EntryID:   8,
+						Balance:   domain.WalletBalance{WalletID: 25, Amount: money.NewFromInt(19845), Version: 5},
+						CreatedAt: createdAt,
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"entryId":8,"walletId":25,"amount":"0.500","bonusAmount":"0.000",` +
+				`"balance":"19.845","realBalance":"19.845","bonusBalance":"0.000","createdAt":"2024-04-05T12:00:00Z"}}`,
+		},
+		{
+			name:       "withdrawal through credit",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/credit",
+			body:       `{"amount": "5", "operation": "withdrawal"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "stake on a game type",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/credit",
+			body:   `{"amount": "1", "gameType": "table"}`,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().CreditMoney(gomock.Any(), domain.CreditEntry{
+					WalletID:     25,
+					Amount:       mustParse(t, "1"),
+					GameType:     "table",
+					EntryDetails: domain.EntryDetails{Initiator: domain.InitiatorService},
+				}).
+					Return(&entity.Operation{
+						EntryID:   11,
+						Balance:   domain.WalletBalance{WalletID: 25, Amount: money.NewFromInt(14000), Version: 7},
+						CreatedAt: createdAt,
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "entries",
+			method: http.MethodGet,
+			path: "/api/v2/wallets/25/entries?operation=bet&operation=win&reference=round-1" +
+				"&initiator=service&metadata[round]=r-1&from=2024-04-05T00:00:00Z&after=3&limit=2",
+			mock: func(svc *MockWalletService) {
+				debit, credit := money.NewFromInt(2500), money.NewFromInt(1000)
+				svc.EXPECT().ListEntries(gomock.Any(), domain.EntryFilter{
+					WalletID:   25,
+					Operations: []domain.OperationType{domain.OperationBet, domain.OperationWin},
+					Reference:  "round-1",
+					Initiator:  domain.InitiatorService,
+					Metadata:   map[string]string{"round": "r-1"},
+					From:       time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC),
+					AfterID:    3,
+					Limit:      2,
+				}).
+					Return([]domain.Entry{
+						{
+							ID: 4, WalletID: 25, Credit: &credit, ReversedBy: 7, CreatedAt: createdAt,
+							BalanceAfter: money.NewFromInt(9000), BonusAfter: money.NewFromInt(500),
+							EntryDetails: domain.EntryDetails{
+								Operation: domain.OperationBet,
+								Reference: "round-1",
+								Initiator: domain.InitiatorService,
+								Metadata:  json.RawMessage(`{"round":"r-1"}`),
+							},
+						},
+						{
+							ID: 5, WalletID: 25, Debit: &debit, CreatedAt: createdAt,
+							BalanceAfter: money.NewFromInt(11500), BonusAfter: money.NewFromInt(500),
+							EntryDetails: domain.EntryDetails{
+								Operation: domain.OperationWin,
+								Reference: "round-1",
+								Initiator: domain.InitiatorService,
+							},
+						},
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":[` +
+				`{"id":4,"walletId":25,"debit":null,"credit":"1.000","bonusAmount":"0.000","operation":"bet",` +
+				`"reference":"round-1","initiator":"service","metadata":{"round":"r-1"},"reversalOf":null,"reversedBy":7,` +
+				`"balance":"9.500","realBalance":"9.000","bonusBalance":"0.500","createdAt":"2024-04-05T12:00:00Z"},` +
+				`{"id":5,"walletId":25,"debit":"2.500","credit":null,"bonusAmount":"0.000","operation":"win",` +
+				`"reference":"round-1","initiator":"service","metadata":null,"reversalOf":null,"reversedBy":null,` +
+				`"balance":"12.000","realBalance":"11.500","bonusBalance":"0.500","createdAt":"2024-04-05T12:00:00Z"}]}`,
+		},
+		{
+			name:   "entries with the default limit",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/entries",
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().ListEntries(gomock.Any(), domain.EntryFilter{WalletID: 25, Limit: 100}).Return(nil, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":[]}`,
+		},
+		{
+			name:       "entries with an unknown operation",
+			method:     http.MethodGet,
+			path:       "/api/v2/wallets/25/entries?operation=jackpot",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "reverse entry",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/entries/4/reverse",
+			body:   `{"reason": "round voided by provider"}`,
+			admin:  true,
+			mock: func(svc *MockWalletService) {
+				amount := money.NewFromInt(1000)
+				svc.EXPECT().ReverseEntry(gomock.Any(), 25, 4, "support", "round voided by provider").
+					Return(&domain.Entry{
+						ID: 7, WalletID: 25, Debit: &amount, BalanceAfter: amount, CreatedAt: createdAt,
+						EntryDetails: domain.EntryDetails{
+							Operation:  domain.OperationReversal,
+							Reference:  "round-1",
+							Initiator:  domain.InitiatorAdmin,
+							Metadata:   json.RawMessage(`{"actor":"support","reason":"round voided by provider"}`),
+							ReversalOf: 4,
+						},
+					}, nil)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody: `{"data":{"id":7,"walletId":25,"debit":"1.000","credit":null,"bonusAmount":"0.000",` +
+				`"operation":"reversal","reference":"round-1","initiator":"admin",` +
+				`"metadata":{"actor":"support","reason":"round voided by provider"},"reversalOf":4,"reversedBy":null,` +
+				`"balance":"1.000","realBalance":"1.000","bonusBalance":"0.000","createdAt":"2024-04-05T12:00:00Z"}}`,
+		},
+		{
+			name:   "reverse entry twice",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/entries/4/reverse",
+			body:   `{"reason": "again"}`,
+			admin:  true,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().ReverseEntry(gomock.Any(), 25, 4, "support", "again").Return(nil, domain.ErrAlreadyReversed)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "reverse unknown entry",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/entries/4/reverse",
+			body:   `{"reason": "typo"}`,
+			admin:  true,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().ReverseEntry(gomock.Any(), 25, 4, "support", "typo").Return(nil, domain.ErrEntryNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "reverse entry without reason",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/entries/4/reverse",
+			body:       `{}`,
+			admin:      true,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "reverse entry without admin scope",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/entries/4/reverse",
+			body:       `{"reason": "typo"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "entries of unknown wallet",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/entries",
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().ListEntries(gomock.Any(), gomock.Any()).Return(nil, domain.ErrWalletNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "entries export",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/entries/export?operation=deposit",
+			mock: func(svc *MockWalletService) {
+				amount := money.NewFromInt(30000)
+				svc.EXPECT().ListEntries(gomock.Any(), domain.EntryFilter{
+					WalletID:   25,
+					Operations: []domain.OperationType{domain.OperationDeposit},
+				}).
+					Return([]domain.Entry{{
+						ID: 4, WalletID: 25, Debit: &amount, CreatedAt: createdAt,
+						EntryDetails: domain.EntryDetails{
+							Operation: domain.OperationDeposit,
+							Reference: "dep-1",
+							Initiator: domain.InitiatorService,
+							Metadata:  json.RawMessage(`{"provider":"fake"}`),
+						},
+					}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "debit with too many decimal places",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/debit",
+			body:       `{"amount": "1.2345"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "debit with integer amount",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/debit",
+			body:       `{"amount": 100}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "debit with zero amount",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/debit",
+			body:       `{"amount": "0.000"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "credit with insufficient funds",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/credit",
+			body:   `{"amount": "100"}`,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().CreditMoney(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInsufficientFunds)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "credit over loss limit",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/credit",
+			body:   `{"amount": "100"}`,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().CreditMoney(gomock.Any(), gomock.Any()).Return(nil, &domain.LimitExceededError{
+					Kind:      domain.LimitLoss,
+					Period:    domain.LimitWeek,
+					Limit:     money.NewFromInt(50000),
+					Remaining: money.NewFromInt(20000),
+				})
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "bet of self-excluded wallet",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/credit",
+			body:   `{"amount": "1"}`,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().CreditMoney(gomock.Any(), gomock.Any()).Return(nil, domain.ErrSelfExcluded)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "credit with internal error",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/credit",
+			body:   `{"amount": "100"}`,
+			mock: func(svc *MockWalletService) {
+				svc.EXPECT().CreditMoney(gomock.Any(), gomock.Any()).Return(nil, errors.New("boom"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			svc := NewMockWalletService(mockCtrl)
+			if tt.mock != nil {
+				tt.mock(svc)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if tt.admin {
+				req.Header.Set("Authorization", "Bearer "+newAdminToken(t))
+			} else if !tt.noAuth {
+				req.Header.Set("Authorization", "Bearer "+newTestToken(t))
+			}
+
+			rec := httptest.NewRecorder()
+			newContractEngine(svc, NewMockBonusService(mockCtrl), NewMockLimitService(mockCtrl),
+				NewMockExclusionService(mockCtrl), NewMockWithdrawalService(mockCtrl)).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+			validateResponse(t, router, req, rec)
+		})
+	}
+}
+
+// TestOpenAPIRoutesDocumented fails when a route is added without documenting it.
+func TestOpenAPIRoutesDocumented(t *testing.T) {
+	router := newSpecRouter(t)
+
+	mockCtrl := gomock.NewController(t)
+	e := newContractEngine(NewMockWalletService(mockCtrl), NewMockBonusService(mockCtrl), NewMockLimitService(mockCtrl),
+		NewMockExclusionService(mockCtrl), NewMockWithdrawalService(mockCtrl))
+
+	for _, route := range e.Routes() {
+		path := strings.NewReplacer(":wallet", "1", ":kind", "deposit", ":period", "day", ":withdrawal", "1", ":entry", "1").Replace(route.Path)
+		req := httptest.NewRequest(route.Method, "http://localhost"+path, nil)
+
+		_, _, err := router.FindRoute(req)
+		assert.NoError(t, err, "%s %s is not documented", route.Method, route.Path)
+	}
+}
+
+func newContractEngine(
+	svc httpv2.WalletService,
+	bonus httpv2.BonusService,
+	limit httpv2.LimitService,
+	exclusion httpv2.ExclusionService,
+	withdrawal httpv2.WithdrawalService,
+) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	e := gin.New()
+	e.Use(requestid.New(), problem.Handler())
+	v2 := e.Group("/api/v2", jwt.Authorize(testJWTSecret))
+	{
+		httpv2.NewWalletRoutes(svc).RegisterRoutes(v2)
+		httpv2.NewBonusRoutes(bonus).RegisterRoutes(v2)
+		httpv2.NewLimitRoutes(limit).RegisterRoutes(v2)
+		httpv2.NewExclusionRoutes(exclusion).RegisterRoutes(v2)
+		httpv2.NewWithdrawalRoutes(withdrawal).RegisterRoutes(v2)
+	}
+
+	return e
+}
+
+func newSpecRouter(t *testing.T) routers.Router {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromData(openapi.Spec)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+
+	doc.Servers = openapi3.Servers{{URL: "http://localhost/api/v2"}}
+
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	return router
+}
+
+func validateResponse(t *testing.T, router routers.Router, req *http.Request, rec *httptest.ResponseRecorder) {
+	t.Helper()
+
+	specReq := req.Clone(context.Background())
+	specReq.URL.Scheme, specReq.URL.Host = "http", "localhost"
+
+	route, pathParams, err := router.FindRoute(specReq)
+	require.NoError(t, err)
+
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    specReq,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status: rec.Code,
+		Header: rec.Header(),
+		Body:   io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+		},
+	})
+	require.NoError(t, err, rec.Body.String())
+}
+
+func newTestToken(t *testing.T) string {
+	t.Helper()
+
+	token, err := gojwt.NewWithClaims(gojwt.SigningMethodHS256, gojwt.MapClaims{
+		"sub":     "player",
+		"wallets": []int{25},
+	}).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+
+	return token
+}
+
+func mustParse(t *testing.T, s string) money.Money {
+	t.Helper()
+
+	m, err := money.Parse(s)
+	require.NoError(t, err)
+
+	return m
+}
@@ -0,0 +1,155 @@
+package v2
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v2/model"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/rs/zerolog/log"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=report.go -destination=report_mock_test.go -package=v2_test
+
+type ReportService interface {
+	ListSummaries(ctx context.Context, from, to time.Time) ([]entity.DailySummary, error)
+	Rebuild(ctx context.Context, from, to time.Time) (int, error)
+}
+
+// ReportRoutes are the finance reports. Like AdminRoutes, they must be
+// registered on a group that requires the admin scope.
+type ReportRoutes struct {
+	service ReportService
+}
+
+func NewReportRoutes(service ReportService) *ReportRoutes {
+	return &ReportRoutes{service: service}
+}
+
+func (r ReportRoutes) RegisterRoutes(e *gin.RouterGroup) {
+	e.GET("/reports/daily", r.listDaily)
+	e.GET("/reports/daily/export", r.exportDaily)
+	e.POST("/reports/daily/rebuild", r.rebuildDaily)
+}
+
+func (r ReportRoutes) listDaily(c *gin.Context) {
+	summaries, ok := r.bindSummaries(c)
+	if !ok {
+		return
+	}
+
+	res := make([]model.DailySummaryResponse, 0, len(summaries))
+	for _, v := range summaries {
+		res = append(res, model.DailySummaryResponse{
+			Day:            v.Day.Format(time.DateOnly),
+			Brand:          v.Brand,
+			Currency:       v.Currency,
+			Bets:           v.Bets,
+			Wins:           v.Wins,
+			Refunds:        v.Refunds,
+			GGR:            v.GGR(),
+			Deposits:       v.Deposits,
+			Chargebacks:    v.Chargebacks,
+			Withdrawals:    v.Withdrawals,
+			BonusGranted:   v.BonusGranted,
+			BonusConverted: v.BonusConverted,
+			BonusReleased:  v.BonusReleased,
+			BonusCost:      v.BonusCost(),
+			NGR:            v.NGR(),
+			Adjustments:    v.Adjustments,
+			Entries:        v.Entries,
+			RefreshedAt:    v.RefreshedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func (r ReportRoutes) exportDaily(c *gin.Context) {
+	summaries, ok := r.bindSummaries(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="daily-report-`+
+		c.Query("from")+"-"+c.Query("to")+`.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{
+		"day", "brand", "currency", "bets", "wins", "refunds", "ggr", "deposits", "chargebacks", "withdrawals",
+		"bonus_granted", "bonus_converted", "bonus_released", "bonus_cost", "ngr", "adjustments", "entries",
+	})
+	for _, v := range summaries {
+		_ = w.Write([]string{
+			v.Day.Format(time.DateOnly),
+			v.Brand,
+			v.Currency,
+			v.Bets.String(),
+			v.Wins.String(),
+			v.Refunds.String(),
+			v.GGR().String(),
+			v.Deposits.String(),
+			v.Chargebacks.String(),
+			v.Withdrawals.String(),
+			v.BonusGranted.String(),
+			v.BonusConverted.String(),
+			v.BonusReleased.String(),
+			v.BonusCost().String(),
+			v.NGR().String(),
+			v.Adjustments.String(),
+			strconv.Itoa(v.Entries),
+		})
+	}
+	w.Flush()
+}
+
+// rebuildDaily aggregates the days again, for example after entries were
+// imported with past dates.
+func (r ReportRoutes) rebuildDaily(c *gin.Context) {
+	var reqBody model.RebuildReportRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// The binding has checked the layout already.
+	from, _ := time.Parse(time.DateOnly, reqBody.From)
+	to, _ := time.Parse(time.DateOnly, reqBody.To)
+
+	days, err := r.service.Rebuild(c.Request.Context(), from, to)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	log.Info().
+		Str("actor", actor(c)).
+		Time("from", from).
+		Time("to", to).
+		Int("days", days).
+		Msg("daily report rebuilt")
+
+	c.JSON(http.StatusOK, gin.H{"data": model.RebuildReportResponse{Days: days}})
+}
+
+func (r ReportRoutes) bindSummaries(c *gin.Context) ([]entity.DailySummary, bool) {
+	var req model.DailyReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return nil, false
+	}
+
+	summaries, err := r.service.ListSummaries(c.Request.Context(), *req.From, *req.To)
+	if err != nil {
+		_ = c.Error(err)
+		return nil, false
+	}
+
+	return summaries, true
+}
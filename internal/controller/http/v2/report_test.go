@@ -0,0 +1,166 @@
+package v2_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/middleware/requestid"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/middleware/jwt"
+	httpv2 "github.com/pprishchepa/go-casino-example/internal/controller/http/v2"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReportRoutes(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	summary := entity.DailySummary{
+		Day:            from,
+		Brand:          "default",
+		Currency:       "EUR",
+		Bets:           money.NewFromInt(300000),
+		Wins:           money.NewFromInt(100000),
+		Refunds:        money.NewFromInt(50000),
+		Deposits:       money.NewFromInt(1000000),
+		Withdrawals:    money.NewFromInt(120000),
+		BonusGranted:   money.NewFromInt(500000),
+		BonusConverted: money.NewFromInt(100000),
+		Adjustments:    money.NewFromInt(50000),
+		Entries:        10,
+		RefreshedAt:    from.Add(25 * time.Hour),
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		player     bool
+		mock       func(svc *MockReportService)
+		wantStatus int
+		wantBody   string
+		wantCSV    string
+	}{
+		{
+			name:   "daily summaries",
+			method: http.MethodGet,
+			path:   "/api/admin/reports/daily?from=2024-06-01&to=2024-06-03",
+			mock: func(svc *MockReportService) {
+				svc.EXPECT().ListSummaries(gomock.Any(), from, to).Return([]entity.DailySummary{summary}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":[{"day":"2024-06-01","brand":"default","currency":"EUR","bets":"300.000","wins":"100.000","refunds":"50.000",` +
+				`"ggr":"150.000","deposits":"1000.000","chargebacks":"0.000","withdrawals":"120.000",` +
+				`"bonusGranted":"500.000","bonusConverted":"100.000","bonusReleased":"0.000",` +
+				`"bonusCost":"100.000","ngr":"50.000","adjustments":"50.000","entries":10,` +
+				`"refreshedAt":"2024-06-02T01:00:00Z"}]}`,
+		},
+		{
+			name:       "daily summaries without range",
+			method:     http.MethodGet,
+			path:       "/api/admin/reports/daily?from=2024-06-01",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "daily summaries over a year",
+			method: http.MethodGet,
+			path:   "/api/admin/reports/daily?from=2024-06-01&to=2026-06-01",
+			mock: func(svc *MockReportService) {
+				svc.EXPECT().ListSummaries(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, entity.ErrInvalidReportRange)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "daily summaries without admin scope",
+			method:     http.MethodGet,
+			path:       "/api/admin/reports/daily?from=2024-06-01&to=2024-06-03",
+			player:     true,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "export",
+			method: http.MethodGet,
+			path:   "/api/admin/reports/daily/export?from=2024-06-01&to=2024-06-03",
+			mock: func(svc *MockReportService) {
+				svc.EXPECT().ListSummaries(gomock.Any(), from, to).Return([]entity.DailySummary{summary}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantCSV: "day,brand,currency,bets,wins,refunds,ggr,deposits,chargebacks,withdrawals,bonus_granted,bonus_converted," +
+				"bonus_released,bonus_cost,ngr,adjustments,entries\n" +
+				"2024-06-01,default,EUR,300.000,100.000,50.000,150.000,1000.000,0.000,120.000,500.000,100.000," +
+				"0.000,100.000,50.000,50.000,10\n",
+		},
+		{
+			name:   "rebuild",
+			method: http.MethodPost,
+			path:   "/api/admin/reports/daily/rebuild",
+			body:   `{"from": "2024-06-01", "to": "2024-06-03"}`,
+			mock: func(svc *MockReportService) {
+				svc.EXPECT().Rebuild(gomock.Any(), from, to).Return(2, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"days":2}}`,
+		},
+		{
+			name:       "rebuild with malformed day",
+			method:     http.MethodPost,
+			path:       "/api/admin/reports/daily/rebuild",
+			body:       `{"from": "2024-06-01T00:00:00Z", "to": "2024-06-03"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			svc := NewMockReportService(mockCtrl)
+			if tt.mock != nil {
+				tt.mock(svc)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			token := newAdminToken(t)
+			if tt.player {
+				token = newTestToken(t)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			rec := httptest.NewRecorder()
+			newReportEngine(svc).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+			if tt.wantCSV != "" {
+				assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="daily-report-2024-06-01-2024-06-03.csv"`,
+					rec.Header().Get("Content-Disposition"))
+				assert.Equal(t, tt.wantCSV, rec.Body.String())
+			}
+		})
+	}
+}
+
+func newReportEngine(svc httpv2.ReportService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	e := gin.New()
+	e.Use(requestid.New(), problem.Handler())
+	admin := e.Group("/api/admin", jwt.Authorize(testJWTSecret), jwt.RequireScope(jwtauth.ScopeAdmin))
+	httpv2.NewReportRoutes(svc).RegisterRoutes(admin)
+
+	return e
+}
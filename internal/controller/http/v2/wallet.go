@@ -0,0 +1,283 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/middleware/jwt"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v2/model"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=wallet.go -destination=wallet_mock_test.go -package=v2_test
+
+type WalletService interface {
+	GetBalance(ctx context.Context, walletID int) (*domain.WalletBalance, error)
+	BalanceAt(ctx context.Context, walletID int, at time.Time) (*domain.BalanceSnapshot, error)
+	DebitMoney(ctx context.Context, entry domain.DebitEntry) (*entity.Operation, error)
+	CreditMoney(ctx context.Context, entry domain.CreditEntry) (*entity.Operation, error)
+	ListEntries(ctx context.Context, filter domain.EntryFilter) ([]domain.Entry, error)
+	ReverseEntry(ctx context.Context, walletID, entryID int, actor, reason string) (*domain.Entry, error)
+}
+
+type WalletRoutes struct {
+	service WalletService
+}
+
+func NewWalletRoutes(service WalletService) *WalletRoutes {
+	return &WalletRoutes{service: service}
+}
+
+func (r WalletRoutes) RegisterRoutes(e *gin.RouterGroup) {
+	e.GET("/wallets/:wallet/balance", r.retrieveBalance)
+	e.POST("/wallets/:wallet/debit", r.debitMoney)
+	e.POST("/wallets/:wallet/credit", r.creditMoney)
+	e.GET("/wallets/:wallet/entries", r.listEntries)
+	e.GET("/wallets/:wallet/entries/export", r.exportEntries)
+	e.POST("/wallets/:wallet/entries/:entry/reverse", jwt.RequireScope(jwtauth.ScopeAdmin), r.reverseEntry)
+}
+
+// retrieveBalance returns the current balance, or the balance at the given
+// time if there is one.
+func (r WalletRoutes) retrieveBalance(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, reqWallet.ID) {
+		return
+	}
+
+	var reqQuery model.BalanceAtRequest
+	if err := c.ShouldBindQuery(&reqQuery); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if reqQuery.At != nil {
+		r.retrieveBalanceAt(c, reqWallet.ID, *reqQuery.At)
+		return
+	}
+
+	balance, err := r.service.GetBalance(c.Request.Context(), reqWallet.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newBalanceResponse(balance)})
+}
+
+func (r WalletRoutes) debitMoney(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, reqWallet.ID) {
+		return
+	}
+
+	var reqBody model.DebitMoneyRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var fields amountFields
+	amount := fields.parse("amount", reqBody.Amount, false)
+	bonus := fields.parseOptional("bonusAmount", reqBody.BonusAmount)
+	if len(fields) == 0 && bonus.Cmp(amount) > 0 {
+		fields = append(fields, problem.FieldError{Field: "bonusAmount", Message: "must not exceed amount"})
+	}
+	if len(fields) > 0 {
+		_ = c.Error(fields.problem())
+		return
+	}
+
+	op, err := r.service.DebitMoney(c.Request.Context(), domain.DebitEntry{
+		WalletID: reqWallet.ID,
+		Amount:   amount,
+		Bonus:    bonus,
+		EntryDetails: domain.EntryDetails{
+			Operation: domain.OperationType(reqBody.Operation),
+			Reference: reqBody.Reference,
+			Initiator: domain.InitiatorService,
+			Metadata:  reqBody.Metadata,
+		},
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newOperationResponse(op, amount)})
+}
+
+func (r WalletRoutes) creditMoney(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, reqWallet.ID) {
+		return
+	}
+
+	var reqBody model.CreditMoneyRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var fields amountFields
+	amount := fields.parse("amount", reqBody.Amount, false)
+	if len(fields) > 0 {
+		_ = c.Error(fields.problem())
+		return
+	}
+
+	op, err := r.service.CreditMoney(c.Request.Context(), domain.CreditEntry{
+		WalletID: reqWallet.ID,
+		Amount:   amount,
+		GameType: domain.GameType(reqBody.GameType),
+		EntryDetails: domain.EntryDetails{
+			Operation: domain.OperationType(reqBody.Operation),
+			Reference: reqBody.Reference,
+			Initiator: domain.InitiatorService,
+			Metadata:  reqBody.Metadata,
+		},
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newOperationResponse(op, amount)})
+}
+
+// authorizeWallet reports whether the token holder owns the wallet or is an
+// admin, and adds a problem to the request if neither.
+func authorizeWallet(c *gin.Context, walletID int) bool {
+	claims := jwtauth.FromContext(c.Request.Context())
+	if claims == nil || !(claims.OwnsWallet(walletID) || claims.HasScope(jwtauth.ScopeAdmin)) {
+		_ = c.Error(problem.ErrForbidden.WithDetail("wallet access denied"))
+		return false
+	}
+	return true
+}
+
+// amountFields collects invalid decimal amounts, so that all of them are
+// reported in a single problem.
+type amountFields []problem.FieldError
+
+// parse parses a required positive amount, or a non-negative one if
+// allowZero is set.
+func (f *amountFields) parse(field, s string, allowZero bool) money.Money {
+	amount, err := money.Parse(s)
+	if err == nil && (amount.IsNegative() || amount.IsZero() && !allowZero) {
+		err = errNotPositive
+		if allowZero {
+			err = errNegative
+		}
+	}
+	if err != nil {
+		*f = append(*f, problem.FieldError{Field: field, Message: amountMessage(err)})
+		return money.Money{}
+	}
+	return amount
+}
+
+// parseOptional parses a non-negative amount that defaults to zero.
+func (f *amountFields) parseOptional(field, s string) money.Money {
+	if s == "" {
+		return money.Money{}
+	}
+	return f.parse(field, s, true)
+}
+
+// parseSigned parses a required amount that may be negative but not zero.
+func (f *amountFields) parseSigned(field, s string) money.Money {
+	amount, err := money.Parse(s)
+	if err == nil && amount.IsZero() {
+		err = errZero
+	}
+	if err != nil {
+		*f = append(*f, problem.FieldError{Field: field, Message: amountMessage(err)})
+		return money.Money{}
+	}
+	return amount
+}
+
+func (f amountFields) problem() *problem.Problem {
+	p := problem.ErrValidation.WithDetail("one or more fields are invalid")
+	p.Errors = f
+	return p
+}
+
+var (
+	errNotPositive = errors.New("amount must be positive")
+	errNegative    = errors.New("amount must not be negative")
+	errZero        = errors.New("amount must not be zero")
+)
+
+func amountMessage(err error) string {
+	switch {
+	case errors.Is(err, money.ErrInvalidScale):
+		return "must have at most 3 decimal places"
+	case errors.Is(err, errNotPositive):
+		return "must be greater than 0"
+	case errors.Is(err, errNegative):
+		return "must not be negative"
+	case errors.Is(err, errZero):
+		return "must not be 0"
+	default:
+		return `must be a decimal string such as "12.345"`
+	}
+}
+
+func (r WalletRoutes) retrieveBalanceAt(c *gin.Context, walletID int, at time.Time) {
+	balance, err := r.service.BalanceAt(c.Request.Context(), walletID, at)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": model.BalanceAtResponse{
+		WalletID: balance.WalletID,
+		Amount:   balance.Total(),
+		Real:     balance.Amount,
+		Bonus:    balance.Bonus,
+		At:       balance.At,
+	}})
+}
+
+func newBalanceResponse(balance *domain.WalletBalance) model.BalanceResponse {
+	return model.BalanceResponse{
+		WalletID: balance.WalletID,
+		Amount:   balance.Total(),
+		Real:     balance.Amount,
+		Bonus:    balance.Bonus,
+		Version:  balance.Version,
+	}
+}
+
+func newOperationResponse(op *entity.Operation, amount money.Money) model.OperationResponse {
+	return model.OperationResponse{
+		EntryID:      op.EntryID,
+		WalletID:     op.Balance.WalletID,
+		Amount:       amount,
+		BonusAmount:  op.Bonus,
+		Balance:      op.Balance.Total(),
+		RealBalance:  op.Balance.Amount,
+		BonusBalance: op.Balance.Bonus,
+		CreatedAt:    op.CreatedAt,
+	}
+}
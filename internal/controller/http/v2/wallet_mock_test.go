@@ -0,0 +1,133 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: wallet.go
+//
+// Generated by this command:
+//
+//	mockgen -source=wallet.go -destination=wallet_mock_test.go -package=v2_test
+//
+
+// Package v2_test is a generated GoMock package.
+package v2_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/pprishchepa/go-casino-example/domain"
+	entity "github.com/pprishchepa/go-casino-example/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWalletService is a mock of WalletService interface.
+type MockWalletService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletServiceMockRecorder
+}
+
+// MockWalletServiceMockRecorder is the mock recorder for MockWalletService.
+type MockWalletServiceMockRecorder struct {
+	mock *MockWalletService
+}
+
+// NewMockWalletService creates a new mock instance.
+func NewMockWalletService(ctrl *gomock.Controller) *MockWalletService {
+	mock := &MockWalletService{ctrl: ctrl}
+	mock.recorder = &MockWalletServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletService) EXPECT() *MockWalletServiceMockRecorder {
+	return m.recorder
+}
+
+// BalanceAt mocks base method.
+func (m *MockWalletService) BalanceAt(ctx context.Context, walletID int, at time.Time) (*domain.BalanceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BalanceAt", ctx, walletID, at)
+	ret0, _ := ret[0].(*domain.BalanceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BalanceAt indicates an expected call of BalanceAt.
+func (mr *MockWalletServiceMockRecorder) BalanceAt(ctx, walletID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceAt", reflect.TypeOf((*MockWalletService)(nil).BalanceAt), ctx, walletID, at)
+}
+
+// CreditMoney mocks base method.
+func (m *MockWalletService) CreditMoney(ctx context.Context, entry domain.CreditEntry) (*entity.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreditMoney", ctx, entry)
+	ret0, _ := ret[0].(*entity.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreditMoney indicates an expected call of CreditMoney.
+func (mr *MockWalletServiceMockRecorder) CreditMoney(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditMoney", reflect.TypeOf((*MockWalletService)(nil).CreditMoney), ctx, entry)
+}
+
+// DebitMoney mocks base method.
+func (m *MockWalletService) DebitMoney(ctx context.Context, entry domain.DebitEntry) (*entity.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DebitMoney", ctx, entry)
+	ret0, _ := ret[0].(*entity.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DebitMoney indicates an expected call of DebitMoney.
+func (mr *MockWalletServiceMockRecorder) DebitMoney(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebitMoney", reflect.TypeOf((*MockWalletService)(nil).DebitMoney), ctx, entry)
+}
+
+// GetBalance mocks base method.
+func (m *MockWalletService) GetBalance(ctx context.Context, walletID int) (*domain.WalletBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", ctx, walletID)
+	ret0, _ := ret[0].(*domain.WalletBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockWalletServiceMockRecorder) GetBalance(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockWalletService)(nil).GetBalance), ctx, walletID)
+}
+
+// ListEntries mocks base method.
+func (m *MockWalletService) ListEntries(ctx context.Context, filter domain.EntryFilter) ([]domain.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", ctx, filter)
+	ret0, _ := ret[0].([]domain.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockWalletServiceMockRecorder) ListEntries(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockWalletService)(nil).ListEntries), ctx, filter)
+}
+
+// ReverseEntry mocks base method.
+func (m *MockWalletService) ReverseEntry(ctx context.Context, walletID, entryID int, actor, reason string) (*domain.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseEntry", ctx, walletID, entryID, actor, reason)
+	ret0, _ := ret[0].(*domain.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseEntry indicates an expected call of ReverseEntry.
+func (mr *MockWalletServiceMockRecorder) ReverseEntry(ctx, walletID, entryID, actor, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseEntry", reflect.TypeOf((*MockWalletService)(nil).ReverseEntry), ctx, walletID, entryID, actor, reason)
+}
@@ -0,0 +1,65 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWalletRoutes_ExportEntries(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	amount := money.NewFromInt(30000)
+	svc := NewMockWalletService(mockCtrl)
+	svc.EXPECT().ListEntries(gomock.Any(), domain.EntryFilter{WalletID: 25}).
+		Return([]domain.Entry{{
+			ID: 4, WalletID: 25, Debit: &amount, BalanceAfter: amount, CreatedAt: time.Date(2024, 4, 5, 12, 0, 0, 0, time.UTC),
+			EntryDetails: domain.EntryDetails{
+				Operation: domain.OperationDeposit,
+				Reference: "dep-1",
+				Initiator: domain.InitiatorService,
+				Metadata:  json.RawMessage(`{"provider":"fake"}`),
+			},
+		}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/wallets/25/entries/export", nil)
+	req.Header.Set("Authorization", "Bearer "+newTestToken(t))
+
+	rec := httptest.NewRecorder()
+	newContractEngine(svc, NewMockBonusService(mockCtrl), NewMockLimitService(mockCtrl),
+		NewMockExclusionService(mockCtrl), NewMockWithdrawalService(mockCtrl)).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "id,created_at,operation,debit,credit,bonus_amount,reference,initiator,metadata,reversal_of,reversed_by,"+
+		"balance,real_balance,bonus_balance\n"+
+		`4,2024-04-05T12:00:00Z,deposit,30.000,,0.000,dep-1,service,"{""provider"":""fake""}",,,30.000,30.000,0.000`+"\n",
+		rec.Body.String())
+}
+
+func TestWalletRoutes_AmountValidation(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/wallets/25/debit", strings.NewReader(`{"amount": "1.2345"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+newTestToken(t))
+
+	rec := httptest.NewRecorder()
+	newContractEngine(NewMockWalletService(mockCtrl), NewMockBonusService(mockCtrl), NewMockLimitService(mockCtrl),
+		NewMockExclusionService(mockCtrl), NewMockWithdrawalService(mockCtrl)).ServeHTTP(rec, req)
+
+	var got problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, problem.CodeValidationFailed, got.Code)
+	assert.Equal(t, []problem.FieldError{{Field: "amount", Message: "must have at most 3 decimal places"}}, got.Errors)
+}
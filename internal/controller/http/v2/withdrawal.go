@@ -0,0 +1,199 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v1/middleware/jwt"
+	"github.com/pprishchepa/go-casino-example/internal/controller/http/v2/model"
+	"github.com/pprishchepa/go-casino-example/internal/pkg/jwtauth"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=withdrawal.go -destination=withdrawal_mock_test.go -package=v2_test
+
+type WithdrawalService interface {
+	RequestWithdrawal(ctx context.Context, withdrawal domain.Withdrawal, actor string) (*domain.Withdrawal, error)
+	TransitionWithdrawal(
+		ctx context.Context,
+		walletID, withdrawalID int,
+		to domain.WithdrawalStatus,
+		initiator domain.Initiator,
+		actor, reason string,
+	) (*domain.Withdrawal, error)
+	ListWithdrawals(ctx context.Context, walletID int) ([]domain.Withdrawal, error)
+	GetWithdrawal(ctx context.Context, walletID, withdrawalID int) (*domain.Withdrawal, []domain.WithdrawalTransition, error)
+}
+
+type WithdrawalRoutes struct {
+	service WithdrawalService
+}
+
+func NewWithdrawalRoutes(service WithdrawalService) *WithdrawalRoutes {
+	return &WithdrawalRoutes{service: service}
+}
+
+func (r WithdrawalRoutes) RegisterRoutes(e *gin.RouterGroup) {
+	e.GET("/wallets/:wallet/withdrawals", r.listWithdrawals)
+	e.POST("/wallets/:wallet/withdrawals", r.requestWithdrawal)
+	e.GET("/wallets/:wallet/withdrawals/:withdrawal", r.getWithdrawal)
+	e.POST("/wallets/:wallet/withdrawals/:withdrawal/cancel", r.cancelWithdrawal)
+	e.POST("/wallets/:wallet/withdrawals/:withdrawal/status", jwt.RequireScope(jwtauth.ScopeAdmin), r.changeWithdrawal)
+}
+
+func (r WithdrawalRoutes) listWithdrawals(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, reqWallet.ID) {
+		return
+	}
+
+	withdrawals, err := r.service.ListWithdrawals(c.Request.Context(), reqWallet.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res := make([]model.WithdrawalResponse, 0, len(withdrawals))
+	for i := range withdrawals {
+		res = append(res, newWithdrawalResponse(&withdrawals[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func (r WithdrawalRoutes) requestWithdrawal(c *gin.Context) {
+	var reqWallet model.WalletRequest
+	if err := c.ShouldBindUri(&reqWallet); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, reqWallet.ID) {
+		return
+	}
+
+	var reqBody model.RequestWithdrawalRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var fields amountFields
+	amount := fields.parse("amount", reqBody.Amount, false)
+	if len(fields) > 0 {
+		_ = c.Error(fields.problem())
+		return
+	}
+
+	withdrawal, err := r.service.RequestWithdrawal(c.Request.Context(), domain.Withdrawal{
+		WalletID: reqWallet.ID,
+		Amount:   amount,
+	}, actor(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": newWithdrawalResponse(withdrawal)})
+}
+
+func (r WithdrawalRoutes) getWithdrawal(c *gin.Context) {
+	var req model.WithdrawalRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, req.WalletID) {
+		return
+	}
+
+	withdrawal, history, err := r.service.GetWithdrawal(c.Request.Context(), req.WalletID, req.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res := model.WithdrawalDetailsResponse{
+		WithdrawalResponse: newWithdrawalResponse(withdrawal),
+		History:            make([]model.WithdrawalTransitionResponse, 0, len(history)),
+	}
+	for _, transition := range history {
+		res.History = append(res.History, model.WithdrawalTransitionResponse{
+			From:   string(transition.From),
+			To:     string(transition.To),
+			Actor:  transition.Actor,
+			Reason: transition.Reason,
+			At:     transition.At,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// cancelWithdrawal lets the player take back a withdrawal that has not been
+// approved yet.
+func (r WithdrawalRoutes) cancelWithdrawal(c *gin.Context) {
+	var req model.WithdrawalRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	if !authorizeWallet(c, req.WalletID) {
+		return
+	}
+
+	withdrawal, err := r.service.TransitionWithdrawal(c.Request.Context(), req.WalletID, req.ID,
+		domain.WithdrawalCancelled, domain.InitiatorUser, actor(c), "cancelled by player")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newWithdrawalResponse(withdrawal)})
+}
+
+func (r WithdrawalRoutes) changeWithdrawal(c *gin.Context) {
+	var req model.WithdrawalRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var reqBody model.ChangeWithdrawalRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	withdrawal, err := r.service.TransitionWithdrawal(c.Request.Context(), req.WalletID, req.ID,
+		domain.WithdrawalStatus(reqBody.Status), domain.InitiatorAdmin, actor(c), reqBody.Reason)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newWithdrawalResponse(withdrawal)})
+}
+
+// actor is the subject of the token the request is authorized with.
+func actor(c *gin.Context) string {
+	if claims := jwtauth.FromContext(c.Request.Context()); claims != nil {
+		return claims.Subject
+	}
+	return ""
+}
+
+func newWithdrawalResponse(withdrawal *domain.Withdrawal) model.WithdrawalResponse {
+	return model.WithdrawalResponse{
+		ID:        withdrawal.ID,
+		WalletID:  withdrawal.WalletID,
+		Amount:    withdrawal.Amount,
+		Status:    string(withdrawal.Status),
+		CreatedAt: withdrawal.CreatedAt,
+		UpdatedAt: withdrawal.UpdatedAt,
+	}
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: withdrawal.go
+//
+// Generated by this command:
+//
+//	mockgen -source=withdrawal.go -destination=withdrawal_mock_test.go -package=v2_test
+//
+
+// Package v2_test is a generated GoMock package.
+package v2_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/pprishchepa/go-casino-example/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWithdrawalService is a mock of WithdrawalService interface.
+type MockWithdrawalService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWithdrawalServiceMockRecorder
+}
+
+// MockWithdrawalServiceMockRecorder is the mock recorder for MockWithdrawalService.
+type MockWithdrawalServiceMockRecorder struct {
+	mock *MockWithdrawalService
+}
+
+// NewMockWithdrawalService creates a new mock instance.
+func NewMockWithdrawalService(ctrl *gomock.Controller) *MockWithdrawalService {
+	mock := &MockWithdrawalService{ctrl: ctrl}
+	mock.recorder = &MockWithdrawalServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWithdrawalService) EXPECT() *MockWithdrawalServiceMockRecorder {
+	return m.recorder
+}
+
+// GetWithdrawal mocks base method.
+func (m *MockWithdrawalService) GetWithdrawal(ctx context.Context, walletID, withdrawalID int) (*domain.Withdrawal, []domain.WithdrawalTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithdrawal", ctx, walletID, withdrawalID)
+	ret0, _ := ret[0].(*domain.Withdrawal)
+	ret1, _ := ret[1].([]domain.WithdrawalTransition)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWithdrawal indicates an expected call of GetWithdrawal.
+func (mr *MockWithdrawalServiceMockRecorder) GetWithdrawal(ctx, walletID, withdrawalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawal", reflect.TypeOf((*MockWithdrawalService)(nil).GetWithdrawal), ctx, walletID, withdrawalID)
+}
+
+// ListWithdrawals mocks base method.
+func (m *MockWithdrawalService) ListWithdrawals(ctx context.Context, walletID int) ([]domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithdrawals", ctx, walletID)
+	ret0, _ := ret[0].([]domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithdrawals indicates an expected call of ListWithdrawals.
+func (mr *MockWithdrawalServiceMockRecorder) ListWithdrawals(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithdrawals", reflect.TypeOf((*MockWithdrawalService)(nil).ListWithdrawals), ctx, walletID)
+}
+
+// RequestWithdrawal mocks base method.
+func (m *MockWithdrawalService) RequestWithdrawal(ctx context.Context, withdrawal domain.Withdrawal, actor string) (*domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestWithdrawal", ctx, withdrawal, actor)
+	ret0, _ := ret[0].(*domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestWithdrawal indicates an expected call of RequestWithdrawal.
+func (mr *MockWithdrawalServiceMockRecorder) RequestWithdrawal(ctx, withdrawal, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestWithdrawal", reflect.TypeOf((*MockWithdrawalService)(nil).RequestWithdrawal), ctx, withdrawal, actor)
+}
+
+// TransitionWithdrawal mocks base method.
+func (m *MockWithdrawalService) TransitionWithdrawal(ctx context.Context, walletID, withdrawalID int, to domain.WithdrawalStatus, initiator domain.Initiator, actor, reason string) (*domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionWithdrawal", ctx, walletID, withdrawalID, to, initiator, actor, reason)
+	ret0, _ := ret[0].(*domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionWithdrawal indicates an expected call of TransitionWithdrawal.
+func (mr *MockWithdrawalServiceMockRecorder) TransitionWithdrawal(ctx, walletID, withdrawalID, to, initiator, actor, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionWithdrawal", reflect.TypeOf((*MockWithdrawalService)(nil).TransitionWithdrawal), ctx, walletID, withdrawalID, to, initiator, actor, reason)
+}
@@ -0,0 +1,236 @@
+package v2_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWithdrawalRoutes(t *testing.T) {
+	router := newSpecRouter(t)
+
+	createdAt := time.Date(2024, 5, 4, 12, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+
+	withdrawal := func(status domain.WithdrawalStatus) *domain.Withdrawal {
+		return &domain.Withdrawal{
+			ID:          12,
+			WalletID:    25,
+			Amount:      money.NewFromInt(40000),
+			Status:      status,
+			HoldEntryID: 300,
+			CreatedAt:   createdAt,
+			UpdatedAt:   updatedAt,
+		}
+	}
+	withdrawalBody := func(status string) string {
+		return `{"data":{"id":12,"walletId":25,"amount":"40.000","status":"` + status + `",` +
+			`"createdAt":"2024-05-04T12:00:00Z","updatedAt":"2024-05-04T13:00:00Z"}}`
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		admin      bool
+		mock       func(svc *MockWithdrawalService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "request",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/withdrawals",
+			body:   `{"amount": "40"}`,
+			mock: func(svc *MockWithdrawalService) {
+				svc.EXPECT().RequestWithdrawal(gomock.Any(), gomock.Any(), "player").
+					DoAndReturn(func(_ any, w domain.Withdrawal, _ string) (*domain.Withdrawal, error) {
+						assert.Equal(t, 25, w.WalletID)
+						assert.Equal(t, "40.000", w.Amount.String())
+						return withdrawal(domain.WithdrawalPendingReview), nil
+					})
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   withdrawalBody("pending_review"),
+		},
+		{
+			name:       "request with invalid amount",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/withdrawals",
+			body:       `{"amount": "-1"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "request over balance",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/withdrawals",
+			body:   `{"amount": "40"}`,
+			mock: func(svc *MockWithdrawalService) {
+				svc.EXPECT().RequestWithdrawal(gomock.Any(), gomock.Any(), "player").
+					Return(nil, domain.ErrInsufficientFunds)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/withdrawals",
+			mock: func(svc *MockWithdrawalService) {
+				svc.EXPECT().ListWithdrawals(gomock.Any(), 25).
+					Return([]domain.Withdrawal{*withdrawal(domain.WithdrawalSent)}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":[{"id":12,"walletId":25,"amount":"40.000","status":"sent",` +
+				`"createdAt":"2024-05-04T12:00:00Z","updatedAt":"2024-05-04T13:00:00Z"}]}`,
+		},
+		{
+			name:   "get with history",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/25/withdrawals/12",
+			mock: func(svc *MockWithdrawalService) {
+				svc.EXPECT().GetWithdrawal(gomock.Any(), 25, 12).Return(
+					withdrawal(domain.WithdrawalApproved),
+					[]domain.WithdrawalTransition{
+						{WithdrawalID: 12, WalletID: 25, To: domain.WithdrawalRequested, Actor: "player", At: createdAt},
+						{
+							WithdrawalID: 12,
+							WalletID:     25,
+							From:         domain.WithdrawalRequested,
+							To:           domain.WithdrawalApproved,
+							Actor:        domain.ActorSystem,
+							Reason:       "below auto-approval limit",
+							At:           createdAt,
+						},
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"data":{"id":12,"walletId":25,"amount":"40.000","status":"approved",` +
+				`"createdAt":"2024-05-04T12:00:00Z","updatedAt":"2024-05-04T13:00:00Z","history":[` +
+				`{"to":"requested","actor":"player","at":"2024-05-04T12:00:00Z"},` +
+				`{"from":"requested","to":"approved","actor":"system","reason":"below auto-approval limit",` +
+				`"at":"2024-05-04T12:00:00Z"}]}}`,
+		},
+		{
+			name:   "get through another wallet",
+			method: http.MethodGet,
+			path:   "/api/v2/wallets/26/withdrawals/12",
+			admin:  true,
+			mock: func(svc *MockWithdrawalService) {
+				svc.EXPECT().GetWithdrawal(gomock.Any(), 26, 12).Return(nil, nil, domain.ErrWithdrawalNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "list of wallet of another player",
+			method:     http.MethodGet,
+			path:       "/api/v2/wallets/26/withdrawals",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "request from wallet of another player",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/26/withdrawals",
+			body:       `{"amount": "40"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "get from wallet of another player",
+			method:     http.MethodGet,
+			path:       "/api/v2/wallets/26/withdrawals/12",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "cancel on wallet of another player",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/26/withdrawals/12/cancel",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "cancel",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/withdrawals/12/cancel",
+			mock: func(svc *MockWithdrawalService) {
+				svc.EXPECT().TransitionWithdrawal(gomock.Any(), 25, 12, domain.WithdrawalCancelled, domain.InitiatorUser, "player", "cancelled by player").
+					Return(withdrawal(domain.WithdrawalCancelled), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   withdrawalBody("cancelled"),
+		},
+		{
+			name:   "cancel after approval",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/withdrawals/12/cancel",
+			mock: func(svc *MockWithdrawalService) {
+				svc.EXPECT().TransitionWithdrawal(gomock.Any(), 25, 12, domain.WithdrawalCancelled, domain.InitiatorUser, "player", "cancelled by player").
+					Return(nil, domain.ErrInvalidTransition)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "approve",
+			method: http.MethodPost,
+			path:   "/api/v2/wallets/25/withdrawals/12/status",
+			body:   `{"status": "approved", "reason": "documents checked"}`,
+			admin:  true,
+			mock: func(svc *MockWithdrawalService) {
+				svc.EXPECT().TransitionWithdrawal(gomock.Any(), 25, 12, domain.WithdrawalApproved, domain.InitiatorAdmin, "support", "documents checked").
+					Return(withdrawal(domain.WithdrawalApproved), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   withdrawalBody("approved"),
+		},
+		{
+			name:       "approve without admin scope",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/withdrawals/12/status",
+			body:       `{"status": "approved"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "change to status set by the wallet",
+			method:     http.MethodPost,
+			path:       "/api/v2/wallets/25/withdrawals/12/status",
+			body:       `{"status": "pending_review"}`,
+			admin:      true,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			svc := NewMockWithdrawalService(mockCtrl)
+			if tt.mock != nil {
+				tt.mock(svc)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			token := newTestToken(t)
+			if tt.admin {
+				token = newAdminToken(t)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			rec := httptest.NewRecorder()
+			newContractEngine(NewMockWalletService(mockCtrl), NewMockBonusService(mockCtrl),
+				NewMockLimitService(mockCtrl), NewMockExclusionService(mockCtrl), svc).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+			validateResponse(t, router, req, rec)
+		})
+	}
+}
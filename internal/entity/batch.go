@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+type BatchMode string
+
+const (
+	// BatchModeAtomic applies either all items or none of them.
+	BatchModeAtomic BatchMode = "atomic"
+	// BatchModeBestEffort applies every item independently.
+	BatchModeBestEffort BatchMode = "best_effort"
+)
+
+type BatchStatus string
+
+const (
+	BatchStatusPending   BatchStatus = "pending"
+	BatchStatusRunning   BatchStatus = "running"
+	BatchStatusCompleted BatchStatus = "completed"
+	BatchStatusFailed    BatchStatus = "failed"
+)
+
+type BatchOperation string
+
+const (
+	BatchOperationDebit  BatchOperation = "debit"
+	BatchOperationCredit BatchOperation = "credit"
+)
+
+type BatchItemStatus string
+
+const (
+	BatchItemStatusPending    BatchItemStatus = "pending"
+	BatchItemStatusSucceeded  BatchItemStatus = "succeeded"
+	BatchItemStatusFailed     BatchItemStatus = "failed"
+	BatchItemStatusRolledBack BatchItemStatus = "rolled_back"
+)
+
+type Batch struct {
+	ID        int
+	Mode      BatchMode
+	Status    BatchStatus
+	Items     []BatchItem
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type BatchItem struct {
+	WalletID  int
+	Operation BatchOperation
+	Amount    money.Money
+	Status    BatchItemStatus
+	Error     string
+}
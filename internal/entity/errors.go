@@ -3,3 +3,11 @@ package entity
 import "errors"
 
 var ErrTxConflict = errors.New("tx conflict")
+
+var ErrBatchNotFound = errors.New("batch not found")
+
+// ErrBatchStatusChanged is returned when a batch no longer has the status it
+// was read with, e.g. as another process took it over.
+var ErrBatchStatusChanged = errors.New("batch status changed")
+
+var ErrInvalidReportRange = errors.New("invalid report range")
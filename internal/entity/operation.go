@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+// Operation is a committed debit or credit together with the balance it
+// resulted in.
+type Operation struct {
+	EntryID int
+	// Bonus is the part of the entry amount that went to or came from the
+	// bonus bucket.
+	Bonus     money.Money
+	Balance   domain.WalletBalance
+	CreatedAt time.Time
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+// DailySummary is what the ledger of the wallets of a brand in a currency
+// adds up to on a UTC day. Amounts include bonus money. A reversal counts
+// against the operation of the entry it undoes, on the day the reversal was
+// made.
+type DailySummary struct {
+	Day      time.Time
+	Brand    string
+	Currency string
+	Bets     money.Money
+	Wins     money.Money
+	Refunds  money.Money
+	Deposits money.Money
+	// Chargebacks are deposits taken back after a dispute.
+	Chargebacks money.Money
+	// Withdrawals are less the funds released back to wallets.
+	Withdrawals    money.Money
+	BonusGranted   money.Money
+	BonusConverted money.Money
+	// BonusReleased is bonus money taken back when a bonus is completed,
+	// expired or forfeited.
+	BonusReleased money.Money
+	// Adjustments are money added by admins less money taken.
+	Adjustments money.Money
+	Entries     int
+	// RefreshedAt is when the day was last aggregated.
+	RefreshedAt time.Time
+}
+
+// GGR is gross gaming revenue: bets less refunded stakes and wins.
+func (s DailySummary) GGR() money.Money {
+	return s.Bets.Sub(s.Refunds).Sub(s.Wins)
+}
+
+// BonusCost is bonus money that became real money the player can withdraw.
+func (s DailySummary) BonusCost() money.Money {
+	return s.BonusConverted
+}
+
+// NGR is net gaming revenue: GGR less the bonus cost.
+func (s DailySummary) NGR() money.Money {
+	return s.GGR().Sub(s.BonusCost())
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "github.com/pprishchepa/go-casino-example/domain"
+
+// WithdrawalEvent is a withdrawal transition waiting to be published. ID is
+// the ID of the transition, so that consumers can tell a transition published
+// again from a new one.
+type WithdrawalEvent struct {
+	ID int
+	domain.WithdrawalTransition
+}
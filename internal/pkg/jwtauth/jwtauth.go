@@ -0,0 +1,102 @@
+package jwtauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// ScopeAdmin grants back-office operations such as lifting an exclusion.
+const ScopeAdmin = "admin"
+
+type Claims struct {
+	jwt.RegisteredClaims
+	// Wallets lists the wallets owned by the token holder.
+	Wallets []int `json:"wallets,omitempty"`
+	// Scopes lists what the token holder is allowed to do beyond using
+	// wallets.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+func (c *Claims) OwnsWallet(walletID int) bool {
+	return slices.Contains(c.Wallets, walletID)
+}
+
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
+}
+
+type claimsKey struct{}
+
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext returns the claims of the authorized request, or nil.
+func FromContext(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(claimsKey{}).(*Claims)
+	return claims
+}
+
+type Validator struct {
+	keyFunc jwt.Keyfunc
+}
+
+func NewValidator(secret string) *Validator {
+	return &Validator{keyFunc: hmacKeyFunc([]byte(secret))}
+}
+
+// ValidateHeader validates a bearer token passed as an Authorization header value.
+func (v *Validator) ValidateHeader(authHeader string) (*Claims, error) {
+	if authHeader == "" {
+		return nil, errors.New("authorization header required")
+	}
+
+	scheme, tokenString := parseAuthHeader(authHeader)
+	if scheme != "bearer" {
+		return nil, errors.New("unexpected auth scheme")
+	}
+	if tokenString == "" {
+		return nil, errors.New("empty token")
+	}
+
+	var claims Claims
+
+	token, err := jwt.ParseWithClaims(tokenString, &claims, v.keyFunc)
+	if err != nil {
+		return nil, fmt.Errorf("parse token: %w", err)
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	return &claims, nil
+}
+
+func parseAuthHeader(s string) (scheme, token string) {
+	chunks := strings.Split(strings.Trim(s, " "), " ")
+	if len(chunks) == 2 {
+		scheme = strings.ToLower(chunks[0])
+		token = chunks[1]
+	}
+	return
+}
+
+func hmacKeyFunc(hmacSecret []byte) jwt.Keyfunc {
+	if len(hmacSecret) == 0 {
+		return func(token *jwt.Token) (interface{}, error) {
+			return nil, fmt.Errorf("no HMAC secret configured")
+		}
+	}
+
+	return func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return hmacSecret, nil
+	}
+}
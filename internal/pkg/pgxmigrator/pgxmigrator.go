@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
 	"strconv"
 	"time"
 
@@ -24,22 +25,94 @@ func NewMigrator() Migrator {
 	return Migrator{}
 }
 
+// Status is where a database is in the migrations.
+type Status struct {
+	// Version is the last migration applied, zero if none is.
+	Version uint
+	// Dirty is true if the last migration failed halfway and needs to be
+	// fixed by hand and forced.
+	Dirty bool
+	// Latest is the last migration there is.
+	Latest uint
+	// Pending is how many migrations are not applied yet.
+	Pending int
+}
+
+// Up applies all the migrations not applied yet.
 func (m Migrator) Up(db *pgxpool.Pool, fs embed.FS) error {
-	hostname := net.JoinHostPort(db.Config().ConnConfig.Host, strconv.Itoa(int(db.Config().ConnConfig.Port)))
+	return m.run(db, fs, "migrating...", func(mg *migrate.Migrate) error {
+		return mg.Up()
+	})
+}
+
+// Down rolls back the last steps migrations, all of them if steps is zero.
+func (m Migrator) Down(db *pgxpool.Pool, fs embed.FS, steps int) error {
+	if steps < 0 {
+		return fmt.Errorf("invalid steps %d", steps)
+	}
+	return m.run(db, fs, "rolling back...", func(mg *migrate.Migrate) error {
+		if steps == 0 {
+			return mg.Down()
+		}
+		return mg.Steps(-steps)
+	})
+}
+
+// Force sets the version without running any migration and clears the dirty
+// flag. A version of -1 means no migration is applied.
+func (m Migrator) Force(db *pgxpool.Pool, fs embed.FS, version int) error {
+	if version < -1 {
+		return fmt.Errorf("invalid version %d", version)
+	}
+	return m.run(db, fs, "forcing version...", func(mg *migrate.Migrate) error {
+		return mg.Force(version)
+	})
+}
+
+// Status returns where the database is in the migrations.
+func (m Migrator) Status(db *pgxpool.Pool, fs embed.FS) (Status, error) {
+	hostname := m.hostname(db)
+
+	versions, err := listVersions(fs)
+	if err != nil {
+		return Status{}, fmt.Errorf("list migrations: %w", err)
+	}
 
 	mg, err := m.newInstance(db, fs)
 	if err != nil {
-		return fmt.Errorf("init migrator: %s: %w", hostname, err)
+		return Status{}, fmt.Errorf("init migrator: %s: %w", hostname, err)
+	}
+	defer m.close(mg, hostname)
+
+	var status Status
+	status.Version, status.Dirty, err = mg.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return Status{}, fmt.Errorf("get version: %s: %w", hostname, err)
 	}
-	defer func() {
-		if _, err := mg.Close(); err != nil {
-			log.Err(err).Str("dbhost", hostname).Msg("could not close migrator")
+	for _, v := range versions {
+		if v > status.Version {
+			status.Pending++
 		}
-	}()
+	}
+	if len(versions) > 0 {
+		status.Latest = versions[len(versions)-1]
+	}
+
+	return status, nil
+}
 
-	log.Info().Str("dbhost", hostname).Msg("migrating...")
+func (m Migrator) run(db *pgxpool.Pool, fs embed.FS, msg string, fn func(mg *migrate.Migrate) error) error {
+	hostname := m.hostname(db)
 
-	if err := mg.Up(); err != nil {
+	mg, err := m.newInstance(db, fs)
+	if err != nil {
+		return fmt.Errorf("init migrator: %s: %w", hostname, err)
+	}
+	defer m.close(mg, hostname)
+
+	log.Info().Str("dbhost", hostname).Msg(msg)
+
+	if err := fn(mg); err != nil {
 		if errors.Is(err, migrate.ErrNoChange) || errors.Is(err, migrate.ErrNilVersion) {
 			log.Info().Str("dbhost", hostname).Msg(err.Error())
 			return nil
@@ -52,6 +125,38 @@ func (m Migrator) Up(db *pgxpool.Pool, fs embed.FS) error {
 	return nil
 }
 
+func (m Migrator) hostname(db *pgxpool.Pool) string {
+	return net.JoinHostPort(db.Config().ConnConfig.Host, strconv.Itoa(int(db.Config().ConnConfig.Port)))
+}
+
+func (m Migrator) close(mg *migrate.Migrate, hostname string) {
+	if _, err := mg.Close(); err != nil {
+		log.Err(err).Str("dbhost", hostname).Msg("could not close migrator")
+	}
+}
+
+// listVersions returns the versions of the migrations in fs in ascending
+// order.
+func listVersions(fs embed.FS) ([]uint, error) {
+	source, err := iofs.New(fs, ".")
+	if err != nil {
+		return nil, fmt.Errorf("create source: %w", err)
+	}
+	defer source.Close()
+
+	var versions []uint
+	version, err := source.First()
+	for err == nil {
+		versions = append(versions, version)
+		version, err = source.Next(version)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
 func (m Migrator) newInstance(db *pgxpool.Pool, fs embed.FS) (*migrate.Migrate, error) {
 	source, err := iofs.New(fs, ".")
 	if err != nil {
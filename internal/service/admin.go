@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/rs/zerolog/log"
+)
+
+// SearchWallets returns balances of wallets that match the filter. Balances
+// are read from the store rather than the cache.
+func (s *WalletService) SearchWallets(ctx context.Context, filter domain.WalletFilter) ([]domain.WalletBalance, error) {
+	var balances []domain.WalletBalance
+
+	err := s.runOnceTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		if balances, err = tx.SearchWallets(ctx, filter); err != nil {
+			return fmt.Errorf("search wallets: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+// AdjustBalance corrects real money of the wallet on behalf of actor and
+// returns the adjustment entry.
+func (s *WalletService) AdjustBalance(
+	ctx context.Context,
+	walletID int,
+	amount money.Money,
+	actor, reason string,
+) (*domain.Entry, error) {
+	var (
+		entry   *domain.Entry
+		balance *domain.WalletBalance
+	)
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		usecase := s.usecases(tx)
+		var err error
+		if entry, err = usecase.AdjustBalance(ctx, walletID, amount, actor, reason); err != nil {
+			return fmt.Errorf("adjust balance: %w", err)
+		}
+		if balance, err = usecase.RetrieveBalance(ctx, walletID); err != nil {
+			return fmt.Errorf("retrieve balance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.balanceChanged(ctx, balance)
+
+	log.Info().
+		Int("walletId", walletID).
+		Int("entryId", entry.ID).
+		Str("amount", amount.String()).
+		Str("actor", actor).
+		Str("reason", reason).
+		Msg("balance adjusted")
+
+	return entry, nil
+}
+
+// FreezeWallet stops the wallet from betting and withdrawing on behalf of
+// actor.
+func (s *WalletService) FreezeWallet(ctx context.Context, walletID int, actor, reason string) (*domain.WalletBalance, error) {
+	return s.setFrozen(ctx, walletID, true, actor, reason)
+}
+
+func (s *WalletService) UnfreezeWallet(ctx context.Context, walletID int, actor, reason string) (*domain.WalletBalance, error) {
+	return s.setFrozen(ctx, walletID, false, actor, reason)
+}
+
+func (s *WalletService) setFrozen(ctx context.Context, walletID int, frozen bool, actor, reason string) (*domain.WalletBalance, error) {
+	var balance *domain.WalletBalance
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		usecase := s.usecases(tx)
+		var err error
+		if frozen {
+			balance, err = usecase.FreezeWallet(ctx, walletID, actor, reason)
+		} else {
+			balance, err = usecase.UnfreezeWallet(ctx, walletID, actor, reason)
+		}
+		if err != nil {
+			return fmt.Errorf("set frozen: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.balanceChanged(ctx, balance)
+
+	log.Info().
+		Int("walletId", walletID).
+		Bool("frozen", frozen).
+		Str("actor", actor).
+		Str("reason", reason).
+		Msg("wallet frozen state changed")
+
+	return balance, nil
+}
+
+// OverrideLimit sets a limit at once on behalf of actor, or removes it if
+// amount is nil.
+func (s *WalletService) OverrideLimit(
+	ctx context.Context,
+	walletID int,
+	kind domain.LimitKind,
+	period domain.LimitPeriod,
+	amount *money.Money,
+	actor, reason string,
+) (*domain.Limit, error) {
+	var limit *domain.Limit
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		limit, err = s.usecases(tx).OverrideLimit(ctx, walletID, kind, period, amount, actor, reason)
+		if err != nil {
+			return fmt.Errorf("override limit: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Int("walletId", walletID).
+		Str("kind", string(kind)).
+		Str("period", string(period)).
+		Str("actor", actor).
+		Str("reason", reason).
+		Msg("limit overridden")
+
+	return limit, nil
+}
+
+// EvictBalance drops the cached balance of the wallet on behalf of actor, so
+// that the next read goes to the store. The eviction is only recorded if the
+// cache has been cleared.
+func (s *WalletService) EvictBalance(ctx context.Context, walletID int, actor, reason string) error {
+	err := s.runOnceTx(ctx, func(tx WalletStoreTx) error {
+		if err := s.usecases(tx).RecordCacheEviction(ctx, walletID, actor, reason); err != nil {
+			return fmt.Errorf("record cache eviction: %w", err)
+		}
+		if err := s.cache.DeleteBalance(ctx, walletID); err != nil {
+			return fmt.Errorf("delete balance from cache: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Int("walletId", walletID).
+		Str("actor", actor).
+		Str("reason", reason).
+		Msg("balance evicted from cache")
+
+	return nil
+}
+
+// ListAdminActions returns actions of the audit trail that match the filter,
+// oldest first.
+func (s *WalletService) ListAdminActions(ctx context.Context, filter domain.AdminActionFilter) ([]domain.AdminAction, error) {
+	var actions []domain.AdminAction
+
+	err := s.runOnceTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		if actions, err = s.usecases(tx).ListAdminActions(ctx, filter); err != nil {
+			return fmt.Errorf("list admin actions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return actions, nil
+}
@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWalletService_Admin(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+
+	db := memory.NewWalletStoreTxFactory()
+	walletID := db.CreateWallet(money.NewFromInt(1000))
+	db.CreateWallet(money.NewFromInt(1000))
+
+	var cached []domain.WalletBalance
+	cache := NewMockWalletCacheStore(mockCtrl)
+	cache.EXPECT().SaveBalance(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, balance *domain.WalletBalance) {
+			cached = append(cached, *balance)
+		}).Return(nil).Times(2)
+	gomock.InOrder(
+		cache.EXPECT().DeleteBalance(gomock.Any(), walletID).Return(errors.New("redis is down")),
+		cache.EXPECT().DeleteBalance(gomock.Any(), walletID).Return(nil),
+	)
+
+	wallets := service.NewWalletService(memoryTxFactory{db}, cache, service.NewBalanceBroker())
+
+	entry, err := wallets.AdjustBalance(ctx, walletID, money.NewFromInt(-250), "finance", "duplicate payout")
+	require.NoError(t, err)
+	assert.Equal(t, domain.OperationAdjustment, entry.Operation)
+	assert.Equal(t, 250, entry.Credit.AsInt())
+
+	balance, err := wallets.FreezeWallet(ctx, walletID, "support", "fraud check")
+	require.NoError(t, err)
+	assert.True(t, balance.Frozen)
+	require.Len(t, cached, 2)
+	assert.True(t, cached[1].Frozen, "the cache sees the freeze")
+	assert.Equal(t, 750, cached[1].Amount.AsInt())
+
+	yes := true
+	frozen, err := wallets.SearchWallets(ctx, domain.WalletFilter{Frozen: &yes})
+	require.NoError(t, err)
+	require.Len(t, frozen, 1)
+	assert.Equal(t, walletID, frozen[0].WalletID)
+
+	require.Error(t, wallets.EvictBalance(ctx, walletID, "support", ""))
+	require.NoError(t, wallets.EvictBalance(ctx, walletID, "support", ""))
+
+	actions, err := wallets.ListAdminActions(ctx, domain.AdminActionFilter{WalletID: walletID})
+	require.NoError(t, err)
+	var kinds []domain.AdminActionKind
+	for _, a := range actions {
+		kinds = append(kinds, a.Kind)
+	}
+	assert.Equal(t, []domain.AdminActionKind{
+		domain.AdminAdjustBalance,
+		domain.AdminFreeze,
+		domain.AdminEvictCache,
+	}, kinds, "a failed eviction is not recorded")
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// AuditService verifies the audit log kept over the ledger of every wallet.
+type AuditService struct {
+	txFactory WalletStoreTxFactory
+}
+
+func NewAuditService(txFactory WalletStoreTxFactory) *AuditService {
+	return &AuditService{txFactory: txFactory}
+}
+
+// VerifyAudit verifies wallets one by one, each in a tx of its own, and
+// returns a report for every wallet.
+func (s *AuditService) VerifyAudit(ctx context.Context) ([]domain.AuditReport, error) {
+	var reports []domain.AuditReport
+	err := s.eachWallet(ctx, func(tx WalletStoreTx, walletID int) error {
+		report, err := domain.NewWalletUseCases(tx).VerifyAudit(ctx, walletID)
+		if err != nil {
+			return fmt.Errorf("verify audit of wallet %d: %w", walletID, err)
+		}
+		reports = append(reports, *report)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// Reconcile checks wallets one by one, each in a tx of its own, and returns
+// the outcome for every wallet. The balance and the ledger of a wallet are
+// read in the same tx, so money that moves meanwhile does not show up as a
+// difference.
+func (s *AuditService) Reconcile(ctx context.Context) ([]domain.Reconciliation, error) {
+	var reconciliations []domain.Reconciliation
+	err := s.eachWallet(ctx, func(tx WalletStoreTx, walletID int) error {
+		reconciliation, err := domain.NewWalletUseCases(tx).Reconcile(ctx, walletID)
+		if err != nil {
+			return fmt.Errorf("reconcile wallet %d: %w", walletID, err)
+		}
+		reconciliations = append(reconciliations, *reconciliation)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliations, nil
+}
+
+// eachWallet calls fn for every wallet in ascending order of IDs, each time in
+// a read tx of its own.
+func (s *AuditService) eachWallet(ctx context.Context, fn func(tx WalletStoreTx, walletID int) error) error {
+	var walletIDs []int
+	err := readTx(ctx, s.txFactory, func(tx WalletStoreTx) error {
+		var err error
+		if walletIDs, err = tx.ListWalletIDs(ctx); err != nil {
+			return fmt.Errorf("list wallet ids: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, walletID := range walletIDs {
+		err := readTx(ctx, s.txFactory, func(tx WalletStoreTx) error {
+			return fn(tx, walletID)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readTx runs fn in a tx that is rolled back afterwards, as fn only reads.
+func readTx(ctx context.Context, txFactory WalletStoreTxFactory, fn func(tx WalletStoreTx) error) error {
+	tx, err := txFactory.NewTx(ctx)
+	if err != nil {
+		return fmt.Errorf("new tx: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil {
+			log.Warn().Err(err).Msg("could not rollback tx")
+		}
+	}()
+
+	return fn(tx)
+}
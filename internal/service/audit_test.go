@@ -0,0 +1,72 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditService_VerifyAudit(t *testing.T) {
+	ctx := context.Background()
+
+	db := memory.NewWalletStoreTxFactory()
+	w1 := db.CreateWallet(money.NewFromInt(1000))
+	w2 := db.CreateWallet(money.NewFromInt(1000))
+
+	tx, err := db.NewTx(ctx)
+	require.NoError(t, err)
+	uc := domain.NewWalletUseCases(tx)
+	require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: w1, Amount: money.NewFromInt(500)}))
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: w1, Amount: money.NewFromInt(200)}))
+	require.NoError(t, tx.Commit(ctx))
+
+	reports, err := service.NewAuditService(memoryTxFactory{db}).VerifyAudit(ctx)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	assert.Equal(t, w1, reports[0].WalletID)
+	assert.Equal(t, 2, reports[0].Records)
+	assert.NotEmpty(t, reports[0].Head)
+	assert.Empty(t, reports[0].Violations)
+
+	assert.Equal(t, w2, reports[1].WalletID)
+	assert.Zero(t, reports[1].Records)
+	assert.Nil(t, reports[1].Head)
+	assert.Empty(t, reports[1].Violations)
+}
+
+func TestAuditService_Reconcile(t *testing.T) {
+	ctx := context.Background()
+
+	db := memory.NewWalletStoreTxFactory()
+	w1 := db.CreateWallet(money.NewFromInt(0))
+	// The opening balance of the wallet is not in its ledger.
+	w2 := db.CreateWallet(money.NewFromInt(1000))
+
+	tx, err := db.NewTx(ctx)
+	require.NoError(t, err)
+	uc := domain.NewWalletUseCases(tx)
+	require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: w1, Amount: money.NewFromInt(500)}))
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: w1, Amount: money.NewFromInt(200)}))
+	require.NoError(t, tx.Commit(ctx))
+
+	got, err := service.NewAuditService(memoryTxFactory{db}).Reconcile(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, w1, got[0].WalletID)
+	assert.Equal(t, 2, got[0].Entries)
+	assert.Equal(t, "0.300", got[0].Ledger.Amount.String())
+	assert.True(t, got[0].Consistent())
+
+	assert.Equal(t, w2, got[1].WalletID)
+	assert.Equal(t, "1.000", got[1].Balance.Amount.String())
+	assert.True(t, got[1].Ledger.Amount.IsZero())
+	assert.False(t, got[1].Balanced())
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/errgroup"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=batch.go -destination=batch_mock_test.go -package=service_test
+
+type BatchStore interface {
+	CreateBatch(ctx context.Context, batch *entity.Batch) error
+	// UpdateBatch saves the batch if its status is still from, or returns
+	// entity.ErrBatchStatusChanged.
+	UpdateBatch(ctx context.Context, batch *entity.Batch, from entity.BatchStatus) error
+	// TouchBatch refreshes a running batch, so that it is not taken for
+	// abandoned while it is processed. It returns entity.ErrBatchStatusChanged
+	// if the batch is not running.
+	TouchBatch(ctx context.Context, batchID int) error
+	GetBatch(ctx context.Context, batchID int) (*entity.Batch, error)
+	// ClaimStaleBatches marks batches that have been pending for longer than
+	// staleAfter as running and returns their IDs. A batch is claimed once
+	// even if several processes claim at the same time.
+	ClaimStaleBatches(ctx context.Context, staleAfter time.Duration) ([]int, error)
+	// FailStaleBatches marks batches that have been running without being
+	// updated or touched for longer than staleAfter as failed, sets reason as the error of their items that are
+	// still pending and returns their IDs.
+	FailStaleBatches(ctx context.Context, staleAfter time.Duration, reason string) ([]int, error)
+}
+
+// interruptedBatchItem is the error of items of a batch whose processing
+// stopped halfway. Whether they were applied is only known from the ledger.
+const interruptedBatchItem = "batch processing was interrupted, the item may or may not be applied"
+
+type BatchService struct {
+	wallets     *WalletService
+	store       BatchStore
+	parallelism int
+	staleAfter  time.Duration
+	wg          sync.WaitGroup
+}
+
+// NewBatchService returns a service that processes up to parallelism items
+// at a time. Batches left pending or running for longer than staleAfter are
+// taken to be abandoned by a process that stopped.
+func NewBatchService(wallets *WalletService, store BatchStore, parallelism int, staleAfter time.Duration) *BatchService {
+	return &BatchService{
+		wallets:     wallets,
+		store:       store,
+		parallelism: max(parallelism, 1),
+		staleAfter:  staleAfter,
+	}
+}
+
+// Start takes over batches abandoned by processes that stopped before they
+// finished them, as batches are processed in memory. Batches that were still
+// pending are processed in the background. Batches that were running are
+// failed, since their items may be partly applied and applying them again
+// would apply some twice.
+func (s *BatchService) Start(ctx context.Context) error {
+	failed, err := s.store.FailStaleBatches(ctx, s.staleAfter, interruptedBatchItem)
+	if err != nil {
+		return fmt.Errorf("fail stale batches: %w", err)
+	}
+	for _, batchID := range failed {
+		log.Warn().Int("batchId", batchID).Msg("batch was interrupted and is failed")
+	}
+
+	claimed, err := s.store.ClaimStaleBatches(ctx, s.staleAfter)
+	if err != nil {
+		return fmt.Errorf("claim stale batches: %w", err)
+	}
+
+	for _, batchID := range claimed {
+		batch, err := s.store.GetBatch(ctx, batchID)
+		if err != nil {
+			return fmt.Errorf("get batch %d: %w", batchID, err)
+		}
+		log.Info().Int("batchId", batchID).Msg("processing abandoned batch")
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.ProcessBatch(context.Background(), batch)
+		}()
+	}
+
+	return nil
+}
+
+// SubmitBatch stores the batch and processes it in the background. The batch
+// status can be polled with GetBatch.
+func (s *BatchService) SubmitBatch(ctx context.Context, batch *entity.Batch) error {
+	batch.Status = entity.BatchStatusPending
+	for i := range batch.Items {
+		batch.Items[i].Status = entity.BatchItemStatusPending
+		batch.Items[i].Error = ""
+	}
+
+	if err := s.store.CreateBatch(ctx, batch); err != nil {
+		return fmt.Errorf("create batch: %w", err)
+	}
+
+	// The caller keeps its copy, so the background processing gets its own.
+	processing := *batch
+	processing.Items = slices.Clone(batch.Items)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.ProcessBatch(context.WithoutCancel(ctx), &processing)
+	}()
+
+	return nil
+}
+
+func (s *BatchService) GetBatch(ctx context.Context, batchID int) (*entity.Batch, error) {
+	return s.store.GetBatch(ctx, batchID)
+}
+
+// ProcessBatch applies the batch items and saves the outcome. A batch whose
+// status was changed by another process is left to it.
+func (s *BatchService) ProcessBatch(ctx context.Context, batch *entity.Batch) {
+	from := batch.Status
+	batch.Status = entity.BatchStatusRunning
+	if err := s.store.UpdateBatch(ctx, batch, from); err != nil {
+		if errors.Is(err, entity.ErrBatchStatusChanged) {
+			log.Warn().Int("batchId", batch.ID).Msg("batch was taken over by another process")
+			return
+		}
+		log.Warn().Err(err).Int("batchId", batch.ID).Msg("could not mark batch as running")
+	}
+
+	stop := s.heartbeat(ctx, batch.ID)
+	switch batch.Mode {
+	case entity.BatchModeAtomic:
+		s.processAtomic(ctx, batch)
+	default:
+		s.processBestEffort(ctx, batch)
+	}
+	stop()
+
+	if err := s.store.UpdateBatch(ctx, batch, entity.BatchStatusRunning); err != nil {
+		if errors.Is(err, entity.ErrBatchStatusChanged) {
+			// The items are applied, but the batch was failed as abandoned.
+			log.Error().Int("batchId", batch.ID).Msg("batch was failed while it was processed, its result is not saved")
+			return
+		}
+		log.Err(err).Int("batchId", batch.ID).Msg("could not save batch result")
+	}
+}
+
+// heartbeat touches the batch every third of staleAfter until the returned
+// func is called.
+func (s *BatchService) heartbeat(ctx context.Context, batchID int) func() {
+	interval := s.staleAfter / 3
+	if interval <= 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				err := s.store.TouchBatch(ctx, batchID)
+				if err != nil && !errors.Is(err, context.Canceled) {
+					log.Warn().Err(err).Int("batchId", batchID).Msg("could not touch running batch")
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// Shutdown waits for batches that are being processed.
+func (s *BatchService) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *BatchService) processAtomic(ctx context.Context, batch *entity.Batch) {
+	failed, err := s.wallets.applyBatchItems(ctx, batch.Items)
+	if err == nil {
+		for i := range batch.Items {
+			batch.Items[i].Status = entity.BatchItemStatusSucceeded
+		}
+		batch.Status = entity.BatchStatusCompleted
+		return
+	}
+
+	for i := range batch.Items {
+		batch.Items[i].Status = entity.BatchItemStatusRolledBack
+	}
+	if failed >= 0 {
+		batch.Items[failed].Status = entity.BatchItemStatusFailed
+		batch.Items[failed].Error = batchItemError(batch.ID, err)
+	}
+	batch.Status = entity.BatchStatusFailed
+}
+
+// processBestEffort applies items of different wallets in parallel, while
+// items of the same wallet are applied one by one in the submitted order.
+func (s *BatchService) processBestEffort(ctx context.Context, batch *entity.Batch) {
+	var walletIDs []int
+	itemsByWallet := make(map[int][]int)
+	for i, item := range batch.Items {
+		if _, ok := itemsByWallet[item.WalletID]; !ok {
+			walletIDs = append(walletIDs, item.WalletID)
+		}
+		itemsByWallet[item.WalletID] = append(itemsByWallet[item.WalletID], i)
+	}
+
+	var g errgroup.Group
+	g.SetLimit(s.parallelism)
+
+	for _, walletID := range walletIDs {
+		indexes := itemsByWallet[walletID]
+		g.Go(func() error {
+			for _, i := range indexes {
+				item := &batch.Items[i]
+				if err := s.wallets.applyBatchItem(ctx, *item); err != nil {
+					item.Status = entity.BatchItemStatusFailed
+					item.Error = batchItemError(batch.ID, err)
+					continue
+				}
+				item.Status = entity.BatchItemStatusSucceeded
+			}
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+	batch.Status = entity.BatchStatusCompleted
+}
+
+func (s *WalletService) applyBatchItem(ctx context.Context, item entity.BatchItem) error {
+	switch item.Operation {
+	case entity.BatchOperationDebit:
+		_, err := s.DebitMoney(ctx, domain.DebitEntry{WalletID: item.WalletID, Amount: item.Amount})
+		return err
+	case entity.BatchOperationCredit:
+		_, err := s.CreditMoney(ctx, domain.CreditEntry{WalletID: item.WalletID, Amount: item.Amount})
+		return err
+	default:
+		return fmt.Errorf("unknown operation: %s", item.Operation)
+	}
+}
+
+// applyBatchItems applies all items in a single transaction. On failure it
+// returns the index of the item that failed, or -1 if no item is to blame.
+func (s *WalletService) applyBatchItems(ctx context.Context, items []entity.BatchItem) (int, error) {
+	var (
+		failed   int
+		balances []*domain.WalletBalance
+	)
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		failed = -1
+		balances = balances[:0]
+
+		usecase := s.usecases(tx)
+		touched := make(map[int]struct{})
+
+		for i, item := range items {
+			var err error
+			switch item.Operation {
+			case entity.BatchOperationDebit:
+				err = usecase.DebitMoney(ctx, &domain.DebitEntry{WalletID: item.WalletID, Amount: item.Amount})
+			case entity.BatchOperationCredit:
+				err = usecase.CreditMoney(ctx, &domain.CreditEntry{WalletID: item.WalletID, Amount: item.Amount})
+			default:
+				err = fmt.Errorf("unknown operation: %s", item.Operation)
+			}
+			if err != nil {
+				failed = i
+				return fmt.Errorf("%s money: %w", item.Operation, err)
+			}
+			touched[item.WalletID] = struct{}{}
+		}
+
+		for walletID := range touched {
+			balance, err := usecase.RetrieveBalance(ctx, walletID)
+			if err != nil {
+				return fmt.Errorf("retrieve balance: %w", err)
+			}
+			balances = append(balances, balance)
+		}
+		return nil
+	})
+	if err != nil {
+		return failed, err
+	}
+
+	for _, balance := range balances {
+		s.balanceChanged(ctx, balance)
+	}
+
+	return -1, nil
+}
+
+// batchItemRejections are the errors of the wallet rejecting an item, which
+// clients can act upon.
+var batchItemRejections = []error{
+	domain.ErrWalletNotFound,
+	domain.ErrInvalidAmount,
+	domain.ErrInsufficientFunds,
+	domain.ErrInvalidEntry,
+	domain.ErrLimitExceeded,
+	domain.ErrSelfExcluded,
+	domain.ErrWalletFrozen,
+	domain.ErrWalletFlagged,
+}
+
+// batchItemError returns an error message that is safe to expose to clients.
+// Errors other than rejections are logged and reported as internal.
+func batchItemError(batchID int, err error) string {
+	// The limit error tells how much may still be spent.
+	var limitErr *domain.LimitExceededError
+	if errors.As(err, &limitErr) {
+		return limitErr.Error()
+	}
+	for _, known := range batchItemRejections {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+
+	log.Err(err).Int("batchId", batchID).Msg("could not apply batch item")
+	return "internal error"
+}
@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: batch.go
+//
+// Generated by this command:
+//
+//	mockgen -source=batch.go -destination=batch_mock_test.go -package=service_test
+//
+
+// Package service_test is a generated GoMock package.
+package service_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/pprishchepa/go-casino-example/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBatchStore is a mock of BatchStore interface.
+type MockBatchStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchStoreMockRecorder
+}
+
+// MockBatchStoreMockRecorder is the mock recorder for MockBatchStore.
+type MockBatchStoreMockRecorder struct {
+	mock *MockBatchStore
+}
+
+// NewMockBatchStore creates a new mock instance.
+func NewMockBatchStore(ctrl *gomock.Controller) *MockBatchStore {
+	mock := &MockBatchStore{ctrl: ctrl}
+	mock.recorder = &MockBatchStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchStore) EXPECT() *MockBatchStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimStaleBatches mocks base method.
+func (m *MockBatchStore) ClaimStaleBatches(ctx context.Context, staleAfter time.Duration) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimStaleBatches", ctx, staleAfter)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimStaleBatches indicates an expected call of ClaimStaleBatches.
+func (mr *MockBatchStoreMockRecorder) ClaimStaleBatches(ctx, staleAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimStaleBatches", reflect.TypeOf((*MockBatchStore)(nil).ClaimStaleBatches), ctx, staleAfter)
+}
+
+// CreateBatch mocks base method.
+func (m *MockBatchStore) CreateBatch(ctx context.Context, batch *entity.Batch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, batch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockBatchStoreMockRecorder) CreateBatch(ctx, batch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockBatchStore)(nil).CreateBatch), ctx, batch)
+}
+
+// FailStaleBatches mocks base method.
+func (m *MockBatchStore) FailStaleBatches(ctx context.Context, staleAfter time.Duration, reason string) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStaleBatches", ctx, staleAfter, reason)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailStaleBatches indicates an expected call of FailStaleBatches.
+func (mr *MockBatchStoreMockRecorder) FailStaleBatches(ctx, staleAfter, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStaleBatches", reflect.TypeOf((*MockBatchStore)(nil).FailStaleBatches), ctx, staleAfter, reason)
+}
+
+// GetBatch mocks base method.
+func (m *MockBatchStore) GetBatch(ctx context.Context, batchID int) (*entity.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, batchID)
+	ret0, _ := ret[0].(*entity.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockBatchStoreMockRecorder) GetBatch(ctx, batchID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockBatchStore)(nil).GetBatch), ctx, batchID)
+}
+
+// TouchBatch mocks base method.
+func (m *MockBatchStore) TouchBatch(ctx context.Context, batchID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchBatch", ctx, batchID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchBatch indicates an expected call of TouchBatch.
+func (mr *MockBatchStoreMockRecorder) TouchBatch(ctx, batchID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchBatch", reflect.TypeOf((*MockBatchStore)(nil).TouchBatch), ctx, batchID)
+}
+
+// UpdateBatch mocks base method.
+func (m *MockBatchStore) UpdateBatch(ctx context.Context, batch *entity.Batch, from entity.BatchStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBatch", ctx, batch, from)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBatch indicates an expected call of UpdateBatch.
+func (mr *MockBatchStoreMockRecorder) UpdateBatch(ctx, batch, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBatch", reflect.TypeOf((*MockBatchStore)(nil).UpdateBatch), ctx, batch, from)
+}
@@ -0,0 +1,290 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBatchService_ProcessBatch(t *testing.T) {
+	debit := func(walletID, amount int) entity.BatchItem {
+		return entity.BatchItem{WalletID: walletID, Operation: entity.BatchOperationDebit, Amount: money.NewFromInt(amount)}
+	}
+	credit := func(walletID, amount int) entity.BatchItem {
+		return entity.BatchItem{WalletID: walletID, Operation: entity.BatchOperationCredit, Amount: money.NewFromInt(amount)}
+	}
+
+	tests := []struct {
+		name         string
+		mode         entity.BatchMode
+		items        func(w1, w2 int) []entity.BatchItem
+		wantStatus   entity.BatchStatus
+		wantItems    []entity.BatchItemStatus
+		wantBalance1 int
+		wantBalance2 int
+	}{
+		{
+			name: "atomic succeeds",
+			mode: entity.BatchModeAtomic,
+			items: func(w1, w2 int) []entity.BatchItem {
+				return []entity.BatchItem{debit(w1, 100), credit(w2, 50), credit(w1, 1100)}
+			},
+			wantStatus: entity.BatchStatusCompleted,
+			wantItems: []entity.BatchItemStatus{
+				entity.BatchItemStatusSucceeded,
+				entity.BatchItemStatusSucceeded,
+				entity.BatchItemStatusSucceeded,
+			},
+			wantBalance1: 0,
+			wantBalance2: 950,
+		},
+		{
+			name: "atomic rolls back everything",
+			mode: entity.BatchModeAtomic,
+			items: func(w1, w2 int) []entity.BatchItem {
+				return []entity.BatchItem{debit(w1, 100), credit(w2, 5000), credit(w1, 50)}
+			},
+			wantStatus: entity.BatchStatusFailed,
+			wantItems: []entity.BatchItemStatus{
+				entity.BatchItemStatusRolledBack,
+				entity.BatchItemStatusFailed,
+				entity.BatchItemStatusRolledBack,
+			},
+			wantBalance1: 1000,
+			wantBalance2: 1000,
+		},
+		{
+			name: "best effort keeps successful items",
+			mode: entity.BatchModeBestEffort,
+			items: func(w1, w2 int) []entity.BatchItem {
+				return []entity.BatchItem{debit(w1, 100), credit(w2, 5000), credit(w2, 50), credit(-1, 1)}
+			},
+			wantStatus: entity.BatchStatusCompleted,
+			wantItems: []entity.BatchItemStatus{
+				entity.BatchItemStatusSucceeded,
+				entity.BatchItemStatusFailed,
+				entity.BatchItemStatusSucceeded,
+				entity.BatchItemStatusFailed,
+			},
+			wantBalance1: 1100,
+			wantBalance2: 950,
+		},
+		{
+			name: "best effort keeps wallet order",
+			mode: entity.BatchModeBestEffort,
+			items: func(w1, w2 int) []entity.BatchItem {
+				// The credit only succeeds if the debit before it was applied first.
+				return []entity.BatchItem{debit(w1, 500), credit(w1, 1500), debit(w2, 1), credit(w2, 1001)}
+			},
+			wantStatus: entity.BatchStatusCompleted,
+			wantItems: []entity.BatchItemStatus{
+				entity.BatchItemStatusSucceeded,
+				entity.BatchItemStatusSucceeded,
+				entity.BatchItemStatusSucceeded,
+				entity.BatchItemStatusSucceeded,
+			},
+			wantBalance1: 0,
+			wantBalance2: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockCtrl := gomock.NewController(t)
+
+			db := memory.NewWalletStoreTxFactory()
+			w1 := db.CreateWallet(money.NewFromInt(1000))
+			w2 := db.CreateWallet(money.NewFromInt(1000))
+
+			cache := NewMockWalletCacheStore(mockCtrl)
+			cache.EXPECT().SaveBalance(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			cache.EXPECT().GetBalance(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+			batchStore := NewMockBatchStore(mockCtrl)
+			batchStore.EXPECT().UpdateBatch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+			wallets := service.NewWalletService(memoryTxFactory{db}, cache, service.NewBalanceBroker())
+			batch := &entity.Batch{ID: 1, Mode: tt.mode, Items: tt.items(w1, w2)}
+
+			service.NewBatchService(wallets, batchStore, 4, time.Minute).ProcessBatch(ctx, batch)
+
+			assert.Equal(t, tt.wantStatus, batch.Status)
+			for i, want := range tt.wantItems {
+				assert.Equal(t, want, batch.Items[i].Status, "item %d", i)
+				if want == entity.BatchItemStatusFailed {
+					assert.NotEmpty(t, batch.Items[i].Error, "item %d", i)
+				}
+			}
+
+			b1, err := wallets.GetBalance(ctx, w1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBalance1, b1.Amount.AsInt())
+
+			b2, err := wallets.GetBalance(ctx, w2)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBalance2, b2.Amount.AsInt())
+		})
+	}
+}
+
+func TestBatchService_ProcessBatchItemErrors(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+
+	db := memory.NewWalletStoreTxFactory()
+	active := db.CreateWallet(money.NewFromInt(1000))
+	frozen := db.CreateWallet(money.NewFromInt(1000))
+
+	tx, err := db.NewTx(ctx)
+	require.NoError(t, err)
+	balance, err := tx.GetBalance(ctx, frozen)
+	require.NoError(t, err)
+	balance.Frozen = true
+	require.NoError(t, tx.SaveBalance(ctx, balance))
+	require.NoError(t, tx.Commit(ctx))
+
+	cache := NewMockWalletCacheStore(mockCtrl)
+	cache.EXPECT().SaveBalance(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	cache.EXPECT().GetBalance(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	batchStore := NewMockBatchStore(mockCtrl)
+	batchStore.EXPECT().UpdateBatch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	wallets := service.NewWalletService(memoryTxFactory{db}, cache, service.NewBalanceBroker())
+	batch := &entity.Batch{ID: 1, Mode: entity.BatchModeBestEffort, Items: []entity.BatchItem{
+		{WalletID: frozen, Operation: entity.BatchOperationCredit, Amount: money.NewFromInt(10)},
+		{WalletID: active, Operation: entity.BatchOperationCredit, Amount: money.NewFromInt(5000)},
+		{WalletID: -1, Operation: entity.BatchOperationCredit, Amount: money.NewFromInt(10)},
+		{WalletID: active, Operation: "transfer", Amount: money.NewFromInt(10)},
+	}}
+
+	service.NewBatchService(wallets, batchStore, 4, time.Minute).ProcessBatch(ctx, batch)
+
+	// Rejections are reported as they are, anything else is not exposed.
+	for i, want := range []string{"wallet is frozen", "insufficient funds", "wallet not found", "internal error"} {
+		assert.Equal(t, entity.BatchItemStatusFailed, batch.Items[i].Status, "item %d", i)
+		assert.Equal(t, want, batch.Items[i].Error, "item %d", i)
+	}
+}
+
+func TestBatchService_ProcessBatchTakenOver(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+
+	db := memory.NewWalletStoreTxFactory()
+	walletID := db.CreateWallet(money.NewFromInt(1000))
+
+	batchStore := NewMockBatchStore(mockCtrl)
+	batchStore.EXPECT().UpdateBatch(gomock.Any(), gomock.Any(), entity.BatchStatusPending).
+		Return(entity.ErrBatchStatusChanged)
+
+	wallets := service.NewWalletService(memoryTxFactory{db}, NewMockWalletCacheStore(mockCtrl), service.NewBalanceBroker())
+	batch := &entity.Batch{ID: 1, Mode: entity.BatchModeBestEffort, Status: entity.BatchStatusPending, Items: []entity.BatchItem{
+		{WalletID: walletID, Operation: entity.BatchOperationDebit, Amount: money.NewFromInt(100)},
+	}}
+
+	service.NewBatchService(wallets, batchStore, 4, time.Minute).ProcessBatch(ctx, batch)
+
+	// Another process runs the batch, so its items are not applied twice.
+	assert.Len(t, db.Entries(walletID), 0)
+}
+
+func TestBatchService_ProcessBatchHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+
+	db := memory.NewWalletStoreTxFactory()
+	walletID := db.CreateWallet(money.NewFromInt(1000))
+
+	// A slow cache keeps the batch running for several heartbeats.
+	cache := NewMockWalletCacheStore(mockCtrl)
+	cache.EXPECT().SaveBalance(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, *domain.WalletBalance) error {
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		}).AnyTimes()
+	cache.EXPECT().GetBalance(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	batchStore := NewMockBatchStore(mockCtrl)
+	gomock.InOrder(
+		batchStore.EXPECT().UpdateBatch(gomock.Any(), gomock.Any(), entity.BatchStatusPending).Return(nil),
+		batchStore.EXPECT().TouchBatch(gomock.Any(), 1).Return(nil).MinTimes(1),
+		batchStore.EXPECT().UpdateBatch(gomock.Any(), gomock.Any(), entity.BatchStatusRunning).Return(nil),
+	)
+
+	wallets := service.NewWalletService(memoryTxFactory{db}, cache, service.NewBalanceBroker())
+	batch := &entity.Batch{ID: 1, Mode: entity.BatchModeBestEffort, Status: entity.BatchStatusPending, Items: []entity.BatchItem{
+		{WalletID: walletID, Operation: entity.BatchOperationDebit, Amount: money.NewFromInt(100)},
+	}}
+
+	service.NewBatchService(wallets, batchStore, 4, 30*time.Millisecond).ProcessBatch(ctx, batch)
+
+	assert.Equal(t, entity.BatchStatusCompleted, batch.Status)
+}
+
+type memoryTxFactory struct {
+	factory *memory.WalletStoreTxFactory
+}
+
+func (f memoryTxFactory) NewTx(ctx context.Context) (service.WalletStoreTx, error) {
+	return f.factory.NewTx(ctx)
+}
+
+func TestBatchService_Start(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+
+	db := memory.NewWalletStoreTxFactory()
+	walletID := db.CreateWallet(money.NewFromInt(1000))
+
+	cache := NewMockWalletCacheStore(mockCtrl)
+	cache.EXPECT().SaveBalance(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	cache.EXPECT().GetBalance(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	var saved []entity.Batch
+	store := NewMockBatchStore(mockCtrl)
+	gomock.InOrder(
+		store.EXPECT().FailStaleBatches(gomock.Any(), 10*time.Minute, gomock.Any()).Return([]int{6}, nil),
+		store.EXPECT().ClaimStaleBatches(gomock.Any(), 10*time.Minute).Return([]int{7}, nil),
+		store.EXPECT().GetBatch(gomock.Any(), 7).Return(&entity.Batch{
+			ID:     7,
+			Mode:   entity.BatchModeBestEffort,
+			Status: entity.BatchStatusRunning,
+			Items: []entity.BatchItem{{
+				WalletID:  walletID,
+				Operation: entity.BatchOperationDebit,
+				Amount:    money.NewFromInt(100),
+				Status:    entity.BatchItemStatusPending,
+			}},
+		}, nil),
+	)
+	store.EXPECT().UpdateBatch(gomock.Any(), gomock.Any(), entity.BatchStatusRunning).
+		Do(func(_ context.Context, batch *entity.Batch, _ entity.BatchStatus) {
+			saved = append(saved, *batch)
+		}).Return(nil).Times(2)
+
+	wallets := service.NewWalletService(memoryTxFactory{db}, cache, service.NewBalanceBroker())
+	svc := service.NewBatchService(wallets, store, 4, 10*time.Minute)
+
+	require.NoError(t, svc.Start(ctx))
+	require.NoError(t, svc.Shutdown(ctx))
+
+	// Only the batch that was still pending is processed.
+	require.Len(t, saved, 2)
+	assert.Equal(t, 7, saved[1].ID)
+	assert.Equal(t, entity.BatchStatusCompleted, saved[1].Status)
+	assert.Equal(t, entity.BatchItemStatusSucceeded, saved[1].Items[0].Status)
+
+	balance, err := wallets.GetBalance(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, 1100, balance.Amount.AsInt())
+}
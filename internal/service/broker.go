@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+)
+
+// BalanceBroker fans out committed balance changes to in-process subscribers.
+// A slow subscriber only receives the latest balance, never a backlog, and
+// changes may arrive out of order, so subscribers should compare versions.
+type BalanceBroker struct {
+	mu   sync.Mutex
+	subs map[int]map[chan domain.WalletBalance]struct{}
+}
+
+func NewBalanceBroker() *BalanceBroker {
+	return &BalanceBroker{subs: make(map[int]map[chan domain.WalletBalance]struct{})}
+}
+
+func (b *BalanceBroker) NotifyBalance(_ context.Context, balance *domain.WalletBalance) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs[balance.WalletID] {
+		latest := *balance
+		select {
+		case pending := <-ch: // replace the balance nobody has read yet
+			if pending.Version > latest.Version {
+				latest = pending
+			}
+		default:
+		}
+		ch <- latest
+	}
+}
+
+// Subscribe returns a channel with balance changes of the wallet and a func
+// that must be called to release the subscription.
+func (b *BalanceBroker) Subscribe(walletID int) (<-chan domain.WalletBalance, func()) {
+	ch := make(chan domain.WalletBalance, 1)
+
+	b.mu.Lock()
+	if b.subs[walletID] == nil {
+		b.subs[walletID] = make(map[chan domain.WalletBalance]struct{})
+	}
+	b.subs[walletID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs[walletID], ch)
+			if len(b.subs[walletID]) == 0 {
+				delete(b.subs, walletID)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWalletService_ApplyDepositEvent(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+
+	db := memory.NewWalletStoreTxFactory()
+	walletID := db.CreateWallet(money.NewFromInt(0))
+
+	// Only the confirmation and the chargeback change the balance.
+	var cached []domain.WalletBalance
+	cache := NewMockWalletCacheStore(mockCtrl)
+	cache.EXPECT().SaveBalance(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, balance *domain.WalletBalance) { cached = append(cached, *balance) }).
+		Return(nil).Times(3)
+
+	wallets := service.NewWalletService(memoryTxFactory{db}, cache, service.NewBalanceBroker())
+
+	event := func(status domain.DepositStatus) domain.DepositEvent {
+		return domain.DepositEvent{
+			Provider:  "fake",
+			Reference: "dep-1",
+			WalletID:  walletID,
+			Amount:    money.NewFromInt(30000),
+			Status:    status,
+		}
+	}
+
+	// The provider sends the confirmation twice and the pending event late.
+	for _, status := range []domain.DepositStatus{
+		domain.DepositConfirmed,
+		domain.DepositConfirmed,
+		domain.DepositPending,
+	} {
+		deposit, err := wallets.ApplyDepositEvent(ctx, event(status))
+		require.NoError(t, err)
+		assert.Equal(t, domain.DepositConfirmed, deposit.Status)
+	}
+	require.Len(t, cached, 1)
+	assert.Equal(t, "30.000", cached[0].Amount.String())
+
+	_, err := wallets.CreditMoney(ctx, domain.CreditEntry{WalletID: walletID, Amount: money.NewFromInt(20000)})
+	require.NoError(t, err)
+
+	deposit, err := wallets.ApplyDepositEvent(ctx, event(domain.DepositChargedBack))
+	require.NoError(t, err)
+	assert.Equal(t, domain.DepositChargedBack, deposit.Status)
+	assert.NotZero(t, deposit.ReversalEntryID)
+
+	require.Len(t, cached, 3)
+	assert.Equal(t, "-20.000", cached[2].Amount.String())
+	assert.True(t, cached[2].Flagged)
+
+	entries := db.Entries(walletID)
+	require.Len(t, entries, 3, "the deposit, the stake and the reversal")
+	assert.Equal(t, 30000, entries[0].Debit.AsInt())
+	assert.Equal(t, 30000, entries[2].Credit.AsInt())
+	assert.Equal(t, domain.OperationChargeback, entries[2].Operation)
+	assert.Equal(t, entries[0].Reference, entries[2].Reference)
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=partition.go -destination=partition_mock_test.go -package=service_test
+
+type PartitionStore interface {
+	// AddPartition adds the partition of the ledger for the month that starts
+	// at month, unless it exists, and moves the entries of the month from the
+	// default partition into it. It returns whether it added the partition
+	// and how many entries it moved.
+	AddPartition(ctx context.Context, month time.Time) (bool, int64, error)
+	// CountDefaultEntries returns how many entries are in no month that has
+	// a partition.
+	CountDefaultEntries(ctx context.Context) (int64, error)
+	// ListPartitions returns the months of the partitions that are not
+	// archived, oldest first.
+	ListPartitions(ctx context.Context) ([]time.Time, error)
+	ExportPartition(ctx context.Context, month time.Time, w io.Writer) (int64, error)
+	ArchivePartition(ctx context.Context, month time.Time) error
+}
+
+// PartitionService keeps partitions of the ledger ahead of the entries that
+// go to them and archives the partitions of old months.
+type PartitionService struct {
+	store        PartitionStore
+	interval     time.Duration
+	ahead        int
+	archiveAfter int
+	archiveDir   string
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// NewPartitionService returns a service that maintains partitions every
+// interval. It keeps partitions for ahead months after the current one. If
+// archiveAfter is not zero, it archives the months that ended more than
+// archiveAfter months before the current one and exports them to archiveDir.
+func NewPartitionService(store PartitionStore, interval time.Duration, ahead, archiveAfter int, archiveDir string) *PartitionService {
+	return &PartitionService{
+		store:        store,
+		interval:     interval,
+		ahead:        ahead,
+		archiveAfter: archiveAfter,
+		archiveDir:   archiveDir,
+		cancel:       func() {},
+	}
+}
+
+// Start maintains partitions at once and then every interval until Shutdown.
+func (s *PartitionService) Start(context.Context) error {
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.run(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown cancels the background runs and waits for the current one to
+// stop.
+func (s *PartitionService) Shutdown(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *PartitionService) run(ctx context.Context) {
+	err := s.Maintain(ctx, time.Now())
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		log.Warn().Err(err).Msg("could not maintain entry partitions")
+	}
+}
+
+// Maintain adds the partitions of the month of now and of the months ahead
+// that are missing, and archives old months if archiving is on. It fails if
+// entries are left in the default partition, as they are in a month that is
+// archived or too far ahead, and need someone to look into them.
+func (s *PartitionService) Maintain(ctx context.Context, now time.Time) error {
+	current := monthOf(now)
+	for i := 0; i <= s.ahead; i++ {
+		month := current.AddDate(0, i, 0)
+		added, moved, err := s.store.AddPartition(ctx, month)
+		if err != nil {
+			return fmt.Errorf("add partition of %s: %w", month.Format("2006-01"), err)
+		}
+		if moved > 0 {
+			// The partition should have been there before the entries were.
+			log.Warn().
+				Str("month", month.Format("2006-01")).
+				Int64("entries", moved).
+				Msg("entries moved from the default partition")
+		}
+		if added {
+			log.Info().Str("month", month.Format("2006-01")).Msg("entry partition added")
+		}
+	}
+
+	if s.archiveAfter != 0 {
+		if _, err := s.Archive(ctx, current.AddDate(0, -s.archiveAfter, 0), now); err != nil {
+			return err
+		}
+	}
+
+	left, err := s.store.CountDefaultEntries(ctx)
+	if err != nil {
+		return fmt.Errorf("count default entries: %w", err)
+	}
+	if left > 0 {
+		return fmt.Errorf("%d entries are in the default partition, outside of the months partitions are added for", left)
+	}
+
+	return nil
+}
+
+// Archive archives every partition of a month before the month of before. It
+// exports each to a gzipped CSV file first, which is replaced if it exists,
+// so Archive can be run again after it failed. The month of now and later
+// ones cannot be archived. It returns the months it archived.
+func (s *PartitionService) Archive(ctx context.Context, before, now time.Time) ([]time.Time, error) {
+	before = monthOf(before)
+	if before.After(monthOf(now)) {
+		return nil, fmt.Errorf("cannot archive %s or later, it has not ended yet", monthOf(now).Format("2006-01"))
+	}
+	if s.archiveDir == "" {
+		return nil, errors.New("archive dir is not set")
+	}
+
+	months, err := s.store.ListPartitions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list partitions: %w", err)
+	}
+
+	var archived []time.Time
+	for _, month := range months {
+		if !month.Before(before) {
+			break
+		}
+
+		path, rows, err := s.export(ctx, month)
+		if err != nil {
+			return archived, fmt.Errorf("export %s: %w", month.Format("2006-01"), err)
+		}
+		if err := s.store.ArchivePartition(ctx, month); err != nil {
+			return archived, fmt.Errorf("archive %s: %w", month.Format("2006-01"), err)
+		}
+
+		log.Info().
+			Str("month", month.Format("2006-01")).
+			Str("path", path).
+			Int64("entries", rows).
+			Msg("entry partition archived")
+		archived = append(archived, month)
+	}
+
+	return archived, nil
+}
+
+// export writes the partition of the month to a temporary file that is only
+// renamed to its final name once it is complete.
+func (s *PartitionService) export(ctx context.Context, month time.Time) (string, int64, error) {
+	if err := os.MkdirAll(s.archiveDir, 0o755); err != nil {
+		return "", 0, fmt.Errorf("create dir: %w", err)
+	}
+
+	path := filepath.Join(s.archiveDir, "wallet_entry_"+month.Format("2006_01")+".csv.gz")
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return "", 0, fmt.Errorf("create file: %w", err)
+	}
+
+	zw := gzip.NewWriter(f)
+	rows, err := s.store.ExportPartition(ctx, month, zw)
+	if err = errors.Join(err, zw.Close(), f.Sync(), f.Close()); err != nil {
+		_ = os.Remove(f.Name())
+		return "", 0, err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		_ = os.Remove(f.Name())
+		return "", 0, fmt.Errorf("rename file: %w", err)
+	}
+
+	return path, rows, nil
+}
+
+// monthOf returns the start of the UTC month of t.
+func monthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
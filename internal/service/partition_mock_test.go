@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: partition.go
+//
+// Generated by this command:
+//
+//	mockgen -source=partition.go -destination=partition_mock_test.go -package=service_test
+//
+
+// Package service_test is a generated GoMock package.
+package service_test
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPartitionStore is a mock of PartitionStore interface.
+type MockPartitionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPartitionStoreMockRecorder
+}
+
+// MockPartitionStoreMockRecorder is the mock recorder for MockPartitionStore.
+type MockPartitionStoreMockRecorder struct {
+	mock *MockPartitionStore
+}
+
+// NewMockPartitionStore creates a new mock instance.
+func NewMockPartitionStore(ctrl *gomock.Controller) *MockPartitionStore {
+	mock := &MockPartitionStore{ctrl: ctrl}
+	mock.recorder = &MockPartitionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPartitionStore) EXPECT() *MockPartitionStoreMockRecorder {
+	return m.recorder
+}
+
+// AddPartition mocks base method.
+func (m *MockPartitionStore) AddPartition(ctx context.Context, month time.Time) (bool, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPartition", ctx, month)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AddPartition indicates an expected call of AddPartition.
+func (mr *MockPartitionStoreMockRecorder) AddPartition(ctx, month any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPartition", reflect.TypeOf((*MockPartitionStore)(nil).AddPartition), ctx, month)
+}
+
+// ArchivePartition mocks base method.
+func (m *MockPartitionStore) ArchivePartition(ctx context.Context, month time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchivePartition", ctx, month)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ArchivePartition indicates an expected call of ArchivePartition.
+func (mr *MockPartitionStoreMockRecorder) ArchivePartition(ctx, month any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchivePartition", reflect.TypeOf((*MockPartitionStore)(nil).ArchivePartition), ctx, month)
+}
+
+// CountDefaultEntries mocks base method.
+func (m *MockPartitionStore) CountDefaultEntries(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDefaultEntries", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDefaultEntries indicates an expected call of CountDefaultEntries.
+func (mr *MockPartitionStoreMockRecorder) CountDefaultEntries(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDefaultEntries", reflect.TypeOf((*MockPartitionStore)(nil).CountDefaultEntries), ctx)
+}
+
+// ExportPartition mocks base method.
+func (m *MockPartitionStore) ExportPartition(ctx context.Context, month time.Time, w io.Writer) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportPartition", ctx, month, w)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportPartition indicates an expected call of ExportPartition.
+func (mr *MockPartitionStoreMockRecorder) ExportPartition(ctx, month, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportPartition", reflect.TypeOf((*MockPartitionStore)(nil).ExportPartition), ctx, month, w)
+}
+
+// ListPartitions mocks base method.
+func (m *MockPartitionStore) ListPartitions(ctx context.Context) ([]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPartitions", ctx)
+	ret0, _ := ret[0].([]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPartitions indicates an expected call of ListPartitions.
+func (mr *MockPartitionStoreMockRecorder) ListPartitions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPartitions", reflect.TypeOf((*MockPartitionStore)(nil).ListPartitions), ctx)
+}
@@ -0,0 +1,142 @@
+package service_test
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPartitionService_Maintain(t *testing.T) {
+	month := func(y int, m time.Month) time.Time { return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC) }
+	now := time.Date(2024, 11, 20, 9, 0, 0, 0, time.UTC)
+
+	t.Run("adds partitions ahead", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		store := NewMockPartitionStore(mockCtrl)
+		gomock.InOrder(
+			store.EXPECT().AddPartition(gomock.Any(), month(2024, 11)).Return(false, int64(0), nil),
+			store.EXPECT().AddPartition(gomock.Any(), month(2024, 12)).Return(false, int64(0), nil),
+			store.EXPECT().AddPartition(gomock.Any(), month(2025, 1)).Return(true, int64(0), nil),
+			store.EXPECT().CountDefaultEntries(gomock.Any()).Return(int64(0), nil),
+		)
+
+		svc := service.NewPartitionService(store, time.Hour, 2, 0, "")
+		require.NoError(t, svc.Maintain(context.Background(), now))
+	})
+
+	t.Run("archives old months", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		store := NewMockPartitionStore(mockCtrl)
+		store.EXPECT().AddPartition(gomock.Any(), month(2024, 11)).Return(false, int64(0), nil)
+		store.EXPECT().ListPartitions(gomock.Any()).
+			Return([]time.Time{month(2024, 8), month(2024, 9), month(2024, 10), month(2024, 11)}, nil)
+		// September and October stay live besides November.
+		store.EXPECT().ExportPartition(gomock.Any(), month(2024, 8), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ time.Time, w io.Writer) (int64, error) {
+				_, err := io.WriteString(w, "id,wallet_id\n1,25\n")
+				return 1, err
+			})
+		store.EXPECT().ArchivePartition(gomock.Any(), month(2024, 8)).Return(nil)
+		store.EXPECT().CountDefaultEntries(gomock.Any()).Return(int64(0), nil)
+
+		dir := t.TempDir()
+		svc := service.NewPartitionService(store, time.Hour, 0, 2, dir)
+		require.NoError(t, svc.Maintain(context.Background(), now))
+
+		f, err := os.Open(filepath.Join(dir, "wallet_entry_2024_08.csv.gz"))
+		require.NoError(t, err)
+		defer f.Close()
+		zr, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		data, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Equal(t, "id,wallet_id\n1,25\n", string(data))
+	})
+
+	t.Run("adds partition of entries in the default partition", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		store := NewMockPartitionStore(mockCtrl)
+		store.EXPECT().AddPartition(gomock.Any(), month(2024, 11)).Return(true, int64(3), nil)
+		store.EXPECT().CountDefaultEntries(gomock.Any()).Return(int64(0), nil)
+
+		svc := service.NewPartitionService(store, time.Hour, 0, 0, "")
+		require.NoError(t, svc.Maintain(context.Background(), now))
+	})
+
+	t.Run("entries left in the default partition", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		store := NewMockPartitionStore(mockCtrl)
+		store.EXPECT().AddPartition(gomock.Any(), month(2024, 11)).Return(false, int64(0), nil)
+		store.EXPECT().CountDefaultEntries(gomock.Any()).Return(int64(2), nil)
+
+		svc := service.NewPartitionService(store, time.Hour, 0, 0, "")
+		err := svc.Maintain(context.Background(), now)
+		require.ErrorContains(t, err, "2 entries are in the default partition")
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		store := NewMockPartitionStore(mockCtrl)
+		store.EXPECT().AddPartition(gomock.Any(), month(2024, 11)).Return(false, int64(0), errors.New("boom"))
+
+		svc := service.NewPartitionService(store, time.Hour, 2, 0, "")
+		require.Error(t, svc.Maintain(context.Background(), now))
+	})
+}
+
+func TestPartitionService_Archive(t *testing.T) {
+	month := func(m time.Month) time.Time { return time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC) }
+	now := time.Date(2024, 11, 20, 9, 0, 0, 0, time.UTC)
+
+	t.Run("current month", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		store := NewMockPartitionStore(mockCtrl)
+
+		svc := service.NewPartitionService(store, time.Hour, 0, 0, t.TempDir())
+		_, err := svc.Archive(context.Background(), month(12), now)
+		require.Error(t, err)
+	})
+
+	t.Run("failed export", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		store := NewMockPartitionStore(mockCtrl)
+		store.EXPECT().ListPartitions(gomock.Any()).Return([]time.Time{month(8), month(9)}, nil)
+		store.EXPECT().ExportPartition(gomock.Any(), month(8), gomock.Any()).Return(int64(0), errors.New("boom"))
+
+		dir := t.TempDir()
+		svc := service.NewPartitionService(store, time.Hour, 0, 0, dir)
+		archived, err := svc.Archive(context.Background(), month(10), now)
+		require.Error(t, err)
+		assert.Empty(t, archived)
+
+		// Neither the partition is archived nor a partial file is left.
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+}
+
+func TestPartitionService_Shutdown(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	store := NewMockPartitionStore(mockCtrl)
+	store.EXPECT().AddPartition(gomock.Any(), gomock.Any()).Return(false, int64(0), nil).AnyTimes()
+	store.EXPECT().CountDefaultEntries(gomock.Any()).Return(int64(0), nil).AnyTimes()
+
+	svc := service.NewPartitionService(store, time.Millisecond, 0, 0, "")
+	require.NoError(t, svc.Start(context.Background()))
+	time.Sleep(5 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, svc.Shutdown(ctx))
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/rs/zerolog/log"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=report.go -destination=report_mock_test.go -package=service_test
+
+type ReportStore interface {
+	// RefreshStart returns the first day whose summary may still change, or
+	// false if the ledger is empty.
+	RefreshStart(ctx context.Context, delay time.Duration) (time.Time, bool, error)
+	// RefreshSummaries aggregates the days in [from, to) and replaces their
+	// summaries.
+	RefreshSummaries(ctx context.Context, from, to time.Time) error
+	ListSummaries(ctx context.Context, from, to time.Time) ([]entity.DailySummary, error)
+}
+
+const (
+	reportDay = 24 * time.Hour
+	// reportChunk is how many days are aggregated at a time, so that a
+	// backfill of the whole ledger is not one long statement.
+	reportChunk = 31
+	// maxReportDays is the longest range that can be listed or rebuilt at
+	// once.
+	maxReportDays = 366
+)
+
+// ReportService keeps daily summaries of the ledger up to date in the
+// background and serves finance reports from them.
+type ReportService struct {
+	store    ReportStore
+	interval time.Duration
+	delay    time.Duration
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewReportService returns a service that refreshes summaries every
+// interval. delay is how long after the end of a day its summary is final, so
+// that entries of txs still committing at midnight are not missed.
+func NewReportService(store ReportStore, interval, delay time.Duration) *ReportService {
+	return &ReportService{
+		store:    store,
+		interval: interval,
+		delay:    delay,
+		cancel:   func() {},
+	}
+}
+
+// Start refreshes summaries at once and then every interval until Shutdown.
+func (s *ReportService) Start(context.Context) error {
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.run(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown cancels the background runs and waits for the current one to
+// stop.
+func (s *ReportService) Shutdown(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *ReportService) run(ctx context.Context) {
+	days, err := s.Refresh(ctx, time.Now())
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		log.Warn().Err(err).Msg("could not refresh daily summaries")
+		return
+	}
+	log.Debug().Int("days", days).Msg("daily summaries refreshed")
+}
+
+// Refresh aggregates the days that may still change, from the first one that
+// is not final up to and including the day of now, and returns how many days
+// it aggregated. Days that are final are left alone; Rebuild redoes them.
+func (s *ReportService) Refresh(ctx context.Context, now time.Time) (int, error) {
+	from, ok, err := s.store.RefreshStart(ctx, s.delay)
+	if err != nil {
+		return 0, fmt.Errorf("refresh start: %w", err)
+	}
+	if !ok {
+		return 0, nil
+	}
+
+	return s.refresh(ctx, from, reportDayOf(now).Add(reportDay))
+}
+
+// Rebuild aggregates every day in [from, to) again, whether final or not, and
+// returns how many days it aggregated. It is safe to run any number of times.
+func (s *ReportService) Rebuild(ctx context.Context, from, to time.Time) (int, error) {
+	from, to, err := reportRange(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return s.refresh(ctx, from, to)
+}
+
+// ListSummaries returns the summaries of the days in [from, to), oldest
+// first. Days not aggregated yet are missing.
+func (s *ReportService) ListSummaries(ctx context.Context, from, to time.Time) ([]entity.DailySummary, error) {
+	from, to, err := reportRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.ListSummaries(ctx, from, to)
+}
+
+func (s *ReportService) refresh(ctx context.Context, from, to time.Time) (int, error) {
+	var days int
+	for chunkFrom := from; chunkFrom.Before(to); {
+		chunkTo := chunkFrom.AddDate(0, 0, reportChunk)
+		if chunkTo.After(to) {
+			chunkTo = to
+		}
+		if err := s.store.RefreshSummaries(ctx, chunkFrom, chunkTo); err != nil {
+			return days, fmt.Errorf("refresh summaries from %s: %w", chunkFrom.Format(time.DateOnly), err)
+		}
+		days += int(chunkTo.Sub(chunkFrom) / reportDay)
+		chunkFrom = chunkTo
+	}
+	return days, nil
+}
+
+// reportRange widens [from, to) to whole UTC days and checks it.
+func reportRange(from, to time.Time) (time.Time, time.Time, error) {
+	from = reportDayOf(from)
+	if dayOfTo := reportDayOf(to); dayOfTo.Before(to) {
+		to = dayOfTo.Add(reportDay)
+	} else {
+		to = dayOfTo
+	}
+
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: range must end after it starts", entity.ErrInvalidReportRange)
+	}
+	if to.Sub(from) > maxReportDays*reportDay {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: range must not be longer than %d days",
+			entity.ErrInvalidReportRange, maxReportDays)
+	}
+	return from, to, nil
+}
+
+func reportDayOf(t time.Time) time.Time {
+	return t.UTC().Truncate(reportDay)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: report.go
+//
+// Generated by this command:
+//
+//	mockgen -source=report.go -destination=report_mock_test.go -package=service_test
+//
+
+// Package service_test is a generated GoMock package.
+package service_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/pprishchepa/go-casino-example/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReportStore is a mock of ReportStore interface.
+type MockReportStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportStoreMockRecorder
+}
+
+// MockReportStoreMockRecorder is the mock recorder for MockReportStore.
+type MockReportStoreMockRecorder struct {
+	mock *MockReportStore
+}
+
+// NewMockReportStore creates a new mock instance.
+func NewMockReportStore(ctrl *gomock.Controller) *MockReportStore {
+	mock := &MockReportStore{ctrl: ctrl}
+	mock.recorder = &MockReportStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportStore) EXPECT() *MockReportStoreMockRecorder {
+	return m.recorder
+}
+
+// ListSummaries mocks base method.
+func (m *MockReportStore) ListSummaries(ctx context.Context, from, to time.Time) ([]entity.DailySummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSummaries", ctx, from, to)
+	ret0, _ := ret[0].([]entity.DailySummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSummaries indicates an expected call of ListSummaries.
+func (mr *MockReportStoreMockRecorder) ListSummaries(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSummaries", reflect.TypeOf((*MockReportStore)(nil).ListSummaries), ctx, from, to)
+}
+
+// RefreshStart mocks base method.
+func (m *MockReportStore) RefreshStart(ctx context.Context, delay time.Duration) (time.Time, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshStart", ctx, delay)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RefreshStart indicates an expected call of RefreshStart.
+func (mr *MockReportStoreMockRecorder) RefreshStart(ctx, delay any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshStart", reflect.TypeOf((*MockReportStore)(nil).RefreshStart), ctx, delay)
+}
+
+// RefreshSummaries mocks base method.
+func (m *MockReportStore) RefreshSummaries(ctx context.Context, from, to time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshSummaries", ctx, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshSummaries indicates an expected call of RefreshSummaries.
+func (mr *MockReportStoreMockRecorder) RefreshSummaries(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSummaries", reflect.TypeOf((*MockReportStore)(nil).RefreshSummaries), ctx, from, to)
+}
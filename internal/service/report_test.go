@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReportService_Refresh(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC) }
+	now := day(6, 20).Add(9 * time.Hour)
+
+	tests := []struct {
+		name      string
+		mockStore func(store *MockReportStore)
+		wantDays  int
+		wantErr   bool
+	}{
+		{
+			name: "from the first day that is not final",
+			mockStore: func(store *MockReportStore) {
+				store.EXPECT().RefreshStart(gomock.Any(), 15*time.Minute).Return(day(6, 19), true, nil)
+				store.EXPECT().RefreshSummaries(gomock.Any(), day(6, 19), day(6, 21)).Return(nil)
+			},
+			wantDays: 2,
+		},
+		{
+			name: "backfill in chunks",
+			mockStore: func(store *MockReportStore) {
+				store.EXPECT().RefreshStart(gomock.Any(), 15*time.Minute).Return(day(5, 1), true, nil)
+				gomock.InOrder(
+					store.EXPECT().RefreshSummaries(gomock.Any(), day(5, 1), day(6, 1)).Return(nil),
+					store.EXPECT().RefreshSummaries(gomock.Any(), day(6, 1), day(6, 21)).Return(nil),
+				)
+			},
+			wantDays: 51,
+		},
+		{
+			name: "empty ledger",
+			mockStore: func(store *MockReportStore) {
+				store.EXPECT().RefreshStart(gomock.Any(), 15*time.Minute).Return(time.Time{}, false, nil)
+			},
+		},
+		{
+			name: "store failure",
+			mockStore: func(store *MockReportStore) {
+				store.EXPECT().RefreshStart(gomock.Any(), 15*time.Minute).Return(day(6, 20), true, nil)
+				store.EXPECT().RefreshSummaries(gomock.Any(), day(6, 20), day(6, 21)).Return(errors.New("boom"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			store := NewMockReportStore(mockCtrl)
+			tt.mockStore(store)
+
+			days, err := service.NewReportService(store, time.Hour, 15*time.Minute).Refresh(context.Background(), now)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDays, days)
+		})
+	}
+}
+
+func TestReportService_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	store := NewMockReportStore(mockCtrl)
+	svc := service.NewReportService(store, time.Hour, 0)
+
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// The range is widened to whole days.
+	store.EXPECT().RefreshSummaries(gomock.Any(), from, from.AddDate(0, 0, 3)).Return(nil)
+	days, err := svc.Rebuild(ctx, from.Add(time.Hour), from.AddDate(0, 0, 2).Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 3, days)
+
+	_, err = svc.Rebuild(ctx, from, from)
+	require.ErrorIs(t, err, entity.ErrInvalidReportRange)
+
+	_, err = svc.Rebuild(ctx, from, from.AddDate(2, 0, 0))
+	require.ErrorIs(t, err, entity.ErrInvalidReportRange)
+}
+
+func TestReportService_ListSummaries(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	store := NewMockReportStore(mockCtrl)
+
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	want := []entity.DailySummary{{Day: from, Entries: 3}}
+	store.EXPECT().ListSummaries(gomock.Any(), from, from.AddDate(0, 0, 30)).Return(want, nil)
+
+	got, err := service.NewReportService(store, time.Hour, 0).ListSummaries(context.Background(), from, from.AddDate(0, 0, 30))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestReportService_Shutdown(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	store := NewMockReportStore(mockCtrl)
+	store.EXPECT().RefreshStart(gomock.Any(), gomock.Any()).Return(time.Time{}, false, nil).AnyTimes()
+
+	svc := service.NewReportService(store, time.Millisecond, 0)
+	require.NoError(t, svc.Start(context.Background()))
+	time.Sleep(5 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, svc.Shutdown(ctx))
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/rs/zerolog/log"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=seed.go -destination=seed_mock_test.go -package=service_test
+
+type WalletCreator interface {
+	// CreateWallet creates an empty wallet of the brand in the currency and
+	// returns its ID.
+	CreateWallet(ctx context.Context, brand, currency string) (int, error)
+}
+
+// currencyCode is an ISO 4217 code, like the wallet table requires.
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// SeedService creates wallets with money on them for tests and demos.
+type SeedService struct {
+	creator   WalletCreator
+	txFactory WalletStoreTxFactory
+}
+
+func NewSeedService(creator WalletCreator, txFactory WalletStoreTxFactory) *SeedService {
+	return &SeedService{creator: creator, txFactory: txFactory}
+}
+
+// Seed creates count wallets of the brand in the currency and deposits amount
+// to each. The money goes through the ledger like any deposit, so seeded
+// wallets reconcile. It returns the IDs of the wallets created, including
+// those before a failure.
+func (s *SeedService) Seed(ctx context.Context, count int, amount money.Money, brand, currency string) ([]int, error) {
+	if count <= 0 || amount.IsNegative() {
+		return nil, fmt.Errorf("invalid seed of %d wallets with %s", count, amount)
+	}
+	if brand == "" || !currencyCode.MatchString(currency) {
+		return nil, fmt.Errorf("invalid seed of wallets of brand %q in %q", brand, currency)
+	}
+
+	walletIDs := make([]int, 0, count)
+	for i := 0; i < count; i++ {
+		walletID, err := s.creator.CreateWallet(ctx, brand, currency)
+		if err != nil {
+			return walletIDs, fmt.Errorf("create wallet: %w", err)
+		}
+		walletIDs = append(walletIDs, walletID)
+
+		if amount.IsZero() {
+			continue
+		}
+		if err := s.deposit(ctx, walletID, amount); err != nil {
+			return walletIDs, fmt.Errorf("deposit to wallet %d: %w", walletID, err)
+		}
+	}
+
+	return walletIDs, nil
+}
+
+func (s *SeedService) deposit(ctx context.Context, walletID int, amount money.Money) error {
+	tx, err := s.txFactory.NewTx(ctx)
+	if err != nil {
+		return fmt.Errorf("new tx: %w", err)
+	}
+
+	err = domain.NewWalletUseCases(tx).DebitMoney(ctx, &domain.DebitEntry{
+		WalletID: walletID,
+		Amount:   amount,
+		EntryDetails: domain.EntryDetails{
+			Operation: domain.OperationDeposit,
+			Reference: "seed",
+			Initiator: domain.InitiatorService,
+		},
+	})
+	if err != nil {
+		if err := tx.Rollback(ctx); err != nil {
+			log.Warn().Err(err).Msg("could not rollback tx")
+		}
+		return fmt.Errorf("debit money: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
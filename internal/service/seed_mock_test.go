@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: seed.go
+//
+// Generated by this command:
+//
+//	mockgen -source=seed.go -destination=seed_mock_test.go -package=service_test
+//
+
+// Package service_test is a generated GoMock package.
+package service_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWalletCreator is a mock of WalletCreator interface.
+type MockWalletCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletCreatorMockRecorder
+}
+
+// MockWalletCreatorMockRecorder is the mock recorder for MockWalletCreator.
+type MockWalletCreatorMockRecorder struct {
+	mock *MockWalletCreator
+}
+
+// NewMockWalletCreator creates a new mock instance.
+func NewMockWalletCreator(ctrl *gomock.Controller) *MockWalletCreator {
+	mock := &MockWalletCreator{ctrl: ctrl}
+	mock.recorder = &MockWalletCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletCreator) EXPECT() *MockWalletCreatorMockRecorder {
+	return m.recorder
+}
+
+// CreateWallet mocks base method.
+func (m *MockWalletCreator) CreateWallet(ctx context.Context, brand, currency string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWallet", ctx, brand, currency)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWallet indicates an expected call of CreateWallet.
+func (mr *MockWalletCreatorMockRecorder) CreateWallet(ctx, brand, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWallet", reflect.TypeOf((*MockWalletCreator)(nil).CreateWallet), ctx, brand, currency)
+}
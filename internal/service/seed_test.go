@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSeedService_Seed(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("seeded wallets reconcile", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		db := memory.NewWalletStoreTxFactory()
+		creator := NewMockWalletCreator(mockCtrl)
+		creator.EXPECT().CreateWallet(gomock.Any(), "default", "EUR").
+			DoAndReturn(func(context.Context, string, string) (int, error) {
+				return db.CreateWallet(money.NewFromInt(0)), nil
+			}).Times(3)
+
+		walletIDs, err := service.NewSeedService(creator, memoryTxFactory{db}).Seed(ctx, 3, money.NewFromInt(500), "default", "EUR")
+		require.NoError(t, err)
+		require.Len(t, walletIDs, 3)
+
+		got, err := service.NewAuditService(memoryTxFactory{db}).Reconcile(ctx)
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		for _, r := range got {
+			assert.Equal(t, "0.500", r.Balance.Amount.String())
+			assert.True(t, r.Consistent(), "wallet %d", r.WalletID)
+		}
+	})
+
+	t.Run("failed creation", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		db := memory.NewWalletStoreTxFactory()
+		creator := NewMockWalletCreator(mockCtrl)
+		gomock.InOrder(
+			creator.EXPECT().CreateWallet(gomock.Any(), "default", "EUR").Return(db.CreateWallet(money.NewFromInt(0)), nil),
+			creator.EXPECT().CreateWallet(gomock.Any(), "default", "EUR").Return(0, errors.New("boom")),
+		)
+
+		walletIDs, err := service.NewSeedService(creator, memoryTxFactory{db}).Seed(ctx, 3, money.NewFromInt(0), "default", "EUR")
+		require.Error(t, err)
+		assert.Len(t, walletIDs, 1)
+	})
+
+	t.Run("invalid count", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		creator := NewMockWalletCreator(mockCtrl)
+
+		_, err := service.NewSeedService(creator, nil).Seed(ctx, 0, money.NewFromInt(0), "default", "EUR")
+		require.Error(t, err)
+	})
+
+	t.Run("invalid currency", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		creator := NewMockWalletCreator(mockCtrl)
+
+		_, err := service.NewSeedService(creator, nil).Seed(ctx, 1, money.NewFromInt(0), "default", "eur")
+		require.Error(t, err)
+	})
+}
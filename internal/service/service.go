@@ -9,6 +9,7 @@ import (
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
 	"github.com/pprishchepa/go-casino-example/internal/entity"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/singleflight"
@@ -19,6 +20,11 @@ import (
 type (
 	WalletStoreTx interface {
 		domain.WalletStore
+		// ListWalletIDs returns IDs of all wallets in ascending order.
+		ListWalletIDs(ctx context.Context) ([]int, error)
+		// SearchWallets returns balances of wallets that match the filter in
+		// ascending order of IDs.
+		SearchWallets(ctx context.Context, filter domain.WalletFilter) ([]domain.WalletBalance, error)
 		Commit(ctx context.Context) error
 		Rollback(ctx context.Context) error
 	}
@@ -28,19 +34,32 @@ type (
 	WalletCacheStore interface {
 		SaveBalance(ctx context.Context, balance *domain.WalletBalance) error
 		GetBalance(ctx context.Context, walletID int) (*domain.WalletBalance, error)
+		DeleteBalance(ctx context.Context, walletID int) error
+	}
+	BalanceNotifier interface {
+		NotifyBalance(ctx context.Context, balance *domain.WalletBalance)
 	}
 )
 
 type WalletService struct {
-	txFactory WalletStoreTxFactory
-	cache     WalletCacheStore
-	group     singleflight.Group
+	txFactory   WalletStoreTxFactory
+	cache       WalletCacheStore
+	notifier    BalanceNotifier
+	usecaseOpts []domain.WalletUseCasesOption
+	group       singleflight.Group
 }
 
-func NewWalletService(txFactory WalletStoreTxFactory, cache WalletCacheStore) *WalletService {
+func NewWalletService(
+	txFactory WalletStoreTxFactory,
+	cache WalletCacheStore,
+	notifier BalanceNotifier,
+	usecaseOpts ...domain.WalletUseCasesOption,
+) *WalletService {
 	return &WalletService{
-		txFactory: txFactory,
-		cache:     cache,
+		txFactory:   txFactory,
+		cache:       cache,
+		notifier:    notifier,
+		usecaseOpts: usecaseOpts,
 	}
 }
 
@@ -55,7 +74,7 @@ func (s *WalletService) GetBalance(ctx context.Context, walletID int) (*domain.W
 
 	v, err, _ := s.group.Do(strconv.Itoa(walletID), func() (interface{}, error) {
 		err := s.runOnceTx(ctx, func(tx WalletStoreTx) error {
-			balance, err = domain.NewWalletUseCases(tx).RetrieveBalance(ctx, walletID)
+			balance, err = s.usecases(tx).RetrieveBalance(ctx, walletID)
 			return err
 		})
 		if err != nil {
@@ -73,54 +92,462 @@ func (s *WalletService) GetBalance(ctx context.Context, walletID int) (*domain.W
 	return v.(*domain.WalletBalance), nil
 }
 
-func (s *WalletService) DebitMoney(ctx context.Context, entry domain.DebitEntry) error {
+func (s *WalletService) DebitMoney(ctx context.Context, entry domain.DebitEntry) (*entity.Operation, error) {
 	var balance *domain.WalletBalance
 
 	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
-		usecase := domain.NewWalletUseCases(tx)
-		if err := usecase.DebitMoney(ctx, entry); err != nil {
+		// Each attempt starts from the entry as passed by the caller.
+		attempt := entry
+		usecase := s.usecases(tx)
+		if err := usecase.DebitMoney(ctx, &attempt); err != nil {
 			return fmt.Errorf("debit money: %w", err)
 		}
 		var err error
 		if balance, err = usecase.RetrieveBalance(ctx, entry.WalletID); err != nil {
 			return fmt.Errorf("retrieve balance: %w", err)
 		}
+		entry = attempt
 		return nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := s.cache.SaveBalance(ctx, balance); err != nil {
-		log.Warn().Err(err).Int("walletId", entry.WalletID).Msg("could not update balance in cache")
-	}
+	s.balanceChanged(ctx, balance)
 
-	return nil
+	return &entity.Operation{
+		EntryID:   entry.ID,
+		Bonus:     entry.Bonus,
+		Balance:   *balance,
+		CreatedAt: entry.CreatedAt,
+	}, nil
 }
 
-func (s *WalletService) CreditMoney(ctx context.Context, entry domain.CreditEntry) error {
+func (s *WalletService) CreditMoney(ctx context.Context, entry domain.CreditEntry) (*entity.Operation, error) {
 	var balance *domain.WalletBalance
 
 	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
-		usecase := domain.NewWalletUseCases(tx)
-		if err := usecase.CreditMoney(ctx, entry); err != nil {
+		// Each attempt starts from the entry as passed by the caller.
+		attempt := entry
+		usecase := s.usecases(tx)
+		if err := usecase.CreditMoney(ctx, &attempt); err != nil {
 			return fmt.Errorf("credit money: %w", err)
 		}
 		var err error
 		if balance, err = usecase.RetrieveBalance(ctx, entry.WalletID); err != nil {
 			return fmt.Errorf("retrieve balance: %w", err)
 		}
+		entry = attempt
 		return nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := s.cache.SaveBalance(ctx, balance); err != nil {
-		log.Warn().Err(err).Int("walletId", entry.WalletID).Msg("could not update balance in cache")
+	s.balanceChanged(ctx, balance)
+
+	return &entity.Operation{
+		EntryID:   entry.ID,
+		Bonus:     entry.Bonus,
+		Balance:   *balance,
+		CreatedAt: entry.CreatedAt,
+	}, nil
+}
+
+func (s *WalletService) GrantBonus(ctx context.Context, grant domain.BonusGrant) (*domain.BonusGrant, error) {
+	var balance *domain.WalletBalance
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		attempt := grant
+		usecase := s.usecases(tx)
+		if err := usecase.GrantBonus(ctx, &attempt); err != nil {
+			return fmt.Errorf("grant bonus: %w", err)
+		}
+		var err error
+		if balance, err = usecase.RetrieveBalance(ctx, grant.WalletID); err != nil {
+			return fmt.Errorf("retrieve balance: %w", err)
+		}
+		grant = attempt
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	s.balanceChanged(ctx, balance)
+
+	return &grant, nil
+}
+
+// ListBonuses expires overdue bonuses of the wallet and returns all of its
+// bonuses.
+func (s *WalletService) ListBonuses(ctx context.Context, walletID int) ([]domain.BonusGrant, error) {
+	var (
+		grants  []domain.BonusGrant
+		before  *domain.WalletBalance
+		balance *domain.WalletBalance
+	)
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		usecase := s.usecases(tx)
+		var err error
+		if before, err = usecase.RetrieveBalance(ctx, walletID); err != nil {
+			return fmt.Errorf("retrieve balance: %w", err)
+		}
+		if err = usecase.ExpireBonuses(ctx, walletID); err != nil {
+			return fmt.Errorf("expire bonuses: %w", err)
+		}
+		if balance, err = usecase.RetrieveBalance(ctx, walletID); err != nil {
+			return fmt.Errorf("retrieve balance: %w", err)
+		}
+		if grants, err = usecase.ListBonuses(ctx, walletID); err != nil {
+			return fmt.Errorf("list bonuses: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if balance.Version != before.Version {
+		s.balanceChanged(ctx, balance)
+	}
+
+	return grants, nil
+}
+
+func (s *WalletService) ListLimits(ctx context.Context, walletID int) ([]domain.LimitUsage, error) {
+	var limits []domain.LimitUsage
+
+	err := s.runOnceTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		if limits, err = s.usecases(tx).ListLimits(ctx, walletID); err != nil {
+			return fmt.Errorf("list limits: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+// SetLimit sets a limit, or removes it if amount is nil.
+func (s *WalletService) SetLimit(
+	ctx context.Context,
+	walletID int,
+	kind domain.LimitKind,
+	period domain.LimitPeriod,
+	amount *money.Money,
+) (*domain.Limit, error) {
+	var limit *domain.Limit
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		if limit, err = s.usecases(tx).SetLimit(ctx, walletID, kind, period, amount); err != nil {
+			return fmt.Errorf("set limit: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return limit, nil
+}
+
+// GetStatus returns the balance of the wallet and its exclusion, if any. The
+// balance is read from the store rather than the cache, so that both are
+// consistent.
+func (s *WalletService) GetStatus(ctx context.Context, walletID int) (*domain.WalletStatus, error) {
+	var status *domain.WalletStatus
+
+	err := s.runOnceTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		if status, err = s.usecases(tx).RetrieveStatus(ctx, walletID); err != nil {
+			return fmt.Errorf("retrieve status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func (s *WalletService) SelfExclude(ctx context.Context, walletID int, period domain.ExclusionPeriod) (*domain.Exclusion, error) {
+	var exclusion *domain.Exclusion
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		if exclusion, err = s.usecases(tx).SelfExclude(ctx, walletID, period); err != nil {
+			return fmt.Errorf("self-exclude: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return exclusion, nil
+}
+
+// LiftExclusion ends the exclusion of the wallet on behalf of actor.
+func (s *WalletService) LiftExclusion(ctx context.Context, walletID int, actor, reason string) (*domain.Exclusion, error) {
+	var exclusion *domain.Exclusion
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		if exclusion, err = s.usecases(tx).LiftExclusion(ctx, walletID, actor, reason); err != nil {
+			return fmt.Errorf("lift exclusion: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Int("walletId", walletID).
+		Int("exclusionId", exclusion.ID).
+		Str("actor", actor).
+		Str("reason", reason).
+		Msg("exclusion lifted")
+
+	return exclusion, nil
+}
+
+// ListEntries returns entries of the wallet that match the filter, oldest
+// first.
+func (s *WalletService) ListEntries(ctx context.Context, filter domain.EntryFilter) ([]domain.Entry, error) {
+	var entries []domain.Entry
+
+	err := s.runOnceTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		if entries, err = s.usecases(tx).ListEntries(ctx, filter); err != nil {
+			return fmt.Errorf("list entries: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ReverseEntry undoes an entry of the wallet on behalf of actor and returns
+// the compensating entry.
+func (s *WalletService) ReverseEntry(ctx context.Context, walletID, entryID int, actor, reason string) (*domain.Entry, error) {
+	var (
+		reversal *domain.Entry
+		balance  *domain.WalletBalance
+	)
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		usecase := s.usecases(tx)
+		var err error
+		if reversal, err = usecase.ReverseEntry(ctx, walletID, entryID, actor, reason); err != nil {
+			return fmt.Errorf("reverse entry: %w", err)
+		}
+		if balance, err = usecase.RetrieveBalance(ctx, walletID); err != nil {
+			return fmt.Errorf("retrieve balance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.balanceChanged(ctx, balance)
+
+	log.Info().
+		Int("walletId", walletID).
+		Int("entryId", entryID).
+		Int("reversalId", reversal.ID).
+		Str("actor", actor).
+		Str("reason", reason).
+		Msg("entry reversed")
+
+	return reversal, nil
+}
+
+// RequestWithdrawal holds the amount of the withdrawal and puts it up for
+// review on behalf of actor.
+func (s *WalletService) RequestWithdrawal(ctx context.Context, withdrawal domain.Withdrawal, actor string) (*domain.Withdrawal, error) {
+	var balance *domain.WalletBalance
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		attempt := withdrawal
+		usecase := s.usecases(tx)
+		var err error
+		if _, err = usecase.RequestWithdrawal(ctx, &attempt, actor); err != nil {
+			return fmt.Errorf("request withdrawal: %w", err)
+		}
+		if balance, err = usecase.RetrieveBalance(ctx, withdrawal.WalletID); err != nil {
+			return fmt.Errorf("retrieve balance: %w", err)
+		}
+		withdrawal = attempt
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.balanceChanged(ctx, balance)
+
+	return &withdrawal, nil
+}
+
+// TransitionWithdrawal changes the status of a withdrawal on behalf of actor.
+func (s *WalletService) TransitionWithdrawal(
+	ctx context.Context,
+	walletID, withdrawalID int,
+	to domain.WithdrawalStatus,
+	initiator domain.Initiator,
+	actor, reason string,
+) (*domain.Withdrawal, error) {
+	var (
+		withdrawal *domain.Withdrawal
+		before     *domain.WalletBalance
+		balance    *domain.WalletBalance
+	)
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		usecase := s.usecases(tx)
+		var err error
+		if before, err = usecase.RetrieveBalance(ctx, walletID); err != nil {
+			return fmt.Errorf("retrieve balance: %w", err)
+		}
+		withdrawal, _, err = usecase.TransitionWithdrawal(ctx, walletID, withdrawalID, to, initiator, actor, reason)
+		if err != nil {
+			return fmt.Errorf("transition withdrawal: %w", err)
+		}
+		if balance, err = usecase.RetrieveBalance(ctx, walletID); err != nil {
+			return fmt.Errorf("retrieve balance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if balance.Version != before.Version {
+		s.balanceChanged(ctx, balance)
+	}
+
+	return withdrawal, nil
+}
+
+func (s *WalletService) ListWithdrawals(ctx context.Context, walletID int) ([]domain.Withdrawal, error) {
+	var withdrawals []domain.Withdrawal
+
+	err := s.runOnceTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		if withdrawals, err = s.usecases(tx).ListWithdrawals(ctx, walletID); err != nil {
+			return fmt.Errorf("list withdrawals: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return withdrawals, nil
+}
+
+// GetWithdrawal returns a withdrawal of the wallet together with its
+// transitions.
+func (s *WalletService) GetWithdrawal(
+	ctx context.Context,
+	walletID, withdrawalID int,
+) (*domain.Withdrawal, []domain.WithdrawalTransition, error) {
+	var (
+		withdrawal *domain.Withdrawal
+		history    []domain.WithdrawalTransition
+	)
+
+	err := s.runOnceTx(ctx, func(tx WalletStoreTx) error {
+		usecase := s.usecases(tx)
+		var err error
+		if withdrawal, err = usecase.GetWithdrawal(ctx, walletID, withdrawalID); err != nil {
+			return fmt.Errorf("get withdrawal: %w", err)
+		}
+		if history, err = usecase.WithdrawalHistory(ctx, walletID, withdrawalID); err != nil {
+			return fmt.Errorf("withdrawal history: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return withdrawal, history, nil
+}
+
+// ApplyDepositEvent applies a payment provider callback. Duplicate and stale
+// callbacks return the deposit unchanged.
+func (s *WalletService) ApplyDepositEvent(ctx context.Context, event domain.DepositEvent) (*domain.Deposit, error) {
+	var (
+		deposit *domain.Deposit
+		changed bool
+		before  *domain.WalletBalance
+		balance *domain.WalletBalance
+	)
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		usecase := s.usecases(tx)
+		var err error
+		if before, err = usecase.RetrieveBalance(ctx, event.WalletID); err != nil {
+			return fmt.Errorf("retrieve balance: %w", err)
+		}
+		if deposit, changed, err = usecase.ApplyDepositEvent(ctx, event); err != nil {
+			return fmt.Errorf("apply deposit event: %w", err)
+		}
+		if balance, err = usecase.RetrieveBalance(ctx, event.WalletID); err != nil {
+			return fmt.Errorf("retrieve balance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !changed {
+		log.Debug().
+			Str("provider", event.Provider).
+			Str("reference", event.Reference).
+			Str("status", string(event.Status)).
+			Msg("deposit event ignored")
+		return deposit, nil
+	}
+
+	if balance.Version != before.Version {
+		s.balanceChanged(ctx, balance)
+	}
+	if balance.Flagged && !before.Flagged {
+		log.Warn().
+			Int("walletId", balance.WalletID).
+			Int("depositId", deposit.ID).
+			Str("amount", balance.Amount.String()).
+			Msg("wallet flagged for a negative balance")
+	}
+
+	return deposit, nil
+}
+
+func (s *WalletService) usecases(tx WalletStoreTx) *domain.WalletUseCases {
+	return domain.NewWalletUseCases(tx, s.usecaseOpts...)
+}
+
+// balanceChanged propagates a committed balance to the cache and subscribers.
+func (s *WalletService) balanceChanged(ctx context.Context, balance *domain.WalletBalance) {
+	if err := s.cache.SaveBalance(ctx, balance); err != nil {
+		log.Warn().Err(err).Int("walletId", balance.WalletID).Msg("could not update balance in cache")
+	}
+	s.notifier.NotifyBalance(ctx, balance)
 }
 
 func (s *WalletService) runOrRepeatTx(ctx context.Context, fn func(tx WalletStoreTx) error) error {
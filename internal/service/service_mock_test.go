@@ -12,6 +12,7 @@ package service_test
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/pprishchepa/go-casino-example/domain"
 	service "github.com/pprishchepa/go-casino-example/internal/service"
@@ -41,8 +42,50 @@ func (m *MockWalletStoreTx) EXPECT() *MockWalletStoreTxMockRecorder {
 	return m.recorder
 }
 
+// AddActivity mocks base method.
+func (m *MockWalletStoreTx) AddActivity(ctx context.Context, walletID int, at time.Time, activity domain.Activity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddActivity", ctx, walletID, at, activity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddActivity indicates an expected call of AddActivity.
+func (mr *MockWalletStoreTxMockRecorder) AddActivity(ctx, walletID, at, activity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActivity", reflect.TypeOf((*MockWalletStoreTx)(nil).AddActivity), ctx, walletID, at, activity)
+}
+
+// AddAdminAction mocks base method.
+func (m *MockWalletStoreTx) AddAdminAction(ctx context.Context, action *domain.AdminAction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAdminAction", ctx, action)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAdminAction indicates an expected call of AddAdminAction.
+func (mr *MockWalletStoreTxMockRecorder) AddAdminAction(ctx, action any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdminAction", reflect.TypeOf((*MockWalletStoreTx)(nil).AddAdminAction), ctx, action)
+}
+
+// AddBonusGrant mocks base method.
+func (m *MockWalletStoreTx) AddBonusGrant(ctx context.Context, grant *domain.BonusGrant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBonusGrant", ctx, grant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBonusGrant indicates an expected call of AddBonusGrant.
+func (mr *MockWalletStoreTxMockRecorder) AddBonusGrant(ctx, grant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBonusGrant", reflect.TypeOf((*MockWalletStoreTx)(nil).AddBonusGrant), ctx, grant)
+}
+
 // AddCreditEntry mocks base method.
-func (m *MockWalletStoreTx) AddCreditEntry(ctx context.Context, entry domain.CreditEntry) error {
+func (m *MockWalletStoreTx) AddCreditEntry(ctx context.Context, entry *domain.CreditEntry) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddCreditEntry", ctx, entry)
 	ret0, _ := ret[0].(error)
@@ -56,7 +99,7 @@ func (mr *MockWalletStoreTxMockRecorder) AddCreditEntry(ctx, entry any) *gomock.
 }
 
 // AddDebitEntry mocks base method.
-func (m *MockWalletStoreTx) AddDebitEntry(ctx context.Context, entry domain.DebitEntry) error {
+func (m *MockWalletStoreTx) AddDebitEntry(ctx context.Context, entry *domain.DebitEntry) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddDebitEntry", ctx, entry)
 	ret0, _ := ret[0].(error)
@@ -69,6 +112,76 @@ func (mr *MockWalletStoreTxMockRecorder) AddDebitEntry(ctx, entry any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDebitEntry", reflect.TypeOf((*MockWalletStoreTx)(nil).AddDebitEntry), ctx, entry)
 }
 
+// AddDeposit mocks base method.
+func (m *MockWalletStoreTx) AddDeposit(ctx context.Context, deposit *domain.Deposit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeposit", ctx, deposit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDeposit indicates an expected call of AddDeposit.
+func (mr *MockWalletStoreTxMockRecorder) AddDeposit(ctx, deposit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeposit", reflect.TypeOf((*MockWalletStoreTx)(nil).AddDeposit), ctx, deposit)
+}
+
+// AddExclusion mocks base method.
+func (m *MockWalletStoreTx) AddExclusion(ctx context.Context, exclusion *domain.Exclusion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddExclusion", ctx, exclusion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddExclusion indicates an expected call of AddExclusion.
+func (mr *MockWalletStoreTxMockRecorder) AddExclusion(ctx, exclusion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddExclusion", reflect.TypeOf((*MockWalletStoreTx)(nil).AddExclusion), ctx, exclusion)
+}
+
+// AddSnapshot mocks base method.
+func (m *MockWalletStoreTx) AddSnapshot(ctx context.Context, snapshot *domain.BalanceSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSnapshot", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSnapshot indicates an expected call of AddSnapshot.
+func (mr *MockWalletStoreTxMockRecorder) AddSnapshot(ctx, snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSnapshot", reflect.TypeOf((*MockWalletStoreTx)(nil).AddSnapshot), ctx, snapshot)
+}
+
+// AddWithdrawal mocks base method.
+func (m *MockWalletStoreTx) AddWithdrawal(ctx context.Context, withdrawal *domain.Withdrawal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWithdrawal", ctx, withdrawal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWithdrawal indicates an expected call of AddWithdrawal.
+func (mr *MockWalletStoreTxMockRecorder) AddWithdrawal(ctx, withdrawal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWithdrawal", reflect.TypeOf((*MockWalletStoreTx)(nil).AddWithdrawal), ctx, withdrawal)
+}
+
+// AddWithdrawalTransition mocks base method.
+func (m *MockWalletStoreTx) AddWithdrawalTransition(ctx context.Context, transition *domain.WithdrawalTransition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWithdrawalTransition", ctx, transition)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWithdrawalTransition indicates an expected call of AddWithdrawalTransition.
+func (mr *MockWalletStoreTxMockRecorder) AddWithdrawalTransition(ctx, transition any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWithdrawalTransition", reflect.TypeOf((*MockWalletStoreTx)(nil).AddWithdrawalTransition), ctx, transition)
+}
+
 // Commit mocks base method.
 func (m *MockWalletStoreTx) Commit(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -83,6 +196,21 @@ func (mr *MockWalletStoreTxMockRecorder) Commit(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockWalletStoreTx)(nil).Commit), ctx)
 }
 
+// GetActivity mocks base method.
+func (m *MockWalletStoreTx) GetActivity(ctx context.Context, walletID int, since time.Time) (domain.Activity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivity", ctx, walletID, since)
+	ret0, _ := ret[0].(domain.Activity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivity indicates an expected call of GetActivity.
+func (mr *MockWalletStoreTxMockRecorder) GetActivity(ctx, walletID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivity", reflect.TypeOf((*MockWalletStoreTx)(nil).GetActivity), ctx, walletID, since)
+}
+
 // GetBalance mocks base method.
 func (m *MockWalletStoreTx) GetBalance(ctx context.Context, walletID int) (*domain.WalletBalance, error) {
 	m.ctrl.T.Helper()
@@ -98,6 +226,201 @@ func (mr *MockWalletStoreTxMockRecorder) GetBalance(ctx, walletID any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockWalletStoreTx)(nil).GetBalance), ctx, walletID)
 }
 
+// GetDeposit mocks base method.
+func (m *MockWalletStoreTx) GetDeposit(ctx context.Context, provider, reference string) (*domain.Deposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeposit", ctx, provider, reference)
+	ret0, _ := ret[0].(*domain.Deposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeposit indicates an expected call of GetDeposit.
+func (mr *MockWalletStoreTxMockRecorder) GetDeposit(ctx, provider, reference any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeposit", reflect.TypeOf((*MockWalletStoreTx)(nil).GetDeposit), ctx, provider, reference)
+}
+
+// GetEntry mocks base method.
+func (m *MockWalletStoreTx) GetEntry(ctx context.Context, entryID int) (*domain.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", ctx, entryID)
+	ret0, _ := ret[0].(*domain.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockWalletStoreTxMockRecorder) GetEntry(ctx, entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockWalletStoreTx)(nil).GetEntry), ctx, entryID)
+}
+
+// GetLimits mocks base method.
+func (m *MockWalletStoreTx) GetLimits(ctx context.Context, walletID int) ([]domain.Limit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimits", ctx, walletID)
+	ret0, _ := ret[0].([]domain.Limit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimits indicates an expected call of GetLimits.
+func (mr *MockWalletStoreTxMockRecorder) GetLimits(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimits", reflect.TypeOf((*MockWalletStoreTx)(nil).GetLimits), ctx, walletID)
+}
+
+// GetSnapshot mocks base method.
+func (m *MockWalletStoreTx) GetSnapshot(ctx context.Context, walletID int, at time.Time) (*domain.BalanceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSnapshot", ctx, walletID, at)
+	ret0, _ := ret[0].(*domain.BalanceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSnapshot indicates an expected call of GetSnapshot.
+func (mr *MockWalletStoreTxMockRecorder) GetSnapshot(ctx, walletID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSnapshot", reflect.TypeOf((*MockWalletStoreTx)(nil).GetSnapshot), ctx, walletID, at)
+}
+
+// GetWithdrawal mocks base method.
+func (m *MockWalletStoreTx) GetWithdrawal(ctx context.Context, withdrawalID int) (*domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithdrawal", ctx, withdrawalID)
+	ret0, _ := ret[0].(*domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWithdrawal indicates an expected call of GetWithdrawal.
+func (mr *MockWalletStoreTxMockRecorder) GetWithdrawal(ctx, withdrawalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawal", reflect.TypeOf((*MockWalletStoreTx)(nil).GetWithdrawal), ctx, withdrawalID)
+}
+
+// ListAdminActions mocks base method.
+func (m *MockWalletStoreTx) ListAdminActions(ctx context.Context, filter domain.AdminActionFilter) ([]domain.AdminAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAdminActions", ctx, filter)
+	ret0, _ := ret[0].([]domain.AdminAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAdminActions indicates an expected call of ListAdminActions.
+func (mr *MockWalletStoreTxMockRecorder) ListAdminActions(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAdminActions", reflect.TypeOf((*MockWalletStoreTx)(nil).ListAdminActions), ctx, filter)
+}
+
+// ListAuditRecords mocks base method.
+func (m *MockWalletStoreTx) ListAuditRecords(ctx context.Context, walletID int) ([]domain.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditRecords", ctx, walletID)
+	ret0, _ := ret[0].([]domain.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditRecords indicates an expected call of ListAuditRecords.
+func (mr *MockWalletStoreTxMockRecorder) ListAuditRecords(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditRecords", reflect.TypeOf((*MockWalletStoreTx)(nil).ListAuditRecords), ctx, walletID)
+}
+
+// ListBonusGrants mocks base method.
+func (m *MockWalletStoreTx) ListBonusGrants(ctx context.Context, walletID int) ([]domain.BonusGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBonusGrants", ctx, walletID)
+	ret0, _ := ret[0].([]domain.BonusGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBonusGrants indicates an expected call of ListBonusGrants.
+func (mr *MockWalletStoreTxMockRecorder) ListBonusGrants(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBonusGrants", reflect.TypeOf((*MockWalletStoreTx)(nil).ListBonusGrants), ctx, walletID)
+}
+
+// ListEntries mocks base method.
+func (m *MockWalletStoreTx) ListEntries(ctx context.Context, filter domain.EntryFilter) ([]domain.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", ctx, filter)
+	ret0, _ := ret[0].([]domain.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockWalletStoreTxMockRecorder) ListEntries(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockWalletStoreTx)(nil).ListEntries), ctx, filter)
+}
+
+// ListExclusions mocks base method.
+func (m *MockWalletStoreTx) ListExclusions(ctx context.Context, walletID int) ([]domain.Exclusion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExclusions", ctx, walletID)
+	ret0, _ := ret[0].([]domain.Exclusion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExclusions indicates an expected call of ListExclusions.
+func (mr *MockWalletStoreTxMockRecorder) ListExclusions(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExclusions", reflect.TypeOf((*MockWalletStoreTx)(nil).ListExclusions), ctx, walletID)
+}
+
+// ListWalletIDs mocks base method.
+func (m *MockWalletStoreTx) ListWalletIDs(ctx context.Context) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletIDs", ctx)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletIDs indicates an expected call of ListWalletIDs.
+func (mr *MockWalletStoreTxMockRecorder) ListWalletIDs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletIDs", reflect.TypeOf((*MockWalletStoreTx)(nil).ListWalletIDs), ctx)
+}
+
+// ListWithdrawalTransitions mocks base method.
+func (m *MockWalletStoreTx) ListWithdrawalTransitions(ctx context.Context, withdrawalID int) ([]domain.WithdrawalTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithdrawalTransitions", ctx, withdrawalID)
+	ret0, _ := ret[0].([]domain.WithdrawalTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithdrawalTransitions indicates an expected call of ListWithdrawalTransitions.
+func (mr *MockWalletStoreTxMockRecorder) ListWithdrawalTransitions(ctx, withdrawalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithdrawalTransitions", reflect.TypeOf((*MockWalletStoreTx)(nil).ListWithdrawalTransitions), ctx, withdrawalID)
+}
+
+// ListWithdrawals mocks base method.
+func (m *MockWalletStoreTx) ListWithdrawals(ctx context.Context, walletID int) ([]domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithdrawals", ctx, walletID)
+	ret0, _ := ret[0].([]domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithdrawals indicates an expected call of ListWithdrawals.
+func (mr *MockWalletStoreTxMockRecorder) ListWithdrawals(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithdrawals", reflect.TypeOf((*MockWalletStoreTx)(nil).ListWithdrawals), ctx, walletID)
+}
+
 // Rollback mocks base method.
 func (m *MockWalletStoreTx) Rollback(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -126,6 +449,121 @@ func (mr *MockWalletStoreTxMockRecorder) SaveBalance(ctx, balance any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBalance", reflect.TypeOf((*MockWalletStoreTx)(nil).SaveBalance), ctx, balance)
 }
 
+// SaveBonusGrant mocks base method.
+func (m *MockWalletStoreTx) SaveBonusGrant(ctx context.Context, grant *domain.BonusGrant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBonusGrant", ctx, grant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBonusGrant indicates an expected call of SaveBonusGrant.
+func (mr *MockWalletStoreTxMockRecorder) SaveBonusGrant(ctx, grant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBonusGrant", reflect.TypeOf((*MockWalletStoreTx)(nil).SaveBonusGrant), ctx, grant)
+}
+
+// SaveDeposit mocks base method.
+func (m *MockWalletStoreTx) SaveDeposit(ctx context.Context, deposit *domain.Deposit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeposit", ctx, deposit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeposit indicates an expected call of SaveDeposit.
+func (mr *MockWalletStoreTxMockRecorder) SaveDeposit(ctx, deposit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeposit", reflect.TypeOf((*MockWalletStoreTx)(nil).SaveDeposit), ctx, deposit)
+}
+
+// SaveExclusion mocks base method.
+func (m *MockWalletStoreTx) SaveExclusion(ctx context.Context, exclusion *domain.Exclusion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveExclusion", ctx, exclusion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveExclusion indicates an expected call of SaveExclusion.
+func (mr *MockWalletStoreTxMockRecorder) SaveExclusion(ctx, exclusion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveExclusion", reflect.TypeOf((*MockWalletStoreTx)(nil).SaveExclusion), ctx, exclusion)
+}
+
+// SaveLimit mocks base method.
+func (m *MockWalletStoreTx) SaveLimit(ctx context.Context, limit *domain.Limit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLimit", ctx, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLimit indicates an expected call of SaveLimit.
+func (mr *MockWalletStoreTxMockRecorder) SaveLimit(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLimit", reflect.TypeOf((*MockWalletStoreTx)(nil).SaveLimit), ctx, limit)
+}
+
+// SaveWithdrawal mocks base method.
+func (m *MockWalletStoreTx) SaveWithdrawal(ctx context.Context, withdrawal *domain.Withdrawal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWithdrawal", ctx, withdrawal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWithdrawal indicates an expected call of SaveWithdrawal.
+func (mr *MockWalletStoreTxMockRecorder) SaveWithdrawal(ctx, withdrawal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWithdrawal", reflect.TypeOf((*MockWalletStoreTx)(nil).SaveWithdrawal), ctx, withdrawal)
+}
+
+// SearchWallets mocks base method.
+func (m *MockWalletStoreTx) SearchWallets(ctx context.Context, filter domain.WalletFilter) ([]domain.WalletBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchWallets", ctx, filter)
+	ret0, _ := ret[0].([]domain.WalletBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchWallets indicates an expected call of SearchWallets.
+func (mr *MockWalletStoreTxMockRecorder) SearchWallets(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchWallets", reflect.TypeOf((*MockWalletStoreTx)(nil).SearchWallets), ctx, filter)
+}
+
+// SumEntries mocks base method.
+func (m *MockWalletStoreTx) SumEntries(ctx context.Context, walletID int, from, to time.Time) (domain.EntrySum, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntries", ctx, walletID, from, to)
+	ret0, _ := ret[0].(domain.EntrySum)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntries indicates an expected call of SumEntries.
+func (mr *MockWalletStoreTxMockRecorder) SumEntries(ctx, walletID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntries", reflect.TypeOf((*MockWalletStoreTx)(nil).SumEntries), ctx, walletID, from, to)
+}
+
+// SumEntriesByDay mocks base method.
+func (m *MockWalletStoreTx) SumEntriesByDay(ctx context.Context, walletID int, from, to time.Time) ([]domain.EntrySum, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesByDay", ctx, walletID, from, to)
+	ret0, _ := ret[0].([]domain.EntrySum)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesByDay indicates an expected call of SumEntriesByDay.
+func (mr *MockWalletStoreTxMockRecorder) SumEntriesByDay(ctx, walletID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesByDay", reflect.TypeOf((*MockWalletStoreTx)(nil).SumEntriesByDay), ctx, walletID, from, to)
+}
+
 // MockWalletStoreTxFactory is a mock of WalletStoreTxFactory interface.
 type MockWalletStoreTxFactory struct {
 	ctrl     *gomock.Controller
@@ -187,6 +625,20 @@ func (m *MockWalletCacheStore) EXPECT() *MockWalletCacheStoreMockRecorder {
 	return m.recorder
 }
 
+// DeleteBalance mocks base method.
+func (m *MockWalletCacheStore) DeleteBalance(ctx context.Context, walletID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBalance", ctx, walletID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBalance indicates an expected call of DeleteBalance.
+func (mr *MockWalletCacheStoreMockRecorder) DeleteBalance(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBalance", reflect.TypeOf((*MockWalletCacheStore)(nil).DeleteBalance), ctx, walletID)
+}
+
 // GetBalance mocks base method.
 func (m *MockWalletCacheStore) GetBalance(ctx context.Context, walletID int) (*domain.WalletBalance, error) {
 	m.ctrl.T.Helper()
@@ -215,3 +667,38 @@ func (mr *MockWalletCacheStoreMockRecorder) SaveBalance(ctx, balance any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBalance", reflect.TypeOf((*MockWalletCacheStore)(nil).SaveBalance), ctx, balance)
 }
+
+// MockBalanceNotifier is a mock of BalanceNotifier interface.
+type MockBalanceNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceNotifierMockRecorder
+}
+
+// MockBalanceNotifierMockRecorder is the mock recorder for MockBalanceNotifier.
+type MockBalanceNotifierMockRecorder struct {
+	mock *MockBalanceNotifier
+}
+
+// NewMockBalanceNotifier creates a new mock instance.
+func NewMockBalanceNotifier(ctrl *gomock.Controller) *MockBalanceNotifier {
+	mock := &MockBalanceNotifier{ctrl: ctrl}
+	mock.recorder = &MockBalanceNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceNotifier) EXPECT() *MockBalanceNotifierMockRecorder {
+	return m.recorder
+}
+
+// NotifyBalance mocks base method.
+func (m *MockBalanceNotifier) NotifyBalance(ctx context.Context, balance *domain.WalletBalance) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyBalance", ctx, balance)
+}
+
+// NotifyBalance indicates an expected call of NotifyBalance.
+func (mr *MockBalanceNotifierMockRecorder) NotifyBalance(ctx, balance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyBalance", reflect.TypeOf((*MockBalanceNotifier)(nil).NotifyBalance), ctx, balance)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// BalanceAt returns the balance of the wallet as the ledger has it at the
+// given time. Unlike GetBalance, it is never cached.
+func (s *WalletService) BalanceAt(ctx context.Context, walletID int, at time.Time) (*domain.BalanceSnapshot, error) {
+	var balance *domain.BalanceSnapshot
+
+	err := s.runOnceTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		if balance, err = s.usecases(tx).BalanceAt(ctx, walletID, at); err != nil {
+			return fmt.Errorf("balance at: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return balance, nil
+}
+
+// TakeSnapshots snapshots the balance of the wallet at the end of every day
+// that ended by until and returns the snapshots taken.
+func (s *WalletService) TakeSnapshots(ctx context.Context, walletID int, until time.Time) ([]domain.BalanceSnapshot, error) {
+	var snapshots []domain.BalanceSnapshot
+
+	err := s.runOrRepeatTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		if snapshots, err = s.usecases(tx).TakeSnapshots(ctx, walletID, until); err != nil {
+			return fmt.Errorf("take snapshots: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// SnapshotService takes balance snapshots of every wallet in the background.
+type SnapshotService struct {
+	wallets  *WalletService
+	interval time.Duration
+	delay    time.Duration
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewSnapshotService returns a service that runs every interval. delay is how
+// long after the end of a day it is snapshotted, so that entries of txs still
+// committing at midnight are not missed.
+func NewSnapshotService(wallets *WalletService, interval, delay time.Duration) *SnapshotService {
+	return &SnapshotService{
+		wallets:  wallets,
+		interval: interval,
+		delay:    delay,
+		cancel:   func() {},
+	}
+}
+
+// Start takes snapshots at once and then every interval until Shutdown.
+func (s *SnapshotService) Start(context.Context) error {
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.run(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown cancels the background runs and waits for the current one to
+// stop.
+func (s *SnapshotService) Shutdown(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *SnapshotService) run(ctx context.Context) {
+	taken, err := s.TakeSnapshots(ctx, time.Now().Add(-s.delay))
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		log.Warn().Err(err).Msg("could not take balance snapshots")
+		return
+	}
+	log.Debug().Int("snapshots", taken).Msg("balance snapshots taken")
+}
+
+// TakeSnapshots snapshots wallets one by one, each in a tx of its own, for
+// days that ended by until and returns how many snapshots were taken. A wallet
+// that fails is logged and left for the next run.
+func (s *SnapshotService) TakeSnapshots(ctx context.Context, until time.Time) (int, error) {
+	var walletIDs []int
+	err := s.wallets.runOnceTx(ctx, func(tx WalletStoreTx) error {
+		var err error
+		if walletIDs, err = tx.ListWalletIDs(ctx); err != nil {
+			return fmt.Errorf("list wallet ids: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var taken int
+	for _, walletID := range walletIDs {
+		if err := ctx.Err(); err != nil {
+			return taken, err
+		}
+		snapshots, err := s.wallets.TakeSnapshots(ctx, walletID, until)
+		if err != nil {
+			log.Warn().Err(err).Int("walletId", walletID).Msg("could not take balance snapshots")
+			continue
+		}
+		taken += len(snapshots)
+	}
+
+	return taken, nil
+}
@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSnapshotService_TakeSnapshots(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+
+	db := memory.NewWalletStoreTxFactory()
+	walletID := db.CreateWallet(money.NewFromInt(0))
+	idleID := db.CreateWallet(money.NewFromInt(0))
+
+	cache := NewMockWalletCacheStore(mockCtrl)
+	cache.EXPECT().SaveBalance(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	wallets := service.NewWalletService(memoryTxFactory{db}, cache, service.NewBalanceBroker())
+	_, err := wallets.DebitMoney(ctx, domain.DebitEntry{
+		WalletID:     walletID,
+		Amount:       money.NewFromInt(1000),
+		EntryDetails: domain.EntryDetails{Operation: domain.OperationDeposit, Initiator: domain.InitiatorService},
+	})
+	require.NoError(t, err)
+	_, err = wallets.CreditMoney(ctx, domain.CreditEntry{
+		WalletID:     walletID,
+		Amount:       money.NewFromInt(300),
+		EntryDetails: domain.EntryDetails{Operation: domain.OperationBet, Initiator: domain.InitiatorUser},
+	})
+	require.NoError(t, err)
+
+	snapshots := service.NewSnapshotService(wallets, time.Hour, 0)
+
+	// The day of the entry has not ended yet.
+	taken, err := snapshots.TakeSnapshots(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, taken)
+
+	tomorrow := time.Now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	taken, err = snapshots.TakeSnapshots(ctx, tomorrow)
+	require.NoError(t, err)
+	assert.Equal(t, 1, taken)
+
+	// Days that are snapshotted already are skipped.
+	taken, err = snapshots.TakeSnapshots(ctx, tomorrow.Add(48*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, taken)
+
+	balance, err := wallets.BalanceAt(ctx, walletID, tomorrow.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 700, balance.Amount.AsInt())
+	assert.Equal(t, tomorrow.Add(time.Hour), balance.At)
+
+	balance, err = wallets.BalanceAt(ctx, walletID, tomorrow.Add(-48*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, balance.Amount.AsInt())
+
+	balance, err = wallets.BalanceAt(ctx, idleID, tomorrow)
+	require.NoError(t, err)
+	assert.Zero(t, balance.Amount.AsInt())
+
+	_, err = wallets.BalanceAt(ctx, -1, tomorrow)
+	require.ErrorIs(t, err, domain.ErrWalletNotFound)
+}
+
+func TestSnapshotService_Shutdown(t *testing.T) {
+	db := memory.NewWalletStoreTxFactory()
+	db.CreateWallet(money.NewFromInt(0))
+	wallets := service.NewWalletService(memoryTxFactory{db}, nil, service.NewBalanceBroker())
+
+	snapshots := service.NewSnapshotService(wallets, time.Millisecond, 0)
+	require.NoError(t, snapshots.Start(context.Background()))
+	time.Sleep(5 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, snapshots.Shutdown(ctx))
+}
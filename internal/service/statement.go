@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/internal/statement"
+)
+
+// StatementService generates account statements of wallets.
+type StatementService struct {
+	txFactory WalletStoreTxFactory
+}
+
+func NewStatementService(txFactory WalletStoreTxFactory) *StatementService {
+	return &StatementService{txFactory: txFactory}
+}
+
+// Statement returns the statement of the wallet for the period. The opening
+// balance and the entries are read in one tx so that they agree.
+func (s *StatementService) Statement(ctx context.Context, walletID int, period statement.Period) (*statement.Statement, error) {
+	if err := period.Validate(); err != nil {
+		return nil, err
+	}
+
+	var res *statement.Statement
+	err := readTx(ctx, s.txFactory, func(tx WalletStoreTx) error {
+		usecase := domain.NewWalletUseCases(tx)
+		opening, err := usecase.BalanceAt(ctx, walletID, period.From)
+		if err != nil {
+			return fmt.Errorf("balance at: %w", err)
+		}
+		entries, err := usecase.ListEntries(ctx, domain.EntryFilter{
+			WalletID: walletID,
+			From:     period.From,
+			To:       period.To,
+		})
+		if err != nil {
+			return fmt.Errorf("list entries: %w", err)
+		}
+		res = statement.New(walletID, period, statement.Balance{Real: opening.Amount, Bonus: opening.Bonus}, entries)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListWalletIDs returns IDs of all wallets in ascending order, for statements
+// to be generated in bulk.
+func (s *StatementService) ListWalletIDs(ctx context.Context) ([]int, error) {
+	var walletIDs []int
+	err := readTx(ctx, s.txFactory, func(tx WalletStoreTx) error {
+		var err error
+		if walletIDs, err = tx.ListWalletIDs(ctx); err != nil {
+			return fmt.Errorf("list wallet ids: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return walletIDs, nil
+}
@@ -0,0 +1,58 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/statement"
+	"github.com/pprishchepa/go-casino-example/internal/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementService_Statement(t *testing.T) {
+	ctx := context.Background()
+
+	db := memory.NewWalletStoreTxFactory()
+	walletID := db.CreateWallet(money.NewFromInt(0))
+	db.CreateWallet(money.NewFromInt(0))
+
+	tx, err := db.NewTx(ctx)
+	require.NoError(t, err)
+	uc := domain.NewWalletUseCases(tx)
+	require.NoError(t, uc.DebitMoney(ctx, &domain.DebitEntry{WalletID: walletID, Amount: money.NewFromInt(500)}))
+	require.NoError(t, uc.CreditMoney(ctx, &domain.CreditEntry{WalletID: walletID, Amount: money.NewFromInt(200)}))
+	require.NoError(t, tx.Commit(ctx))
+
+	svc := service.NewStatementService(memoryTxFactory{db})
+	now := time.Now()
+
+	st, err := svc.Statement(ctx, walletID, statement.Period{From: now.Add(-time.Hour), To: now.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Zero(t, st.Opening.Total().AsInt())
+	require.Len(t, st.Entries, 2)
+	assert.Equal(t, 500, st.Totals.Debits.AsInt())
+	assert.Equal(t, 200, st.Totals.Credits.AsInt())
+	assert.Equal(t, 300, st.Closing.Total().AsInt())
+
+	// The next period opens with the balance the previous one closed with.
+	st, err = svc.Statement(ctx, walletID, statement.Period{From: now.Add(time.Hour), To: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, 300, st.Opening.Total().AsInt())
+	assert.Empty(t, st.Entries)
+	assert.Equal(t, 300, st.Closing.Total().AsInt())
+
+	_, err = svc.Statement(ctx, walletID, statement.Period{From: now, To: now})
+	require.ErrorIs(t, err, statement.ErrInvalidPeriod)
+
+	_, err = svc.Statement(ctx, -1, statement.Month(now))
+	require.ErrorIs(t, err, domain.ErrWalletNotFound)
+
+	walletIDs, err := svc.ListWalletIDs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int{walletID, walletID + 1}, walletIDs)
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/rs/zerolog/log"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=withdrawal.go -destination=withdrawal_mock_test.go -package=service_test
+
+type (
+	WithdrawalOutbox interface {
+		// RelayTransitions publishes up to limit of the oldest transitions
+		// not published yet, in order, and returns how many it published. It
+		// stops at the first one publish fails for.
+		RelayTransitions(
+			ctx context.Context,
+			limit int,
+			publish func(ctx context.Context, event entity.WithdrawalEvent) error,
+		) (int, error)
+	}
+	WithdrawalPublisher interface {
+		PublishWithdrawal(ctx context.Context, event entity.WithdrawalEvent) error
+	}
+)
+
+// relayBatch is how many transitions are published in one tx.
+const relayBatch = 100
+
+// WithdrawalRelay publishes withdrawal transitions from the outbox in the
+// background. A transition is taken out of the outbox only after it is
+// published, so it is published at least once; consumers tell repeats apart
+// by the ID of the event.
+type WithdrawalRelay struct {
+	outbox    WithdrawalOutbox
+	publisher WithdrawalPublisher
+	interval  time.Duration
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewWithdrawalRelay returns a relay that looks for new transitions every
+// interval.
+func NewWithdrawalRelay(outbox WithdrawalOutbox, publisher WithdrawalPublisher, interval time.Duration) *WithdrawalRelay {
+	return &WithdrawalRelay{
+		outbox:    outbox,
+		publisher: publisher,
+		interval:  interval,
+		cancel:    func() {},
+	}
+}
+
+// Start relays transitions at once and then every interval until Shutdown.
+func (s *WithdrawalRelay) Start(context.Context) error {
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.run(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown cancels the background runs and waits for the current one to
+// stop.
+func (s *WithdrawalRelay) Shutdown(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *WithdrawalRelay) run(ctx context.Context) {
+	published, err := s.Relay(ctx)
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		log.Warn().Err(err).Int("published", published).Msg("could not relay withdrawal transitions")
+		return
+	}
+	if published > 0 {
+		log.Debug().Int("published", published).Msg("withdrawal transitions relayed")
+	}
+}
+
+// Relay publishes transitions until the outbox is empty and returns how many
+// it published. Transitions it could not publish are left for the next run.
+func (s *WithdrawalRelay) Relay(ctx context.Context) (int, error) {
+	var total int
+	for {
+		published, err := s.outbox.RelayTransitions(ctx, relayBatch, s.publisher.PublishWithdrawal)
+		total += published
+		if err != nil {
+			return total, fmt.Errorf("relay transitions: %w", err)
+		}
+		if published < relayBatch {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: withdrawal.go
+//
+// Generated by this command:
+//
+//	mockgen -source=withdrawal.go -destination=withdrawal_mock_test.go -package=service_test
+//
+
+// Package service_test is a generated GoMock package.
+package service_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/pprishchepa/go-casino-example/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWithdrawalOutbox is a mock of WithdrawalOutbox interface.
+type MockWithdrawalOutbox struct {
+	ctrl     *gomock.Controller
+	recorder *MockWithdrawalOutboxMockRecorder
+}
+
+// MockWithdrawalOutboxMockRecorder is the mock recorder for MockWithdrawalOutbox.
+type MockWithdrawalOutboxMockRecorder struct {
+	mock *MockWithdrawalOutbox
+}
+
+// NewMockWithdrawalOutbox creates a new mock instance.
+func NewMockWithdrawalOutbox(ctrl *gomock.Controller) *MockWithdrawalOutbox {
+	mock := &MockWithdrawalOutbox{ctrl: ctrl}
+	mock.recorder = &MockWithdrawalOutboxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWithdrawalOutbox) EXPECT() *MockWithdrawalOutboxMockRecorder {
+	return m.recorder
+}
+
+// RelayTransitions mocks base method.
+func (m *MockWithdrawalOutbox) RelayTransitions(ctx context.Context, limit int, publish func(context.Context, entity.WithdrawalEvent) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelayTransitions", ctx, limit, publish)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RelayTransitions indicates an expected call of RelayTransitions.
+func (mr *MockWithdrawalOutboxMockRecorder) RelayTransitions(ctx, limit, publish any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayTransitions", reflect.TypeOf((*MockWithdrawalOutbox)(nil).RelayTransitions), ctx, limit, publish)
+}
+
+// MockWithdrawalPublisher is a mock of WithdrawalPublisher interface.
+type MockWithdrawalPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockWithdrawalPublisherMockRecorder
+}
+
+// MockWithdrawalPublisherMockRecorder is the mock recorder for MockWithdrawalPublisher.
+type MockWithdrawalPublisherMockRecorder struct {
+	mock *MockWithdrawalPublisher
+}
+
+// NewMockWithdrawalPublisher creates a new mock instance.
+func NewMockWithdrawalPublisher(ctrl *gomock.Controller) *MockWithdrawalPublisher {
+	mock := &MockWithdrawalPublisher{ctrl: ctrl}
+	mock.recorder = &MockWithdrawalPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWithdrawalPublisher) EXPECT() *MockWithdrawalPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishWithdrawal mocks base method.
+func (m *MockWithdrawalPublisher) PublishWithdrawal(ctx context.Context, event entity.WithdrawalEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishWithdrawal", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishWithdrawal indicates an expected call of PublishWithdrawal.
+func (mr *MockWithdrawalPublisherMockRecorder) PublishWithdrawal(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishWithdrawal", reflect.TypeOf((*MockWithdrawalPublisher)(nil).PublishWithdrawal), ctx, event)
+}
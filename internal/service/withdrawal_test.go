@@ -0,0 +1,144 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWalletService_Withdrawal(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+
+	db := memory.NewWalletStoreTxFactory()
+	walletID := db.CreateWallet(money.NewFromInt(1000))
+
+	cache := NewMockWalletCacheStore(mockCtrl)
+	cache.EXPECT().SaveBalance(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	wallets := service.NewWalletService(memoryTxFactory{db}, cache, service.NewBalanceBroker())
+
+	withdrawal, err := wallets.RequestWithdrawal(ctx, domain.Withdrawal{WalletID: walletID, Amount: money.NewFromInt(400)}, "player")
+	require.NoError(t, err)
+	assert.Equal(t, domain.WithdrawalPendingReview, withdrawal.Status)
+
+	// Approval does not touch the balance, so the cache is not updated.
+	_, err = wallets.TransitionWithdrawal(ctx, walletID, withdrawal.ID, domain.WithdrawalApproved, domain.InitiatorAdmin, "support", "")
+	require.NoError(t, err)
+
+	_, err = wallets.TransitionWithdrawal(ctx, walletID, withdrawal.ID, domain.WithdrawalFailed, domain.InitiatorAdmin, "support", "bank rejected")
+	require.NoError(t, err)
+
+	_, err = wallets.TransitionWithdrawal(ctx, walletID, withdrawal.ID, domain.WithdrawalCancelled, domain.InitiatorUser, "player", "")
+	require.ErrorIs(t, err, domain.ErrInvalidTransition)
+
+	got, history, err := wallets.GetWithdrawal(ctx, walletID, withdrawal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WithdrawalFailed, got.Status)
+
+	var tos []domain.WithdrawalStatus
+	for _, transition := range history {
+		tos = append(tos, transition.To)
+	}
+	assert.Equal(t, []domain.WithdrawalStatus{
+		domain.WithdrawalRequested,
+		domain.WithdrawalPendingReview,
+		domain.WithdrawalApproved,
+		domain.WithdrawalFailed,
+	}, tos)
+
+	entries := db.Entries(walletID)
+	require.Len(t, entries, 2, "the hold and its release")
+	assert.Equal(t, 400, entries[0].Credit.AsInt())
+	assert.Equal(t, 400, entries[1].Debit.AsInt())
+	assert.Equal(t, domain.OperationWithdrawalRelease, entries[1].Operation)
+	assert.Equal(t, domain.InitiatorAdmin, entries[1].Initiator)
+}
+
+func TestWithdrawalRelay_Relay(t *testing.T) {
+	// relay makes the outbox hand the events to publish the way the store
+	// does: in order, stopping at the first failure.
+	relay := func(events ...entity.WithdrawalEvent) any {
+		return func(ctx context.Context, _ int, publish func(context.Context, entity.WithdrawalEvent) error) (int, error) {
+			for i, event := range events {
+				if err := publish(ctx, event); err != nil {
+					return i, err
+				}
+			}
+			return len(events), nil
+		}
+	}
+	events := func(from, n int) []entity.WithdrawalEvent {
+		var events []entity.WithdrawalEvent
+		for id := from; id < from+n; id++ {
+			events = append(events, entity.WithdrawalEvent{
+				ID:                   id,
+				WithdrawalTransition: domain.WithdrawalTransition{WithdrawalID: 12, To: domain.WithdrawalApproved},
+			})
+		}
+		return events
+	}
+
+	tests := []struct {
+		name          string
+		mock          func(outbox *MockWithdrawalOutbox, publisher *MockWithdrawalPublisher)
+		wantPublished int
+		wantErr       bool
+	}{
+		{
+			name: "until the outbox is empty",
+			mock: func(outbox *MockWithdrawalOutbox, publisher *MockWithdrawalPublisher) {
+				gomock.InOrder(
+					outbox.EXPECT().RelayTransitions(gomock.Any(), 100, gomock.Any()).DoAndReturn(relay(events(1, 100)...)),
+					outbox.EXPECT().RelayTransitions(gomock.Any(), 100, gomock.Any()).DoAndReturn(relay(events(101, 3)...)),
+				)
+				publisher.EXPECT().PublishWithdrawal(gomock.Any(), gomock.Any()).Return(nil).Times(103)
+			},
+			wantPublished: 103,
+		},
+		{
+			name: "empty outbox",
+			mock: func(outbox *MockWithdrawalOutbox, _ *MockWithdrawalPublisher) {
+				outbox.EXPECT().RelayTransitions(gomock.Any(), 100, gomock.Any()).DoAndReturn(relay())
+			},
+		},
+		{
+			name: "publisher failure",
+			mock: func(outbox *MockWithdrawalOutbox, publisher *MockWithdrawalPublisher) {
+				outbox.EXPECT().RelayTransitions(gomock.Any(), 100, gomock.Any()).DoAndReturn(relay(events(1, 3)...))
+				gomock.InOrder(
+					publisher.EXPECT().PublishWithdrawal(gomock.Any(), events(1, 1)[0]).Return(nil),
+					publisher.EXPECT().PublishWithdrawal(gomock.Any(), events(2, 1)[0]).Return(errors.New("boom")),
+				)
+			},
+			wantPublished: 1,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			outbox := NewMockWithdrawalOutbox(mockCtrl)
+			publisher := NewMockWithdrawalPublisher(mockCtrl)
+			tt.mock(outbox, publisher)
+
+			published, err := service.NewWithdrawalRelay(outbox, publisher, time.Second).Relay(context.Background())
+			assert.Equal(t, tt.wantPublished, published)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
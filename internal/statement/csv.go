@@ -0,0 +1,64 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+// WriteCSV writes the statement as a single table so that it loads into a
+// spreadsheet as is. The kind column tells the opening balance, entries,
+// totals and the closing balance apart.
+func (s *Statement) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{
+		"kind", "wallet_id", "id", "created_at", "operation", "reference", "initiator",
+		"debit", "credit", "bonus_amount", "balance", "real_balance", "bonus_balance",
+	})
+
+	walletID := strconv.Itoa(s.WalletID)
+	balance := func(b Balance) []string {
+		return []string{b.Total().String(), b.Real.String(), b.Bonus.String()}
+	}
+
+	_ = cw.Write(append([]string{
+		"opening", walletID, "", s.Period.From.UTC().Format(time.RFC3339), "", "", "", "", "", "",
+	}, balance(s.Opening)...))
+
+	for _, entry := range s.Entries {
+		_ = cw.Write(append([]string{
+			"entry",
+			walletID,
+			strconv.Itoa(entry.ID),
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			string(entry.Operation),
+			entry.Reference,
+			string(entry.Initiator),
+			optionalMoney(entry.Debit),
+			optionalMoney(entry.Credit),
+			entry.Bonus.String(),
+		}, balance(Balance{Real: entry.BalanceAfter, Bonus: entry.BonusAfter})...))
+	}
+
+	_ = cw.Write(append([]string{
+		"totals", walletID, "", "", "", "", "",
+		s.Totals.Debits.String(), s.Totals.Credits.String(), "",
+	}, balance(s.Totals.Change)...))
+
+	_ = cw.Write(append([]string{
+		"closing", walletID, "", s.Period.To.UTC().Format(time.RFC3339), "", "", "", "", "", "",
+	}, balance(s.Closing)...))
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func optionalMoney(m *money.Money) string {
+	if m == nil {
+		return ""
+	}
+	return m.String()
+}
@@ -0,0 +1,79 @@
+package statement
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+type jsonStatement struct {
+	WalletID int         `json:"walletId"`
+	From     time.Time   `json:"from"`
+	To       time.Time   `json:"to"`
+	Opening  jsonBalance `json:"opening"`
+	Entries  []jsonEntry `json:"entries"`
+	Totals   jsonTotals  `json:"totals"`
+	Closing  jsonBalance `json:"closing"`
+}
+
+type jsonBalance struct {
+	Balance money.Money `json:"balance"`
+	Real    money.Money `json:"real"`
+	Bonus   money.Money `json:"bonus"`
+}
+
+type jsonEntry struct {
+	ID          int          `json:"id"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	Operation   string       `json:"operation"`
+	Reference   string       `json:"reference"`
+	Initiator   string       `json:"initiator"`
+	Debit       *money.Money `json:"debit"`
+	Credit      *money.Money `json:"credit"`
+	BonusAmount money.Money  `json:"bonusAmount"`
+	After       jsonBalance  `json:"after"`
+}
+
+type jsonTotals struct {
+	Debits  money.Money `json:"debits"`
+	Credits money.Money `json:"credits"`
+	Change  jsonBalance `json:"change"`
+}
+
+func newJSONBalance(b Balance) jsonBalance {
+	return jsonBalance{Balance: b.Total(), Real: b.Real, Bonus: b.Bonus}
+}
+
+// WriteJSON writes the statement as a JSON document.
+func (s *Statement) WriteJSON(w io.Writer) error {
+	doc := jsonStatement{
+		WalletID: s.WalletID,
+		From:     s.Period.From.UTC(),
+		To:       s.Period.To.UTC(),
+		Opening:  newJSONBalance(s.Opening),
+		Entries:  make([]jsonEntry, 0, len(s.Entries)),
+		Totals: jsonTotals{
+			Debits:  s.Totals.Debits,
+			Credits: s.Totals.Credits,
+			Change:  newJSONBalance(s.Totals.Change),
+		},
+		Closing: newJSONBalance(s.Closing),
+	}
+	for _, entry := range s.Entries {
+		doc.Entries = append(doc.Entries, jsonEntry{
+			ID:          entry.ID,
+			CreatedAt:   entry.CreatedAt.UTC(),
+			Operation:   string(entry.Operation),
+			Reference:   entry.Reference,
+			Initiator:   string(entry.Initiator),
+			Debit:       entry.Debit,
+			Credit:      entry.Credit,
+			BonusAmount: entry.Bonus,
+			After:       newJSONBalance(Balance{Real: entry.BalanceAfter, Bonus: entry.BonusAfter}),
+		})
+	}
+
+	return json.NewEncoder(w).Encode(doc)
+}
@@ -0,0 +1,176 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The PDF is plain text set in Courier, one of the standard fonts every
+// reader has, so that nothing needs to be embedded and columns line up.
+const (
+	pdfPageWidth    = 595 // A4 in points
+	pdfPageHeight   = 842
+	pdfMargin       = 40
+	pdfFontSize     = 9
+	pdfLineHeight   = 12
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLineHeight
+)
+
+const pdfDateTime = "2006-01-02 15:04"
+
+// WritePDF writes the statement as a PDF document of as many A4 pages as its
+// entries take.
+func (s *Statement) WritePDF(w io.Writer) error {
+	lines := s.pdfLines()
+
+	var pages [][]string
+	for len(lines) > pdfLinesPerPage {
+		pages = append(pages, lines[:pdfLinesPerPage])
+		lines = lines[pdfLinesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	var doc pdfDocument
+	doc.header()
+	// Objects 1 to 3 are the catalog, the page tree and the font. Every page
+	// then takes two objects: the page and its content stream.
+	kids := make([]string, 0, len(pages))
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 4+2*i))
+	}
+	doc.object("<< /Type /Catalog /Pages 2 0 R >>")
+	doc.object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	doc.object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	for i, page := range pages {
+		doc.object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 5+2*i))
+		doc.stream(pdfContent(page))
+	}
+	doc.trailer()
+
+	_, err := w.Write(doc.buf.Bytes())
+	return err
+}
+
+func (s *Statement) pdfLines() []string {
+	row := func(date, operation, reference, debit, credit, balance string) string {
+		return fmt.Sprintf("%-16s %-18s %-16s %11s %11s %12s",
+			date, truncate(operation, 18), truncate(reference, 16), debit, credit, balance)
+	}
+	amount := func(b Balance) string {
+		return fmt.Sprintf("%s (real %s, bonus %s)", b.Total().Format("en"), b.Real.Format("en"), b.Bonus.Format("en"))
+	}
+
+	lines := []string{
+		"ACCOUNT STATEMENT",
+		"",
+		fmt.Sprintf("Wallet:  %d", s.WalletID),
+		fmt.Sprintf("Period:  %s to %s UTC",
+			s.Period.From.UTC().Format(pdfDateTime), s.Period.To.UTC().Format(pdfDateTime)),
+		"",
+		"Opening balance:  " + amount(s.Opening),
+		"",
+		row("Date", "Operation", "Reference", "Debit", "Credit", "Balance"),
+		strings.Repeat("-", 89),
+	}
+	for _, entry := range s.Entries {
+		var debit, credit string
+		if entry.Debit != nil {
+			debit = entry.Debit.Format("en")
+		} else {
+			credit = entry.Credit.Format("en")
+		}
+		lines = append(lines, row(
+			entry.CreatedAt.UTC().Format(pdfDateTime),
+			string(entry.Operation),
+			entry.Reference,
+			debit,
+			credit,
+			entry.BalanceAfter.Add(entry.BonusAfter).Format("en"),
+		))
+	}
+	if len(s.Entries) == 0 {
+		lines = append(lines, "No entries in this period.")
+	}
+
+	return append(lines,
+		strings.Repeat("-", 89),
+		row("Totals", "", "", s.Totals.Debits.Format("en"), s.Totals.Credits.Format("en"), ""),
+		"",
+		"Closing balance:  "+amount(s.Closing),
+		"",
+		fmt.Sprintf("Entries: %d. Balances include bonus money. Times are UTC.", len(s.Entries)),
+	)
+}
+
+func pdfContent(lines []string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n",
+		pdfFontSize, pdfLineHeight, pdfMargin, pdfPageHeight-pdfMargin-pdfFontSize)
+	for _, line := range lines {
+		fmt.Fprintf(&buf, "(%s) Tj T*\n", pdfEscape(line))
+	}
+	buf.WriteString("ET\n")
+	return buf.Bytes()
+}
+
+// pdfEscape makes the text a valid PDF string literal. Characters outside of
+// printable ASCII are replaced, as the font is not embedded.
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < ' ' || r > '~':
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "~"
+	}
+	return s
+}
+
+// pdfDocument writes numbered objects and keeps their offsets for the
+// cross-reference table.
+type pdfDocument struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func (d *pdfDocument) header() {
+	// The comment with bytes above 127 tells tools the file is binary.
+	d.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+}
+
+func (d *pdfDocument) object(body string) {
+	d.offsets = append(d.offsets, d.buf.Len())
+	fmt.Fprintf(&d.buf, "%d 0 obj\n%s\nendobj\n", len(d.offsets), body)
+}
+
+func (d *pdfDocument) stream(data []byte) {
+	d.offsets = append(d.offsets, d.buf.Len())
+	fmt.Fprintf(&d.buf, "%d 0 obj\n<< /Length %d >>\nstream\n", len(d.offsets), len(data))
+	d.buf.Write(data)
+	d.buf.WriteString("\nendstream\nendobj\n")
+}
+
+func (d *pdfDocument) trailer() {
+	xref := d.buf.Len()
+	fmt.Fprintf(&d.buf, "xref\n0 %d\n0000000000 65535 f \n", len(d.offsets)+1)
+	for _, offset := range d.offsets {
+		fmt.Fprintf(&d.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&d.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.offsets)+1, xref)
+}
@@ -0,0 +1,155 @@
+// Package statement generates account statements of wallets: the balance at
+// the start of a period, the entries made in it, their totals and the balance
+// at its end. Statements render to CSV, JSON and PDF without any external
+// service.
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+)
+
+var (
+	ErrInvalidPeriod = errors.New("invalid statement period")
+	ErrInvalidFormat = errors.New("invalid statement format")
+)
+
+// MaxPeriod is the longest period a statement may cover.
+const MaxPeriod = 366 * 24 * time.Hour
+
+// Period is [From, To).
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// Month returns the period of the calendar month, in UTC, that t falls into.
+func Month(t time.Time) Period {
+	t = t.UTC()
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return Period{From: from, To: from.AddDate(0, 1, 0)}
+}
+
+func (p Period) Validate() error {
+	if !p.To.After(p.From) {
+		return fmt.Errorf("%w: period must end after it starts", ErrInvalidPeriod)
+	}
+	if p.To.Sub(p.From) > MaxPeriod {
+		return fmt.Errorf("%w: period must not be longer than %s", ErrInvalidPeriod, MaxPeriod)
+	}
+	return nil
+}
+
+// Balance is real and bonus money of a wallet.
+type Balance struct {
+	Real  money.Money
+	Bonus money.Money
+}
+
+// Total is real and bonus money together.
+func (b Balance) Total() money.Money {
+	return b.Real.Add(b.Bonus)
+}
+
+func (b Balance) add(other Balance) Balance {
+	return Balance{Real: b.Real.Add(other.Real), Bonus: b.Bonus.Add(other.Bonus)}
+}
+
+// Totals add up the entries of a statement.
+type Totals struct {
+	// Debits is all money that came in and Credits all money that went out,
+	// real and bonus alike.
+	Debits  money.Money
+	Credits money.Money
+	// Change is how the entries changed the balance.
+	Change Balance
+}
+
+// Statement is the account statement of a wallet for a period.
+type Statement struct {
+	WalletID int
+	Period   Period
+	Opening  Balance
+	// Entries are the entries created in the period, oldest first.
+	Entries []domain.Entry
+	Totals  Totals
+	Closing Balance
+}
+
+// New returns the statement of a wallet whose balance at the start of the
+// period was opening and that has the given entries in it. The closing
+// balance is the opening one plus what the entries changed.
+func New(walletID int, period Period, opening Balance, entries []domain.Entry) *Statement {
+	var totals Totals
+	for _, entry := range entries {
+		if entry.Debit != nil {
+			totals.Debits = totals.Debits.Add(*entry.Debit)
+		} else {
+			totals.Credits = totals.Credits.Add(*entry.Credit)
+		}
+		change := entry.Change()
+		totals.Change = totals.Change.add(Balance{Real: change.Amount, Bonus: change.Bonus})
+	}
+
+	return &Statement{
+		WalletID: walletID,
+		Period:   period,
+		Opening:  opening,
+		Entries:  entries,
+		Totals:   totals,
+		Closing:  opening.add(totals.Change),
+	}
+}
+
+// Format is a file format a statement renders to.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	FormatPDF  Format = "pdf"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatCSV, FormatJSON, FormatPDF:
+		return f, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidFormat, s)
+}
+
+// ContentType is the media type of statements in the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "application/json"
+	}
+}
+
+// FileName is the name a statement is saved under, e.g.
+// "statement-25-2024-06-01.pdf".
+func (s *Statement) FileName(f Format) string {
+	return fmt.Sprintf("statement-%d-%s.%s", s.WalletID, s.Period.From.UTC().Format(time.DateOnly), f)
+}
+
+// Render writes the statement in the format.
+func (s *Statement) Render(w io.Writer, f Format) error {
+	switch f {
+	case FormatCSV:
+		return s.WriteCSV(w)
+	case FormatJSON:
+		return s.WriteJSON(w)
+	case FormatPDF:
+		return s.WritePDF(w)
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidFormat, f)
+}
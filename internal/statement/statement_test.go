@@ -0,0 +1,183 @@
+package statement_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/statement"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStatement(t *testing.T, entries int) *statement.Statement {
+	t.Helper()
+
+	period := statement.Month(time.Date(2024, 6, 12, 15, 0, 0, 0, time.UTC))
+	opening := statement.Balance{Real: money.NewFromInt(10000), Bonus: money.NewFromInt(2000)}
+	createdAt := time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC)
+
+	deposit, bet, win := money.NewFromInt(5000), money.NewFromInt(1500), money.NewFromInt(3000)
+	list := []domain.Entry{
+		{
+			ID: 4, WalletID: 25, Debit: &deposit, CreatedAt: createdAt,
+			EntryDetails: domain.EntryDetails{Operation: domain.OperationDeposit, Reference: "dep-1", Initiator: domain.InitiatorService},
+			BalanceAfter: money.NewFromInt(15000), BonusAfter: money.NewFromInt(2000),
+		},
+		{
+			ID: 5, WalletID: 25, Credit: &bet, Bonus: money.NewFromInt(500), CreatedAt: createdAt.Add(time.Hour),
+			EntryDetails: domain.EntryDetails{Operation: domain.OperationBet, Reference: "round-1", Initiator: domain.InitiatorUser},
+			BalanceAfter: money.NewFromInt(14000), BonusAfter: money.NewFromInt(1500),
+		},
+	}
+	for i := len(list); i < entries; i++ {
+		list = append(list, domain.Entry{
+			ID: 4 + i, WalletID: 25, Debit: &win, CreatedAt: createdAt.Add(time.Duration(i) * time.Hour),
+			EntryDetails: domain.EntryDetails{Operation: domain.OperationWin, Reference: "round-" + strconv.Itoa(i), Initiator: domain.InitiatorService},
+			BalanceAfter: money.NewFromInt(14000 + 3000*(i-1)), BonusAfter: money.NewFromInt(1500),
+		})
+	}
+
+	return statement.New(25, period, opening, list[:entries])
+}
+
+func TestNew(t *testing.T) {
+	s := newStatement(t, 2)
+
+	assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), s.Period.From)
+	assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), s.Period.To)
+	assert.Equal(t, "5.000", s.Totals.Debits.String())
+	assert.Equal(t, "1.500", s.Totals.Credits.String())
+	assert.Equal(t, "4.000", s.Totals.Change.Real.String())
+	assert.Equal(t, "-0.500", s.Totals.Change.Bonus.String())
+	assert.Equal(t, "14.000", s.Closing.Real.String())
+	assert.Equal(t, "1.500", s.Closing.Bonus.String())
+
+	empty := statement.New(25, s.Period, s.Opening, nil)
+	assert.Equal(t, s.Opening, empty.Closing)
+}
+
+func TestPeriod_Validate(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		period  statement.Period
+		wantErr bool
+	}{
+		{name: "month", period: statement.Month(from)},
+		{name: "empty", period: statement.Period{From: from, To: from}, wantErr: true},
+		{name: "backwards", period: statement.Period{From: from, To: from.Add(-time.Hour)}, wantErr: true},
+		{name: "too long", period: statement.Period{From: from, To: from.AddDate(2, 0, 0)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.period.Validate()
+			if tt.wantErr {
+				require.ErrorIs(t, err, statement.ErrInvalidPeriod)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"csv", "json", "pdf"} {
+		f, err := statement.ParseFormat(s)
+		require.NoError(t, err)
+		assert.Equal(t, statement.Format(s), f)
+	}
+
+	_, err := statement.ParseFormat("xlsx")
+	require.ErrorIs(t, err, statement.ErrInvalidFormat)
+}
+
+func TestStatement_WriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newStatement(t, 2).WriteCSV(&buf))
+
+	assert.Equal(t, "kind,wallet_id,id,created_at,operation,reference,initiator,debit,credit,bonus_amount,"+
+		"balance,real_balance,bonus_balance\n"+
+		"opening,25,,2024-06-01T00:00:00Z,,,,,,,12.000,10.000,2.000\n"+
+		"entry,25,4,2024-06-05T12:00:00Z,deposit,dep-1,service,5.000,,0.000,17.000,15.000,2.000\n"+
+		"entry,25,5,2024-06-05T13:00:00Z,bet,round-1,user,,1.500,0.500,15.500,14.000,1.500\n"+
+		"totals,25,,,,,,5.000,1.500,,3.500,4.000,-0.500\n"+
+		"closing,25,,2024-07-01T00:00:00Z,,,,,,,15.500,14.000,1.500\n",
+		buf.String())
+}
+
+func TestStatement_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newStatement(t, 2).WriteJSON(&buf))
+
+	assert.JSONEq(t, `{
+		"walletId": 25,
+		"from": "2024-06-01T00:00:00Z",
+		"to": "2024-07-01T00:00:00Z",
+		"opening": {"balance": "12.000", "real": "10.000", "bonus": "2.000"},
+		"entries": [
+			{"id": 4, "createdAt": "2024-06-05T12:00:00Z", "operation": "deposit", "reference": "dep-1",
+				"initiator": "service", "debit": "5.000", "credit": null, "bonusAmount": "0.000",
+				"after": {"balance": "17.000", "real": "15.000", "bonus": "2.000"}},
+			{"id": 5, "createdAt": "2024-06-05T13:00:00Z", "operation": "bet", "reference": "round-1",
+				"initiator": "user", "debit": null, "credit": "1.500", "bonusAmount": "0.500",
+				"after": {"balance": "15.500", "real": "14.000", "bonus": "1.500"}}
+		],
+		"totals": {"debits": "5.000", "credits": "1.500",
+			"change": {"balance": "3.500", "real": "4.000", "bonus": "-0.500"}},
+		"closing": {"balance": "15.500", "real": "14.000", "bonus": "1.500"}
+	}`, buf.String())
+}
+
+func TestStatement_WritePDF(t *testing.T) {
+	tests := []struct {
+		name      string
+		entries   int
+		wantPages int
+	}{
+		{name: "no entries", entries: 0, wantPages: 1},
+		{name: "one page", entries: 2, wantPages: 1},
+		{name: "several pages", entries: 150, wantPages: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, newStatement(t, tt.entries).WritePDF(&buf))
+			doc := buf.String()
+
+			assert.True(t, strings.HasPrefix(doc, "%PDF-1.4\n"))
+			assert.True(t, strings.HasSuffix(doc, "%%EOF\n"))
+			assert.Contains(t, doc, fmt.Sprintf("/Count %d", tt.wantPages))
+			assert.Contains(t, doc, "(Wallet:  25) Tj")
+			assert.Contains(t, doc, "(Closing balance:  ")
+
+			// Every object must be where the cross-reference table says.
+			xref := regexp.MustCompile(`(?m)^(\d{10}) 00000 n $`).FindAllStringSubmatch(doc, -1)
+			require.Len(t, xref, 3+2*tt.wantPages)
+			for i, m := range xref {
+				offset, err := strconv.Atoi(m[1])
+				require.NoError(t, err)
+				assert.True(t, strings.HasPrefix(doc[offset:], fmt.Sprintf("%d 0 obj\n", i+1)), "object %d", i+1)
+			}
+
+			startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(doc)
+			require.NotNil(t, startxref)
+			offset, err := strconv.Atoi(startxref[1])
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(doc[offset:], "xref\n"))
+		})
+	}
+}
+
+func TestStatement_FileName(t *testing.T) {
+	s := newStatement(t, 0)
+	assert.Equal(t, "statement-25-2024-06-01.pdf", s.FileName(statement.FormatPDF))
+	assert.Equal(t, "statement-25-2024-06-01.csv", s.FileName(statement.FormatCSV))
+}
@@ -0,0 +1,1296 @@
+// Package memory implements wallet storage in process memory. It mirrors the
+// serializable semantics of the postgres store and is meant for tests.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+)
+
+var (
+	ErrNegativeBalance   = errors.New("balance must not be negative")
+	ErrNegativeEntry     = errors.New("entry amount must not be negative")
+	ErrBonusExceedsEntry = errors.New("entry bonus must not exceed the entry amount")
+	ErrUnknownOperation  = errors.New("entry operation or initiator is unknown")
+	ErrGrantNotFound     = errors.New("bonus grant not found")
+	ErrNegativeLimit     = errors.New("limit must not be negative")
+	ErrExclusionNotFound = errors.New("exclusion not found")
+	ErrActorRequired     = errors.New("admin action actor is required")
+	ErrTxDone            = errors.New("tx is already committed or rolled back")
+)
+
+type wallet struct {
+	amount  money.Money
+	bonus   money.Money
+	version int
+	flagged bool
+	frozen  bool
+}
+
+type limitKey struct {
+	walletID int
+	kind     domain.LimitKind
+	period   domain.LimitPeriod
+}
+
+type depositKey struct {
+	provider  string
+	reference string
+}
+
+type activityKey struct {
+	walletID int
+	hour     time.Time
+}
+
+type WalletStoreTxFactory struct {
+	mu      sync.Mutex
+	wallets map[int]*wallet
+	entries []domain.Entry
+	// audit is never changed once appended, like the audit log of the
+	// postgres store.
+	audit  []domain.AuditRecord
+	grants []domain.BonusGrant
+	limits map[limitKey]domain.Limit
+	// activity is aggregated by the hour like in the postgres store.
+	activity    map[activityKey]domain.Activity
+	exclusions  []domain.Exclusion
+	withdrawals []domain.Withdrawal
+	transitions []domain.WithdrawalTransition
+	deposits    []domain.Deposit
+	// actions is never changed once appended, like the admin_action table of
+	// the postgres store.
+	actions   []domain.AdminAction
+	snapshots []domain.BalanceSnapshot
+
+	lastWalletID     int
+	lastEntryID      int
+	lastGrantID      int
+	lastExclusionID  int
+	lastWithdrawalID int
+	lastDepositID    int
+	lastActionID     int
+}
+
+func NewWalletStoreTxFactory() *WalletStoreTxFactory {
+	return &WalletStoreTxFactory{
+		wallets:  make(map[int]*wallet),
+		limits:   make(map[limitKey]domain.Limit),
+		activity: make(map[activityKey]domain.Activity),
+	}
+}
+
+// CreateWallet creates a wallet with the given balance and returns its ID.
+func (f *WalletStoreTxFactory) CreateWallet(amount money.Money) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastWalletID++
+	f.wallets[f.lastWalletID] = &wallet{amount: amount}
+	return f.lastWalletID
+}
+
+// Entries returns committed entries of the wallet in insertion order.
+func (f *WalletStoreTxFactory) Entries(walletID int) []domain.Entry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var res []domain.Entry
+	for _, e := range f.entries {
+		if e.WalletID == walletID {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// nextEntryID works like a database sequence: IDs are never reused, even if
+// the tx that took one is rolled back.
+func (f *WalletStoreTxFactory) nextEntryID() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastEntryID++
+	return f.lastEntryID
+}
+
+func (f *WalletStoreTxFactory) nextGrantID() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastGrantID++
+	return f.lastGrantID
+}
+
+func (f *WalletStoreTxFactory) nextExclusionID() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastExclusionID++
+	return f.lastExclusionID
+}
+
+func (f *WalletStoreTxFactory) nextWithdrawalID() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastWithdrawalID++
+	return f.lastWithdrawalID
+}
+
+func (f *WalletStoreTxFactory) nextDepositID() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastDepositID++
+	return f.lastDepositID
+}
+
+func (f *WalletStoreTxFactory) nextActionID() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastActionID++
+	return f.lastActionID
+}
+
+func (f *WalletStoreTxFactory) NewTx(_ context.Context) (*WalletStore, error) {
+	return &WalletStore{
+		db:           f,
+		reads:        make(map[int]int),
+		writes:       make(map[int]domain.WalletBalance),
+		saves:        make(map[int]int),
+		grantWrites:  make(map[int]domain.BonusGrant),
+		limitWrites:  make(map[limitKey]domain.Limit),
+		activity:     make(map[activityKey]domain.Activity),
+		exclusions:   make(map[int]domain.Exclusion),
+		withdrawals:  make(map[int]domain.Withdrawal),
+		statusReads:  make(map[int]domain.WithdrawalStatus),
+		deposits:     make(map[int]domain.Deposit),
+		depositReads: make(map[depositKey]domain.DepositStatus),
+	}, nil
+}
+
+type WalletStore struct {
+	db      *WalletStoreTxFactory
+	reads   map[int]int
+	writes  map[int]domain.WalletBalance
+	saves   map[int]int
+	entries []domain.Entry
+	audit   []domain.AuditRecord
+	// grantWrites holds grants added or saved in the tx by ID.
+	grantWrites map[int]domain.BonusGrant
+	// limitWrites holds limits saved in the tx. Deleted limits have neither
+	// an amount nor a pending change.
+	limitWrites map[limitKey]domain.Limit
+	// activity holds activity added in the tx.
+	activity map[activityKey]domain.Activity
+	// exclusions holds exclusions added or saved in the tx by ID.
+	exclusions map[int]domain.Exclusion
+	// withdrawals holds withdrawals added or saved in the tx by ID.
+	withdrawals map[int]domain.Withdrawal
+	// statusReads holds the status of each committed withdrawal read in the
+	// tx. Approving and cancelling the same withdrawal concurrently conflicts
+	// even though approval does not touch the balance.
+	statusReads map[int]domain.WithdrawalStatus
+	transitions []domain.WithdrawalTransition
+	// deposits holds deposits added or saved in the tx by ID.
+	deposits map[int]domain.Deposit
+	// depositReads holds the status of each committed deposit looked up in
+	// the tx, or an empty status if there was none. Two callbacks about the
+	// same reference conflict like two writes of the same balance.
+	depositReads map[depositKey]domain.DepositStatus
+	actions      []domain.AdminAction
+	snapshots    []domain.BalanceSnapshot
+	done         bool
+}
+
+func (s *WalletStore) ListWalletIDs(_ context.Context) ([]int, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	ids := make([]int, 0, len(s.db.wallets))
+	for id := range s.db.wallets {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// SearchWallets sees committed balances and those saved in the tx.
+func (s *WalletStore) SearchWallets(ctx context.Context, filter domain.WalletFilter) ([]domain.WalletBalance, error) {
+	ids, err := s.ListWalletIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []domain.WalletBalance
+	for _, id := range ids {
+		if filter.Limit > 0 && len(res) == filter.Limit {
+			break
+		}
+		if id <= filter.AfterID {
+			continue
+		}
+		balance, err := s.GetBalance(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if matchWallet(*balance, filter) {
+			res = append(res, *balance)
+		}
+	}
+
+	return res, nil
+}
+
+func matchWallet(b domain.WalletBalance, filter domain.WalletFilter) bool {
+	return (filter.Flagged == nil || b.Flagged == *filter.Flagged) &&
+		(filter.Frozen == nil || b.Frozen == *filter.Frozen) &&
+		(filter.MinAmount == nil || b.Amount.Cmp(*filter.MinAmount) >= 0) &&
+		(filter.MaxAmount == nil || b.Amount.Cmp(*filter.MaxAmount) <= 0)
+}
+
+func (s *WalletStore) GetBalance(_ context.Context, walletID int) (*domain.WalletBalance, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	if balance, ok := s.writes[walletID]; ok {
+		return &balance, nil
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	w, ok := s.db.wallets[walletID]
+	if !ok {
+		return nil, domain.ErrWalletNotFound
+	}
+	if _, ok := s.reads[walletID]; !ok {
+		s.reads[walletID] = w.version
+	}
+
+	return &domain.WalletBalance{
+		WalletID: walletID,
+		Amount:   w.amount,
+		Bonus:    w.bonus,
+		Version:  w.version,
+		Flagged:  w.flagged,
+		Frozen:   w.frozen,
+	}, nil
+}
+
+func (s *WalletStore) SaveBalance(_ context.Context, balance *domain.WalletBalance) error {
+	if s.done {
+		return ErrTxDone
+	}
+	// Only a flagged wallet may owe real money, like in the postgres store.
+	if balance.Amount.IsNegative() && !balance.Flagged || balance.Bonus.IsNegative() {
+		return ErrNegativeBalance
+	}
+
+	s.db.mu.Lock()
+	w, ok := s.db.wallets[balance.WalletID]
+	if !ok {
+		s.db.mu.Unlock()
+		return domain.ErrWalletNotFound
+	}
+	s.saves[balance.WalletID]++
+	balance.Version = w.version + s.saves[balance.WalletID]
+	s.db.mu.Unlock()
+
+	s.writes[balance.WalletID] = *balance
+	return nil
+}
+
+func (s *WalletStore) AddDebitEntry(_ context.Context, entry *domain.DebitEntry) error {
+	if s.done {
+		return ErrTxDone
+	}
+	if entry.Amount.IsNegative() || entry.Bonus.IsNegative() {
+		return ErrNegativeEntry
+	}
+	if entry.Bonus.Cmp(entry.Amount) > 0 {
+		return ErrBonusExceedsEntry
+	}
+	if !entry.Operation.Valid() || !entry.Initiator.Valid() {
+		return ErrUnknownOperation
+	}
+	if err := s.checkWallet(entry.WalletID); err != nil {
+		return err
+	}
+	if err := s.checkReversal(entry.ReversalOf); err != nil {
+		return err
+	}
+
+	entry.ID, entry.CreatedAt = s.db.nextEntryID(), time.Now()
+
+	amount := entry.Amount
+	added := s.addEntry(domain.Entry{
+		ID:           entry.ID,
+		WalletID:     entry.WalletID,
+		Debit:        &amount,
+		Bonus:        entry.Bonus,
+		EntryDetails: entry.EntryDetails,
+		CreatedAt:    entry.CreatedAt,
+	})
+	entry.BalanceAfter, entry.BonusAfter = added.BalanceAfter, added.BonusAfter
+	return s.appendAudit(added)
+}
+
+func (s *WalletStore) AddCreditEntry(_ context.Context, entry *domain.CreditEntry) error {
+	if s.done {
+		return ErrTxDone
+	}
+	if entry.Amount.IsNegative() || entry.Bonus.IsNegative() {
+		return ErrNegativeEntry
+	}
+	if entry.Bonus.Cmp(entry.Amount) > 0 {
+		return ErrBonusExceedsEntry
+	}
+	if !entry.Operation.Valid() || !entry.Initiator.Valid() {
+		return ErrUnknownOperation
+	}
+	if err := s.checkWallet(entry.WalletID); err != nil {
+		return err
+	}
+	if err := s.checkReversal(entry.ReversalOf); err != nil {
+		return err
+	}
+
+	entry.ID, entry.CreatedAt = s.db.nextEntryID(), time.Now()
+
+	amount := entry.Amount
+	added := s.addEntry(domain.Entry{
+		ID:           entry.ID,
+		WalletID:     entry.WalletID,
+		Credit:       &amount,
+		Bonus:        entry.Bonus,
+		EntryDetails: entry.EntryDetails,
+		CreatedAt:    entry.CreatedAt,
+	})
+	entry.BalanceAfter, entry.BonusAfter = added.BalanceAfter, added.BonusAfter
+	return s.appendAudit(added)
+}
+
+// addEntry adds the entry to the tx with the balance after it, which follows
+// from the balance after the previous entry of the wallet.
+func (s *WalletStore) addEntry(entry domain.Entry) domain.Entry {
+	all := s.allEntries()
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].WalletID == entry.WalletID {
+			entry.BalanceAfter, entry.BonusAfter = all[i].BalanceAfter, all[i].BonusAfter
+			break
+		}
+	}
+
+	change := entry.Change()
+	entry.BalanceAfter, entry.BonusAfter = entry.BalanceAfter.Add(change.Amount), entry.BonusAfter.Add(change.Bonus)
+	s.entries = append(s.entries, entry)
+	return entry
+}
+
+// ListEntries sees committed entries and those added in the tx. Entries are
+// ordered by ID, which is not always the commit order, like in the postgres
+// store.
+func (s *WalletStore) ListEntries(_ context.Context, filter domain.EntryFilter) ([]domain.Entry, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	var entries []domain.Entry
+	for _, e := range s.allEntries() {
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+		if e.WalletID == filter.WalletID && e.ID > filter.AfterID && matchEntry(e, filter) {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries, nil
+}
+
+func (s *WalletStore) GetEntry(_ context.Context, entryID int) (*domain.Entry, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	for _, e := range s.allEntries() {
+		if e.ID == entryID {
+			return &e, nil
+		}
+	}
+	return nil, domain.ErrEntryNotFound
+}
+
+func (s *WalletStore) SumEntries(_ context.Context, walletID int, from, to time.Time) (domain.EntrySum, error) {
+	if s.done {
+		return domain.EntrySum{}, ErrTxDone
+	}
+
+	var sum domain.EntrySum
+	for _, e := range s.allEntries() {
+		if e.WalletID == walletID && !e.CreatedAt.Before(from) && e.CreatedAt.Before(to) {
+			change := e.Change()
+			sum.Amount, sum.Bonus = sum.Amount.Add(change.Amount), sum.Bonus.Add(change.Bonus)
+		}
+	}
+	return sum, nil
+}
+
+func (s *WalletStore) SumEntriesByDay(_ context.Context, walletID int, from, to time.Time) ([]domain.EntrySum, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	byDay := make(map[time.Time]domain.EntrySum)
+	for _, e := range s.allEntries() {
+		if e.WalletID != walletID || e.CreatedAt.Before(from) || !e.CreatedAt.Before(to) {
+			continue
+		}
+		day := e.CreatedAt.UTC().Truncate(24 * time.Hour)
+		sum, change := byDay[day], e.Change()
+		byDay[day] = domain.EntrySum{Day: day, Amount: sum.Amount.Add(change.Amount), Bonus: sum.Bonus.Add(change.Bonus)}
+	}
+
+	sums := make([]domain.EntrySum, 0, len(byDay))
+	for _, sum := range byDay {
+		sums = append(sums, sum)
+	}
+	sort.Slice(sums, func(i, j int) bool { return sums[i].Day.Before(sums[j].Day) })
+	return sums, nil
+}
+
+// GetSnapshot sees committed snapshots and those added in the tx.
+func (s *WalletStore) GetSnapshot(_ context.Context, walletID int, at time.Time) (*domain.BalanceSnapshot, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	s.db.mu.Lock()
+	all := append(append([]domain.BalanceSnapshot(nil), s.db.snapshots...), s.snapshots...)
+	s.db.mu.Unlock()
+
+	var latest *domain.BalanceSnapshot
+	for i, snapshot := range all {
+		if snapshot.WalletID == walletID && !snapshot.At.After(at) && (latest == nil || snapshot.At.After(latest.At)) {
+			latest = &all[i]
+		}
+	}
+	if latest == nil {
+		return nil, domain.ErrSnapshotNotFound
+	}
+	return latest, nil
+}
+
+func (s *WalletStore) AddSnapshot(ctx context.Context, snapshot *domain.BalanceSnapshot) error {
+	if s.done {
+		return ErrTxDone
+	}
+	if err := s.checkWallet(snapshot.WalletID); err != nil {
+		return err
+	}
+
+	// A snapshot of the same day is a conflict, like a unique violation in
+	// the postgres store.
+	existing, err := s.GetSnapshot(ctx, snapshot.WalletID, snapshot.At)
+	if err == nil && existing.At.Equal(snapshot.At) {
+		return entity.ErrTxConflict
+	}
+
+	snapshot.CreatedAt = time.Now()
+
+	s.snapshots = append(s.snapshots, *snapshot)
+	return nil
+}
+
+// appendAudit adds the record of an entry added in the tx to the audit log,
+// like the trigger of the postgres store does.
+func (s *WalletStore) appendAudit(entry domain.Entry) error {
+	var prev *domain.AuditRecord
+	if records := s.auditRecords(entry.WalletID); len(records) > 0 {
+		prev = &records[len(records)-1]
+	}
+
+	record, err := domain.NewAuditRecord(prev, entry)
+	if err != nil {
+		return err
+	}
+	s.audit = append(s.audit, record)
+	return nil
+}
+
+func (s *WalletStore) ListAuditRecords(_ context.Context, walletID int) ([]domain.AuditRecord, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+	return s.auditRecords(walletID), nil
+}
+
+// auditRecords returns committed records of the wallet and those added in the
+// tx ordered by Seq.
+func (s *WalletStore) auditRecords(walletID int) []domain.AuditRecord {
+	s.db.mu.Lock()
+	all := append(append([]domain.AuditRecord(nil), s.db.audit...), s.audit...)
+	s.db.mu.Unlock()
+
+	var records []domain.AuditRecord
+	for _, r := range all {
+		if r.WalletID == walletID {
+			records = append(records, r)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Seq < records[j].Seq })
+	return records
+}
+
+// allEntries returns committed entries and those added in the tx ordered by
+// ID, with ReversedBy filled in.
+func (s *WalletStore) allEntries() []domain.Entry {
+	s.db.mu.Lock()
+	all := append(append([]domain.Entry(nil), s.db.entries...), s.entries...)
+	s.db.mu.Unlock()
+
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+
+	reversedBy := make(map[int]int)
+	for _, e := range all {
+		if e.ReversalOf != 0 {
+			reversedBy[e.ReversalOf] = e.ID
+		}
+	}
+	for i := range all {
+		all[i].ReversedBy = reversedBy[all[i].ID]
+	}
+	return all
+}
+
+// checkReversal makes sure the entry a reversal undoes exists and is not
+// reversed yet. It does nothing if entryID is zero.
+func (s *WalletStore) checkReversal(entryID int) error {
+	if entryID == 0 {
+		return nil
+	}
+	for _, e := range s.allEntries() {
+		if e.ID != entryID {
+			continue
+		}
+		if e.ReversedBy != 0 {
+			return domain.ErrAlreadyReversed
+		}
+		return nil
+	}
+	return domain.ErrEntryNotFound
+}
+
+func (s *WalletStore) AddBonusGrant(_ context.Context, grant *domain.BonusGrant) error {
+	if s.done {
+		return ErrTxDone
+	}
+	if err := s.checkWallet(grant.WalletID); err != nil {
+		return err
+	}
+
+	grant.ID, grant.CreatedAt = s.db.nextGrantID(), time.Now()
+
+	s.grantWrites[grant.ID] = *grant
+	return nil
+}
+
+func (s *WalletStore) SaveBonusGrant(_ context.Context, grant *domain.BonusGrant) error {
+	if s.done {
+		return ErrTxDone
+	}
+
+	if _, ok := s.grantWrites[grant.ID]; !ok {
+		s.db.mu.Lock()
+		_, ok = s.db.grantIndex(grant.ID)
+		s.db.mu.Unlock()
+		if !ok {
+			return ErrGrantNotFound
+		}
+	}
+
+	s.grantWrites[grant.ID] = *grant
+	return nil
+}
+
+func (s *WalletStore) ListBonusGrants(_ context.Context, walletID int) ([]domain.BonusGrant, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	s.db.mu.Lock()
+	// Grants change together with the balance, so reading them takes part in
+	// conflict detection the same way reading the balance does.
+	if w, ok := s.db.wallets[walletID]; ok {
+		if _, ok := s.reads[walletID]; !ok {
+			s.reads[walletID] = w.version
+		}
+	}
+	var grants []domain.BonusGrant
+	for _, g := range s.db.grants {
+		if g.WalletID == walletID {
+			grants = append(grants, g)
+		}
+	}
+	s.db.mu.Unlock()
+
+	for i, g := range grants {
+		if w, ok := s.grantWrites[g.ID]; ok {
+			grants[i] = w
+		}
+	}
+	// Grants added in the tx have the highest IDs.
+	for _, g := range s.grantWrites {
+		if g.WalletID == walletID && !s.db.hasGrant(g.ID) {
+			grants = append(grants, g)
+		}
+	}
+	sort.Slice(grants, func(i, j int) bool { return grants[i].ID < grants[j].ID })
+
+	return grants, nil
+}
+
+func (s *WalletStore) GetLimits(_ context.Context, walletID int) ([]domain.Limit, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	merged := make(map[limitKey]domain.Limit)
+	s.db.mu.Lock()
+	for key, limit := range s.db.limits {
+		if key.walletID == walletID {
+			merged[key] = limit
+		}
+	}
+	s.db.mu.Unlock()
+	for key, limit := range s.limitWrites {
+		if key.walletID == walletID {
+			merged[key] = limit
+		}
+	}
+
+	var limits []domain.Limit
+	for _, limit := range merged {
+		if limit.Amount != nil || !limit.PendingAt.IsZero() {
+			limits = append(limits, limit)
+		}
+	}
+	sort.Slice(limits, func(i, j int) bool {
+		if limits[i].Kind != limits[j].Kind {
+			return limits[i].Kind < limits[j].Kind
+		}
+		return limits[i].Period < limits[j].Period
+	})
+
+	return limits, nil
+}
+
+func (s *WalletStore) SaveLimit(_ context.Context, limit *domain.Limit) error {
+	if s.done {
+		return ErrTxDone
+	}
+	if (limit.Amount != nil && limit.Amount.IsNegative()) ||
+		(limit.PendingAmount != nil && limit.PendingAmount.IsNegative()) {
+		return ErrNegativeLimit
+	}
+	if err := s.checkWallet(limit.WalletID); err != nil {
+		return err
+	}
+
+	s.limitWrites[limitKey{walletID: limit.WalletID, kind: limit.Kind, period: limit.Period}] = *limit
+	return nil
+}
+
+func (s *WalletStore) GetActivity(_ context.Context, walletID int, since time.Time) (domain.Activity, error) {
+	if s.done {
+		return domain.Activity{}, ErrTxDone
+	}
+
+	from := since.Truncate(time.Hour)
+	sum := func(res domain.Activity, activity map[activityKey]domain.Activity) domain.Activity {
+		for key, a := range activity {
+			if key.walletID == walletID && !key.hour.Before(from) {
+				res = res.Add(a)
+			}
+		}
+		return res
+	}
+
+	s.db.mu.Lock()
+	res := sum(domain.Activity{}, s.db.activity)
+	s.db.mu.Unlock()
+
+	return sum(res, s.activity), nil
+}
+
+func (s *WalletStore) AddActivity(_ context.Context, walletID int, at time.Time, activity domain.Activity) error {
+	if s.done {
+		return ErrTxDone
+	}
+	if err := s.checkWallet(walletID); err != nil {
+		return err
+	}
+
+	key := activityKey{walletID: walletID, hour: at.Truncate(time.Hour)}
+	s.activity[key] = s.activity[key].Add(activity)
+	return nil
+}
+
+func (s *WalletStore) AddExclusion(_ context.Context, exclusion *domain.Exclusion) error {
+	if s.done {
+		return ErrTxDone
+	}
+	if err := s.checkWallet(exclusion.WalletID); err != nil {
+		return err
+	}
+
+	exclusion.ID, exclusion.CreatedAt = s.db.nextExclusionID(), time.Now()
+
+	s.exclusions[exclusion.ID] = *exclusion
+	return nil
+}
+
+func (s *WalletStore) SaveExclusion(_ context.Context, exclusion *domain.Exclusion) error {
+	if s.done {
+		return ErrTxDone
+	}
+
+	current, ok := s.exclusions[exclusion.ID]
+	if !ok {
+		s.db.mu.Lock()
+		var i int
+		if i, ok = s.db.exclusionIndex(exclusion.ID); ok {
+			current = s.db.exclusions[i]
+		}
+		s.db.mu.Unlock()
+		if !ok {
+			return ErrExclusionNotFound
+		}
+	}
+
+	// Only the Lifted fields can change, like in the postgres store.
+	current.LiftedAt, current.LiftedBy, current.LiftReason = exclusion.LiftedAt, exclusion.LiftedBy, exclusion.LiftReason
+	s.exclusions[exclusion.ID] = current
+	return nil
+}
+
+func (s *WalletStore) ListExclusions(_ context.Context, walletID int) ([]domain.Exclusion, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	s.db.mu.Lock()
+	var exclusions []domain.Exclusion
+	for _, e := range s.db.exclusions {
+		if e.WalletID == walletID {
+			exclusions = append(exclusions, e)
+		}
+	}
+	s.db.mu.Unlock()
+
+	seen := make(map[int]bool, len(exclusions))
+	for i, e := range exclusions {
+		seen[e.ID] = true
+		if w, ok := s.exclusions[e.ID]; ok {
+			exclusions[i] = w
+		}
+	}
+	for _, e := range s.exclusions {
+		if e.WalletID == walletID && !seen[e.ID] {
+			exclusions = append(exclusions, e)
+		}
+	}
+	sort.Slice(exclusions, func(i, j int) bool { return exclusions[i].ID < exclusions[j].ID })
+
+	return exclusions, nil
+}
+
+func (s *WalletStore) AddWithdrawal(_ context.Context, withdrawal *domain.Withdrawal) error {
+	if s.done {
+		return ErrTxDone
+	}
+	if err := s.checkWallet(withdrawal.WalletID); err != nil {
+		return err
+	}
+	if withdrawal.Amount.IsNegative() {
+		return ErrNegativeEntry
+	}
+
+	now := time.Now()
+	withdrawal.ID, withdrawal.CreatedAt, withdrawal.UpdatedAt = s.db.nextWithdrawalID(), now, now
+
+	s.withdrawals[withdrawal.ID] = *withdrawal
+	return nil
+}
+
+func (s *WalletStore) SaveWithdrawal(ctx context.Context, withdrawal *domain.Withdrawal) error {
+	if s.done {
+		return ErrTxDone
+	}
+
+	current, err := s.GetWithdrawal(ctx, withdrawal.ID)
+	if err != nil {
+		return err
+	}
+
+	// Only the status can change, like in the postgres store.
+	current.Status, current.UpdatedAt = withdrawal.Status, time.Now()
+	withdrawal.UpdatedAt = current.UpdatedAt
+	s.withdrawals[withdrawal.ID] = *current
+	return nil
+}
+
+func (s *WalletStore) GetWithdrawal(_ context.Context, withdrawalID int) (*domain.Withdrawal, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	if w, ok := s.withdrawals[withdrawalID]; ok {
+		return &w, nil
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	i, ok := s.db.withdrawalIndex(withdrawalID)
+	if !ok {
+		return nil, domain.ErrWithdrawalNotFound
+	}
+	w := s.db.withdrawals[i]
+	if _, ok := s.statusReads[w.ID]; !ok {
+		s.statusReads[w.ID] = w.Status
+	}
+	return &w, nil
+}
+
+func (s *WalletStore) ListWithdrawals(_ context.Context, walletID int) ([]domain.Withdrawal, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	s.db.mu.Lock()
+	var withdrawals []domain.Withdrawal
+	for _, w := range s.db.withdrawals {
+		if w.WalletID == walletID {
+			withdrawals = append(withdrawals, w)
+		}
+	}
+	s.db.mu.Unlock()
+
+	seen := make(map[int]bool, len(withdrawals))
+	for i, w := range withdrawals {
+		seen[w.ID] = true
+		if tw, ok := s.withdrawals[w.ID]; ok {
+			withdrawals[i] = tw
+		}
+	}
+	for _, w := range s.withdrawals {
+		if w.WalletID == walletID && !seen[w.ID] {
+			withdrawals = append(withdrawals, w)
+		}
+	}
+	sort.Slice(withdrawals, func(i, j int) bool { return withdrawals[i].ID < withdrawals[j].ID })
+
+	return withdrawals, nil
+}
+
+func (s *WalletStore) AddWithdrawalTransition(ctx context.Context, transition *domain.WithdrawalTransition) error {
+	if s.done {
+		return ErrTxDone
+	}
+	if _, err := s.GetWithdrawal(ctx, transition.WithdrawalID); err != nil {
+		return err
+	}
+
+	s.transitions = append(s.transitions, *transition)
+	return nil
+}
+
+func (s *WalletStore) ListWithdrawalTransitions(_ context.Context, withdrawalID int) ([]domain.WithdrawalTransition, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	s.db.mu.Lock()
+	var transitions []domain.WithdrawalTransition
+	for _, t := range s.db.transitions {
+		if t.WithdrawalID == withdrawalID {
+			transitions = append(transitions, t)
+		}
+	}
+	s.db.mu.Unlock()
+
+	for _, t := range s.transitions {
+		if t.WithdrawalID == withdrawalID {
+			transitions = append(transitions, t)
+		}
+	}
+	return transitions, nil
+}
+
+func (s *WalletStore) AddDeposit(_ context.Context, deposit *domain.Deposit) error {
+	if s.done {
+		return ErrTxDone
+	}
+	if err := s.checkWallet(deposit.WalletID); err != nil {
+		return err
+	}
+	if deposit.Amount.IsNegative() {
+		return ErrNegativeEntry
+	}
+
+	now := time.Now()
+	deposit.ID, deposit.CreatedAt, deposit.UpdatedAt = s.db.nextDepositID(), now, now
+
+	s.deposits[deposit.ID] = *deposit
+	return nil
+}
+
+func (s *WalletStore) SaveDeposit(ctx context.Context, deposit *domain.Deposit) error {
+	if s.done {
+		return ErrTxDone
+	}
+
+	current, err := s.GetDeposit(ctx, deposit.Provider, deposit.Reference)
+	if err != nil {
+		return err
+	}
+
+	// Only the status and the entries can change, like in the postgres store.
+	current.Status, current.EntryID, current.ReversalEntryID = deposit.Status, deposit.EntryID, deposit.ReversalEntryID
+	current.UpdatedAt = time.Now()
+	deposit.UpdatedAt = current.UpdatedAt
+	s.deposits[current.ID] = *current
+	return nil
+}
+
+func (s *WalletStore) GetDeposit(_ context.Context, provider, reference string) (*domain.Deposit, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	key := depositKey{provider: provider, reference: reference}
+	for _, d := range s.deposits {
+		if d.Provider == provider && d.Reference == reference {
+			return &d, nil
+		}
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	i, ok := s.db.depositIndex(key)
+	if _, read := s.depositReads[key]; !read {
+		s.depositReads[key] = ""
+		if ok {
+			s.depositReads[key] = s.db.deposits[i].Status
+		}
+	}
+	if !ok {
+		return nil, domain.ErrDepositNotFound
+	}
+	d := s.db.deposits[i]
+	return &d, nil
+}
+
+func (s *WalletStore) AddAdminAction(_ context.Context, action *domain.AdminAction) error {
+	if s.done {
+		return ErrTxDone
+	}
+	if action.Actor == "" {
+		return ErrActorRequired
+	}
+	if err := s.checkWallet(action.WalletID); err != nil {
+		return err
+	}
+
+	action.ID, action.CreatedAt = s.db.nextActionID(), time.Now()
+
+	s.actions = append(s.actions, *action)
+	return nil
+}
+
+func (s *WalletStore) ListAdminActions(_ context.Context, filter domain.AdminActionFilter) ([]domain.AdminAction, error) {
+	if s.done {
+		return nil, ErrTxDone
+	}
+
+	s.db.mu.Lock()
+	all := append(append([]domain.AdminAction(nil), s.db.actions...), s.actions...)
+	s.db.mu.Unlock()
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+
+	var actions []domain.AdminAction
+	for _, a := range all {
+		if filter.Limit > 0 && len(actions) == filter.Limit {
+			break
+		}
+		if a.ID > filter.AfterID &&
+			(filter.WalletID == 0 || a.WalletID == filter.WalletID) &&
+			(filter.Actor == "" || a.Actor == filter.Actor) &&
+			(filter.Kind == "" || a.Kind == filter.Kind) {
+			actions = append(actions, a)
+		}
+	}
+
+	return actions, nil
+}
+
+func (s *WalletStore) Commit(_ context.Context) error {
+	if s.done {
+		return ErrTxDone
+	}
+	s.done = true
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for walletID, version := range s.reads {
+		if s.db.wallets[walletID].version != version {
+			return entity.ErrTxConflict
+		}
+	}
+	for id, status := range s.statusReads {
+		if i, _ := s.db.withdrawalIndex(id); s.db.withdrawals[i].Status != status {
+			return entity.ErrTxConflict
+		}
+	}
+	for key, status := range s.depositReads {
+		var current domain.DepositStatus
+		if i, ok := s.db.depositIndex(key); ok {
+			current = s.db.deposits[i].Status
+		}
+		if current != status {
+			return entity.ErrTxConflict
+		}
+	}
+
+	// An entry may only be reversed once. A reversal committed since it was
+	// checked is a conflict, retrying finds it.
+	for _, e := range s.entries {
+		if e.ReversalOf == 0 {
+			continue
+		}
+		for _, committed := range s.db.entries {
+			if committed.ReversalOf == e.ReversalOf {
+				return entity.ErrTxConflict
+			}
+		}
+	}
+
+	// Two txs that extend the audit log of the same wallet would fork it.
+	for _, r := range s.audit {
+		for _, committed := range s.db.audit {
+			if committed.WalletID == r.WalletID && committed.Seq == r.Seq {
+				return entity.ErrTxConflict
+			}
+		}
+	}
+
+	// Two txs that snapshot the same day of a wallet conflict.
+	for _, snapshot := range s.snapshots {
+		for _, committed := range s.db.snapshots {
+			if committed.WalletID == snapshot.WalletID && committed.At.Equal(snapshot.At) {
+				return entity.ErrTxConflict
+			}
+		}
+	}
+
+	for walletID, balance := range s.writes {
+		w := s.db.wallets[walletID]
+		w.amount = balance.Amount
+		w.bonus = balance.Bonus
+		w.flagged = balance.Flagged
+		w.frozen = balance.Frozen
+		w.version += s.saves[walletID]
+	}
+
+	s.db.entries = append(s.db.entries, s.entries...)
+	s.db.audit = append(s.db.audit, s.audit...)
+
+	ids := make([]int, 0, len(s.grantWrites))
+	for id := range s.grantWrites {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		if i, ok := s.db.grantIndex(id); ok {
+			s.db.grants[i] = s.grantWrites[id]
+		} else {
+			s.db.grants = append(s.db.grants, s.grantWrites[id])
+		}
+	}
+
+	for key, limit := range s.limitWrites {
+		if limit.Amount == nil && limit.PendingAt.IsZero() {
+			delete(s.db.limits, key)
+		} else {
+			s.db.limits[key] = limit
+		}
+	}
+
+	for key, activity := range s.activity {
+		s.db.activity[key] = s.db.activity[key].Add(activity)
+	}
+
+	ids = ids[:0]
+	for id := range s.exclusions {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		if i, ok := s.db.exclusionIndex(id); ok {
+			s.db.exclusions[i] = s.exclusions[id]
+		} else {
+			s.db.exclusions = append(s.db.exclusions, s.exclusions[id])
+		}
+	}
+
+	ids = ids[:0]
+	for id := range s.withdrawals {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		if i, ok := s.db.withdrawalIndex(id); ok {
+			s.db.withdrawals[i] = s.withdrawals[id]
+		} else {
+			s.db.withdrawals = append(s.db.withdrawals, s.withdrawals[id])
+		}
+	}
+
+	s.db.transitions = append(s.db.transitions, s.transitions...)
+	s.db.actions = append(s.db.actions, s.actions...)
+	s.db.snapshots = append(s.db.snapshots, s.snapshots...)
+
+	ids = ids[:0]
+	for id := range s.deposits {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		d := s.deposits[id]
+		if i, ok := s.db.depositIndex(depositKey{provider: d.Provider, reference: d.Reference}); ok {
+			s.db.deposits[i] = d
+		} else {
+			s.db.deposits = append(s.db.deposits, d)
+		}
+	}
+
+	return nil
+}
+
+func (s *WalletStore) Rollback(_ context.Context) error {
+	if s.done {
+		return ErrTxDone
+	}
+	s.done = true
+	return nil
+}
+
+// grantIndex must be called with f.mu held.
+func (f *WalletStoreTxFactory) grantIndex(id int) (int, bool) {
+	for i, g := range f.grants {
+		if g.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// exclusionIndex must be called with f.mu held.
+func (f *WalletStoreTxFactory) exclusionIndex(id int) (int, bool) {
+	for i, e := range f.exclusions {
+		if e.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// withdrawalIndex must be called with f.mu held.
+func (f *WalletStoreTxFactory) withdrawalIndex(id int) (int, bool) {
+	for i, w := range f.withdrawals {
+		if w.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// depositIndex must be called with f.mu held.
+func (f *WalletStoreTxFactory) depositIndex(key depositKey) (int, bool) {
+	for i, d := range f.deposits {
+		if d.Provider == key.provider && d.Reference == key.reference {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (f *WalletStoreTxFactory) hasGrant(id int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.grantIndex(id)
+	return ok
+}
+
+func matchEntry(e domain.Entry, filter domain.EntryFilter) bool {
+	if len(filter.Operations) > 0 && !slices.Contains(filter.Operations, e.Operation) {
+		return false
+	}
+	if filter.Reference != "" && e.Reference != filter.Reference {
+		return false
+	}
+	if filter.Initiator != "" && e.Initiator != filter.Initiator {
+		return false
+	}
+	if !filter.From.IsZero() && e.CreatedAt.Before(filter.From) {
+		return false
+	}
+	if !filter.To.IsZero() && !e.CreatedAt.Before(filter.To) {
+		return false
+	}
+	if len(filter.Metadata) == 0 {
+		return true
+	}
+
+	var metadata map[string]any
+	if err := json.Unmarshal(e.Metadata, &metadata); err != nil {
+		return false
+	}
+	for key, value := range filter.Metadata {
+		if v, ok := metadata[key].(string); !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *WalletStore) checkWallet(walletID int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.wallets[walletID]; !ok {
+		return domain.ErrWalletNotFound
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/pprishchepa/go-casino-example/internal/storage/memory"
+	"github.com/pprishchepa/go-casino-example/internal/storage/storagetest"
+)
+
+func TestWalletStore(t *testing.T) {
+	storagetest.Run(t, harness{memory.NewWalletStoreTxFactory()})
+}
+
+type harness struct {
+	factory *memory.WalletStoreTxFactory
+}
+
+func (h harness) NewTx(ctx context.Context) (service.WalletStoreTx, error) {
+	return h.factory.NewTx(ctx)
+}
+
+func (h harness) CreateWallet(_ *testing.T, amount money.Money) int {
+	return h.factory.CreateWallet(amount)
+}
+
+func (h harness) ListEntries(_ *testing.T, walletID int) []storagetest.Entry {
+	var res []storagetest.Entry
+	for _, e := range h.factory.Entries(walletID) {
+		res = append(res, storagetest.Entry{Debit: e.Debit, Credit: e.Credit, Bonus: e.Bonus})
+	}
+	return res
+}
@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+)
+
+type BatchStore struct {
+	db *pgxpool.Pool
+}
+
+func NewBatchStore(db *pgxpool.Pool) *BatchStore {
+	return &BatchStore{db: db}
+}
+
+func (s BatchStore) CreateBatch(ctx context.Context, batch *entity.Batch) error {
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		sql := `
+			INSERT INTO batch (mode, status) 
+			VALUES ($1, $2)
+			RETURNING id, created_at, updated_at`
+
+		err := tx.QueryRow(ctx, sql, batch.Mode, batch.Status).
+			Scan(&batch.ID, &batch.CreatedAt, &batch.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("insert batch: %w", err)
+		}
+
+		rows := make([][]any, 0, len(batch.Items))
+		for i, item := range batch.Items {
+			rows = append(rows, []any{batch.ID, i, item.WalletID, item.Operation, item.Amount, item.Status})
+		}
+
+		_, err = tx.CopyFrom(ctx,
+			pgx.Identifier{"batch_item"},
+			[]string{"batch_id", "seq", "wallet_id", "operation", "amount", "status"},
+			pgx.CopyFromRows(rows))
+		if err != nil {
+			return fmt.Errorf("copy batch items: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (s BatchStore) UpdateBatch(ctx context.Context, batch *entity.Batch, from entity.BatchStatus) error {
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		sql := `
+			UPDATE batch SET status = $2, updated_at = NOW() 
+			WHERE id = $1 AND status = $3
+			RETURNING updated_at`
+
+		if err := tx.QueryRow(ctx, sql, batch.ID, batch.Status, from).Scan(&batch.UpdatedAt); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return s.recognizeMissingBatch(ctx, tx, batch.ID)
+			}
+			return fmt.Errorf("update batch: %w", err)
+		}
+
+		seqs := make([]int, len(batch.Items))
+		statuses := make([]string, len(batch.Items))
+		errs := make([]*string, len(batch.Items))
+		for i, item := range batch.Items {
+			seqs[i] = i
+			statuses[i] = string(item.Status)
+			if item.Error != "" {
+				errs[i] = &item.Error
+			}
+		}
+
+		sql = `
+			UPDATE batch_item SET status = u.status, error = u.error
+			FROM UNNEST($2::INT[], $3::TEXT[], $4::TEXT[]) AS u (seq, status, error)
+			WHERE batch_item.batch_id = $1 AND batch_item.seq = u.seq`
+
+		if _, err := tx.Exec(ctx, sql, batch.ID, seqs, statuses, errs); err != nil {
+			return fmt.Errorf("update batch items: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// recognizeMissingBatch tells a batch that does not exist from one whose
+// status has changed.
+func (s BatchStore) recognizeMissingBatch(ctx context.Context, tx pgx.Tx, batchID int) error {
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM batch WHERE id = $1)`, batchID).Scan(&exists); err != nil {
+		return fmt.Errorf("select batch: %w", err)
+	}
+	if exists {
+		return entity.ErrBatchStatusChanged
+	}
+	return entity.ErrBatchNotFound
+}
+
+func (s BatchStore) TouchBatch(ctx context.Context, batchID int) error {
+	sql := `UPDATE batch SET updated_at = NOW() WHERE id = $1 AND status = 'running'`
+
+	tag, err := s.db.Exec(ctx, sql, batchID)
+	if err != nil {
+		return fmt.Errorf("update batch: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrBatchStatusChanged
+	}
+
+	return nil
+}
+
+func (s BatchStore) GetBatch(ctx context.Context, batchID int) (*entity.Batch, error) {
+	sql := `SELECT mode, status, created_at, updated_at FROM batch WHERE id = $1`
+
+	batch := entity.Batch{ID: batchID}
+
+	err := s.db.QueryRow(ctx, sql, batchID).Scan(&batch.Mode, &batch.Status, &batch.CreatedAt, &batch.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrBatchNotFound
+		}
+		return nil, fmt.Errorf("query batch: %w", err)
+	}
+
+	sql = `
+		SELECT wallet_id, operation, amount, status, COALESCE(error, '')
+		FROM batch_item
+		WHERE batch_id = $1
+		ORDER BY seq`
+
+	rows, err := s.db.Query(ctx, sql, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("query batch items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.BatchItem
+		if err := rows.Scan(&item.WalletID, &item.Operation, &item.Amount, &item.Status, &item.Error); err != nil {
+			return nil, fmt.Errorf("scan batch item: %w", err)
+		}
+		batch.Items = append(batch.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read batch items: %w", err)
+	}
+
+	return &batch, nil
+}
+
+func (s BatchStore) ClaimStaleBatches(ctx context.Context, staleAfter time.Duration) ([]int, error) {
+	sql := `
+		UPDATE batch SET status = 'running', updated_at = NOW()
+		WHERE status = 'pending' AND updated_at < NOW() - $1::FLOAT8 * INTERVAL '1 second'
+		RETURNING id`
+
+	rows, err := s.db.Query(ctx, sql, staleAfter.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("update batches: %w", err)
+	}
+
+	batchIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("collect rows: %w", err)
+	}
+
+	return batchIDs, nil
+}
+
+func (s BatchStore) FailStaleBatches(ctx context.Context, staleAfter time.Duration, reason string) ([]int, error) {
+	var batchIDs []int
+
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		sql := `
+			UPDATE batch SET status = 'failed', updated_at = NOW()
+			WHERE status = 'running' AND updated_at < NOW() - $1::FLOAT8 * INTERVAL '1 second'
+			RETURNING id`
+
+		rows, err := tx.Query(ctx, sql, staleAfter.Seconds())
+		if err != nil {
+			return fmt.Errorf("update batches: %w", err)
+		}
+		if batchIDs, err = pgx.CollectRows(rows, pgx.RowTo[int]); err != nil {
+			return fmt.Errorf("collect rows: %w", err)
+		}
+
+		sql = `
+			UPDATE batch_item SET error = $2
+			WHERE batch_id = ANY($1) AND status = 'pending'`
+
+		if _, err := tx.Exec(ctx, sql, batchIDs, reason); err != nil {
+			return fmt.Errorf("update batch items: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return batchIDs, nil
+}
@@ -0,0 +1,114 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchStore_StaleBatches(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	store := postgres.NewBatchStore(db)
+
+	create := func(status entity.BatchStatus, age time.Duration) int {
+		t.Helper()
+		batch := entity.Batch{
+			Mode:   entity.BatchModeBestEffort,
+			Status: status,
+			Items: []entity.BatchItem{{
+				WalletID:  1,
+				Operation: entity.BatchOperationDebit,
+				Amount:    money.NewFromInt(100),
+				Status:    entity.BatchItemStatusPending,
+			}},
+		}
+		require.NoError(t, store.CreateBatch(ctx, &batch))
+		_, err := db.Exec(ctx, `UPDATE batch SET updated_at = NOW() - $2::FLOAT8 * INTERVAL '1 second' WHERE id = $1`,
+			batch.ID, age.Seconds())
+		require.NoError(t, err)
+		return batch.ID
+	}
+
+	stalePending := create(entity.BatchStatusPending, time.Hour)
+	freshPending := create(entity.BatchStatusPending, 0)
+	staleRunning := create(entity.BatchStatusRunning, time.Hour)
+	freshRunning := create(entity.BatchStatusRunning, 0)
+
+	failed, err := store.FailStaleBatches(ctx, 10*time.Minute, "interrupted")
+	require.NoError(t, err)
+	assert.Contains(t, failed, staleRunning)
+	assert.NotContains(t, failed, freshRunning)
+
+	claimed, err := store.ClaimStaleBatches(ctx, 10*time.Minute)
+	require.NoError(t, err)
+	assert.Contains(t, claimed, stalePending)
+	assert.NotContains(t, claimed, freshPending)
+
+	// A batch is claimed once.
+	claimed, err = store.ClaimStaleBatches(ctx, 10*time.Minute)
+	require.NoError(t, err)
+	assert.NotContains(t, claimed, stalePending)
+
+	for batchID, want := range map[int]entity.BatchStatus{
+		stalePending: entity.BatchStatusRunning,
+		freshPending: entity.BatchStatusPending,
+		staleRunning: entity.BatchStatusFailed,
+		freshRunning: entity.BatchStatusRunning,
+	} {
+		batch, err := store.GetBatch(ctx, batchID)
+		require.NoError(t, err)
+		assert.Equal(t, want, batch.Status, "batch %d", batchID)
+	}
+
+	batch, err := store.GetBatch(ctx, staleRunning)
+	require.NoError(t, err)
+	assert.Equal(t, entity.BatchItemStatusPending, batch.Items[0].Status)
+	assert.Equal(t, "interrupted", batch.Items[0].Error)
+}
+
+func TestBatchStore_UpdateBatchOfChangedStatus(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	store := postgres.NewBatchStore(db)
+
+	batch := entity.Batch{
+		Mode:   entity.BatchModeBestEffort,
+		Status: entity.BatchStatusPending,
+		Items: []entity.BatchItem{{
+			WalletID:  1,
+			Operation: entity.BatchOperationDebit,
+			Amount:    money.NewFromInt(100),
+			Status:    entity.BatchItemStatusPending,
+		}},
+	}
+	require.NoError(t, store.CreateBatch(ctx, &batch))
+
+	batch.Status = entity.BatchStatusRunning
+	require.NoError(t, store.UpdateBatch(ctx, &batch, entity.BatchStatusPending))
+	require.NoError(t, store.TouchBatch(ctx, batch.ID))
+
+	// Another process fails the batch as abandoned, and the result of this
+	// one does not overwrite it.
+	_, err := db.Exec(ctx, `UPDATE batch SET updated_at = NOW() - INTERVAL '1 hour' WHERE id = $1`, batch.ID)
+	require.NoError(t, err)
+	_, err = store.FailStaleBatches(ctx, 10*time.Minute, "interrupted")
+	require.NoError(t, err)
+
+	require.ErrorIs(t, store.TouchBatch(ctx, batch.ID), entity.ErrBatchStatusChanged)
+	batch.Status = entity.BatchStatusCompleted
+	require.ErrorIs(t, store.UpdateBatch(ctx, &batch, entity.BatchStatusRunning), entity.ErrBatchStatusChanged)
+
+	got, err := store.GetBatch(ctx, batch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.BatchStatusFailed, got.Status)
+
+	batch.ID = -1
+	require.ErrorIs(t, store.UpdateBatch(ctx, &batch, entity.BatchStatusRunning), entity.ErrBatchNotFound)
+}
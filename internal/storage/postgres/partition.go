@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PartitionStore manages the monthly partitions of wallet_entry. A partition
+// is named after the UTC month it covers, e.g. wallet_entry_2024_06.
+type PartitionStore struct {
+	db *pgxpool.Pool
+}
+
+func NewPartitionStore(db *pgxpool.Pool) *PartitionStore {
+	return &PartitionStore{db: db}
+}
+
+// AddPartition adds the partition of the month that starts at month, unless it
+// exists, live or archived. Entries of the month in the default partition are
+// moved into the new one. It returns whether it added the partition and how
+// many entries it moved.
+func (s PartitionStore) AddPartition(ctx context.Context, month time.Time) (bool, int64, error) {
+	var (
+		added bool
+		moved int64
+	)
+	if err := s.db.QueryRow(ctx, `SELECT * FROM wallet_entry_add_partition($1)`, month).Scan(&added, &moved); err != nil {
+		return false, 0, fmt.Errorf("add partition: %w", err)
+	}
+
+	return added, moved, nil
+}
+
+// CountDefaultEntries returns how many entries are in the default partition,
+// i.e. in no month that has a partition.
+func (s PartitionStore) CountDefaultEntries(ctx context.Context) (int64, error) {
+	var count int64
+	if err := s.db.QueryRow(ctx, `SELECT COUNT(*) FROM wallet_entry_default`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count entries: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListPartitions returns the months of the partitions new entries can still
+// go to, oldest first. The default partition is not one of them.
+func (s PartitionStore) ListPartitions(ctx context.Context) ([]time.Time, error) {
+	sql := `
+		SELECT to_date(substring(c.relname FROM '^wallet_entry_(\d{4}_\d{2})$'), 'YYYY_MM')
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'wallet_entry'::REGCLASS
+		  AND c.relname ~ '^wallet_entry_\d{4}_\d{2}$'
+		ORDER BY 1`
+
+	rows, err := s.db.Query(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("select partitions: %w", err)
+	}
+
+	months, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (time.Time, error) {
+		var month time.Time
+		err := row.Scan(&month)
+		return month.UTC(), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect partitions: %w", err)
+	}
+
+	return months, nil
+}
+
+// ExportPartition writes the entries of the month as CSV with a header, in the
+// order of their IDs, and returns how many it wrote.
+func (s PartitionStore) ExportPartition(ctx context.Context, month time.Time, w io.Writer) (int64, error) {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("acquire conn: %w", err)
+	}
+	defer conn.Release()
+
+	sql := `COPY (SELECT * FROM ` + partitionName(month) + ` ORDER BY id) TO STDOUT WITH (FORMAT csv, HEADER)`
+
+	tag, err := conn.Conn().PgConn().CopyTo(ctx, w, sql)
+	if err != nil {
+		return 0, fmt.Errorf("copy: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ArchivePartition moves the partition of the month from wallet_entry to
+// wallet_entry_archive. Its entries stay in the history, but new entries can
+// no longer go to the month.
+//
+// Attaching a table as a partition scans it to check that it fits the range,
+// unless a check constraint already proves that. The constraint is added
+// without a scan and validated in a statement of its own, which does not
+// block reads and writes, so that the ledger is only locked for as long as
+// the partition is moved.
+func (s PartitionStore) ArchivePartition(ctx context.Context, month time.Time) error {
+	month = month.UTC()
+	name := partitionName(month)
+	from, to := timestampLiteral(month), timestampLiteral(month.AddDate(0, 1, 0))
+	constraint := pgx.Identifier{"wallet_entry_" + month.Format("2006_01") + "_range"}.Sanitize()
+
+	for _, sql := range []string{
+		`ALTER TABLE ` + name + ` DROP CONSTRAINT IF EXISTS ` + constraint,
+		`ALTER TABLE ` + name + ` ADD CONSTRAINT ` + constraint +
+			` CHECK (created_at >= ` + from + ` AND created_at < ` + to + `) NOT VALID`,
+		`ALTER TABLE ` + name + ` VALIDATE CONSTRAINT ` + constraint,
+	} {
+		if _, err := s.db.Exec(ctx, sql); err != nil {
+			return fmt.Errorf("constrain partition: %w", err)
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, sql := range []string{
+		// Rather fail than queue up every entry behind the lock.
+		`SET LOCAL lock_timeout = '5s'`,
+		`ALTER TABLE wallet_entry DETACH PARTITION ` + name,
+		`ALTER TABLE wallet_entry_archive ATTACH PARTITION ` + name + ` FOR VALUES FROM (` + from + `) TO (` + to + `)`,
+	} {
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return fmt.Errorf("move partition: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+func partitionName(month time.Time) string {
+	return pgx.Identifier{"wallet_entry_" + month.UTC().Format("2006_01")}.Sanitize()
+}
+
+// timestampLiteral quotes t for DDL, which takes no parameters.
+func timestampLiteral(t time.Time) string {
+	return "'" + t.UTC().Format(time.RFC3339) + "'"
+}
@@ -0,0 +1,171 @@
+package postgres_test
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionStore(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	h := harness{db: db, factory: postgres.NewWalletStoreTxFactory(db)}
+	walletID := h.CreateWallet(t, money.NewFromInt(0))
+	store := postgres.NewPartitionStore(db)
+
+	// Partitions outlive the test, so every run takes a month of its own far
+	// in the future.
+	month := time.Date(2500, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, int(time.Now().UnixNano()%100000), 0)
+
+	added, moved, err := store.AddPartition(ctx, month)
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.Zero(t, moved)
+	added, _, err = store.AddPartition(ctx, month)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	_, _, err = store.AddPartition(ctx, month.Add(time.Hour))
+	require.Error(t, err, "partitions start at a month")
+
+	months, err := store.ListPartitions(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, months, month)
+
+	var entryID int
+	err = db.QueryRow(ctx, `
+		INSERT INTO wallet_entry (wallet_id, debit_amount, operation, initiator, balance_after, bonus_balance_after, created_at)
+		VALUES ($1, 100, 'win', 'service', 100, 0, $2)
+		RETURNING id`, walletID, month.Add(time.Hour)).Scan(&entryID)
+	require.NoError(t, err)
+
+	_, err = db.Exec(ctx, `TRUNCATE wallet_entry_`+month.Format("2006_01"))
+	require.Error(t, err, "partitions are append-only too")
+
+	var buf bytes.Buffer
+	rows, err := store.ExportPartition(ctx, month, &buf)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, rows)
+	assert.Contains(t, buf.String(), strconv.Itoa(entryID))
+
+	require.NoError(t, store.ArchivePartition(ctx, month))
+
+	months, err = store.ListPartitions(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, months, month)
+
+	// An archived month is not added again.
+	added, _, err = store.AddPartition(ctx, month)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	// The history still has the archived entry, and the next entry of the
+	// wallet continues from its balance.
+	tx, err := h.NewTx(ctx)
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	entry, err := tx.GetEntry(ctx, entryID)
+	require.NoError(t, err)
+	assert.Equal(t, walletID, entry.WalletID)
+
+	win := &domain.DebitEntry{WalletID: walletID, Amount: money.NewFromInt(50), EntryDetails: domain.EntryDetails{
+		Operation: domain.OperationWin,
+		Initiator: domain.InitiatorService,
+	}}
+	require.NoError(t, tx.AddDebitEntry(ctx, win))
+	assert.Equal(t, "0.150", win.BalanceAfter.String())
+
+	_, err = db.Exec(ctx, `UPDATE wallet_entry_archive SET debit_amount = 200 WHERE id = $1`, entryID)
+	require.Error(t, err, "archived entries are append-only too")
+}
+
+func TestPartitionStore_AddPartitionOfDefaultEntries(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	h := harness{db: db, factory: postgres.NewWalletStoreTxFactory(db)}
+	walletID := h.CreateWallet(t, money.NewFromInt(0))
+	store := postgres.NewPartitionStore(db)
+
+	month := time.Date(2500, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, int(time.Now().UnixNano()%100000), 0)
+	addEntry := func(at time.Time) int {
+		t.Helper()
+		var id int
+		err := db.QueryRow(ctx, `
+			INSERT INTO wallet_entry (wallet_id, debit_amount, operation, initiator, balance_after, bonus_balance_after, created_at)
+			VALUES ($1, 100, 'win', 'service', 0, 0, $2)
+			RETURNING id`, walletID, at).Scan(&id)
+		require.NoError(t, err)
+		return id
+	}
+	partitionOf := func(entryID int) string {
+		t.Helper()
+		var name string
+		err := db.QueryRow(ctx, `SELECT tableoid::REGCLASS::TEXT FROM wallet_entry WHERE id = $1`, entryID).Scan(&name)
+		require.NoError(t, err)
+		return name
+	}
+	balanceOf := func(entryID int) int {
+		t.Helper()
+		var balance int
+		require.NoError(t, db.QueryRow(ctx, `SELECT balance_after FROM wallet_entry WHERE id = $1`, entryID).Scan(&balance))
+		return balance
+	}
+	countAudit := func() int {
+		t.Helper()
+		var n int
+		require.NoError(t, db.QueryRow(ctx, `SELECT COUNT(*) FROM wallet_audit WHERE wallet_id = $1`, walletID).Scan(&n))
+		return n
+	}
+
+	// The month has no partition yet, so its entries go to the default one,
+	// and so does the entry of the month after, which gets no partition here.
+	first, second := addEntry(month.Add(time.Hour)), addEntry(month.Add(48*time.Hour))
+	later := addEntry(month.AddDate(0, 1, 0))
+	require.Equal(t, "wallet_entry_default", partitionOf(first))
+	audited, balance := countAudit(), balanceOf(second)
+
+	count, err := store.CountDefaultEntries(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, int64(3))
+
+	added, moved, err := store.AddPartition(ctx, month)
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.EqualValues(t, 2, moved)
+
+	name := "wallet_entry_" + month.Format("2006_01")
+	assert.Equal(t, name, partitionOf(first))
+	assert.Equal(t, name, partitionOf(second))
+	assert.Equal(t, "wallet_entry_default", partitionOf(later))
+
+	// Moving the entries neither audits them again nor changes them.
+	assert.Equal(t, audited, countAudit())
+	assert.Equal(t, balance, balanceOf(second))
+
+	// Both partitions are append-only again.
+	_, err = db.Exec(ctx, `DELETE FROM wallet_entry WHERE id = $1`, first)
+	require.Error(t, err)
+	_, err = db.Exec(ctx, `DELETE FROM wallet_entry WHERE id = $1`, later)
+	require.Error(t, err)
+	_, err = db.Exec(ctx, `TRUNCATE `+name)
+	require.Error(t, err)
+	_, err = db.Exec(ctx, `TRUNCATE wallet_entry_default`)
+	require.Error(t, err)
+
+	// New entries of the month go to its partition and are audited.
+	assert.Equal(t, name, partitionOf(addEntry(month.Add(72*time.Hour))))
+	assert.Equal(t, audited+1, countAudit())
+
+	_, moved, err = store.AddPartition(ctx, month.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, moved)
+}
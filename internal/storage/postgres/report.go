@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+)
+
+type ReportStore struct {
+	db *pgxpool.Pool
+}
+
+func NewReportStore(db *pgxpool.Pool) *ReportStore {
+	return &ReportStore{db: db}
+}
+
+// RefreshStart returns the first day whose summary may still change: the
+// first day refreshed less than delay after it ended, else the day after the
+// last summary, else the day of the first entry. It returns false if there
+// are no entries at all.
+func (s ReportStore) RefreshStart(ctx context.Context, delay time.Duration) (time.Time, bool, error) {
+	sql := `
+		SELECT COALESCE(
+		    (SELECT MIN(day)
+		     FROM report_daily_summary
+		     WHERE refreshed_at < ((day + INTERVAL '1 day') AT TIME ZONE 'UTC') + $1::FLOAT8 * INTERVAL '1 second'),
+		    (SELECT MAX(day) + 1 FROM report_daily_summary),
+		    (SELECT (MIN(created_at) AT TIME ZONE 'UTC')::DATE FROM wallet_entry_history))`
+
+	var day *time.Time
+	if err := s.db.QueryRow(ctx, sql, delay.Seconds()).Scan(&day); err != nil {
+		return time.Time{}, false, fmt.Errorf("select refresh start: %w", err)
+	}
+	if day == nil {
+		return time.Time{}, false, nil
+	}
+
+	return *day, true, nil
+}
+
+// RefreshSummaries aggregates the entries of every day in [from, to) per
+// brand and currency of their wallets and replaces the summaries of those
+// days. Every brand and currency that has a wallet gets a summary of every
+// day. from and to must be midnights UTC.
+func (s ReportStore) RefreshSummaries(ctx context.Context, from, to time.Time) error {
+	sql := `
+		WITH days AS (
+		    SELECT g::DATE AS day
+		    FROM generate_series($1::TIMESTAMPTZ AT TIME ZONE 'UTC',
+		                         $2::TIMESTAMPTZ AT TIME ZONE 'UTC' - INTERVAL '1 day',
+		                         INTERVAL '1 day') AS g
+		),
+		segments AS (
+		    SELECT DISTINCT brand, currency FROM wallet
+		),
+		entries AS (
+		    SELECT (e.created_at AT TIME ZONE 'UTC')::DATE                AS day,
+		           w.brand,
+		           w.currency,
+		           COALESCE(o.operation, e.operation)                     AS operation,
+		           CASE WHEN e.reversal_of IS NULL THEN 1 ELSE -1 END     AS sign,
+		           COALESCE(e.debit_amount, e.credit_amount)::BIGINT      AS amount,
+		           COALESCE(e.debit_amount, -e.credit_amount)::BIGINT     AS change
+		    FROM wallet_entry_history e
+		    JOIN wallet w ON w.id = e.wallet_id
+		    LEFT JOIN wallet_entry_history o ON o.id = e.reversal_of
+		    WHERE e.created_at >= $1 AND e.created_at < $2
+		)
+		INSERT INTO report_daily_summary (
+		    day, brand, currency, bets, wins, refunds, deposits, chargebacks, withdrawals,
+		    bonus_granted, bonus_converted, bonus_released, adjustments, entries, refreshed_at)
+		SELECT d.day,
+		       g.brand,
+		       g.currency,
+		       COALESCE(SUM(sign * amount) FILTER (WHERE operation = 'bet'), 0),
+		       COALESCE(SUM(sign * amount) FILTER (WHERE operation = 'win'), 0),
+		       COALESCE(SUM(sign * amount) FILTER (WHERE operation = 'refund'), 0),
+		       COALESCE(SUM(sign * amount) FILTER (WHERE operation = 'deposit'), 0),
+		       COALESCE(SUM(sign * amount) FILTER (WHERE operation = 'chargeback'), 0),
+		       COALESCE(SUM(sign * amount) FILTER (WHERE operation = 'withdrawal'), 0) -
+		       COALESCE(SUM(sign * amount) FILTER (WHERE operation = 'withdrawal_release'), 0),
+		       COALESCE(SUM(sign * amount) FILTER (WHERE operation = 'bonus_grant'), 0),
+		       COALESCE(SUM(sign * amount) FILTER (WHERE operation = 'bonus_conversion'), 0),
+		       COALESCE(SUM(sign * amount) FILTER (WHERE operation = 'bonus_release'), 0),
+		       COALESCE(SUM(change) FILTER (WHERE operation = 'adjustment'), 0),
+		       COUNT(e.day),
+		       NOW()
+		FROM days d
+		CROSS JOIN segments g
+		LEFT JOIN entries e ON e.day = d.day AND e.brand = g.brand AND e.currency = g.currency
+		GROUP BY d.day, g.brand, g.currency
+		ON CONFLICT (day, brand, currency) DO UPDATE SET
+		    bets            = EXCLUDED.bets,
+		    wins            = EXCLUDED.wins,
+		    refunds         = EXCLUDED.refunds,
+		    deposits        = EXCLUDED.deposits,
+		    chargebacks     = EXCLUDED.chargebacks,
+		    withdrawals     = EXCLUDED.withdrawals,
+		    bonus_granted   = EXCLUDED.bonus_granted,
+		    bonus_converted = EXCLUDED.bonus_converted,
+		    bonus_released  = EXCLUDED.bonus_released,
+		    adjustments     = EXCLUDED.adjustments,
+		    entries         = EXCLUDED.entries,
+		    refreshed_at    = EXCLUDED.refreshed_at`
+
+	if _, err := s.db.Exec(ctx, sql, from, to); err != nil {
+		return fmt.Errorf("upsert daily summaries: %w", err)
+	}
+
+	return nil
+}
+
+// ListSummaries returns the summaries of the days in [from, to), oldest
+// first, and of each day by brand and currency.
+func (s ReportStore) ListSummaries(ctx context.Context, from, to time.Time) ([]entity.DailySummary, error) {
+	sql := `
+		SELECT day, brand, currency, bets, wins, refunds, deposits, chargebacks, withdrawals,
+		       bonus_granted, bonus_converted, bonus_released, adjustments, entries, refreshed_at
+		FROM report_daily_summary
+		WHERE day >= ($1::TIMESTAMPTZ AT TIME ZONE 'UTC')::DATE
+		  AND day < ($2::TIMESTAMPTZ AT TIME ZONE 'UTC')::DATE
+		ORDER BY day, brand, currency`
+
+	rows, err := s.db.Query(ctx, sql, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("select daily summaries: %w", err)
+	}
+
+	summaries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.DailySummary, error) {
+		var v entity.DailySummary
+		err := row.Scan(&v.Day, &v.Brand, &v.Currency, &v.Bets, &v.Wins, &v.Refunds, &v.Deposits, &v.Chargebacks, &v.Withdrawals,
+			&v.BonusGranted, &v.BonusConverted, &v.BonusReleased, &v.Adjustments, &v.Entries, &v.RefreshedAt)
+		return v, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect daily summaries: %w", err)
+	}
+
+	return summaries, nil
+}
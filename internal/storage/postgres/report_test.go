@@ -0,0 +1,111 @@
+package postgres_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportStore_RefreshSummaries(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	h := harness{db: db, factory: postgres.NewWalletStoreTxFactory(db)}
+	walletID, usdWalletID := h.CreateWallet(t, money.NewFromInt(0)), h.CreateWallet(t, money.NewFromInt(0))
+
+	// The ledger is append-only, so every run aggregates days of its own far
+	// in the future, and of a brand of its own.
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(100, 0, int(time.Now().UnixNano()%100000))
+	brand := "test-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	_, err := db.Exec(ctx, `UPDATE wallet SET brand = $2, currency = 'EUR' WHERE id = $1`, walletID, brand)
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, `UPDATE wallet SET brand = $2, currency = 'USD' WHERE id = $1`, usdWalletID, brand)
+	require.NoError(t, err)
+
+	addEntryOf := func(walletID int, at time.Time, operation string, debit, credit *int, reversalOf *int) int {
+		t.Helper()
+		var id int
+		err := db.QueryRow(ctx, `
+			INSERT INTO wallet_entry (wallet_id, debit_amount, credit_amount, operation, initiator, reversal_of,
+			                          balance_after, bonus_balance_after, created_at)
+			VALUES ($1, $2, $3, $4, 'service', $5, 0, 0, $6)
+			RETURNING id`, walletID, debit, credit, operation, reversalOf, at).Scan(&id)
+		require.NoError(t, err)
+		return id
+	}
+	addEntry := func(at time.Time, operation string, debit, credit *int, reversalOf *int) int {
+		t.Helper()
+		return addEntryOf(walletID, at, operation, debit, credit, reversalOf)
+	}
+	amount := func(v int) *int { return &v }
+
+	at := day.Add(10 * time.Hour)
+	addEntry(at, "deposit", amount(1000000), nil, nil)
+	addEntry(at, "bet", nil, amount(300000), nil)
+	win := addEntry(at, "win", amount(100000), nil, nil)
+	addEntry(at, "refund", amount(50000), nil, nil)
+	addEntry(at, "withdrawal", nil, amount(200000), nil)
+	addEntry(at, "withdrawal_release", amount(80000), nil, nil)
+	addEntry(at, "bonus_grant", amount(500000), nil, nil)
+	addEntry(at, "bonus_conversion", amount(100000), nil, nil)
+	addEntry(at, "adjustment", amount(70000), nil, nil)
+	addEntry(at, "adjustment", nil, amount(20000), nil)
+	// The reversal counts against the win on the day it is made.
+	addEntry(day.Add(30*time.Hour), "reversal", nil, amount(100000), &win)
+	// Money in another currency is summed up on its own.
+	addEntryOf(usdWalletID, at, "bet", nil, amount(40000), nil)
+
+	store := postgres.NewReportStore(db)
+	to := day.AddDate(0, 0, 3)
+	// Refreshing again changes nothing.
+	require.NoError(t, store.RefreshSummaries(ctx, day, to))
+	require.NoError(t, store.RefreshSummaries(ctx, day, to))
+
+	all, err := store.ListSummaries(ctx, day, to)
+	require.NoError(t, err)
+	var summaries, usd []entity.DailySummary
+	for _, v := range all {
+		switch {
+		case v.Brand != brand:
+		case v.Currency == "USD":
+			usd = append(usd, v)
+		default:
+			summaries = append(summaries, v)
+		}
+	}
+	require.Len(t, summaries, 3)
+	require.Len(t, usd, 3)
+
+	first := summaries[0]
+	assert.True(t, day.Equal(first.Day))
+	assert.Equal(t, "EUR", first.Currency)
+	assert.Equal(t, "300.000", first.Bets.String())
+	assert.Equal(t, "100.000", first.Wins.String())
+	assert.Equal(t, "50.000", first.Refunds.String())
+	assert.Equal(t, "150.000", first.GGR().String())
+	assert.Equal(t, "1000.000", first.Deposits.String())
+	assert.Equal(t, "120.000", first.Withdrawals.String())
+	assert.Equal(t, "500.000", first.BonusGranted.String())
+	assert.Equal(t, "100.000", first.BonusCost().String())
+	assert.Equal(t, "50.000", first.Adjustments.String())
+	assert.Equal(t, 10, first.Entries)
+
+	second := summaries[1]
+	assert.Equal(t, "-100.000", second.Wins.String())
+	assert.Equal(t, "100.000", second.GGR().String())
+	assert.Equal(t, 1, second.Entries)
+
+	// Days without entries have a summary too.
+	assert.Zero(t, summaries[2].Entries)
+	assert.True(t, summaries[2].GGR().IsZero())
+
+	assert.Equal(t, "40.000", usd[0].Bets.String())
+	assert.Equal(t, 1, usd[0].Entries)
+	assert.Zero(t, usd[1].Entries)
+}
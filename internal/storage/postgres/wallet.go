@@ -2,19 +2,25 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pprishchepa/go-casino-example/domain"
-	"github.com/pprishchepa/go-casino-example/domain/money"
 	"github.com/pprishchepa/go-casino-example/internal/entity"
 )
 
 // See https://www.postgresql.org/docs/current/errcodes-appendix.html
-const errorCodeSerializationFailure = "40001"
+const (
+	errorCodeSerializationFailure = "40001"
+	errorCodeForeignKeyViolation  = "23503"
+	errorCodeUniqueViolation      = "23505"
+)
 
 type WalletStoreTxFactory struct {
 	db *pgxpool.Pool
@@ -33,45 +39,540 @@ func (f WalletStoreTxFactory) NewTx(ctx context.Context) (*WalletStore, error) {
 	return &WalletStore{tx: tx}, nil
 }
 
+// CreateWallet creates a wallet of the brand in the currency with a zero
+// balance and returns its ID.
+func (f WalletStoreTxFactory) CreateWallet(ctx context.Context, brand, currency string) (int, error) {
+	var walletID int
+	err := pgx.BeginFunc(ctx, f.db, func(tx pgx.Tx) error {
+		// Seed data inserts explicit IDs, so the sequence cannot be relied on.
+		err := tx.QueryRow(ctx, `
+			INSERT INTO wallet (id, brand, currency)
+			SELECT COALESCE(MAX(id), 0) + 1, $1, $2 FROM wallet
+			RETURNING id`, brand, currency).Scan(&walletID)
+		if err != nil {
+			return fmt.Errorf("insert wallet: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO wallet_balance (wallet_id) VALUES ($1)`, walletID); err != nil {
+			return fmt.Errorf("insert balance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return walletID, nil
+}
+
 type WalletStore struct {
 	tx pgx.Tx
 }
 
+func (s WalletStore) ListWalletIDs(ctx context.Context) ([]int, error) {
+	rows, err := s.tx.Query(ctx, `SELECT id FROM wallet ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", s.recognizeError(err))
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", s.recognizeError(err))
+	}
+
+	return ids, nil
+}
+
+func (s WalletStore) SearchWallets(ctx context.Context, filter domain.WalletFilter) ([]domain.WalletBalance, error) {
+	var (
+		where = []string{"wallet_id > $1"}
+		args  = []any{filter.AfterID}
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.Flagged != nil {
+		add("flagged = $%d", *filter.Flagged)
+	}
+	if filter.Frozen != nil {
+		add("frozen = $%d", *filter.Frozen)
+	}
+	if filter.MinAmount != nil {
+		add("amount >= $%d", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		add("amount <= $%d", *filter.MaxAmount)
+	}
+
+	sql := `
+		SELECT wallet_id, amount, bonus_amount, version, flagged, frozen 
+		FROM wallet_balance 
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY wallet_id`
+	if filter.Limit > 0 {
+		sql += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	rows, err := s.tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", s.recognizeError(err))
+	}
+	defer rows.Close()
+
+	var balances []domain.WalletBalance
+	for rows.Next() {
+		var b domain.WalletBalance
+		if err := rows.Scan(&b.WalletID, &b.Amount, &b.Bonus, &b.Version, &b.Flagged, &b.Frozen); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		balances = append(balances, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", s.recognizeError(err))
+	}
+
+	return balances, nil
+}
+
 func (s WalletStore) GetBalance(ctx context.Context, walletID int) (*domain.WalletBalance, error) {
-	sql := `SELECT amount FROM wallet_balance WHERE wallet_id = $1`
+	sql := `SELECT amount, bonus_amount, version, flagged, frozen FROM wallet_balance WHERE wallet_id = $1`
 
-	var amount int
+	balance := domain.WalletBalance{WalletID: walletID}
 
-	if err := s.tx.QueryRow(ctx, sql, walletID).Scan(&amount); err != nil {
+	err := s.tx.QueryRow(ctx, sql, walletID).
+		Scan(&balance.Amount, &balance.Bonus, &balance.Version, &balance.Flagged, &balance.Frozen)
+	if err != nil {
 		return nil, fmt.Errorf("query row: %w", s.recognizeError(err))
 	}
 
-	return &domain.WalletBalance{
-		WalletID: walletID,
-		Amount:   money.NewFromInt(amount),
-	}, nil
+	return &balance, nil
 }
 
 func (s WalletStore) SaveBalance(ctx context.Context, balance *domain.WalletBalance) error {
 	sql := `
-		INSERT INTO wallet_balance (wallet_id, amount) 
-		VALUES ($1, $2)
-		ON CONFLICT (wallet_id) DO UPDATE SET amount = $2`
+		INSERT INTO wallet_balance (wallet_id, amount, bonus_amount, flagged, frozen) 
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (wallet_id) DO UPDATE SET amount = $2, bonus_amount = $3, flagged = $4, frozen = $5, version = wallet_balance.version + 1
+		RETURNING version`
+
+	err := s.tx.QueryRow(ctx, sql, balance.WalletID, balance.Amount, balance.Bonus, balance.Flagged, balance.Frozen).
+		Scan(&balance.Version)
+	if err != nil {
+		return fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+func (s WalletStore) AddDebitEntry(ctx context.Context, entry *domain.DebitEntry) error {
+	amount := entry.Amount
+	change := domain.Entry{Debit: &amount, Bonus: entry.Bonus}.Change()
+
+	err := s.tx.QueryRow(ctx, insertEntrySQL("debit_amount"),
+		entry.WalletID, entry.Amount, entry.Bonus,
+		entry.Operation, entry.Reference, entry.Initiator, nullJSON(entry.Metadata), entry.ReversalOf,
+		change.Amount, change.Bonus,
+	).Scan(&entry.ID, &entry.BalanceAfter, &entry.BonusAfter, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+func (s WalletStore) AddCreditEntry(ctx context.Context, entry *domain.CreditEntry) error {
+	amount := entry.Amount
+	change := domain.Entry{Credit: &amount, Bonus: entry.Bonus}.Change()
+
+	err := s.tx.QueryRow(ctx, insertEntrySQL("credit_amount"),
+		entry.WalletID, entry.Amount, entry.Bonus,
+		entry.Operation, entry.Reference, entry.Initiator, nullJSON(entry.Metadata), entry.ReversalOf,
+		change.Amount, change.Bonus,
+	).Scan(&entry.ID, &entry.BalanceAfter, &entry.BonusAfter, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+// insertEntrySQL adds an entry with the amount in the given column. The
+// balance after it is the balance after the previous entry of the wallet
+// changed by $9 real and $10 bonus money. The previous entry may be archived
+// if the wallet was idle for long. Two txs that read the same previous entry
+// fail to serialize, so the balances chain. A reversal is recorded in
+// wallet_entry_reversal as well, which rejects a second one.
+func insertEntrySQL(amountColumn string) string {
+	return `
+		WITH prev AS (
+			SELECT balance_after, bonus_balance_after 
+			FROM wallet_entry_history 
+			WHERE wallet_id = $1 
+			ORDER BY id DESC 
+			LIMIT 1
+		), 
+		entry AS (
+			INSERT INTO wallet_entry (wallet_id, ` + amountColumn + `, bonus_amount, operation, reference, initiator, 
+			                          metadata, reversal_of, balance_after, bonus_balance_after) 
+			SELECT $1, $2, $3, $4, $5, $6, $7, NULLIF($8::BIGINT, 0), 
+			       COALESCE((SELECT balance_after FROM prev), 0) + $9::INT, 
+			       COALESCE((SELECT bonus_balance_after FROM prev), 0) + $10::INT
+			RETURNING id, balance_after, bonus_balance_after, created_at
+		), 
+		reversal AS (
+			INSERT INTO wallet_entry_reversal (entry_id, reversal_id) 
+			SELECT $8::BIGINT, id FROM entry WHERE $8::BIGINT <> 0
+		)
+		SELECT id, balance_after, bonus_balance_after, created_at FROM entry`
+}
+
+// entryColumns are read by scanEntry. The entry table is aliased as e.
+const entryColumns = `e.id, e.wallet_id, e.debit_amount, e.credit_amount, e.bonus_amount, 
+		e.operation, e.reference, e.initiator, e.metadata, COALESCE(e.reversal_of, 0), 
+		COALESCE((SELECT r.reversal_id FROM wallet_entry_reversal r WHERE r.entry_id = e.id), 0), 
+		e.balance_after, e.bonus_balance_after, e.created_at`
+
+func scanEntry(row pgx.Row) (*domain.Entry, error) {
+	var (
+		entry    domain.Entry
+		metadata []byte
+	)
+	err := row.Scan(&entry.ID, &entry.WalletID, &entry.Debit, &entry.Credit, &entry.Bonus,
+		&entry.Operation, &entry.Reference, &entry.Initiator, &metadata, &entry.ReversalOf,
+		&entry.ReversedBy, &entry.BalanceAfter, &entry.BonusAfter, &entry.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	entry.Metadata = metadata
+	return &entry, nil
+}
+
+func (s WalletStore) GetEntry(ctx context.Context, entryID int) (*domain.Entry, error) {
+	sql := `
+		SELECT ` + entryColumns + ` 
+		FROM wallet_entry_history e 
+		WHERE e.id = $1`
+
+	entry, err := scanEntry(s.tx.QueryRow(ctx, sql, entryID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrEntryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return entry, nil
+}
+
+func (s WalletStore) ListEntries(ctx context.Context, filter domain.EntryFilter) ([]domain.Entry, error) {
+	// Conditions are only added for the fields that are set, so that the
+	// planner can pick the index that fits the filter.
+	var (
+		where = []string{"e.wallet_id = $1"}
+		args  = []any{filter.WalletID}
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if len(filter.Operations) > 0 {
+		ops := make([]string, 0, len(filter.Operations))
+		for _, op := range filter.Operations {
+			ops = append(ops, string(op))
+		}
+		add("e.operation = ANY($%d)", ops)
+	}
+	if filter.Reference != "" {
+		add("e.reference = $%d", filter.Reference)
+	}
+	if filter.Initiator != "" {
+		add("e.initiator = $%d", filter.Initiator)
+	}
+	if len(filter.Metadata) > 0 {
+		add("e.metadata @> $%d", filter.Metadata)
+	}
+	if !filter.From.IsZero() {
+		add("e.created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("e.created_at < $%d", filter.To)
+	}
+	if filter.AfterID > 0 {
+		add("e.id > $%d", filter.AfterID)
+	}
+
+	sql := `
+		SELECT ` + entryColumns + ` 
+		FROM wallet_entry_history e 
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY e.id`
+	if filter.Limit > 0 {
+		sql += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	rows, err := s.tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", s.recognizeError(err))
+	}
+	defer rows.Close()
+
+	var entries []domain.Entry
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		entries = append(entries, *entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", s.recognizeError(err))
+	}
+
+	return entries, nil
+}
+
+// entrySumColumns sum up how entries change real and bonus money.
+const entrySumColumns = `
+		COALESCE(SUM(CASE WHEN debit_amount IS NOT NULL THEN debit_amount - bonus_amount 
+		                  ELSE bonus_amount - credit_amount END), 0), 
+		COALESCE(SUM(CASE WHEN debit_amount IS NOT NULL THEN bonus_amount ELSE -bonus_amount END), 0)`
+
+func (s WalletStore) SumEntries(ctx context.Context, walletID int, from, to time.Time) (domain.EntrySum, error) {
+	sql := `
+		SELECT ` + entrySumColumns + ` 
+		FROM wallet_entry_history 
+		WHERE wallet_id = $1 AND created_at >= $2 AND created_at < $3`
+
+	var sum domain.EntrySum
+	err := s.tx.QueryRow(ctx, sql, walletID, from, to).Scan(&sum.Amount, &sum.Bonus)
+	if err != nil {
+		return domain.EntrySum{}, fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return sum, nil
+}
+
+func (s WalletStore) SumEntriesByDay(ctx context.Context, walletID int, from, to time.Time) ([]domain.EntrySum, error) {
+	sql := `
+		SELECT date_trunc('day', created_at, 'UTC') AS day, ` + entrySumColumns + ` 
+		FROM wallet_entry_history 
+		WHERE wallet_id = $1 AND created_at >= $2 AND created_at < $3 
+		GROUP BY day 
+		ORDER BY day`
+
+	rows, err := s.tx.Query(ctx, sql, walletID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", s.recognizeError(err))
+	}
+	defer rows.Close()
+
+	var sums []domain.EntrySum
+	for rows.Next() {
+		var sum domain.EntrySum
+		if err := rows.Scan(&sum.Day, &sum.Amount, &sum.Bonus); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		sum.Day = sum.Day.UTC()
+		sums = append(sums, sum)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", s.recognizeError(err))
+	}
+
+	return sums, nil
+}
+
+func (s WalletStore) GetSnapshot(ctx context.Context, walletID int, at time.Time) (*domain.BalanceSnapshot, error) {
+	sql := `
+		SELECT as_of, amount, bonus_amount, created_at 
+		FROM wallet_balance_snapshot 
+		WHERE wallet_id = $1 AND as_of <= $2 
+		ORDER BY as_of DESC 
+		LIMIT 1`
 
-	_, err := s.tx.Exec(ctx, sql, balance.WalletID, balance.Amount.AsInt())
+	snapshot := domain.BalanceSnapshot{WalletID: walletID}
+	err := s.tx.QueryRow(ctx, sql, walletID, at).
+		Scan(&snapshot.At, &snapshot.Amount, &snapshot.Bonus, &snapshot.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrSnapshotNotFound
+	}
 	if err != nil {
+		return nil, fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+	snapshot.At = snapshot.At.UTC()
+
+	return &snapshot, nil
+}
+
+func (s WalletStore) AddSnapshot(ctx context.Context, snapshot *domain.BalanceSnapshot) error {
+	sql := `
+		INSERT INTO wallet_balance_snapshot (wallet_id, as_of, amount, bonus_amount) 
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at`
+
+	err := s.tx.QueryRow(ctx, sql, snapshot.WalletID, snapshot.At, snapshot.Amount, snapshot.Bonus).
+		Scan(&snapshot.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+// ListAuditRecords reads the records the wallet_audit_append trigger adds for
+// every entry.
+func (s WalletStore) ListAuditRecords(ctx context.Context, walletID int) ([]domain.AuditRecord, error) {
+	sql := `
+		SELECT seq, entry_id, payload, prev_hash, hash, created_at 
+		FROM wallet_audit 
+		WHERE wallet_id = $1 
+		ORDER BY seq`
+
+	rows, err := s.tx.Query(ctx, sql, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", s.recognizeError(err))
+	}
+	defer rows.Close()
+
+	var records []domain.AuditRecord
+	for rows.Next() {
+		var (
+			record  = domain.AuditRecord{WalletID: walletID}
+			payload string
+		)
+		err := rows.Scan(&record.Seq, &record.EntryID, &payload, &record.PrevHash, &record.Hash, &record.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		record.Payload = []byte(payload)
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", s.recognizeError(err))
+	}
+
+	return records, nil
+}
+
+func (s WalletStore) AddBonusGrant(ctx context.Context, grant *domain.BonusGrant) error {
+	sql := `
+		INSERT INTO bonus_grant (wallet_id, amount, multiplier, wagered, status, expires_at) 
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	err := s.tx.QueryRow(ctx, sql,
+		grant.WalletID, grant.Amount, grant.Multiplier, grant.Wagered, grant.Status, grant.ExpiresAt,
+	).Scan(&grant.ID, &grant.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+func (s WalletStore) SaveBonusGrant(ctx context.Context, grant *domain.BonusGrant) error {
+	sql := `UPDATE bonus_grant SET wagered = $2, status = $3, updated_at = NOW() WHERE id = $1`
+
+	if _, err := s.tx.Exec(ctx, sql, grant.ID, grant.Wagered, grant.Status); err != nil {
 		return fmt.Errorf("exec: %w", s.recognizeError(err))
 	}
 
 	return nil
 }
 
-func (s WalletStore) AddDebitEntry(ctx context.Context, entry domain.DebitEntry) error {
+func (s WalletStore) ListBonusGrants(ctx context.Context, walletID int) ([]domain.BonusGrant, error) {
 	sql := `
-		INSERT INTO wallet_entry (wallet_id, debit_amount) 
-		VALUES ($1, $2)`
+		SELECT id, amount, multiplier, wagered, status, expires_at, created_at 
+		FROM bonus_grant 
+		WHERE wallet_id = $1 
+		ORDER BY id`
 
-	_, err := s.tx.Exec(ctx, sql, entry.WalletID, entry.Amount.AsInt())
+	rows, err := s.tx.Query(ctx, sql, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", s.recognizeError(err))
+	}
+	defer rows.Close()
+
+	var grants []domain.BonusGrant
+	for rows.Next() {
+		grant := domain.BonusGrant{WalletID: walletID}
+		err := rows.Scan(&grant.ID, &grant.Amount, &grant.Multiplier, &grant.Wagered,
+			&grant.Status, &grant.ExpiresAt, &grant.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		grants = append(grants, grant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", s.recognizeError(err))
+	}
+
+	return grants, nil
+}
+
+func (s WalletStore) GetLimits(ctx context.Context, walletID int) ([]domain.Limit, error) {
+	sql := `
+		SELECT kind, period, amount, pending_amount, pending_at 
+		FROM wallet_limit 
+		WHERE wallet_id = $1 
+		ORDER BY kind, period`
+
+	rows, err := s.tx.Query(ctx, sql, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", s.recognizeError(err))
+	}
+	defer rows.Close()
+
+	var limits []domain.Limit
+	for rows.Next() {
+		limit := domain.Limit{WalletID: walletID}
+		var pendingAt *time.Time
+		if err := rows.Scan(&limit.Kind, &limit.Period, &limit.Amount, &limit.PendingAmount, &pendingAt); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		if pendingAt != nil {
+			limit.PendingAt = *pendingAt
+		}
+		limits = append(limits, limit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", s.recognizeError(err))
+	}
+
+	return limits, nil
+}
+
+func (s WalletStore) SaveLimit(ctx context.Context, limit *domain.Limit) error {
+	if limit.Amount == nil && limit.PendingAt.IsZero() {
+		sql := `DELETE FROM wallet_limit WHERE wallet_id = $1 AND kind = $2 AND period = $3`
+
+		if _, err := s.tx.Exec(ctx, sql, limit.WalletID, limit.Kind, limit.Period); err != nil {
+			return fmt.Errorf("exec: %w", s.recognizeError(err))
+		}
+		return nil
+	}
+
+	sql := `
+		INSERT INTO wallet_limit (wallet_id, kind, period, amount, pending_amount, pending_at) 
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (wallet_id, kind, period) DO UPDATE 
+		SET amount = $4, pending_amount = $5, pending_at = $6, updated_at = NOW()`
+
+	var pendingAt *time.Time
+	if !limit.PendingAt.IsZero() {
+		pendingAt = &limit.PendingAt
+	}
+
+	_, err := s.tx.Exec(ctx, sql,
+		limit.WalletID, limit.Kind, limit.Period, limit.Amount, limit.PendingAmount, pendingAt)
 	if err != nil {
 		return fmt.Errorf("exec: %w", s.recognizeError(err))
 	}
@@ -79,12 +580,67 @@ func (s WalletStore) AddDebitEntry(ctx context.Context, entry domain.DebitEntry)
 	return nil
 }
 
-func (s WalletStore) AddCreditEntry(ctx context.Context, entry domain.CreditEntry) error {
+func (s WalletStore) GetActivity(ctx context.Context, walletID int, since time.Time) (domain.Activity, error) {
 	sql := `
-		INSERT INTO wallet_entry (wallet_id, credit_amount) 
-		VALUES ($1, $2)`
+		SELECT COALESCE(SUM(deposits), 0), COALESCE(SUM(wagers), 0), COALESCE(SUM(wins), 0) 
+		FROM wallet_activity 
+		WHERE wallet_id = $1 AND hour >= date_trunc('hour', $2::timestamptz)`
+
+	var activity domain.Activity
+	err := s.tx.QueryRow(ctx, sql, walletID, since).Scan(&activity.Deposits, &activity.Wagers, &activity.Wins)
+	if err != nil {
+		return domain.Activity{}, fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return activity, nil
+}
+
+func (s WalletStore) AddActivity(ctx context.Context, walletID int, at time.Time, activity domain.Activity) error {
+	sql := `
+		INSERT INTO wallet_activity (wallet_id, hour, deposits, wagers, wins) 
+		VALUES ($1, date_trunc('hour', $2::timestamptz), $3, $4, $5)
+		ON CONFLICT (wallet_id, hour) DO UPDATE 
+		SET deposits = wallet_activity.deposits + $3, 
+		    wagers = wallet_activity.wagers + $4, 
+		    wins = wallet_activity.wins + $5`
+
+	_, err := s.tx.Exec(ctx, sql, walletID, at, activity.Deposits, activity.Wagers, activity.Wins)
+	if err != nil {
+		return fmt.Errorf("exec: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+func (s WalletStore) AddExclusion(ctx context.Context, exclusion *domain.Exclusion) error {
+	sql := `
+		INSERT INTO wallet_exclusion (wallet_id, period, until) 
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at`
+
+	var until *time.Time
+	if !exclusion.Until.IsZero() {
+		until = &exclusion.Until
+	}
+
+	err := s.tx.QueryRow(ctx, sql, exclusion.WalletID, exclusion.Period, until).
+		Scan(&exclusion.ID, &exclusion.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+func (s WalletStore) SaveExclusion(ctx context.Context, exclusion *domain.Exclusion) error {
+	sql := `UPDATE wallet_exclusion SET lifted_at = $2, lifted_by = $3, lift_reason = $4 WHERE id = $1`
 
-	_, err := s.tx.Exec(ctx, sql, entry.WalletID, entry.Amount.AsInt())
+	var liftedAt *time.Time
+	if !exclusion.LiftedAt.IsZero() {
+		liftedAt = &exclusion.LiftedAt
+	}
+
+	_, err := s.tx.Exec(ctx, sql, exclusion.ID, liftedAt, exclusion.LiftedBy, exclusion.LiftReason)
 	if err != nil {
 		return fmt.Errorf("exec: %w", s.recognizeError(err))
 	}
@@ -92,6 +648,296 @@ func (s WalletStore) AddCreditEntry(ctx context.Context, entry domain.CreditEntr
 	return nil
 }
 
+func (s WalletStore) ListExclusions(ctx context.Context, walletID int) ([]domain.Exclusion, error) {
+	sql := `
+		SELECT id, period, until, created_at, lifted_at, lifted_by, lift_reason 
+		FROM wallet_exclusion 
+		WHERE wallet_id = $1 
+		ORDER BY id`
+
+	rows, err := s.tx.Query(ctx, sql, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", s.recognizeError(err))
+	}
+	defer rows.Close()
+
+	var exclusions []domain.Exclusion
+	for rows.Next() {
+		exclusion := domain.Exclusion{WalletID: walletID}
+		var until, liftedAt *time.Time
+		err := rows.Scan(&exclusion.ID, &exclusion.Period, &until, &exclusion.CreatedAt,
+			&liftedAt, &exclusion.LiftedBy, &exclusion.LiftReason)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		if until != nil {
+			exclusion.Until = *until
+		}
+		if liftedAt != nil {
+			exclusion.LiftedAt = *liftedAt
+		}
+		exclusions = append(exclusions, exclusion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", s.recognizeError(err))
+	}
+
+	return exclusions, nil
+}
+
+func (s WalletStore) AddWithdrawal(ctx context.Context, withdrawal *domain.Withdrawal) error {
+	sql := `
+		INSERT INTO withdrawal (wallet_id, amount, status, hold_entry_id) 
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`
+
+	err := s.tx.QueryRow(ctx, sql, withdrawal.WalletID, withdrawal.Amount, withdrawal.Status, withdrawal.HoldEntryID).
+		Scan(&withdrawal.ID, &withdrawal.CreatedAt, &withdrawal.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+func (s WalletStore) SaveWithdrawal(ctx context.Context, withdrawal *domain.Withdrawal) error {
+	sql := `UPDATE withdrawal SET status = $2, updated_at = NOW() WHERE id = $1 RETURNING updated_at`
+
+	err := s.tx.QueryRow(ctx, sql, withdrawal.ID, withdrawal.Status).Scan(&withdrawal.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrWithdrawalNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+func (s WalletStore) GetWithdrawal(ctx context.Context, withdrawalID int) (*domain.Withdrawal, error) {
+	sql := `
+		SELECT wallet_id, amount, status, hold_entry_id, created_at, updated_at 
+		FROM withdrawal 
+		WHERE id = $1`
+
+	withdrawal := domain.Withdrawal{ID: withdrawalID}
+
+	err := s.tx.QueryRow(ctx, sql, withdrawalID).Scan(&withdrawal.WalletID, &withdrawal.Amount, &withdrawal.Status,
+		&withdrawal.HoldEntryID, &withdrawal.CreatedAt, &withdrawal.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrWithdrawalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return &withdrawal, nil
+}
+
+func (s WalletStore) ListWithdrawals(ctx context.Context, walletID int) ([]domain.Withdrawal, error) {
+	sql := `
+		SELECT id, amount, status, hold_entry_id, created_at, updated_at 
+		FROM withdrawal 
+		WHERE wallet_id = $1 
+		ORDER BY id`
+
+	rows, err := s.tx.Query(ctx, sql, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", s.recognizeError(err))
+	}
+	defer rows.Close()
+
+	var withdrawals []domain.Withdrawal
+	for rows.Next() {
+		withdrawal := domain.Withdrawal{WalletID: walletID}
+		err := rows.Scan(&withdrawal.ID, &withdrawal.Amount, &withdrawal.Status, &withdrawal.HoldEntryID,
+			&withdrawal.CreatedAt, &withdrawal.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		withdrawals = append(withdrawals, withdrawal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", s.recognizeError(err))
+	}
+
+	return withdrawals, nil
+}
+
+func (s WalletStore) AddWithdrawalTransition(ctx context.Context, transition *domain.WithdrawalTransition) error {
+	sql := `
+		INSERT INTO withdrawal_transition (withdrawal_id, wallet_id, from_status, to_status, actor, reason, created_at) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := s.tx.Exec(ctx, sql, transition.WithdrawalID, transition.WalletID, transition.From, transition.To,
+		transition.Actor, transition.Reason, transition.At)
+	if err != nil {
+		return fmt.Errorf("exec: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+func (s WalletStore) ListWithdrawalTransitions(ctx context.Context, withdrawalID int) ([]domain.WithdrawalTransition, error) {
+	sql := `
+		SELECT wallet_id, from_status, to_status, actor, reason, created_at 
+		FROM withdrawal_transition 
+		WHERE withdrawal_id = $1 
+		ORDER BY id`
+
+	rows, err := s.tx.Query(ctx, sql, withdrawalID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", s.recognizeError(err))
+	}
+	defer rows.Close()
+
+	var transitions []domain.WithdrawalTransition
+	for rows.Next() {
+		transition := domain.WithdrawalTransition{WithdrawalID: withdrawalID}
+		err := rows.Scan(&transition.WalletID, &transition.From, &transition.To, &transition.Actor,
+			&transition.Reason, &transition.At)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		transitions = append(transitions, transition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", s.recognizeError(err))
+	}
+
+	return transitions, nil
+}
+
+func (s WalletStore) AddDeposit(ctx context.Context, deposit *domain.Deposit) error {
+	sql := `
+		INSERT INTO deposit (wallet_id, provider, reference, amount, status) 
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`
+
+	err := s.tx.QueryRow(ctx, sql, deposit.WalletID, deposit.Provider, deposit.Reference, deposit.Amount, deposit.Status).
+		Scan(&deposit.ID, &deposit.CreatedAt, &deposit.UpdatedAt)
+
+	// Another callback about the same reference got there first. Retrying
+	// finds its deposit.
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == errorCodeUniqueViolation {
+		return entity.ErrTxConflict
+	}
+	if err != nil {
+		return fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+func (s WalletStore) SaveDeposit(ctx context.Context, deposit *domain.Deposit) error {
+	sql := `
+		UPDATE deposit 
+		SET status = $2, entry_id = NULLIF($3, 0), reversal_entry_id = NULLIF($4, 0), updated_at = NOW() 
+		WHERE id = $1 
+		RETURNING updated_at`
+
+	err := s.tx.QueryRow(ctx, sql, deposit.ID, deposit.Status, deposit.EntryID, deposit.ReversalEntryID).
+		Scan(&deposit.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrDepositNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+func (s WalletStore) GetDeposit(ctx context.Context, provider, reference string) (*domain.Deposit, error) {
+	sql := `
+		SELECT id, wallet_id, amount, status, COALESCE(entry_id, 0), COALESCE(reversal_entry_id, 0), created_at, updated_at 
+		FROM deposit 
+		WHERE provider = $1 AND reference = $2`
+
+	deposit := domain.Deposit{Provider: provider, Reference: reference}
+
+	err := s.tx.QueryRow(ctx, sql, provider, reference).Scan(&deposit.ID, &deposit.WalletID, &deposit.Amount,
+		&deposit.Status, &deposit.EntryID, &deposit.ReversalEntryID, &deposit.CreatedAt, &deposit.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrDepositNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return &deposit, nil
+}
+
+func (s WalletStore) AddAdminAction(ctx context.Context, action *domain.AdminAction) error {
+	sql := `
+		INSERT INTO admin_action (wallet_id, kind, actor, reason, details) 
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	err := s.tx.QueryRow(ctx, sql, action.WalletID, action.Kind, action.Actor, action.Reason, nullJSON(action.Details)).
+		Scan(&action.ID, &action.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("query row: %w", s.recognizeError(err))
+	}
+
+	return nil
+}
+
+func (s WalletStore) ListAdminActions(ctx context.Context, filter domain.AdminActionFilter) ([]domain.AdminAction, error) {
+	var (
+		where = []string{"id > $1"}
+		args  = []any{filter.AfterID}
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.WalletID > 0 {
+		add("wallet_id = $%d", filter.WalletID)
+	}
+	if filter.Actor != "" {
+		add("actor = $%d", filter.Actor)
+	}
+	if filter.Kind != "" {
+		add("kind = $%d", filter.Kind)
+	}
+
+	sql := `
+		SELECT id, wallet_id, kind, actor, reason, details, created_at 
+		FROM admin_action 
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY id`
+	if filter.Limit > 0 {
+		sql += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	rows, err := s.tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", s.recognizeError(err))
+	}
+	defer rows.Close()
+
+	var actions []domain.AdminAction
+	for rows.Next() {
+		var (
+			action  domain.AdminAction
+			details []byte
+		)
+		err := rows.Scan(&action.ID, &action.WalletID, &action.Kind, &action.Actor, &action.Reason, &details, &action.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		action.Details = details
+		actions = append(actions, action)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", s.recognizeError(err))
+	}
+
+	return actions, nil
+}
+
 func (s WalletStore) Commit(ctx context.Context) error {
 	return s.recognizeError(s.tx.Commit(ctx))
 }
@@ -100,12 +946,40 @@ func (s WalletStore) Rollback(ctx context.Context) error {
 	return s.recognizeError(s.tx.Rollback(ctx))
 }
 
+// nullJSON stores empty metadata as NULL rather than an empty JSON document.
+func nullJSON(v json.RawMessage) any {
+	if len(v) == 0 {
+		return nil
+	}
+	return string(v)
+}
+
 func (s WalletStore) recognizeError(err error) error {
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) && pgErr.Code == errorCodeSerializationFailure {
 		return entity.ErrTxConflict
 	}
 
+	// Every foreign key of the wallet tables references the wallet, except
+	// for withdrawal transitions.
+	if errors.As(err, &pgErr) && pgErr.Code == errorCodeForeignKeyViolation && pgErr.ConstraintName == "fk_withdrawal" {
+		return domain.ErrWithdrawalNotFound
+	}
+	if errors.As(err, &pgErr) && pgErr.Code == errorCodeUniqueViolation && pgErr.ConstraintName == "wallet_entry_reversal_unique" {
+		return domain.ErrAlreadyReversed
+	}
+	// Two txs extended the audit log of the same wallet.
+	if errors.As(err, &pgErr) && pgErr.Code == errorCodeUniqueViolation && pgErr.ConstraintName == "wallet_audit_pkey" {
+		return entity.ErrTxConflict
+	}
+	// Two runs snapshotted the same day of the wallet.
+	if errors.As(err, &pgErr) && pgErr.Code == errorCodeUniqueViolation && pgErr.ConstraintName == "wallet_balance_snapshot_pkey" {
+		return entity.ErrTxConflict
+	}
+	if errors.As(err, &pgErr) && pgErr.Code == errorCodeForeignKeyViolation {
+		return domain.ErrWalletNotFound
+	}
+
 	if errors.Is(err, pgx.ErrNoRows) {
 		return domain.ErrWalletNotFound
 	}
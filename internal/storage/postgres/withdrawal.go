@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+)
+
+// WithdrawalOutboxStore reads withdrawal transitions that are not published
+// yet. Transitions are put into the outbox by a trigger in the tx that adds
+// them.
+type WithdrawalOutboxStore struct {
+	db *pgxpool.Pool
+}
+
+func NewWithdrawalOutboxStore(db *pgxpool.Pool) *WithdrawalOutboxStore {
+	return &WithdrawalOutboxStore{db: db}
+}
+
+// RelayTransitions publishes up to limit of the oldest transitions in the
+// outbox and removes the ones published from it. It stops at the first
+// transition that could not be published, so that transitions of a
+// withdrawal are published in order, and returns how many it published.
+// Transitions locked by another relay are skipped.
+func (s WithdrawalOutboxStore) RelayTransitions(
+	ctx context.Context,
+	limit int,
+	publish func(ctx context.Context, event entity.WithdrawalEvent) error,
+) (int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	sql := `
+		SELECT t.id, t.withdrawal_id, t.wallet_id, t.from_status, t.to_status, t.actor, t.reason, t.created_at
+		FROM withdrawal_outbox o
+		JOIN withdrawal_transition t ON t.id = o.transition_id
+		ORDER BY o.transition_id
+		LIMIT $1
+		FOR UPDATE OF o SKIP LOCKED`
+
+	rows, err := tx.Query(ctx, sql, limit)
+	if err != nil {
+		return 0, fmt.Errorf("query: %w", err)
+	}
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.WithdrawalEvent, error) {
+		var event entity.WithdrawalEvent
+		err := row.Scan(&event.ID, &event.WithdrawalID, &event.WalletID, &event.From, &event.To, &event.Actor,
+			&event.Reason, &event.At)
+		return event, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("collect rows: %w", err)
+	}
+
+	var (
+		published []int
+		pubErr    error
+	)
+	for _, event := range events {
+		if pubErr = publish(ctx, event); pubErr != nil {
+			break
+		}
+		published = append(published, event.ID)
+	}
+
+	if len(published) > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM withdrawal_outbox WHERE transition_id = ANY($1)`, published); err != nil {
+			return 0, fmt.Errorf("delete published: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return 0, fmt.Errorf("commit: %w", err)
+		}
+	}
+	if pubErr != nil {
+		return len(published), fmt.Errorf("publish transition %d: %w", events[len(published)].ID, pubErr)
+	}
+
+	return len(published), nil
+}
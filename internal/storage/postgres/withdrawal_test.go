@@ -0,0 +1,79 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithdrawalOutboxStore_RelayTransitions(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	h := harness{db: db, factory: postgres.NewWalletStoreTxFactory(db)}
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+	at := time.Now().UTC().Truncate(time.Second)
+
+	var withdrawalID int
+	err := db.QueryRow(ctx, `
+		INSERT INTO withdrawal (wallet_id, amount, status, hold_entry_id)
+		VALUES ($1, 300, 'requested', 0)
+		RETURNING id`, walletID).Scan(&withdrawalID)
+	require.NoError(t, err)
+
+	statuses := []domain.WithdrawalStatus{"", domain.WithdrawalRequested, domain.WithdrawalPendingReview, domain.WithdrawalApproved}
+	for i := 1; i < len(statuses); i++ {
+		_, err := db.Exec(ctx, `
+			INSERT INTO withdrawal_transition (withdrawal_id, wallet_id, from_status, to_status, actor, created_at)
+			VALUES ($1, $2, $3, $4, 'player', $5)`, withdrawalID, walletID, statuses[i-1], statuses[i], at)
+		require.NoError(t, err)
+	}
+
+	// Other tests leave transitions in the outbox too, so only the ones of
+	// this withdrawal are looked at.
+	var published []entity.WithdrawalEvent
+	publish := func(failAt domain.WithdrawalStatus) func(context.Context, entity.WithdrawalEvent) error {
+		return func(_ context.Context, event entity.WithdrawalEvent) error {
+			if event.WithdrawalID != withdrawalID {
+				return nil
+			}
+			if event.To == failAt {
+				return errors.New("broker is down")
+			}
+			published = append(published, event)
+			return nil
+		}
+	}
+	store := postgres.NewWithdrawalOutboxStore(db)
+
+	// The transitions after the one that failed are left for later too.
+	_, err = store.RelayTransitions(ctx, 1000000, publish(domain.WithdrawalPendingReview))
+	require.Error(t, err)
+	require.Len(t, published, 1)
+
+	_, err = store.RelayTransitions(ctx, 1000000, publish(""))
+	require.NoError(t, err)
+	require.Len(t, published, 3)
+
+	for i, event := range published {
+		assert.NotZero(t, event.ID)
+		assert.Equal(t, walletID, event.WalletID)
+		assert.Equal(t, statuses[i], event.From)
+		assert.Equal(t, statuses[i+1], event.To)
+		assert.Equal(t, "player", event.Actor)
+		assert.True(t, at.Equal(event.At))
+	}
+
+	// Published transitions are gone from the outbox.
+	n, err := store.RelayTransitions(ctx, 1000000, publish(""))
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Len(t, published, 3)
+}
@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const balanceChannel = "wallet:balance"
+
+type BalanceNotifier interface {
+	NotifyBalance(ctx context.Context, balance *domain.WalletBalance)
+}
+
+// BalancePubSub fans out balance changes to every service instance through
+// Redis pub/sub and passes received changes to the local notifier.
+type BalancePubSub struct {
+	ring  *redis.Ring
+	local BalanceNotifier
+}
+
+type publishedBalance struct {
+	WalletID int         `json:"walletId"`
+	Amount   money.Money `json:"amount"`
+	Bonus    money.Money `json:"bonus"`
+	Version  int         `json:"version"`
+	Flagged  bool        `json:"flagged,omitempty"`
+	Frozen   bool        `json:"frozen,omitempty"`
+}
+
+func NewBalancePubSub(ring *redis.Ring, local BalanceNotifier) *BalancePubSub {
+	return &BalancePubSub{ring: ring, local: local}
+}
+
+func (p *BalancePubSub) NotifyBalance(ctx context.Context, balance *domain.WalletBalance) {
+	payload, err := json.Marshal(publishedBalance{
+		WalletID: balance.WalletID,
+		Amount:   balance.Amount,
+		Bonus:    balance.Bonus,
+		Version:  balance.Version,
+		Flagged:  balance.Flagged,
+		Frozen:   balance.Frozen,
+	})
+	if err == nil {
+		err = p.ring.Publish(ctx, balanceChannel, payload).Err()
+	}
+	if err != nil {
+		// Other instances miss this change, but local subscribers still get it.
+		log.Warn().Err(err).Int("walletId", balance.WalletID).Msg("could not publish balance")
+		p.local.NotifyBalance(ctx, balance)
+	}
+}
+
+// Run receives balance changes until ctx is cancelled.
+func (p *BalancePubSub) Run(ctx context.Context) {
+	sub := p.ring.Subscribe(ctx, balanceChannel)
+	defer func() {
+		if err := sub.Close(); err != nil {
+			log.Warn().Err(err).Msg("could not close balance subscription")
+		}
+	}()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var published publishedBalance
+			if err := json.Unmarshal([]byte(msg.Payload), &published); err != nil {
+				log.Warn().Err(err).Msg("could not decode published balance")
+				continue
+			}
+			p.local.NotifyBalance(ctx, &domain.WalletBalance{
+				WalletID: published.WalletID,
+				Amount:   published.Amount,
+				Bonus:    published.Bonus,
+				Version:  published.Version,
+				Flagged:  published.Flagged,
+				Frozen:   published.Frozen,
+			})
+		}
+	}
+}
@@ -18,7 +18,11 @@ type WalletCacheStore struct {
 
 type cachedWalletBalance struct {
 	WalletID int
-	Amount   int
+	Amount   money.Money
+	Bonus    money.Money
+	Version  int
+	Flagged  bool
+	Frozen   bool
 }
 
 func NewWalletCacheStore(ring *redis.Ring) *WalletCacheStore {
@@ -36,7 +40,11 @@ func (s WalletCacheStore) SaveBalance(ctx context.Context, balance *domain.Walle
 		Key: s.newKey(balance.WalletID),
 		Value: cachedWalletBalance{
 			WalletID: balance.WalletID,
-			Amount:   balance.Amount.AsInt(),
+			Amount:   balance.Amount,
+			Bonus:    balance.Bonus,
+			Version:  balance.Version,
+			Flagged:  balance.Flagged,
+			Frozen:   balance.Frozen,
 		},
 	})
 }
@@ -53,10 +61,24 @@ func (s WalletCacheStore) GetBalance(ctx context.Context, walletID int) (*domain
 
 	return &domain.WalletBalance{
 		WalletID: cachedBalance.WalletID,
-		Amount:   money.NewFromInt(cachedBalance.Amount),
+		Amount:   cachedBalance.Amount,
+		Bonus:    cachedBalance.Bonus,
+		Version:  cachedBalance.Version,
+		Flagged:  cachedBalance.Flagged,
+		Frozen:   cachedBalance.Frozen,
 	}, nil
 }
 
+// DeleteBalance evicts the balance from Redis and the local cache of this
+// instance. Other instances may keep serving it from their local caches for
+// up to a minute.
+func (s WalletCacheStore) DeleteBalance(ctx context.Context, walletID int) error {
+	if err := s.cache.Delete(ctx, s.newKey(walletID)); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	return nil
+}
+
 func (s WalletCacheStore) newKey(walletID int) string {
 	return fmt.Sprintf("account:%d:balance", walletID)
 }
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+const withdrawalChannel = "wallet:withdrawal"
+
+// WithdrawalPublisher publishes withdrawal transitions through Redis pub/sub
+// for payment and back-office consumers.
+type WithdrawalPublisher struct {
+	ring *redis.Ring
+}
+
+type publishedTransition struct {
+	// ID is the same every time a transition is published, so that
+	// consumers can skip the ones they have seen.
+	ID           int       `json:"id"`
+	WithdrawalID int       `json:"withdrawalId"`
+	WalletID     int       `json:"walletId"`
+	From         string    `json:"from,omitempty"`
+	To           string    `json:"to"`
+	Actor        string    `json:"actor"`
+	Reason       string    `json:"reason,omitempty"`
+	At           time.Time `json:"at"`
+}
+
+func NewWithdrawalPublisher(ring *redis.Ring) *WithdrawalPublisher {
+	return &WithdrawalPublisher{ring: ring}
+}
+
+func (p *WithdrawalPublisher) PublishWithdrawal(ctx context.Context, event entity.WithdrawalEvent) error {
+	payload, err := json.Marshal(publishedTransition{
+		ID:           event.ID,
+		WithdrawalID: event.WithdrawalID,
+		WalletID:     event.WalletID,
+		From:         string(event.From),
+		To:           string(event.To),
+		Actor:        event.Actor,
+		Reason:       event.Reason,
+		At:           event.At,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	if err := p.ring.Publish(ctx, withdrawalChannel, payload).Err(); err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,1164 @@
+// Package storagetest provides a conformance suite that any wallet storage
+// implementation can run to verify it behaves the way the domain expects.
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/pprishchepa/go-casino-example/domain"
+	"github.com/pprishchepa/go-casino-example/domain/money"
+	"github.com/pprishchepa/go-casino-example/internal/entity"
+	"github.com/pprishchepa/go-casino-example/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Harness adapts a storage implementation to the suite. Wallet creation and
+// entry listing are not part of domain.WalletStore, so the implementation
+// provides them directly.
+type Harness interface {
+	service.WalletStoreTxFactory
+	CreateWallet(t *testing.T, amount money.Money) int
+	ListEntries(t *testing.T, walletID int) []Entry
+}
+
+// Entry is a committed ledger entry. Exactly one of Debit and Credit is set.
+// Bonus is the part of it that belongs to the bonus bucket.
+type Entry struct {
+	Debit  *money.Money
+	Credit *money.Money
+	Bonus  money.Money
+}
+
+// Run runs the whole conformance suite against h.
+func Run(t *testing.T, h Harness) {
+	t.Run("wallet not found", func(t *testing.T) { testWalletNotFound(t, h) })
+	t.Run("commit is visible", func(t *testing.T) { testCommitVisible(t, h) })
+	t.Run("rollback is invisible", func(t *testing.T) { testRollbackInvisible(t, h) })
+	t.Run("concurrent conflicting txs", func(t *testing.T) { testConcurrentConflict(t, h) })
+	t.Run("concurrent txs on different wallets", func(t *testing.T) { testConcurrentNoConflict(t, h) })
+	t.Run("entries keep insertion order", func(t *testing.T) { testEntryOrdering(t, h) })
+	t.Run("entry details are kept and filtered", func(t *testing.T) { testEntryDetails(t, h) })
+	t.Run("entries are reversed once", func(t *testing.T) { testEntryReversal(t, h) })
+	t.Run("entries are audited", func(t *testing.T) { testAuditLog(t, h) })
+	t.Run("entries keep running balance", func(t *testing.T) { testRunningBalance(t, h) })
+	t.Run("entries are summed up", func(t *testing.T) { testEntrySums(t, h) })
+	t.Run("balance snapshots are kept", func(t *testing.T) { testSnapshots(t, h) })
+	t.Run("negative balance is rejected", func(t *testing.T) { testNegativeBalance(t, h) })
+	t.Run("flagged balance may be negative", func(t *testing.T) { testFlaggedBalance(t, h) })
+	t.Run("wallets are searched", func(t *testing.T) { testWalletSearch(t, h) })
+	t.Run("admin actions are kept", func(t *testing.T) { testAdminActions(t, h) })
+	t.Run("negative entry is rejected", func(t *testing.T) { testNegativeEntry(t, h) })
+	t.Run("bonus bucket is kept", func(t *testing.T) { testBonusBucket(t, h) })
+	t.Run("bonus grants are kept", func(t *testing.T) { testBonusGrants(t, h) })
+	t.Run("limits are kept", func(t *testing.T) { testLimits(t, h) })
+	t.Run("activity is summed up", func(t *testing.T) { testActivity(t, h) })
+	t.Run("exclusions are kept", func(t *testing.T) { testExclusions(t, h) })
+	t.Run("withdrawals are kept", func(t *testing.T) { testWithdrawals(t, h) })
+	t.Run("concurrent withdrawal transitions", func(t *testing.T) { testWithdrawalConflict(t, h) })
+	t.Run("deposits are kept", func(t *testing.T) { testDeposits(t, h) })
+	t.Run("concurrent deposits with the same reference", func(t *testing.T) { testDepositConflict(t, h) })
+}
+
+func testWalletNotFound(t *testing.T, h Harness) {
+	ctx := context.Background()
+	tx := newTx(t, h)
+
+	_, err := tx.GetBalance(ctx, -1)
+	require.ErrorIs(t, err, domain.ErrWalletNotFound)
+}
+
+func testCommitVisible(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+	before := assertBalance(t, h, walletID, 1000)
+
+	tx := newTx(t, h)
+	require.NoError(t, domain.NewWalletUseCases(tx).DebitMoney(ctx, &domain.DebitEntry{
+		WalletID: walletID,
+		Amount:   money.NewFromInt(250),
+	}))
+	require.NoError(t, tx.Commit(ctx))
+
+	after := assertBalance(t, h, walletID, 1250)
+	assert.Greater(t, after.Version, before.Version, "version must grow on every save")
+
+	entries := h.ListEntries(t, walletID)
+	require.Len(t, entries, 1)
+	assertDebit(t, entries[0], 250)
+}
+
+func testRollbackInvisible(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+
+	tx := newTx(t, h)
+	require.NoError(t, domain.NewWalletUseCases(tx).CreditMoney(ctx, &domain.CreditEntry{
+		WalletID: walletID,
+		Amount:   money.NewFromInt(400),
+	}))
+
+	balance, err := tx.GetBalance(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, 600, balance.Amount.AsInt(), "tx must see its own writes")
+
+	require.NoError(t, tx.Rollback(ctx))
+
+	assertBalance(t, h, walletID, 1000)
+	assert.Empty(t, h.ListEntries(t, walletID))
+}
+
+func testConcurrentConflict(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+
+	tx1 := newTx(t, h)
+	tx2 := newTx(t, h)
+
+	b1, err := tx1.GetBalance(ctx, walletID)
+	require.NoError(t, err)
+	b2, err := tx2.GetBalance(ctx, walletID)
+	require.NoError(t, err)
+
+	b1.Amount = b1.Amount.Add(money.NewFromInt(100))
+	require.NoError(t, tx1.SaveBalance(ctx, b1))
+	require.NoError(t, tx1.Commit(ctx))
+
+	// The second writer read a stale balance. The implementation may refuse
+	// either the write itself or the commit, but it must report a conflict.
+	b2.Amount = b2.Amount.Add(money.NewFromInt(200))
+	err = tx2.SaveBalance(ctx, b2)
+	if err == nil {
+		err = tx2.Commit(ctx)
+	} else {
+		_ = tx2.Rollback(ctx)
+	}
+	require.ErrorIs(t, err, entity.ErrTxConflict)
+
+	assertBalance(t, h, walletID, 1100)
+}
+
+func testConcurrentNoConflict(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID1 := h.CreateWallet(t, money.NewFromInt(1000))
+	walletID2 := h.CreateWallet(t, money.NewFromInt(1000))
+
+	tx1 := newTx(t, h)
+	tx2 := newTx(t, h)
+
+	require.NoError(t, domain.NewWalletUseCases(tx1).DebitMoney(ctx, &domain.DebitEntry{
+		WalletID: walletID1,
+		Amount:   money.NewFromInt(100),
+	}))
+	require.NoError(t, domain.NewWalletUseCases(tx2).DebitMoney(ctx, &domain.DebitEntry{
+		WalletID: walletID2,
+		Amount:   money.NewFromInt(200),
+	}))
+	require.NoError(t, tx1.Commit(ctx))
+	require.NoError(t, tx2.Commit(ctx))
+
+	assertBalance(t, h, walletID1, 1100)
+	assertBalance(t, h, walletID2, 1200)
+}
+
+func testEntryOrdering(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(0))
+
+	uc := func() (*domain.WalletUseCases, service.WalletStoreTx) {
+		tx := newTx(t, h)
+		return domain.NewWalletUseCases(tx), tx
+	}
+
+	first := &domain.DebitEntry{WalletID: walletID, Amount: money.NewFromInt(300)}
+	second := &domain.CreditEntry{WalletID: walletID, Amount: money.NewFromInt(100)}
+	third := &domain.DebitEntry{WalletID: walletID, Amount: money.NewFromInt(50)}
+
+	usecase, tx := uc()
+	require.NoError(t, usecase.DebitMoney(ctx, first))
+	require.NoError(t, usecase.CreditMoney(ctx, second))
+	require.NoError(t, tx.Commit(ctx))
+
+	usecase, tx = uc()
+	require.NoError(t, usecase.DebitMoney(ctx, third))
+	require.NoError(t, tx.Commit(ctx))
+
+	// The store assigns increasing IDs and a creation time to every entry.
+	assert.Positive(t, first.ID)
+	assert.Greater(t, second.ID, first.ID)
+	assert.Greater(t, third.ID, second.ID)
+	assert.False(t, first.CreatedAt.IsZero())
+	assert.False(t, third.CreatedAt.Before(first.CreatedAt))
+
+	entries := h.ListEntries(t, walletID)
+	require.Len(t, entries, 3)
+	assertDebit(t, entries[0], 300)
+	assertCredit(t, entries[1], 100)
+	assertDebit(t, entries[2], 50)
+
+	assertBalance(t, h, walletID, 250)
+}
+
+func testEntryDetails(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+
+	bet := &domain.CreditEntry{WalletID: walletID, Amount: money.NewFromInt(100), EntryDetails: domain.EntryDetails{
+		Operation: domain.OperationBet,
+		Reference: "round-1",
+		Initiator: domain.InitiatorUser,
+		Metadata:  json.RawMessage(`{"game": "slots", "round": "r-1"}`),
+	}}
+	win := &domain.DebitEntry{WalletID: walletID, Amount: money.NewFromInt(250), EntryDetails: domain.EntryDetails{
+		Operation: domain.OperationWin,
+		Reference: "round-1",
+		Initiator: domain.InitiatorService,
+		Metadata:  json.RawMessage(`{"round": "r-1"}`),
+	}}
+	deposit := &domain.DebitEntry{WalletID: walletID, Amount: money.NewFromInt(300), EntryDetails: depositDetails}
+
+	tx := newTx(t, h)
+	require.NoError(t, tx.AddCreditEntry(ctx, bet))
+	require.NoError(t, tx.AddDebitEntry(ctx, win))
+	require.NoError(t, tx.AddDebitEntry(ctx, deposit))
+	require.NoError(t, tx.Commit(ctx))
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	all, err := tx.ListEntries(ctx, domain.EntryFilter{WalletID: walletID})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, bet.ID, all[0].ID)
+	assert.Equal(t, walletID, all[0].WalletID)
+	require.NotNil(t, all[0].Credit)
+	assert.Equal(t, 100, all[0].Credit.AsInt())
+	assert.Equal(t, domain.OperationBet, all[0].Operation)
+	assert.Equal(t, "round-1", all[0].Reference)
+	assert.Equal(t, domain.InitiatorUser, all[0].Initiator)
+	assert.JSONEq(t, `{"game": "slots", "round": "r-1"}`, string(all[0].Metadata))
+	assert.False(t, all[0].CreatedAt.IsZero())
+	require.NotNil(t, all[2].Debit)
+	assert.Equal(t, domain.OperationDeposit, all[2].Operation)
+
+	tests := []struct {
+		name   string
+		filter domain.EntryFilter
+		want   []int
+	}{
+		{
+			name:   "by operation",
+			filter: domain.EntryFilter{Operations: []domain.OperationType{domain.OperationWin, domain.OperationDeposit}},
+			want:   []int{win.ID, deposit.ID},
+		},
+		{
+			name:   "by reference",
+			filter: domain.EntryFilter{Reference: "round-1"},
+			want:   []int{bet.ID, win.ID},
+		},
+		{
+			name:   "by initiator",
+			filter: domain.EntryFilter{Initiator: domain.InitiatorUser},
+			want:   []int{bet.ID},
+		},
+		{
+			name:   "by metadata",
+			filter: domain.EntryFilter{Metadata: map[string]string{"round": "r-1", "game": "slots"}},
+			want:   []int{bet.ID},
+		},
+		{
+			name:   "by time",
+			filter: domain.EntryFilter{To: bet.CreatedAt},
+		},
+		{
+			name:   "after cursor with limit",
+			filter: domain.EntryFilter{AfterID: bet.ID, Limit: 1},
+			want:   []int{win.ID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			filter.WalletID = walletID
+			entries, err := tx.ListEntries(ctx, filter)
+			require.NoError(t, err)
+
+			var got []int
+			for _, e := range entries {
+				got = append(got, e.ID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func testEntryReversal(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+
+	tx := newTx(t, h)
+	bet := &domain.CreditEntry{WalletID: walletID, Amount: money.NewFromInt(100), EntryDetails: domain.EntryDetails{
+		Operation: domain.OperationBet,
+		Initiator: domain.InitiatorService,
+	}}
+	require.NoError(t, tx.AddCreditEntry(ctx, bet))
+	require.NoError(t, tx.Commit(ctx))
+
+	newReversal := func() *domain.DebitEntry {
+		return &domain.DebitEntry{WalletID: walletID, Amount: money.NewFromInt(100), EntryDetails: domain.EntryDetails{
+			Operation:  domain.OperationReversal,
+			Initiator:  domain.InitiatorAdmin,
+			Metadata:   json.RawMessage(`{"actor": "support", "reason": "void round"}`),
+			ReversalOf: bet.ID,
+		}}
+	}
+
+	// Both txs look the entry up before either reverses it.
+	tx1, tx2 := newTx(t, h), newTx(t, h)
+	original, err := tx1.GetEntry(ctx, bet.ID)
+	require.NoError(t, err)
+	assert.Zero(t, original.ReversedBy)
+	_, err = tx2.GetEntry(ctx, bet.ID)
+	require.NoError(t, err)
+
+	reversal := newReversal()
+	require.NoError(t, tx1.AddDebitEntry(ctx, reversal))
+	require.NoError(t, tx1.Commit(ctx))
+
+	// Depending on the store the second reversal fails right away or as a
+	// conflict, after which a retry finds the first one.
+	err = tx2.AddDebitEntry(ctx, newReversal())
+	if err == nil {
+		err = tx2.Commit(ctx)
+	} else {
+		_ = tx2.Rollback(ctx)
+	}
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, domain.ErrAlreadyReversed) || errors.Is(err, entity.ErrTxConflict), err)
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	original, err = tx.GetEntry(ctx, bet.ID)
+	require.NoError(t, err)
+	assert.Equal(t, reversal.ID, original.ReversedBy)
+	assert.Equal(t, walletID, original.WalletID)
+
+	got, err := tx.GetEntry(ctx, reversal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.OperationReversal, got.Operation)
+	assert.Equal(t, bet.ID, got.ReversalOf)
+	assert.Zero(t, got.ReversedBy)
+
+	entries, err := tx.ListEntries(ctx, domain.EntryFilter{WalletID: walletID})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, reversal.ID, entries[0].ReversedBy)
+	assert.Equal(t, bet.ID, entries[1].ReversalOf)
+
+	_, err = tx.GetEntry(ctx, math.MaxInt32)
+	require.ErrorIs(t, err, domain.ErrEntryNotFound)
+
+	err = tx.AddDebitEntry(ctx, newReversal())
+	require.ErrorIs(t, err, domain.ErrAlreadyReversed)
+}
+
+func testAuditLog(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+
+	tx := newTx(t, h)
+	bet := &domain.CreditEntry{WalletID: walletID, Amount: money.NewFromInt(100), EntryDetails: domain.EntryDetails{
+		Operation: domain.OperationBet,
+		Reference: "round-1",
+		Initiator: domain.InitiatorUser,
+		Metadata:  json.RawMessage(`{"game": "slots", "round": "r-1"}`),
+	}}
+	require.NoError(t, tx.AddCreditEntry(ctx, bet))
+	win := &domain.DebitEntry{WalletID: walletID, Amount: money.NewFromInt(250), Bonus: money.NewFromInt(50), EntryDetails: domain.EntryDetails{
+		Operation: domain.OperationWin,
+		Initiator: domain.InitiatorService,
+	}}
+	require.NoError(t, tx.AddDebitEntry(ctx, win))
+	require.NoError(t, tx.Commit(ctx))
+
+	tx = newTx(t, h)
+	reversal := &domain.DebitEntry{WalletID: walletID, Amount: money.NewFromInt(100), EntryDetails: domain.EntryDetails{
+		Operation:  domain.OperationReversal,
+		Initiator:  domain.InitiatorAdmin,
+		ReversalOf: bet.ID,
+	}}
+	require.NoError(t, tx.AddDebitEntry(ctx, reversal))
+	require.NoError(t, tx.Commit(ctx))
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	records, err := tx.ListAuditRecords(ctx, walletID)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	for i, want := range []int{bet.ID, win.ID, reversal.ID} {
+		assert.Equal(t, i+1, records[i].Seq)
+		assert.Equal(t, want, records[i].EntryID)
+	}
+	assert.Nil(t, records[0].PrevHash)
+	assert.Equal(t, records[1].Hash, records[2].PrevHash)
+
+	// The records the store keeps must check out against its own entries.
+	report, err := domain.NewWalletUseCases(tx).VerifyAudit(ctx, walletID)
+	require.NoError(t, err)
+	assert.Empty(t, report.Violations)
+	assert.Equal(t, 3, report.Records)
+	assert.Equal(t, records[2].Hash, report.Head)
+
+	ids, err := tx.ListWalletIDs(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, ids, walletID)
+}
+
+func testRunningBalance(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(0))
+
+	tx := newTx(t, h)
+	deposit := &domain.DebitEntry{
+		WalletID: walletID, Amount: money.NewFromInt(1000), Bonus: money.NewFromInt(400), EntryDetails: depositDetails,
+	}
+	require.NoError(t, tx.AddDebitEntry(ctx, deposit))
+	require.NoError(t, tx.Commit(ctx))
+	assert.Equal(t, 600, deposit.BalanceAfter.AsInt())
+	assert.Equal(t, 400, deposit.BonusAfter.AsInt())
+
+	tx = newTx(t, h)
+	bet := &domain.CreditEntry{
+		WalletID: walletID, Amount: money.NewFromInt(300), Bonus: money.NewFromInt(100), EntryDetails: betDetails,
+	}
+	require.NoError(t, tx.AddCreditEntry(ctx, bet))
+	// The balance follows entries added earlier in the same tx.
+	win := &domain.DebitEntry{WalletID: walletID, Amount: money.NewFromInt(50), EntryDetails: domain.EntryDetails{
+		Operation: domain.OperationWin, Reference: "round-1", Initiator: domain.InitiatorService,
+	}}
+	require.NoError(t, tx.AddDebitEntry(ctx, win))
+	require.NoError(t, tx.Commit(ctx))
+	assert.Equal(t, 400, bet.BalanceAfter.AsInt())
+	assert.Equal(t, 300, bet.BonusAfter.AsInt())
+	assert.Equal(t, 450, win.BalanceAfter.AsInt())
+	assert.Equal(t, 300, win.BonusAfter.AsInt())
+
+	tx = newTx(t, h)
+	require.NoError(t, tx.AddCreditEntry(ctx, &domain.CreditEntry{
+		WalletID: walletID, Amount: money.NewFromInt(100), EntryDetails: betDetails,
+	}))
+	require.NoError(t, tx.Rollback(ctx))
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	entries, err := tx.ListEntries(ctx, domain.EntryFilter{WalletID: walletID})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, 600, entries[0].BalanceAfter.AsInt())
+	assert.Equal(t, 400, entries[0].BonusAfter.AsInt())
+	assert.Equal(t, 400, entries[1].BalanceAfter.AsInt())
+	assert.Equal(t, 300, entries[1].BonusAfter.AsInt())
+	assert.Equal(t, 450, entries[2].BalanceAfter.AsInt())
+	assert.Equal(t, 300, entries[2].BonusAfter.AsInt())
+
+	// The rolled back entry left no gap in the chain.
+	report, err := domain.NewWalletUseCases(tx).VerifyAudit(ctx, walletID)
+	require.NoError(t, err)
+	assert.Empty(t, report.Violations)
+}
+
+func testEntrySums(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(0))
+
+	tx := newTx(t, h)
+	require.NoError(t, tx.AddDebitEntry(ctx, &domain.DebitEntry{
+		WalletID: walletID, Amount: money.NewFromInt(1000), Bonus: money.NewFromInt(400), EntryDetails: depositDetails,
+	}))
+	require.NoError(t, tx.AddCreditEntry(ctx, &domain.CreditEntry{
+		WalletID: walletID, Amount: money.NewFromInt(300), Bonus: money.NewFromInt(100), EntryDetails: betDetails,
+	}))
+	require.NoError(t, tx.Commit(ctx))
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	to := time.Now().Add(time.Hour)
+	sum, err := tx.SumEntries(ctx, walletID, time.Time{}, to)
+	require.NoError(t, err)
+	assert.Equal(t, 400, sum.Amount.AsInt())
+	assert.Equal(t, 300, sum.Bonus.AsInt())
+
+	sum, err = tx.SumEntries(ctx, walletID, to, to.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, sum.Amount.AsInt())
+	assert.Zero(t, sum.Bonus.AsInt())
+
+	days, err := tx.SumEntriesByDay(ctx, walletID, time.Time{}, to)
+	require.NoError(t, err)
+	require.NotEmpty(t, days)
+	var total domain.EntrySum
+	for _, day := range days {
+		assert.Equal(t, day.Day, day.Day.UTC().Truncate(24*time.Hour))
+		total.Amount, total.Bonus = total.Amount.Add(day.Amount), total.Bonus.Add(day.Bonus)
+	}
+	assert.Equal(t, 400, total.Amount.AsInt())
+	assert.Equal(t, 300, total.Bonus.AsInt())
+}
+
+func testSnapshots(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(0))
+	day := time.Date(2024, 6, 19, 0, 0, 0, 0, time.UTC)
+
+	tx := newTx(t, h)
+	first := &domain.BalanceSnapshot{WalletID: walletID, At: day, Amount: money.NewFromInt(1000)}
+	require.NoError(t, tx.AddSnapshot(ctx, first))
+	second := &domain.BalanceSnapshot{
+		WalletID: walletID, At: day.Add(48 * time.Hour), Amount: money.NewFromInt(700), Bonus: money.NewFromInt(200),
+	}
+	require.NoError(t, tx.AddSnapshot(ctx, second))
+	require.NoError(t, tx.Commit(ctx))
+	assert.False(t, first.CreatedAt.IsZero())
+
+	tx = newTx(t, h)
+	err := tx.AddSnapshot(ctx, &domain.BalanceSnapshot{WalletID: walletID, At: day})
+	if err == nil {
+		err = tx.Commit(ctx)
+	} else {
+		_ = tx.Rollback(ctx)
+	}
+	require.ErrorIs(t, err, entity.ErrTxConflict)
+
+	tx = newTx(t, h)
+	err = tx.AddSnapshot(ctx, &domain.BalanceSnapshot{WalletID: -1, At: day})
+	require.ErrorIs(t, err, domain.ErrWalletNotFound)
+	_ = tx.Rollback(ctx)
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.GetSnapshot(ctx, walletID, day.Add(-time.Second))
+	require.ErrorIs(t, err, domain.ErrSnapshotNotFound)
+
+	snapshot, err := tx.GetSnapshot(ctx, walletID, day)
+	require.NoError(t, err)
+	assert.True(t, day.Equal(snapshot.At))
+	assert.Equal(t, 1000, snapshot.Amount.AsInt())
+
+	snapshot, err = tx.GetSnapshot(ctx, walletID, day.Add(47*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, day.Equal(snapshot.At))
+
+	snapshot, err = tx.GetSnapshot(ctx, walletID, day.Add(72*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, second.At.Equal(snapshot.At))
+	assert.Equal(t, 700, snapshot.Amount.AsInt())
+	assert.Equal(t, 200, snapshot.Bonus.AsInt())
+}
+
+func testNegativeBalance(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(100))
+
+	tx := newTx(t, h)
+	err := tx.SaveBalance(ctx, &domain.WalletBalance{
+		WalletID: walletID,
+		Amount:   money.NewFromInt(-1),
+	})
+	if err == nil {
+		err = tx.Commit(ctx)
+	} else {
+		_ = tx.Rollback(ctx)
+	}
+	require.Error(t, err)
+	require.NotErrorIs(t, err, entity.ErrTxConflict)
+
+	assertBalance(t, h, walletID, 100)
+}
+
+func testFlaggedBalance(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(100))
+
+	tx := newTx(t, h)
+	require.NoError(t, tx.SaveBalance(ctx, &domain.WalletBalance{
+		WalletID: walletID,
+		Amount:   money.NewFromInt(-50),
+		Flagged:  true,
+	}))
+	require.NoError(t, tx.Commit(ctx))
+
+	balance := assertBalance(t, h, walletID, -50)
+	assert.True(t, balance.Flagged)
+}
+
+func testWalletSearch(t *testing.T, h Harness) {
+	ctx := context.Background()
+	first := h.CreateWallet(t, money.NewFromInt(1000))
+	frozen := h.CreateWallet(t, money.NewFromInt(5000))
+	last := h.CreateWallet(t, money.NewFromInt(3000))
+
+	tx := newTx(t, h)
+	require.NoError(t, tx.SaveBalance(ctx, &domain.WalletBalance{WalletID: frozen, Amount: money.NewFromInt(5000), Frozen: true}))
+	require.NoError(t, tx.Commit(ctx))
+
+	balance := assertBalance(t, h, frozen, 5000)
+	assert.True(t, balance.Frozen)
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	search := func(filter domain.WalletFilter) []int {
+		t.Helper()
+		// Other tests may have created wallets before these.
+		filter.AfterID = first - 1
+		balances, err := tx.SearchWallets(ctx, filter)
+		require.NoError(t, err)
+		var ids []int
+		for _, b := range balances {
+			if b.WalletID <= last {
+				ids = append(ids, b.WalletID)
+			}
+		}
+		return ids
+	}
+	yes := true
+	minAmount, maxAmount := money.NewFromInt(2000), money.NewFromInt(4000)
+
+	assert.Equal(t, []int{first, frozen, last}, search(domain.WalletFilter{}))
+	assert.Equal(t, []int{first, frozen}, search(domain.WalletFilter{Limit: 2}))
+	assert.Equal(t, []int{frozen}, search(domain.WalletFilter{Frozen: &yes}))
+	assert.Empty(t, search(domain.WalletFilter{Flagged: &yes}))
+	assert.Equal(t, []int{frozen, last}, search(domain.WalletFilter{MinAmount: &minAmount}))
+	assert.Equal(t, []int{last}, search(domain.WalletFilter{MinAmount: &minAmount, MaxAmount: &maxAmount}))
+}
+
+func testAdminActions(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+
+	tx := newTx(t, h)
+	freeze := &domain.AdminAction{WalletID: walletID, Kind: domain.AdminFreeze, Actor: "support", Reason: "fraud check"}
+	require.NoError(t, tx.AddAdminAction(ctx, freeze))
+	adjust := &domain.AdminAction{
+		WalletID: walletID,
+		Kind:     domain.AdminAdjustBalance,
+		Actor:    "finance",
+		Reason:   "goodwill",
+		Details:  json.RawMessage(`{"amount": "1.000", "entryId": 7}`),
+	}
+	require.NoError(t, tx.AddAdminAction(ctx, adjust))
+	require.NoError(t, tx.Commit(ctx))
+	assert.NotZero(t, freeze.ID)
+	assert.Greater(t, adjust.ID, freeze.ID)
+	assert.False(t, adjust.CreatedAt.IsZero())
+
+	tx = newTx(t, h)
+	require.NoError(t, tx.AddAdminAction(ctx, &domain.AdminAction{WalletID: walletID, Kind: domain.AdminUnfreeze, Actor: "support"}))
+	require.NoError(t, tx.Rollback(ctx))
+
+	tx = newTx(t, h)
+	err := tx.AddAdminAction(ctx, &domain.AdminAction{WalletID: -1, Kind: domain.AdminFreeze, Actor: "support"})
+	require.ErrorIs(t, err, domain.ErrWalletNotFound)
+	_ = tx.Rollback(ctx)
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	actions, err := tx.ListAdminActions(ctx, domain.AdminActionFilter{WalletID: walletID})
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+	assert.Equal(t, freeze.ID, actions[0].ID)
+	assert.Equal(t, domain.AdminFreeze, actions[0].Kind)
+	assert.Equal(t, "fraud check", actions[0].Reason)
+	assert.Nil(t, actions[0].Details)
+	assert.Equal(t, "finance", actions[1].Actor)
+	assert.JSONEq(t, string(adjust.Details), string(actions[1].Details))
+
+	actions, err = tx.ListAdminActions(ctx, domain.AdminActionFilter{WalletID: walletID, Actor: "finance"})
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	assert.Equal(t, adjust.ID, actions[0].ID)
+
+	actions, err = tx.ListAdminActions(ctx, domain.AdminActionFilter{WalletID: walletID, AfterID: freeze.ID, Kind: domain.AdminAdjustBalance})
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	assert.Equal(t, adjust.ID, actions[0].ID)
+}
+
+func testNegativeEntry(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(100))
+
+	tx := newTx(t, h)
+	err := tx.AddDebitEntry(ctx, &domain.DebitEntry{
+		WalletID: walletID,
+		Amount:   money.NewFromInt(-1),
+	})
+	if err == nil {
+		err = tx.Commit(ctx)
+	} else {
+		_ = tx.Rollback(ctx)
+	}
+	require.Error(t, err)
+
+	assert.Empty(t, h.ListEntries(t, walletID))
+}
+
+func testBonusBucket(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+
+	tx := newTx(t, h)
+	usecase := domain.NewWalletUseCases(tx)
+	require.NoError(t, usecase.DebitMoney(ctx, &domain.DebitEntry{
+		WalletID: walletID,
+		Amount:   money.NewFromInt(500),
+		Bonus:    money.NewFromInt(300),
+	}))
+	require.NoError(t, usecase.CreditMoney(ctx, &domain.CreditEntry{
+		WalletID: walletID,
+		Amount:   money.NewFromInt(1300),
+	}))
+	require.NoError(t, tx.Commit(ctx))
+
+	balance := assertBalance(t, h, walletID, 0)
+	assert.Equal(t, 200, balance.Bonus.AsInt())
+
+	entries := h.ListEntries(t, walletID)
+	require.Len(t, entries, 2)
+	assertDebit(t, entries[0], 500)
+	assert.Equal(t, 300, entries[0].Bonus.AsInt())
+	assertCredit(t, entries[1], 1300)
+	assert.Equal(t, 100, entries[1].Bonus.AsInt())
+
+	tx = newTx(t, h)
+	err := tx.SaveBalance(ctx, &domain.WalletBalance{
+		WalletID: walletID,
+		Bonus:    money.NewFromInt(-1),
+	})
+	if err == nil {
+		err = tx.Commit(ctx)
+	} else {
+		_ = tx.Rollback(ctx)
+	}
+	require.Error(t, err, "negative bonus must be rejected")
+}
+
+func testBonusGrants(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	tx := newTx(t, h)
+	usecase := domain.NewWalletUseCases(tx)
+	first := &domain.BonusGrant{WalletID: walletID, Amount: money.NewFromInt(500), Multiplier: 2, ExpiresAt: expiresAt}
+	second := &domain.BonusGrant{WalletID: walletID, Amount: money.NewFromInt(200), Multiplier: 1, ExpiresAt: expiresAt}
+	require.NoError(t, usecase.GrantBonus(ctx, first))
+	require.NoError(t, usecase.GrantBonus(ctx, second))
+	require.NoError(t, usecase.CreditMoney(ctx, &domain.CreditEntry{
+		WalletID: walletID,
+		Amount:   money.NewFromInt(300),
+	}))
+
+	grants, err := tx.ListBonusGrants(ctx, walletID)
+	require.NoError(t, err)
+	require.Len(t, grants, 2, "tx must see its own grants")
+	require.NoError(t, tx.Commit(ctx))
+
+	assert.Positive(t, first.ID)
+	assert.Greater(t, second.ID, first.ID)
+	assert.False(t, first.CreatedAt.IsZero())
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	grants, err = tx.ListBonusGrants(ctx, walletID)
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+	assert.Equal(t, first.ID, grants[0].ID)
+	assert.Equal(t, domain.BonusActive, grants[0].Status)
+	assert.Equal(t, 300, grants[0].Wagered.AsInt())
+	assert.Equal(t, 500, grants[0].Amount.AsInt())
+	assert.Equal(t, 2, grants[0].Multiplier)
+	assert.True(t, expiresAt.Equal(grants[0].ExpiresAt))
+	assert.Equal(t, second.ID, grants[1].ID)
+	assert.Equal(t, 0, grants[1].Wagered.AsInt())
+
+	grants, err = tx.ListBonusGrants(ctx, walletID+1)
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+}
+
+func testLimits(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+	pendingAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	deposit := money.NewFromInt(5000)
+	loss, higherLoss := money.NewFromInt(2000), money.NewFromInt(3000)
+
+	tx := newTx(t, h)
+	require.NoError(t, tx.SaveLimit(ctx, &domain.Limit{
+		WalletID: walletID, Kind: domain.LimitDeposit, Period: domain.LimitDay, Amount: &deposit,
+	}))
+	require.NoError(t, tx.SaveLimit(ctx, &domain.Limit{
+		WalletID: walletID, Kind: domain.LimitLoss, Period: domain.LimitWeek, Amount: &loss,
+		PendingAmount: &higherLoss, PendingAt: pendingAt,
+	}))
+	require.NoError(t, tx.SaveLimit(ctx, &domain.Limit{
+		WalletID: walletID, Kind: domain.LimitWager, Period: domain.LimitMonth, Amount: &deposit,
+		PendingAt: pendingAt,
+	}))
+	require.NoError(t, tx.Commit(ctx))
+
+	tx = newTx(t, h)
+	limits, err := tx.GetLimits(ctx, walletID)
+	require.NoError(t, err)
+	require.Len(t, limits, 3)
+
+	assert.Equal(t, domain.LimitDeposit, limits[0].Kind)
+	assert.Equal(t, domain.LimitDay, limits[0].Period)
+	require.NotNil(t, limits[0].Amount)
+	assert.Equal(t, 5000, limits[0].Amount.AsInt())
+	assert.Nil(t, limits[0].PendingAmount)
+	assert.True(t, limits[0].PendingAt.IsZero())
+
+	assert.Equal(t, domain.LimitLoss, limits[1].Kind)
+	require.NotNil(t, limits[1].PendingAmount)
+	assert.Equal(t, 3000, limits[1].PendingAmount.AsInt())
+	assert.True(t, pendingAt.Equal(limits[1].PendingAt))
+
+	assert.Equal(t, domain.LimitWager, limits[2].Kind)
+	assert.Nil(t, limits[2].PendingAmount, "pending removal")
+	assert.True(t, pendingAt.Equal(limits[2].PendingAt))
+
+	// A limit with neither an amount nor a pending change is deleted.
+	require.NoError(t, tx.SaveLimit(ctx, &domain.Limit{WalletID: walletID, Kind: domain.LimitDeposit, Period: domain.LimitDay}))
+	require.NoError(t, tx.Commit(ctx))
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	limits, err = tx.GetLimits(ctx, walletID)
+	require.NoError(t, err)
+	require.Len(t, limits, 2)
+	assert.Equal(t, domain.LimitLoss, limits[0].Kind)
+}
+
+func testActivity(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+	now := time.Now().Truncate(time.Hour).Add(30 * time.Minute)
+
+	tx := newTx(t, h)
+	require.NoError(t, tx.AddActivity(ctx, walletID, now.Add(-48*time.Hour), domain.Activity{Deposits: money.NewFromInt(100)}))
+	require.NoError(t, tx.AddActivity(ctx, walletID, now.Add(-time.Hour), domain.Activity{Wagers: money.NewFromInt(20)}))
+	require.NoError(t, tx.AddActivity(ctx, walletID, now, domain.Activity{Deposits: money.NewFromInt(10), Wins: money.NewFromInt(5)}))
+	require.NoError(t, tx.AddActivity(ctx, walletID, now, domain.Activity{Wagers: money.NewFromInt(7)}))
+
+	activity, err := tx.GetActivity(ctx, walletID, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 27, activity.Wagers.AsInt(), "tx must see its own activity")
+	require.NoError(t, tx.Commit(ctx))
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	activity, err = tx.GetActivity(ctx, walletID, now.Add(-72*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 110, activity.Deposits.AsInt())
+	assert.Equal(t, 27, activity.Wagers.AsInt())
+	assert.Equal(t, 5, activity.Wins.AsInt())
+
+	// The bucket that since falls into is included.
+	activity, err = tx.GetActivity(ctx, walletID, now.Add(-40*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 10, activity.Deposits.AsInt())
+	assert.Equal(t, 27, activity.Wagers.AsInt())
+
+	activity, err = tx.GetActivity(ctx, walletID+1, now.Add(-72*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, activity.Deposits.AsInt())
+
+	err = tx.AddActivity(ctx, -1, now, domain.Activity{Deposits: money.NewFromInt(1)})
+	require.ErrorIs(t, err, domain.ErrWalletNotFound)
+}
+
+func testExclusions(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+	until := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	tx := newTx(t, h)
+	day := domain.Exclusion{WalletID: walletID, Period: domain.ExclusionDay, Until: until}
+	require.NoError(t, tx.AddExclusion(ctx, &day))
+	permanent := domain.Exclusion{WalletID: walletID, Period: domain.ExclusionPermanent}
+	require.NoError(t, tx.AddExclusion(ctx, &permanent))
+	require.NoError(t, tx.Commit(ctx))
+
+	assert.NotZero(t, day.ID)
+	assert.Greater(t, permanent.ID, day.ID)
+	assert.False(t, day.CreatedAt.IsZero())
+
+	liftedAt := time.Now().Truncate(time.Second)
+	tx = newTx(t, h)
+	permanent.LiftedAt, permanent.LiftedBy, permanent.LiftReason = liftedAt, "support", "opened by mistake"
+	require.NoError(t, tx.SaveExclusion(ctx, &permanent))
+	require.NoError(t, tx.Commit(ctx))
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	exclusions, err := tx.ListExclusions(ctx, walletID)
+	require.NoError(t, err)
+	require.Len(t, exclusions, 2)
+
+	assert.Equal(t, domain.ExclusionDay, exclusions[0].Period)
+	assert.True(t, until.Equal(exclusions[0].Until))
+	assert.True(t, exclusions[0].LiftedAt.IsZero())
+
+	assert.Equal(t, domain.ExclusionPermanent, exclusions[1].Period)
+	assert.True(t, exclusions[1].Until.IsZero())
+	assert.True(t, liftedAt.Equal(exclusions[1].LiftedAt))
+	assert.Equal(t, "support", exclusions[1].LiftedBy)
+	assert.Equal(t, "opened by mistake", exclusions[1].LiftReason)
+
+	err = tx.AddExclusion(ctx, &domain.Exclusion{WalletID: walletID + 1000, Period: domain.ExclusionDay, Until: until})
+	require.ErrorIs(t, err, domain.ErrWalletNotFound)
+}
+
+func testWithdrawals(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+	at := time.Now().Truncate(time.Second)
+
+	tx := newTx(t, h)
+	hold := domain.CreditEntry{WalletID: walletID, Amount: money.NewFromInt(300), EntryDetails: withdrawalDetails}
+	require.NoError(t, tx.AddCreditEntry(ctx, &hold))
+	withdrawal := domain.Withdrawal{
+		WalletID:    walletID,
+		Amount:      money.NewFromInt(300),
+		Status:      domain.WithdrawalRequested,
+		HoldEntryID: hold.ID,
+	}
+	require.NoError(t, tx.AddWithdrawal(ctx, &withdrawal))
+	require.NoError(t, tx.AddWithdrawalTransition(ctx, &domain.WithdrawalTransition{
+		WithdrawalID: withdrawal.ID,
+		WalletID:     walletID,
+		To:           domain.WithdrawalRequested,
+		Actor:        "player",
+		At:           at,
+	}))
+	require.NoError(t, tx.Commit(ctx))
+
+	assert.NotZero(t, withdrawal.ID)
+	assert.False(t, withdrawal.CreatedAt.IsZero())
+
+	tx = newTx(t, h)
+	withdrawal.Status = domain.WithdrawalCancelled
+	require.NoError(t, tx.SaveWithdrawal(ctx, &withdrawal))
+	require.NoError(t, tx.AddWithdrawalTransition(ctx, &domain.WithdrawalTransition{
+		WithdrawalID: withdrawal.ID,
+		WalletID:     walletID,
+		From:         domain.WithdrawalRequested,
+		To:           domain.WithdrawalCancelled,
+		Actor:        "support",
+		Reason:       "duplicate",
+		At:           at,
+	}))
+	require.NoError(t, tx.Commit(ctx))
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	got, err := tx.GetWithdrawal(ctx, withdrawal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, walletID, got.WalletID)
+	assert.Equal(t, 300, got.Amount.AsInt())
+	assert.Equal(t, domain.WithdrawalCancelled, got.Status)
+	assert.Equal(t, hold.ID, got.HoldEntryID)
+
+	withdrawals, err := tx.ListWithdrawals(ctx, walletID)
+	require.NoError(t, err)
+	require.Len(t, withdrawals, 1)
+	assert.Equal(t, withdrawal.ID, withdrawals[0].ID)
+
+	transitions, err := tx.ListWithdrawalTransitions(ctx, withdrawal.ID)
+	require.NoError(t, err)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, domain.WithdrawalStatus(""), transitions[0].From)
+	assert.Equal(t, "player", transitions[0].Actor)
+	assert.Equal(t, domain.WithdrawalCancelled, transitions[1].To)
+	assert.Equal(t, "support", transitions[1].Actor)
+	assert.Equal(t, "duplicate", transitions[1].Reason)
+	assert.True(t, at.Equal(transitions[1].At))
+
+	_, err = tx.GetWithdrawal(ctx, withdrawal.ID+1000)
+	require.ErrorIs(t, err, domain.ErrWithdrawalNotFound)
+}
+
+func testWithdrawalConflict(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+
+	tx := newTx(t, h)
+	hold := domain.CreditEntry{WalletID: walletID, Amount: money.NewFromInt(300), EntryDetails: withdrawalDetails}
+	require.NoError(t, tx.AddCreditEntry(ctx, &hold))
+	withdrawal := domain.Withdrawal{
+		WalletID:    walletID,
+		Amount:      money.NewFromInt(300),
+		Status:      domain.WithdrawalPendingReview,
+		HoldEntryID: hold.ID,
+	}
+	require.NoError(t, tx.AddWithdrawal(ctx, &withdrawal))
+	require.NoError(t, tx.Commit(ctx))
+
+	// Both txs read the withdrawal before either writes it.
+	tx1, tx2 := newTx(t, h), newTx(t, h)
+	w1, err := tx1.GetWithdrawal(ctx, withdrawal.ID)
+	require.NoError(t, err)
+	w2, err := tx2.GetWithdrawal(ctx, withdrawal.ID)
+	require.NoError(t, err)
+
+	w1.Status = domain.WithdrawalApproved
+	require.NoError(t, tx1.SaveWithdrawal(ctx, w1))
+	require.NoError(t, tx1.Commit(ctx))
+
+	// Approval does not touch the balance, yet cancelling on a stale status
+	// must be reported as a conflict.
+	w2.Status = domain.WithdrawalCancelled
+	err = tx2.SaveWithdrawal(ctx, w2)
+	if err == nil {
+		err = tx2.Commit(ctx)
+	} else {
+		_ = tx2.Rollback(ctx)
+	}
+	require.ErrorIs(t, err, entity.ErrTxConflict)
+}
+
+func testDeposits(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+
+	tx := newTx(t, h)
+	_, err := tx.GetDeposit(ctx, "fake", "dep-1")
+	require.ErrorIs(t, err, domain.ErrDepositNotFound)
+
+	deposit := domain.Deposit{
+		WalletID:  walletID,
+		Provider:  "fake",
+		Reference: "dep-1",
+		Amount:    money.NewFromInt(300),
+		Status:    domain.DepositPending,
+	}
+	require.NoError(t, tx.AddDeposit(ctx, &deposit))
+	require.NoError(t, tx.Commit(ctx))
+
+	assert.NotZero(t, deposit.ID)
+	assert.False(t, deposit.CreatedAt.IsZero())
+
+	tx = newTx(t, h)
+	entry := domain.DebitEntry{WalletID: walletID, Amount: money.NewFromInt(300), EntryDetails: depositDetails}
+	require.NoError(t, tx.AddDebitEntry(ctx, &entry))
+	deposit.Status, deposit.EntryID = domain.DepositConfirmed, entry.ID
+	require.NoError(t, tx.SaveDeposit(ctx, &deposit))
+	require.NoError(t, tx.Commit(ctx))
+
+	tx = newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	got, err := tx.GetDeposit(ctx, "fake", "dep-1")
+	require.NoError(t, err)
+	assert.Equal(t, deposit.ID, got.ID)
+	assert.Equal(t, walletID, got.WalletID)
+	assert.Equal(t, 300, got.Amount.AsInt())
+	assert.Equal(t, domain.DepositConfirmed, got.Status)
+	assert.Equal(t, entry.ID, got.EntryID)
+	assert.Zero(t, got.ReversalEntryID)
+
+	_, err = tx.GetDeposit(ctx, "other", "dep-1")
+	require.ErrorIs(t, err, domain.ErrDepositNotFound)
+}
+
+func testDepositConflict(t *testing.T, h Harness) {
+	ctx := context.Background()
+	walletID := h.CreateWallet(t, money.NewFromInt(1000))
+
+	// Both txs look the reference up before either adds it.
+	tx1, tx2 := newTx(t, h), newTx(t, h)
+	_, err := tx1.GetDeposit(ctx, "fake", "dep-race")
+	require.ErrorIs(t, err, domain.ErrDepositNotFound)
+	_, err = tx2.GetDeposit(ctx, "fake", "dep-race")
+	require.ErrorIs(t, err, domain.ErrDepositNotFound)
+
+	newDeposit := func() *domain.Deposit {
+		return &domain.Deposit{
+			WalletID:  walletID,
+			Provider:  "fake",
+			Reference: "dep-race",
+			Amount:    money.NewFromInt(300),
+			Status:    domain.DepositPending,
+		}
+	}
+	require.NoError(t, tx1.AddDeposit(ctx, newDeposit()))
+	require.NoError(t, tx1.Commit(ctx))
+
+	err = tx2.AddDeposit(ctx, newDeposit())
+	if err == nil {
+		err = tx2.Commit(ctx)
+	} else {
+		_ = tx2.Rollback(ctx)
+	}
+	require.ErrorIs(t, err, entity.ErrTxConflict)
+}
+
+var (
+	withdrawalDetails = domain.EntryDetails{Operation: domain.OperationWithdrawal, Initiator: domain.InitiatorUser}
+	depositDetails    = domain.EntryDetails{Operation: domain.OperationDeposit, Reference: "dep-1", Initiator: domain.InitiatorService}
+	betDetails        = domain.EntryDetails{Operation: domain.OperationBet, Reference: "round-1", Initiator: domain.InitiatorUser}
+)
+
+func newTx(t *testing.T, h Harness) service.WalletStoreTx {
+	t.Helper()
+	tx, err := h.NewTx(context.Background())
+	require.NoError(t, err)
+	return tx
+}
+
+func assertBalance(t *testing.T, h Harness, walletID int, want int) *domain.WalletBalance {
+	t.Helper()
+	ctx := context.Background()
+
+	tx := newTx(t, h)
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	balance, err := tx.GetBalance(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, walletID, balance.WalletID)
+	assert.Equal(t, want, balance.Amount.AsInt())
+	return balance
+}
+
+func assertDebit(t *testing.T, e Entry, want int) {
+	t.Helper()
+	require.NotNil(t, e.Debit, "expected debit entry")
+	assert.Nil(t, e.Credit)
+	assert.Equal(t, want, e.Debit.AsInt())
+}
+
+func assertCredit(t *testing.T, e Entry, want int) {
+	t.Helper()
+	require.NotNil(t, e.Credit, "expected credit entry")
+	assert.Nil(t, e.Debit)
+	assert.Equal(t, want, e.Credit.AsInt())
+}
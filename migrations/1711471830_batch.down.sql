@@ -0,0 +1,2 @@
+DROP TABLE batch_item;
+DROP TABLE batch;
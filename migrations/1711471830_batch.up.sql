@@ -0,0 +1,25 @@
+CREATE TABLE batch
+(
+    id         BIGSERIAL   NOT NULL PRIMARY KEY,
+    mode       TEXT        NOT NULL,
+    status     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT mode_known CHECK (mode IN ('atomic', 'best_effort')),
+    CONSTRAINT status_known CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+);
+
+CREATE TABLE batch_item
+(
+    batch_id  BIGINT NOT NULL,
+    seq       INT    NOT NULL,
+    wallet_id BIGINT NOT NULL,
+    operation TEXT   NOT NULL,
+    amount    INT    NOT NULL,
+    status    TEXT   NOT NULL,
+    error     TEXT            DEFAULT NULL,
+    PRIMARY KEY (batch_id, seq),
+    CONSTRAINT fk_batch FOREIGN KEY (batch_id) REFERENCES batch (id) ON DELETE CASCADE,
+    CONSTRAINT operation_known CHECK (operation IN ('debit', 'credit')),
+    CONSTRAINT status_known CHECK (status IN ('pending', 'succeeded', 'failed', 'rolled_back'))
+);
@@ -0,0 +1,2 @@
+ALTER TABLE wallet_balance
+    DROP COLUMN version;
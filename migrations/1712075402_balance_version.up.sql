@@ -0,0 +1,2 @@
+ALTER TABLE wallet_balance
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+ALTER TABLE wallet_entry
+    DROP COLUMN bonus_amount;
+
+ALTER TABLE wallet_balance
+    DROP COLUMN bonus_amount;
@@ -0,0 +1,9 @@
+ALTER TABLE wallet_balance
+    ADD COLUMN bonus_amount INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT bonus_amount_nonnegative CHECK (bonus_amount >= 0);
+
+-- The part of the entry amount that went to or came from the bonus bucket.
+ALTER TABLE wallet_entry
+    ADD COLUMN bonus_amount INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT bonus_amount_within_entry CHECK (
+        bonus_amount >= 0 AND bonus_amount <= COALESCE(debit_amount, credit_amount));
@@ -0,0 +1,19 @@
+CREATE TABLE bonus_grant
+(
+    id         BIGSERIAL   NOT NULL PRIMARY KEY,
+    wallet_id  BIGINT      NOT NULL,
+    amount     INT         NOT NULL,
+    multiplier INT         NOT NULL,
+    wagered    INT         NOT NULL DEFAULT 0,
+    status     TEXT        NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id) ON DELETE CASCADE,
+    CONSTRAINT amount_positive CHECK (amount > 0),
+    CONSTRAINT multiplier_positive CHECK (multiplier > 0),
+    CONSTRAINT wagered_nonnegative CHECK (wagered >= 0),
+    CONSTRAINT status_known CHECK (status IN ('active', 'completed', 'forfeited', 'expired'))
+);
+
+CREATE INDEX bonus_grant_wallet_id_idx ON bonus_grant (wallet_id, id);
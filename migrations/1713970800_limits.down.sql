@@ -0,0 +1,2 @@
+DROP TABLE wallet_activity;
+DROP TABLE wallet_limit;
@@ -0,0 +1,30 @@
+CREATE TABLE wallet_limit
+(
+    wallet_id      BIGINT      NOT NULL,
+    kind           TEXT        NOT NULL,
+    period         TEXT        NOT NULL,
+    amount         INT                  DEFAULT NULL,
+    -- Raising or removing a limit replaces amount with pending_amount at pending_at.
+    pending_amount INT                  DEFAULT NULL,
+    pending_at     TIMESTAMPTZ          DEFAULT NULL,
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (wallet_id, kind, period),
+    CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id) ON DELETE CASCADE,
+    CONSTRAINT kind_known CHECK (kind IN ('deposit', 'loss', 'wager')),
+    CONSTRAINT period_known CHECK (period IN ('day', 'week', 'month')),
+    CONSTRAINT amount_nonnegative CHECK (COALESCE(amount, 0) >= 0 AND COALESCE(pending_amount, 0) >= 0),
+    CONSTRAINT pending_has_time CHECK (pending_amount IS NULL OR pending_at IS NOT NULL)
+);
+
+-- Player activity aggregated by the hour, so that limits over rolling windows
+-- do not have to scan the ledger.
+CREATE TABLE wallet_activity
+(
+    wallet_id BIGINT      NOT NULL,
+    hour      TIMESTAMPTZ NOT NULL,
+    deposits  BIGINT      NOT NULL DEFAULT 0,
+    wagers    BIGINT      NOT NULL DEFAULT 0,
+    wins      BIGINT      NOT NULL DEFAULT 0,
+    PRIMARY KEY (wallet_id, hour),
+    CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id) ON DELETE CASCADE
+);
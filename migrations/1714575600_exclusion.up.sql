@@ -0,0 +1,18 @@
+-- Exclusions are never deleted, so that overrides can be audited.
+CREATE TABLE wallet_exclusion
+(
+    id          BIGSERIAL   NOT NULL PRIMARY KEY,
+    wallet_id   BIGINT      NOT NULL,
+    period      TEXT        NOT NULL,
+    -- NULL for a permanent exclusion.
+    until       TIMESTAMPTZ          DEFAULT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    lifted_at   TIMESTAMPTZ          DEFAULT NULL,
+    lifted_by   TEXT        NOT NULL DEFAULT '',
+    lift_reason TEXT        NOT NULL DEFAULT '',
+    CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id) ON DELETE CASCADE,
+    CONSTRAINT period_known CHECK (period IN ('24h', '6m', 'permanent')),
+    CONSTRAINT lift_audited CHECK (lifted_at IS NULL OR (lifted_by <> '' AND lift_reason <> ''))
+);
+
+CREATE INDEX wallet_exclusion_wallet_id_idx ON wallet_exclusion (wallet_id, id);
@@ -0,0 +1,2 @@
+DROP TABLE withdrawal_transition;
+DROP TABLE withdrawal;
@@ -0,0 +1,35 @@
+-- The amount is held by the credit entry hold_entry_id from the moment the
+-- withdrawal is requested.
+CREATE TABLE withdrawal
+(
+    id            BIGSERIAL   NOT NULL PRIMARY KEY,
+    wallet_id     BIGINT      NOT NULL,
+    amount        INT         NOT NULL,
+    status        TEXT        NOT NULL,
+    hold_entry_id BIGINT      NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id) ON DELETE CASCADE,
+    CONSTRAINT amount_positive CHECK (amount > 0),
+    CONSTRAINT status_known CHECK (status IN ('requested', 'pending_review', 'approved', 'sent', 'failed', 'cancelled'))
+);
+
+CREATE INDEX withdrawal_wallet_id_idx ON withdrawal (wallet_id, id);
+
+-- Transitions are never updated or deleted, so that reviews can be audited.
+CREATE TABLE withdrawal_transition
+(
+    id            BIGSERIAL   NOT NULL PRIMARY KEY,
+    withdrawal_id BIGINT      NOT NULL,
+    wallet_id     BIGINT      NOT NULL,
+    -- Empty for the request itself.
+    from_status   TEXT        NOT NULL DEFAULT '',
+    to_status     TEXT        NOT NULL,
+    actor         TEXT        NOT NULL,
+    reason        TEXT        NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_withdrawal FOREIGN KEY (withdrawal_id) REFERENCES withdrawal (id) ON DELETE CASCADE,
+    CONSTRAINT actor_known CHECK (actor <> '')
+);
+
+CREATE INDEX withdrawal_transition_withdrawal_id_idx ON withdrawal_transition (withdrawal_id, id);
@@ -0,0 +1,6 @@
+DROP TABLE deposit;
+
+ALTER TABLE wallet_balance
+    DROP CONSTRAINT amount_nonnegative,
+    DROP COLUMN flagged,
+    ADD CONSTRAINT amount_nonnegative CHECK (amount >= 0);
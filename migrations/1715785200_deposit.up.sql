@@ -0,0 +1,28 @@
+-- A chargeback may take back more real money than is left. The balance then
+-- goes negative and the wallet is flagged until deposits cover the debt.
+ALTER TABLE wallet_balance
+    ADD COLUMN flagged BOOLEAN NOT NULL DEFAULT FALSE,
+    DROP CONSTRAINT amount_nonnegative,
+    ADD CONSTRAINT amount_nonnegative CHECK (amount >= 0 OR flagged);
+
+CREATE TABLE deposit
+(
+    id                BIGSERIAL   NOT NULL PRIMARY KEY,
+    wallet_id         BIGINT      NOT NULL,
+    provider          TEXT        NOT NULL,
+    reference         TEXT        NOT NULL,
+    amount            INT         NOT NULL,
+    status            TEXT        NOT NULL,
+    -- The debit entry that added the amount on confirmation.
+    entry_id          BIGINT               DEFAULT NULL,
+    -- The credit entry that took the amount back on a chargeback.
+    reversal_entry_id BIGINT               DEFAULT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id) ON DELETE CASCADE,
+    CONSTRAINT deposit_reference_unique UNIQUE (provider, reference),
+    CONSTRAINT amount_positive CHECK (amount > 0),
+    CONSTRAINT status_known CHECK (status IN ('pending', 'confirmed', 'failed', 'charged_back'))
+);
+
+CREATE INDEX deposit_wallet_id_idx ON deposit (wallet_id, id);
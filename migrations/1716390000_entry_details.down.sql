@@ -0,0 +1,11 @@
+DROP INDEX wallet_entry_metadata_idx;
+DROP INDEX wallet_entry_reference_idx;
+DROP INDEX wallet_entry_operation_idx;
+DROP INDEX wallet_entry_created_at_idx;
+DROP INDEX wallet_entry_wallet_id_idx;
+
+ALTER TABLE wallet_entry
+    DROP COLUMN metadata,
+    DROP COLUMN initiator,
+    DROP COLUMN reference,
+    DROP COLUMN operation;
@@ -0,0 +1,85 @@
+ALTER TABLE wallet_entry
+    ADD COLUMN operation TEXT           DEFAULT NULL,
+    -- Links the entry to a game round, a payment and so on. Not unique.
+    ADD COLUMN reference TEXT  NOT NULL DEFAULT '',
+    ADD COLUMN initiator TEXT  NOT NULL DEFAULT 'service',
+    ADD COLUMN metadata  JSONB          DEFAULT NULL;
+
+-- Older entries are told apart where a deposit, a withdrawal or a bonus grant
+-- links to them. The rows written in a transaction share its NOW(), so entries
+-- are matched to grants and withdrawals by the time they were written.
+UPDATE wallet_entry e
+SET operation = 'deposit',
+    reference = d.reference,
+    metadata  = jsonb_build_object('provider', d.provider)
+FROM deposit d
+WHERE d.entry_id = e.id;
+
+UPDATE wallet_entry e
+SET operation = 'chargeback',
+    reference = d.reference,
+    metadata  = jsonb_build_object('provider', d.provider)
+FROM deposit d
+WHERE d.reversal_entry_id = e.id;
+
+UPDATE wallet_entry e
+SET operation = 'withdrawal',
+    initiator = 'user'
+FROM withdrawal w
+WHERE w.hold_entry_id = e.id;
+
+-- A grant debits its whole amount into the bonus bucket.
+UPDATE wallet_entry e
+SET operation = 'bonus_grant'
+FROM bonus_grant g
+WHERE e.operation IS NULL
+  AND e.wallet_id = g.wallet_id
+  AND e.created_at = g.created_at
+  AND e.debit_amount = g.amount
+  AND e.bonus_amount = g.amount;
+
+-- A failed or cancelled withdrawal debits back the amount it held, and nothing
+-- else is written when it gets there.
+UPDATE wallet_entry e
+SET operation = 'withdrawal_release'
+FROM withdrawal w
+WHERE e.operation IS NULL
+  AND w.status IN ('failed', 'cancelled')
+  AND e.wallet_id = w.wallet_id
+  AND e.created_at = w.updated_at
+  AND e.debit_amount = w.amount
+  AND e.bonus_amount = 0;
+
+-- Closing a bonus releases it from the bonus bucket and converts it to real
+-- money in the same transaction as the bet or the operation that closed it,
+-- and the entries cannot be told apart. They are marked as legacy rather than
+-- taken for bets and wins.
+UPDATE wallet_entry e
+SET operation = 'legacy'
+WHERE e.operation IS NULL
+  AND EXISTS (
+    SELECT 1
+    FROM bonus_grant g
+    WHERE g.wallet_id = e.wallet_id
+      AND g.status <> 'active'
+      AND g.updated_at = e.created_at);
+
+-- Entries left were made by games, and only bets and wins were.
+UPDATE wallet_entry
+SET operation = CASE WHEN debit_amount IS NOT NULL THEN 'win' ELSE 'bet' END
+WHERE operation IS NULL;
+
+ALTER TABLE wallet_entry
+    ALTER COLUMN operation SET NOT NULL,
+    ALTER COLUMN initiator DROP DEFAULT,
+    ADD CONSTRAINT operation_known CHECK (operation IN (
+        'bet', 'win', 'refund', 'deposit', 'withdrawal', 'withdrawal_release', 'chargeback',
+        'bonus_grant', 'bonus_release', 'bonus_conversion', 'legacy')),
+    ADD CONSTRAINT initiator_known CHECK (initiator IN ('user', 'service', 'admin')),
+    ADD CONSTRAINT metadata_object CHECK (metadata IS NULL OR jsonb_typeof(metadata) = 'object');
+
+CREATE INDEX wallet_entry_wallet_id_idx ON wallet_entry (wallet_id, id);
+CREATE INDEX wallet_entry_created_at_idx ON wallet_entry (wallet_id, created_at);
+CREATE INDEX wallet_entry_operation_idx ON wallet_entry (wallet_id, operation, id);
+CREATE INDEX wallet_entry_reference_idx ON wallet_entry (reference) WHERE reference <> '';
+CREATE INDEX wallet_entry_metadata_idx ON wallet_entry USING GIN (metadata jsonb_path_ops);
@@ -0,0 +1,14 @@
+-- Reversals stay in the ledger, so that balances still add up, and are taken
+-- for wins and bets like untyped entries were.
+UPDATE wallet_entry
+SET operation = CASE WHEN debit_amount IS NOT NULL THEN 'win' ELSE 'bet' END
+WHERE operation = 'reversal';
+
+ALTER TABLE wallet_entry
+    DROP CONSTRAINT operation_known,
+    ADD CONSTRAINT operation_known CHECK (operation IN (
+        'bet', 'win', 'refund', 'deposit', 'withdrawal', 'withdrawal_release', 'chargeback',
+        'bonus_grant', 'bonus_release', 'bonus_conversion', 'legacy')),
+    DROP CONSTRAINT wallet_entry_reversal_unique,
+    DROP CONSTRAINT fk_reversal_of,
+    DROP COLUMN reversal_of;
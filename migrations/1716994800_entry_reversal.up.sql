@@ -0,0 +1,10 @@
+-- A reversal references the entry it undoes. The unique constraint makes sure
+-- an entry is reversed at most once and serves the lookup of the reversal.
+ALTER TABLE wallet_entry
+    ADD COLUMN reversal_of BIGINT DEFAULT NULL,
+    ADD CONSTRAINT fk_reversal_of FOREIGN KEY (reversal_of) REFERENCES wallet_entry (id),
+    ADD CONSTRAINT wallet_entry_reversal_unique UNIQUE (reversal_of),
+    DROP CONSTRAINT operation_known,
+    ADD CONSTRAINT operation_known CHECK (operation IN (
+        'bet', 'win', 'refund', 'deposit', 'withdrawal', 'withdrawal_release', 'chargeback',
+        'bonus_grant', 'bonus_release', 'bonus_conversion', 'legacy', 'reversal'));
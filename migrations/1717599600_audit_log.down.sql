@@ -0,0 +1,15 @@
+ALTER TABLE wallet_entry
+    DROP CONSTRAINT fk_wallet,
+    ADD CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id) ON DELETE CASCADE;
+
+DROP TRIGGER wallet_audit_no_truncate ON wallet_audit;
+DROP TRIGGER wallet_audit_append_only ON wallet_audit;
+DROP TRIGGER wallet_entry_no_truncate ON wallet_entry;
+DROP TRIGGER wallet_entry_append_only ON wallet_entry;
+DROP FUNCTION reject_change();
+
+DROP TRIGGER wallet_entry_audit ON wallet_entry;
+DROP FUNCTION wallet_audit_entry_added();
+DROP FUNCTION wallet_audit_append(wallet_entry);
+
+DROP TABLE wallet_audit;
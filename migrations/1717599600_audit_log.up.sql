@@ -0,0 +1,119 @@
+-- Every ledger entry gets a record in the audit log of its wallet. Each record
+-- keeps the entry as it was added and a hash over it and the hash of the
+-- previous record, so that any later change to either table breaks the chain.
+CREATE TABLE wallet_audit
+(
+    wallet_id  BIGINT      NOT NULL,
+    -- Numbers the records of a wallet from 1 without gaps.
+    seq        BIGINT      NOT NULL,
+    entry_id   BIGINT      NOT NULL,
+    -- Kept as text rather than JSONB, so that it reads back byte for byte as
+    -- it was hashed.
+    payload    TEXT        NOT NULL,
+    prev_hash  BYTEA                DEFAULT NULL,
+    hash       BYTEA       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT wallet_audit_pkey PRIMARY KEY (wallet_id, seq),
+    CONSTRAINT wallet_audit_entry_unique UNIQUE (entry_id)
+);
+
+-- The hash is SHA-256 over the previous hash followed by the payload, the same
+-- as domain.AuditHash.
+CREATE FUNCTION wallet_audit_append(e wallet_entry) RETURNS VOID AS
+$$
+DECLARE
+    last    wallet_audit%ROWTYPE;
+    payload TEXT;
+BEGIN
+    SELECT *
+    INTO last
+    FROM wallet_audit
+    WHERE wallet_id = e.wallet_id
+    ORDER BY seq DESC
+    LIMIT 1;
+
+    payload := jsonb_build_object(
+            'id', e.id,
+            'walletId', e.wallet_id,
+            'debit', e.debit_amount,
+            'credit', e.credit_amount,
+            'bonus', e.bonus_amount,
+            'operation', e.operation,
+            'reference', e.reference,
+            'initiator', e.initiator,
+            'metadata', e.metadata,
+            'reversalOf', e.reversal_of,
+            'createdAt', e.created_at)::TEXT;
+
+    INSERT INTO wallet_audit (wallet_id, seq, entry_id, payload, prev_hash, hash, created_at)
+    VALUES (e.wallet_id, COALESCE(last.seq, 0) + 1, e.id, payload, last.hash,
+            sha256(COALESCE(last.hash, ''::BYTEA) || convert_to(payload, 'UTF8')), e.created_at);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Entries added before the audit log are chained in the order of their IDs.
+DO
+$$
+DECLARE
+    e wallet_entry;
+BEGIN
+    FOR e IN SELECT * FROM wallet_entry ORDER BY wallet_id, id
+        LOOP
+            PERFORM wallet_audit_append(e);
+        END LOOP;
+END;
+$$;
+
+CREATE FUNCTION wallet_audit_entry_added() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM wallet_audit_append(NEW);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER wallet_entry_audit
+    AFTER INSERT
+    ON wallet_entry
+    FOR EACH ROW
+EXECUTE FUNCTION wallet_audit_entry_added();
+
+-- The ledger and its audit log are append-only. Only the owner of the tables
+-- can get around this, by disabling the triggers, and the verification tells
+-- if anything was changed that way.
+CREATE FUNCTION reject_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION '% is append-only, % is not allowed', TG_TABLE_NAME, TG_OP
+        USING ERRCODE = 'insufficient_privilege';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER wallet_entry_append_only
+    BEFORE UPDATE OR DELETE
+    ON wallet_entry
+    FOR EACH ROW
+EXECUTE FUNCTION reject_change();
+
+CREATE TRIGGER wallet_entry_no_truncate
+    BEFORE TRUNCATE
+    ON wallet_entry
+    FOR EACH STATEMENT
+EXECUTE FUNCTION reject_change();
+
+CREATE TRIGGER wallet_audit_append_only
+    BEFORE UPDATE OR DELETE
+    ON wallet_audit
+    FOR EACH ROW
+EXECUTE FUNCTION reject_change();
+
+CREATE TRIGGER wallet_audit_no_truncate
+    BEFORE TRUNCATE
+    ON wallet_audit
+    FOR EACH STATEMENT
+EXECUTE FUNCTION reject_change();
+
+-- Deleting a wallet must not wipe its history.
+ALTER TABLE wallet_entry
+    DROP CONSTRAINT fk_wallet,
+    ADD CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id);
@@ -0,0 +1,15 @@
+DROP TABLE admin_action;
+
+-- Adjustments stay in the ledger: it is append-only and relabelling them
+-- would break the audit log. The old constraint only applies to new entries.
+ALTER TABLE wallet_entry
+    DROP CONSTRAINT operation_known,
+    ADD CONSTRAINT operation_known CHECK (operation IN (
+        'bet', 'win', 'refund', 'deposit', 'withdrawal', 'withdrawal_release', 'chargeback',
+        'bonus_grant', 'bonus_release', 'bonus_conversion', 'legacy', 'reversal')) NOT VALID;
+
+DROP INDEX wallet_balance_frozen_idx;
+DROP INDEX wallet_balance_flagged_idx;
+
+ALTER TABLE wallet_balance
+    DROP COLUMN frozen;
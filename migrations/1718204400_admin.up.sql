@@ -0,0 +1,43 @@
+-- A frozen wallet cannot bet or withdraw until an admin unfreezes it.
+ALTER TABLE wallet_balance
+    ADD COLUMN frozen BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX wallet_balance_flagged_idx ON wallet_balance (wallet_id) WHERE flagged;
+CREATE INDEX wallet_balance_frozen_idx ON wallet_balance (wallet_id) WHERE frozen;
+
+ALTER TABLE wallet_entry
+    DROP CONSTRAINT operation_known,
+    ADD CONSTRAINT operation_known CHECK (operation IN (
+        'bet', 'win', 'refund', 'deposit', 'withdrawal', 'withdrawal_release', 'chargeback',
+        'bonus_grant', 'bonus_release', 'bonus_conversion', 'legacy', 'reversal', 'adjustment'));
+
+-- The audit trail of back-office operations. Like the ledger, it is
+-- append-only and outlives the wallets it refers to.
+CREATE TABLE admin_action
+(
+    id         BIGSERIAL   NOT NULL PRIMARY KEY,
+    wallet_id  BIGINT      NOT NULL,
+    kind       TEXT        NOT NULL,
+    actor      TEXT        NOT NULL,
+    reason     TEXT        NOT NULL DEFAULT '',
+    details    JSONB                DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id),
+    CONSTRAINT actor_known CHECK (actor <> ''),
+    CONSTRAINT details_object CHECK (details IS NULL OR jsonb_typeof(details) = 'object')
+);
+
+CREATE INDEX admin_action_wallet_id_idx ON admin_action (wallet_id, id);
+CREATE INDEX admin_action_actor_idx ON admin_action (actor, id);
+
+CREATE TRIGGER admin_action_append_only
+    BEFORE UPDATE OR DELETE
+    ON admin_action
+    FOR EACH ROW
+EXECUTE FUNCTION reject_change();
+
+CREATE TRIGGER admin_action_no_truncate
+    BEFORE TRUNCATE
+    ON admin_action
+    FOR EACH STATEMENT
+EXECUTE FUNCTION reject_change();
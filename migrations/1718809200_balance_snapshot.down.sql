@@ -0,0 +1,8 @@
+DROP TRIGGER wallet_entry_running_balance ON wallet_entry;
+DROP FUNCTION wallet_entry_running_balance();
+
+ALTER TABLE wallet_entry
+    DROP COLUMN bonus_balance_after,
+    DROP COLUMN balance_after;
+
+DROP TABLE wallet_balance_snapshot;
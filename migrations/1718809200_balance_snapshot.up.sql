@@ -0,0 +1,68 @@
+-- The balance of each wallet as the ledger has it at the end of every UTC day
+-- it has entries on, so that the balance at a point in time only needs the
+-- entries since the nearest snapshot. as_of is the end of the day: a snapshot
+-- covers every entry created before it.
+CREATE TABLE wallet_balance_snapshot
+(
+    wallet_id    BIGINT      NOT NULL,
+    as_of        TIMESTAMPTZ NOT NULL,
+    amount       INT         NOT NULL,
+    bonus_amount INT         NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT wallet_balance_snapshot_pkey PRIMARY KEY (wallet_id, as_of),
+    CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id)
+);
+
+-- Real and bonus money of the wallet after the entry. Entries added before
+-- these columns have none.
+ALTER TABLE wallet_entry
+    ADD COLUMN balance_after       INT DEFAULT NULL,
+    ADD COLUMN bonus_balance_after INT DEFAULT NULL;
+
+-- Takes the running balance of the previous entry of the wallet and applies
+-- the new one. If the previous entry has none, the history of the wallet is
+-- summed up instead.
+CREATE FUNCTION wallet_entry_running_balance() RETURNS TRIGGER AS
+$$
+DECLARE
+    prev_real  INT;
+    prev_bonus INT;
+BEGIN
+    SELECT balance_after, bonus_balance_after
+    INTO prev_real, prev_bonus
+    FROM wallet_entry
+    WHERE wallet_id = NEW.wallet_id
+    ORDER BY id DESC
+    LIMIT 1;
+
+    IF NOT FOUND THEN
+        prev_real := 0;
+        prev_bonus := 0;
+    ELSIF prev_real IS NULL THEN
+        SELECT COALESCE(SUM(CASE
+                                WHEN debit_amount IS NOT NULL THEN debit_amount - bonus_amount
+                                ELSE bonus_amount - credit_amount END), 0),
+               COALESCE(SUM(CASE
+                                WHEN debit_amount IS NOT NULL THEN bonus_amount
+                                ELSE -bonus_amount END), 0)
+        INTO prev_real, prev_bonus
+        FROM wallet_entry
+        WHERE wallet_id = NEW.wallet_id;
+    END IF;
+
+    IF NEW.debit_amount IS NOT NULL THEN
+        NEW.balance_after := prev_real + NEW.debit_amount - NEW.bonus_amount;
+        NEW.bonus_balance_after := prev_bonus + NEW.bonus_amount;
+    ELSE
+        NEW.balance_after := prev_real - NEW.credit_amount + NEW.bonus_amount;
+        NEW.bonus_balance_after := prev_bonus - NEW.bonus_amount;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER wallet_entry_running_balance
+    BEFORE INSERT
+    ON wallet_entry
+    FOR EACH ROW
+EXECUTE FUNCTION wallet_entry_running_balance();
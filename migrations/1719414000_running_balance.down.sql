@@ -0,0 +1,50 @@
+-- Backfilled balances are kept, they are what the trigger would have
+-- computed from the history anyway.
+ALTER TABLE wallet_entry
+    ALTER COLUMN balance_after DROP NOT NULL,
+    ALTER COLUMN bonus_balance_after DROP NOT NULL;
+
+CREATE FUNCTION wallet_entry_running_balance() RETURNS TRIGGER AS
+$$
+DECLARE
+    prev_real  INT;
+    prev_bonus INT;
+BEGIN
+    SELECT balance_after, bonus_balance_after
+    INTO prev_real, prev_bonus
+    FROM wallet_entry
+    WHERE wallet_id = NEW.wallet_id
+    ORDER BY id DESC
+    LIMIT 1;
+
+    IF NOT FOUND THEN
+        prev_real := 0;
+        prev_bonus := 0;
+    ELSIF prev_real IS NULL THEN
+        SELECT COALESCE(SUM(CASE
+                                WHEN debit_amount IS NOT NULL THEN debit_amount - bonus_amount
+                                ELSE bonus_amount - credit_amount END), 0),
+               COALESCE(SUM(CASE
+                                WHEN debit_amount IS NOT NULL THEN bonus_amount
+                                ELSE -bonus_amount END), 0)
+        INTO prev_real, prev_bonus
+        FROM wallet_entry
+        WHERE wallet_id = NEW.wallet_id;
+    END IF;
+
+    IF NEW.debit_amount IS NOT NULL THEN
+        NEW.balance_after := prev_real + NEW.debit_amount - NEW.bonus_amount;
+        NEW.bonus_balance_after := prev_bonus + NEW.bonus_amount;
+    ELSE
+        NEW.balance_after := prev_real - NEW.credit_amount + NEW.bonus_amount;
+        NEW.bonus_balance_after := prev_bonus - NEW.bonus_amount;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER wallet_entry_running_balance
+    BEFORE INSERT
+    ON wallet_entry
+    FOR EACH ROW
+EXECUTE FUNCTION wallet_entry_running_balance();
@@ -0,0 +1,35 @@
+-- The store now records the balance after each entry as it adds it, so the
+-- trigger that filled it in is no longer needed.
+DROP TRIGGER wallet_entry_running_balance ON wallet_entry;
+DROP FUNCTION wallet_entry_running_balance();
+
+-- Entries added before the running balance was recorded get it from the
+-- history of their wallet. The ledger is append-only, so the trigger that
+-- enforces it is disabled for the backfill. The audit log does not cover the
+-- running balance, so it stays intact.
+ALTER TABLE wallet_entry
+    DISABLE TRIGGER wallet_entry_append_only;
+
+UPDATE wallet_entry e
+SET balance_after       = b.balance_after,
+    bonus_balance_after = b.bonus_balance_after
+FROM (SELECT id,
+             SUM(CASE
+                     WHEN debit_amount IS NOT NULL THEN debit_amount - bonus_amount
+                     ELSE bonus_amount - credit_amount END)
+             OVER (PARTITION BY wallet_id ORDER BY id) AS balance_after,
+             SUM(CASE
+                     WHEN debit_amount IS NOT NULL THEN bonus_amount
+                     ELSE -bonus_amount END)
+             OVER (PARTITION BY wallet_id ORDER BY id) AS bonus_balance_after
+      FROM wallet_entry) b
+WHERE e.id = b.id
+  AND (e.balance_after IS DISTINCT FROM b.balance_after
+    OR e.bonus_balance_after IS DISTINCT FROM b.bonus_balance_after);
+
+ALTER TABLE wallet_entry
+    ENABLE TRIGGER wallet_entry_append_only;
+
+ALTER TABLE wallet_entry
+    ALTER COLUMN balance_after SET NOT NULL,
+    ALTER COLUMN bonus_balance_after SET NOT NULL;
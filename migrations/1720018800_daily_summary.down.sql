@@ -0,0 +1,3 @@
+DROP INDEX wallet_entry_created_at_all_idx;
+
+DROP TABLE report_daily_summary;
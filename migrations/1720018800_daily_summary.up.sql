@@ -0,0 +1,28 @@
+-- Daily totals of the ledger across all wallets for finance reports. Every
+-- UTC day from the first entry on has a row, zeros included. Amounts are
+-- thousandths, as in the ledger, but summed up into BIGINT. A reversal counts
+-- against the operation of the entry it undoes, on the day it was made.
+-- refreshed_at tells whether a day may still change: entries of txs that
+-- commit after midnight can still land on the day before.
+CREATE TABLE report_daily_summary
+(
+    day             DATE        NOT NULL PRIMARY KEY,
+    bets            BIGINT      NOT NULL,
+    wins            BIGINT      NOT NULL,
+    refunds         BIGINT      NOT NULL,
+    deposits        BIGINT      NOT NULL,
+    chargebacks     BIGINT      NOT NULL,
+    -- Withdrawals less the funds released back to wallets.
+    withdrawals     BIGINT      NOT NULL,
+    bonus_granted   BIGINT      NOT NULL,
+    bonus_converted BIGINT      NOT NULL,
+    -- Bonus money taken back when a bonus is completed, expired or forfeited.
+    bonus_released  BIGINT      NOT NULL,
+    -- Money added by admins less money taken.
+    adjustments     BIGINT      NOT NULL,
+    entries         INT         NOT NULL,
+    refreshed_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- The existing index leads with the wallet, the aggregation reads all wallets.
+CREATE INDEX wallet_entry_created_at_all_idx ON wallet_entry (created_at);
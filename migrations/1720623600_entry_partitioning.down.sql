@@ -0,0 +1,100 @@
+-- Archived entries are taken back into the single table too. Files they were
+-- exported to are left alone.
+DROP VIEW wallet_entry_history;
+
+DROP FUNCTION wallet_audit_append(wallet_entry);
+
+ALTER TABLE wallet_entry
+    RENAME TO wallet_entry_partitioned;
+
+ALTER SEQUENCE wallet_entry_id_seq OWNED BY NONE;
+
+CREATE TABLE wallet_entry
+(
+    LIKE wallet_entry_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS
+);
+
+INSERT INTO wallet_entry
+SELECT *
+FROM wallet_entry_partitioned
+UNION ALL
+SELECT *
+FROM wallet_entry_archive;
+
+DROP TABLE wallet_entry_partitioned;
+DROP TABLE wallet_entry_archive;
+DROP TABLE wallet_entry_reversal;
+DROP FUNCTION wallet_entry_add_partition(TIMESTAMPTZ);
+
+ALTER SEQUENCE wallet_entry_id_seq OWNED BY wallet_entry.id;
+
+ALTER TABLE wallet_entry
+    ADD CONSTRAINT wallet_entry_pkey PRIMARY KEY (id),
+    ADD CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id),
+    ADD CONSTRAINT fk_reversal_of FOREIGN KEY (reversal_of) REFERENCES wallet_entry (id),
+    ADD CONSTRAINT wallet_entry_reversal_unique UNIQUE (reversal_of);
+
+CREATE INDEX wallet_entry_wallet_id_idx ON wallet_entry (wallet_id, id);
+CREATE INDEX wallet_entry_created_at_idx ON wallet_entry (wallet_id, created_at);
+CREATE INDEX wallet_entry_operation_idx ON wallet_entry (wallet_id, operation, id);
+CREATE INDEX wallet_entry_reference_idx ON wallet_entry (reference) WHERE reference <> '';
+CREATE INDEX wallet_entry_metadata_idx ON wallet_entry USING GIN (metadata jsonb_path_ops);
+CREATE INDEX wallet_entry_created_at_all_idx ON wallet_entry (created_at);
+
+CREATE FUNCTION wallet_audit_append(e wallet_entry) RETURNS VOID AS
+$$
+DECLARE
+    last    wallet_audit%ROWTYPE;
+    payload TEXT;
+BEGIN
+    SELECT *
+    INTO last
+    FROM wallet_audit
+    WHERE wallet_id = e.wallet_id
+    ORDER BY seq DESC
+    LIMIT 1;
+
+    payload := jsonb_build_object(
+            'id', e.id,
+            'walletId', e.wallet_id,
+            'debit', e.debit_amount,
+            'credit', e.credit_amount,
+            'bonus', e.bonus_amount,
+            'operation', e.operation,
+            'reference', e.reference,
+            'initiator', e.initiator,
+            'metadata', e.metadata,
+            'reversalOf', e.reversal_of,
+            'createdAt', e.created_at)::TEXT;
+
+    INSERT INTO wallet_audit (wallet_id, seq, entry_id, payload, prev_hash, hash, created_at)
+    VALUES (e.wallet_id, COALESCE(last.seq, 0) + 1, e.id, payload, last.hash,
+            sha256(COALESCE(last.hash, ''::BYTEA) || convert_to(payload, 'UTF8')), e.created_at);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION wallet_audit_entry_added() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM wallet_audit_append(NEW);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER wallet_entry_audit
+    AFTER INSERT
+    ON wallet_entry
+    FOR EACH ROW
+EXECUTE FUNCTION wallet_audit_entry_added();
+
+CREATE TRIGGER wallet_entry_append_only
+    BEFORE UPDATE OR DELETE
+    ON wallet_entry
+    FOR EACH ROW
+EXECUTE FUNCTION reject_change();
+
+CREATE TRIGGER wallet_entry_no_truncate
+    BEFORE TRUNCATE
+    ON wallet_entry
+    FOR EACH STATEMENT
+EXECUTE FUNCTION reject_change();
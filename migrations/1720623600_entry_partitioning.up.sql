@@ -0,0 +1,208 @@
+-- The ledger is partitioned by the UTC month of created_at. The primary key
+-- of a partitioned table has to include the partition key, so the ID alone is
+-- no longer unique to the database, though the sequence still hands out each
+-- ID once. Entries outside of the partitions land in the default partition,
+-- which the maintenance job keeps empty by adding partitions ahead of time.
+--
+-- The audit function takes the row type of the table, so it goes first and
+-- is added back for the new table below.
+DROP FUNCTION wallet_audit_append(wallet_entry);
+
+ALTER TABLE wallet_entry
+    RENAME TO wallet_entry_unpartitioned;
+
+-- Dropping the old table would drop the sequence of the IDs with it.
+ALTER SEQUENCE wallet_entry_id_seq OWNED BY NONE;
+
+CREATE TABLE wallet_entry
+(
+    LIKE wallet_entry_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS
+) PARTITION BY RANGE (created_at);
+
+-- Adds the partition of wallet_entry for the UTC month that starts at
+-- month_start, unless it already exists either live or archived. Returns
+-- whether it added it. Partitions are named after their month, e.g.
+-- wallet_entry_2024_06.
+CREATE FUNCTION wallet_entry_add_partition(month_start TIMESTAMPTZ) RETURNS BOOLEAN AS
+$$
+DECLARE
+    month TIMESTAMP := month_start AT TIME ZONE 'UTC';
+    name  TEXT      := 'wallet_entry_' || to_char(month, 'YYYY_MM');
+BEGIN
+    IF month <> date_trunc('month', month) THEN
+        RAISE EXCEPTION 'partition must start at a UTC month, not at %', month_start;
+    END IF;
+    IF to_regclass(name) IS NOT NULL THEN
+        RETURN FALSE;
+    END IF;
+
+    EXECUTE format('CREATE TABLE %I PARTITION OF wallet_entry FOR VALUES FROM (%L) TO (%L)',
+                   name, month_start, (month + INTERVAL '1 month') AT TIME ZONE 'UTC');
+    -- Statement triggers of the parent do not fire for its partitions.
+    EXECUTE format('CREATE TRIGGER %I BEFORE TRUNCATE ON %I FOR EACH STATEMENT EXECUTE FUNCTION reject_change()',
+                   name || '_no_truncate', name);
+    RETURN TRUE;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Month arithmetic is done on UTC timestamps, as it depends on the time zone
+-- of the session otherwise.
+SELECT wallet_entry_add_partition(month AT TIME ZONE 'UTC')
+FROM generate_series(
+             date_trunc('month', COALESCE((SELECT MIN(created_at) FROM wallet_entry_unpartitioned), NOW()) AT TIME ZONE 'UTC'),
+             date_trunc('month', NOW() AT TIME ZONE 'UTC') + INTERVAL '3 months',
+             INTERVAL '1 month') AS month;
+
+CREATE TABLE wallet_entry_default PARTITION OF wallet_entry DEFAULT;
+
+CREATE TRIGGER wallet_entry_default_no_truncate
+    BEFORE TRUNCATE
+    ON wallet_entry_default
+    FOR EACH STATEMENT
+EXECUTE FUNCTION reject_change();
+
+-- The new table has no triggers yet, so the entries are not audited again.
+INSERT INTO wallet_entry
+SELECT *
+FROM wallet_entry_unpartitioned;
+
+DROP TABLE wallet_entry_unpartitioned;
+
+ALTER SEQUENCE wallet_entry_id_seq OWNED BY wallet_entry.id;
+
+ALTER TABLE wallet_entry
+    ADD CONSTRAINT wallet_entry_pkey PRIMARY KEY (id, created_at),
+    ADD CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id);
+
+CREATE INDEX wallet_entry_wallet_id_idx ON wallet_entry (wallet_id, id);
+CREATE INDEX wallet_entry_created_at_idx ON wallet_entry (wallet_id, created_at);
+CREATE INDEX wallet_entry_operation_idx ON wallet_entry (wallet_id, operation, id);
+CREATE INDEX wallet_entry_reference_idx ON wallet_entry (reference) WHERE reference <> '';
+CREATE INDEX wallet_entry_metadata_idx ON wallet_entry USING GIN (metadata jsonb_path_ops);
+CREATE INDEX wallet_entry_created_at_all_idx ON wallet_entry (created_at);
+
+-- A unique constraint on reversal_of alone cannot span the partitions, and a
+-- foreign key cannot reference the ID alone, so reversals are also recorded
+-- here. The primary key makes sure an entry is reversed at most once. That
+-- the reversed entry exists is up to the store.
+CREATE TABLE wallet_entry_reversal
+(
+    entry_id    BIGINT NOT NULL,
+    reversal_id BIGINT NOT NULL,
+    CONSTRAINT wallet_entry_reversal_unique PRIMARY KEY (entry_id)
+);
+
+INSERT INTO wallet_entry_reversal (entry_id, reversal_id)
+SELECT reversal_of, id
+FROM wallet_entry
+WHERE reversal_of IS NOT NULL;
+
+CREATE TRIGGER wallet_entry_reversal_append_only
+    BEFORE UPDATE OR DELETE
+    ON wallet_entry_reversal
+    FOR EACH ROW
+EXECUTE FUNCTION reject_change();
+
+CREATE TRIGGER wallet_entry_reversal_no_truncate
+    BEFORE TRUNCATE
+    ON wallet_entry_reversal
+    FOR EACH STATEMENT
+EXECUTE FUNCTION reject_change();
+
+CREATE FUNCTION wallet_audit_append(e wallet_entry) RETURNS VOID AS
+$$
+DECLARE
+    last    wallet_audit%ROWTYPE;
+    payload TEXT;
+BEGIN
+    SELECT *
+    INTO last
+    FROM wallet_audit
+    WHERE wallet_id = e.wallet_id
+    ORDER BY seq DESC
+    LIMIT 1;
+
+    payload := jsonb_build_object(
+            'id', e.id,
+            'walletId', e.wallet_id,
+            'debit', e.debit_amount,
+            'credit', e.credit_amount,
+            'bonus', e.bonus_amount,
+            'operation', e.operation,
+            'reference', e.reference,
+            'initiator', e.initiator,
+            'metadata', e.metadata,
+            'reversalOf', e.reversal_of,
+            'createdAt', e.created_at)::TEXT;
+
+    INSERT INTO wallet_audit (wallet_id, seq, entry_id, payload, prev_hash, hash, created_at)
+    VALUES (e.wallet_id, COALESCE(last.seq, 0) + 1, e.id, payload, last.hash,
+            sha256(COALESCE(last.hash, ''::BYTEA) || convert_to(payload, 'UTF8')), e.created_at);
+END;
+$$ LANGUAGE plpgsql;
+
+-- The trigger runs on the partition the entry went to, so the row has the
+-- type of the partition.
+CREATE OR REPLACE FUNCTION wallet_audit_entry_added() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM wallet_audit_append(NEW::wallet_entry);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER wallet_entry_audit
+    AFTER INSERT
+    ON wallet_entry
+    FOR EACH ROW
+EXECUTE FUNCTION wallet_audit_entry_added();
+
+CREATE TRIGGER wallet_entry_append_only
+    BEFORE UPDATE OR DELETE
+    ON wallet_entry
+    FOR EACH ROW
+EXECUTE FUNCTION reject_change();
+
+CREATE TRIGGER wallet_entry_no_truncate
+    BEFORE TRUNCATE
+    ON wallet_entry
+    FOR EACH STATEMENT
+EXECUTE FUNCTION reject_change();
+
+-- Archived partitions are detached from wallet_entry and attached here, so
+-- that new entries never touch them. The indexes match those of wallet_entry,
+-- so that attaching a partition takes over its indexes instead of building
+-- them again.
+CREATE TABLE wallet_entry_archive
+(
+    LIKE wallet_entry INCLUDING CONSTRAINTS,
+    CONSTRAINT wallet_entry_archive_pkey PRIMARY KEY (id, created_at),
+    CONSTRAINT fk_wallet FOREIGN KEY (wallet_id) REFERENCES wallet (id)
+) PARTITION BY RANGE (created_at);
+
+CREATE INDEX wallet_entry_archive_wallet_id_idx ON wallet_entry_archive (wallet_id, id);
+CREATE INDEX wallet_entry_archive_created_at_idx ON wallet_entry_archive (wallet_id, created_at);
+CREATE INDEX wallet_entry_archive_operation_idx ON wallet_entry_archive (wallet_id, operation, id);
+CREATE INDEX wallet_entry_archive_reference_idx ON wallet_entry_archive (reference) WHERE reference <> '';
+CREATE INDEX wallet_entry_archive_metadata_idx ON wallet_entry_archive USING GIN (metadata jsonb_path_ops);
+CREATE INDEX wallet_entry_archive_created_at_all_idx ON wallet_entry_archive (created_at);
+
+CREATE TRIGGER wallet_entry_archive_append_only
+    BEFORE INSERT OR UPDATE OR DELETE
+    ON wallet_entry_archive
+    FOR EACH ROW
+EXECUTE FUNCTION reject_change();
+
+CREATE TRIGGER wallet_entry_archive_no_truncate
+    BEFORE TRUNCATE
+    ON wallet_entry_archive
+    FOR EACH STATEMENT
+EXECUTE FUNCTION reject_change();
+
+-- History is read from here, so that it covers live and archived entries.
+CREATE VIEW wallet_entry_history AS
+SELECT *
+FROM wallet_entry
+UNION ALL
+SELECT *
+FROM wallet_entry_archive;
@@ -0,0 +1,3 @@
+DROP TRIGGER withdrawal_transition_outbox ON withdrawal_transition;
+DROP FUNCTION withdrawal_transition_added();
+DROP TABLE withdrawal_outbox;
@@ -0,0 +1,23 @@
+-- Transitions not published yet. A row is added in the same tx as its
+-- transition and deleted once the transition is published, so that no
+-- transition is lost when the broker is down or the process dies after the
+-- commit.
+CREATE TABLE withdrawal_outbox
+(
+    transition_id BIGINT NOT NULL PRIMARY KEY,
+    CONSTRAINT fk_transition FOREIGN KEY (transition_id) REFERENCES withdrawal_transition (id) ON DELETE CASCADE
+);
+
+CREATE FUNCTION withdrawal_transition_added() RETURNS TRIGGER AS
+$$
+BEGIN
+    INSERT INTO withdrawal_outbox (transition_id) VALUES (NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER withdrawal_transition_outbox
+    AFTER INSERT
+    ON withdrawal_transition
+    FOR EACH ROW
+EXECUTE FUNCTION withdrawal_transition_added();
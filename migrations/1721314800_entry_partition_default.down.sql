@@ -0,0 +1,23 @@
+DROP FUNCTION wallet_entry_add_partition(TIMESTAMPTZ);
+
+CREATE FUNCTION wallet_entry_add_partition(month_start TIMESTAMPTZ) RETURNS BOOLEAN AS
+$$
+DECLARE
+    month TIMESTAMP := month_start AT TIME ZONE 'UTC';
+    name  TEXT      := 'wallet_entry_' || to_char(month, 'YYYY_MM');
+BEGIN
+    IF month <> date_trunc('month', month) THEN
+        RAISE EXCEPTION 'partition must start at a UTC month, not at %', month_start;
+    END IF;
+    IF to_regclass(name) IS NOT NULL THEN
+        RETURN FALSE;
+    END IF;
+
+    EXECUTE format('CREATE TABLE %I PARTITION OF wallet_entry FOR VALUES FROM (%L) TO (%L)',
+                   name, month_start, (month + INTERVAL '1 month') AT TIME ZONE 'UTC');
+    -- Statement triggers of the parent do not fire for its partitions.
+    EXECUTE format('CREATE TRIGGER %I BEFORE TRUNCATE ON %I FOR EACH STATEMENT EXECUTE FUNCTION reject_change()',
+                   name || '_no_truncate', name);
+    RETURN TRUE;
+END;
+$$ LANGUAGE plpgsql;
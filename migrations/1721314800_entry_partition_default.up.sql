@@ -0,0 +1,59 @@
+-- Entries of a month without a partition land in the default partition. A
+-- partition cannot be added for a month the default partition has entries of,
+-- so adding one now moves those entries into it. The default partition is
+-- detached while they are moved, which drops the append-only triggers it has
+-- as a partition, and attached again after. It all happens in the tx of the
+-- caller, so the ledger is locked until it commits and no one sees the
+-- entries missing in between. Returns whether the partition was added and how
+-- many entries were moved into it.
+DROP FUNCTION wallet_entry_add_partition(TIMESTAMPTZ);
+
+CREATE FUNCTION wallet_entry_add_partition(month_start TIMESTAMPTZ, OUT added BOOLEAN, OUT moved BIGINT) AS
+$$
+DECLARE
+    month     TIMESTAMP   := month_start AT TIME ZONE 'UTC';
+    month_end TIMESTAMPTZ := (month + INTERVAL '1 month') AT TIME ZONE 'UTC';
+    name      TEXT        := 'wallet_entry_' || to_char(month, 'YYYY_MM');
+BEGIN
+    added := FALSE;
+    moved := 0;
+
+    IF month <> date_trunc('month', month) THEN
+        RAISE EXCEPTION 'partition must start at a UTC month, not at %', month_start;
+    END IF;
+    IF to_regclass(name) IS NOT NULL THEN
+        RETURN;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1
+                   FROM wallet_entry_default
+                   WHERE created_at >= month_start
+                     AND created_at < month_end) THEN
+        EXECUTE format('CREATE TABLE %I PARTITION OF wallet_entry FOR VALUES FROM (%L) TO (%L)',
+                       name, month_start, month_end);
+    ELSE
+        LOCK TABLE wallet_entry IN ACCESS EXCLUSIVE MODE;
+        ALTER TABLE wallet_entry DETACH PARTITION wallet_entry_default;
+
+        -- A table of its own has no triggers, so the entries are neither
+        -- audited again nor get their balances recomputed.
+        EXECUTE format('CREATE TABLE %I (LIKE wallet_entry INCLUDING DEFAULTS INCLUDING CONSTRAINTS)', name);
+        EXECUTE format('INSERT INTO %I SELECT * FROM wallet_entry_default WHERE created_at >= %L AND created_at < %L',
+                       name, month_start, month_end);
+        GET DIAGNOSTICS moved = ROW_COUNT;
+        DELETE
+        FROM wallet_entry_default
+        WHERE created_at >= month_start
+          AND created_at < month_end;
+
+        EXECUTE format('ALTER TABLE wallet_entry ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)',
+                       name, month_start, month_end);
+        ALTER TABLE wallet_entry ATTACH PARTITION wallet_entry_default DEFAULT;
+    END IF;
+
+    -- Statement triggers of the parent do not fire for its partitions.
+    EXECUTE format('CREATE TRIGGER %I BEFORE TRUNCATE ON %I FOR EACH STATEMENT EXECUTE FUNCTION reject_change()',
+                   name || '_no_truncate', name);
+    added := TRUE;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,15 @@
+-- Summaries of several brands or currencies cannot be merged back into one
+-- per day, so they are dropped and aggregated again from the ledger.
+DELETE FROM report_daily_summary;
+
+ALTER TABLE report_daily_summary
+    DROP CONSTRAINT report_daily_summary_pkey,
+    DROP COLUMN currency,
+    DROP COLUMN brand,
+    ADD PRIMARY KEY (day);
+
+ALTER TABLE wallet
+    DROP CONSTRAINT currency_code,
+    DROP CONSTRAINT brand_not_empty,
+    DROP COLUMN currency,
+    DROP COLUMN brand;
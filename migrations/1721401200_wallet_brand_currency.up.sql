@@ -0,0 +1,19 @@
+-- The brand a wallet was opened with and the currency of its money. Wallets
+-- made before either was recorded belong to the default brand and are in EUR.
+ALTER TABLE wallet
+    ADD COLUMN brand    TEXT NOT NULL DEFAULT 'default',
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'EUR',
+    ADD CONSTRAINT brand_not_empty CHECK (brand <> ''),
+    ADD CONSTRAINT currency_code CHECK (currency ~ '^[A-Z]{3}$');
+
+-- Money of different currencies cannot be summed up, so summaries are kept
+-- per brand and currency. Every summary so far is of the wallets above.
+ALTER TABLE report_daily_summary
+    ADD COLUMN brand    TEXT NOT NULL DEFAULT 'default',
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'EUR',
+    DROP CONSTRAINT report_daily_summary_pkey,
+    ADD PRIMARY KEY (day, brand, currency);
+
+ALTER TABLE report_daily_summary
+    ALTER COLUMN brand DROP DEFAULT,
+    ALTER COLUMN currency DROP DEFAULT;